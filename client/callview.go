@@ -2,6 +2,7 @@ package client
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/kv/dict"
@@ -16,3 +17,14 @@ func (c *WaspClient) CallView(contractID coretypes.ContractID, fname string, arg
 	}
 	return res, nil
 }
+
+// CallViewAtBlock calls a view function of a given contract as of the past state of the chain,
+// after the block with the given index
+func (c *WaspClient) CallViewAtBlock(contractID coretypes.ContractID, fname string, arguments dict.Dict, blockIndex uint32) (dict.Dict, error) {
+	var res dict.Dict
+	route := routes.CallViewAtBlock(contractID.Base58(), fname, strconv.FormatUint(uint64(blockIndex), 10))
+	if err := c.do(http.MethodGet, route, arguments, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
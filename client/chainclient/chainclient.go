@@ -3,12 +3,16 @@ package chainclient
 import (
 	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/coretypes/requestargs"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/vm/core/root"
 
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address/signaturescheme"
 	"github.com/iotaledger/wasp/client"
 	"github.com/iotaledger/wasp/client/level1"
 	"github.com/iotaledger/wasp/packages/apilib"
 	"github.com/iotaledger/wasp/packages/sctransaction"
+	"github.com/iotaledger/wasp/packages/tcrypto"
 )
 
 // Client allows to send webapi requests to a specific chain in the node
@@ -35,11 +39,32 @@ func New(
 }
 
 type PostRequestParams struct {
-	Transfer coretypes.ColoredBalances
-	Args     requestargs.RequestArgs
+	Transfer  coretypes.ColoredBalances
+	Args      requestargs.RequestArgs
+	GasBudget uint64
+	// Stealth keys of the committee. If not nil, Args are encrypted, so only the committee can read them
+	Stealth *tcrypto.StealthKeys
 }
 
-// PostRequest sends a request transaction to the chain
+// ChainAddress returns the address of the current committee of the chain.
+// It differs from the chain ID after the committee has been rotated
+func (c *Client) ChainAddress() (address.Address, error) {
+	info, err := c.CallView(root.Interface.Hname(), root.FuncGetChainInfo, nil)
+	if err != nil {
+		return address.Address{}, err
+	}
+	addr, ok, err := codec.DecodeAddress(info.MustGet(root.VarChainAddress))
+	if err != nil {
+		return address.Address{}, err
+	}
+	if !ok {
+		return address.Address(c.ChainID), nil
+	}
+	return addr, nil
+}
+
+// PostRequest sends a request transaction to the chain.
+// The request is sent to the address of the current committee of the chain
 func (c *Client) PostRequest(
 	contractHname coretypes.Hname,
 	entryPoint coretypes.Hname,
@@ -49,7 +74,21 @@ func (c *Client) PostRequest(
 	if len(params) > 0 {
 		par = params[0]
 	}
+	args := par.Args
+	if par.Stealth != nil {
+		if args == nil {
+			args = requestargs.New(nil)
+		}
+		var err error
+		if args, err = requestargs.NewEncryptedRequestArgs(args, par.Stealth.Encrypt); err != nil {
+			return nil, err
+		}
+	}
 
+	chainAddress, err := c.ChainAddress()
+	if err != nil {
+		return nil, err
+	}
 	return apilib.CreateRequestTransaction(apilib.CreateRequestTransactionParams{
 		Level1Client:    c.Level1Client,
 		SenderSigScheme: c.SigScheme,
@@ -57,7 +96,9 @@ func (c *Client) PostRequest(
 			TargetContractID: coretypes.NewContractID(c.ChainID, contractHname),
 			EntryPointCode:   entryPoint,
 			Transfer:         par.Transfer,
-			Args:             par.Args,
+			Args:             args,
+			GasBudget:        par.GasBudget,
+			TargetAddress:    &chainAddress,
 		}},
 		Post: true,
 	})
@@ -0,0 +1,11 @@
+package chainclient
+
+import (
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+)
+
+// ChainEvents streams committed blocks, processed requests and event log records of the chain into the channel
+func (c *Client) ChainEvents(contract *coretypes.Hname, done <-chan struct{}) (<-chan *model.ChainEvent, error) {
+	return c.WaspClient.ChainEvents(&c.ChainID, contract, done)
+}
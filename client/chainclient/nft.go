@@ -0,0 +1,17 @@
+package chainclient
+
+import (
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+)
+
+// GetNFT returns the NFT with the ID
+func (c *Client) GetNFT(id balance.Color) (*model.NFT, error) {
+	return c.WaspClient.GetNFT(&c.ChainID, id)
+}
+
+// GetNFTs returns NFTs owned by the agent
+func (c *Client) GetNFTs(agentID coretypes.AgentID) ([]*model.NFT, error) {
+	return c.WaspClient.GetNFTs(&c.ChainID, agentID)
+}
@@ -0,0 +1,33 @@
+package chainclient
+
+import (
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/coretypes/requestargs"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/sctransaction"
+)
+
+// PostOffTangleRequest sends a request with the arguments delivered to the committee off-tangle
+//   - all arguments are uploaded to the blob cache of the Wasp node of the client
+//   - only hashes of the arguments are placed into the request transaction
+//   - committee nodes receive the data from each other before the request is processed.
+//     The request status is reported by RequestStatus
+func (c *Client) PostOffTangleRequest(
+	contractHname coretypes.Hname,
+	entryPoint coretypes.Hname,
+	args dict.Dict,
+	params ...PostRequestParams,
+) (*sctransaction.Transaction, error) {
+	par := PostRequestParams{}
+	if len(params) > 0 {
+		par = params[0]
+	}
+	argsEncoded, blobs := requestargs.NewOffTangleRequestArgs(args)
+	for _, data := range blobs {
+		if _, err := c.WaspClient.PutBlob(data); err != nil {
+			return nil, err
+		}
+	}
+	par.Args = argsEncoded
+	return c.PostRequest(contractHname, entryPoint, par)
+}
@@ -0,0 +1,37 @@
+package chainclient
+
+import (
+	"encoding/base64"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
+	"github.com/iotaledger/wasp/packages/tcrypto"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+)
+
+// StealthKeys retrieves public key shares of the committee with the address from the node.
+// The keys are used in PostRequestParams to post "stealth" requests, which arguments
+// can only be read by the committee. The committee address is equal to the chain ID unless
+// the committee of the chain has been rotated
+func (c *Client) StealthKeys(committeeAddress *address.Address) (*tcrypto.StealthKeys, error) {
+	info, err := c.WaspClient.DKSharesGet(committeeAddress)
+	if err != nil {
+		return nil, err
+	}
+	suite := pairing.NewSuiteBn256()
+	ret := &tcrypto.StealthKeys{
+		Suite:        suite,
+		PubKeyShares: make([]kyber.Point, len(info.PubKeyShares)),
+	}
+	for i, s := range info.PubKeyShares {
+		b, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return nil, err
+		}
+		ret.PubKeyShares[i] = suite.Point()
+		if err := ret.PubKeyShares[i].UnmarshalBinary(b); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
@@ -0,0 +1,16 @@
+package chainclient
+
+import (
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+)
+
+// GetNativeTokens returns all chain-native tokens of the chain
+func (c *Client) GetNativeTokens() ([]*model.NativeToken, error) {
+	return c.WaspClient.GetNativeTokens(&c.ChainID)
+}
+
+// GetTokenHoldings returns balances of chain-native tokens in the on-chain account of the agent
+func (c *Client) GetTokenHoldings(agentID coretypes.AgentID) ([]*model.TokenHolding, error) {
+	return c.WaspClient.GetTokenHoldings(&c.ChainID, agentID)
+}
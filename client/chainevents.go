@@ -0,0 +1,82 @@
+package client
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/packages/webapi/routes"
+	"golang.org/x/net/websocket"
+)
+
+// ChainEvents opens a WebSocket stream of the events of the chain: committed blocks, processed requests
+// and event log records. If contract is not nil, only requests and event log records of the contract are streamed.
+// The returned channel is closed when done is closed or the connection is lost
+func (c *WaspClient) ChainEvents(chainID *coretypes.ChainID, contract *coretypes.Hname, done <-chan struct{}) (<-chan *model.ChainEvent, error) {
+	wsURL := strings.TrimRight(c.baseURL, "/") + routes.ChainEventsWebSocket(chainID.String())
+	if contract != nil {
+		wsURL += "?contract=" + url.QueryEscape(contract.String())
+	}
+	switch {
+	case strings.HasPrefix(wsURL, "https://"):
+		wsURL = "wss://" + strings.TrimPrefix(wsURL, "https://")
+	case strings.HasPrefix(wsURL, "http://"):
+		wsURL = "ws://" + strings.TrimPrefix(wsURL, "http://")
+	}
+	ws, err := websocket.Dial(wsURL, "", c.baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("can't connect to %s: %v", wsURL, err)
+	}
+
+	ret := make(chan *model.ChainEvent)
+	stopped := make(chan struct{})
+	go func() {
+		select {
+		case <-done:
+		case <-stopped:
+		}
+		ws.Close()
+	}()
+	go func() {
+		defer close(ret)
+		defer close(stopped)
+		for {
+			ev := &model.ChainEvent{}
+			if err := websocket.JSON.Receive(ws, ev); err != nil {
+				return
+			}
+			select {
+			case ret <- ev:
+			case <-done:
+				return
+			}
+		}
+	}()
+	return ret, nil
+}
+
+// GetEventsByTopic returns up to maxLast last structured events of the chain with the topic, in time descending order.
+// If contract is not nil, only events of the contract are returned. maxLast == 0 means the default maximum
+func (c *WaspClient) GetEventsByTopic(chainID *coretypes.ChainID, topic []byte, contract *coretypes.Hname, maxLast int) ([]*model.TypedEvent, error) {
+	query := url.Values{}
+	if contract != nil {
+		query.Set("contract", contract.String())
+	}
+	if maxLast > 0 {
+		query.Set("max", strconv.Itoa(maxLast))
+	}
+	route := routes.EventsByTopic(chainID.String(), hex.EncodeToString(topic))
+	if len(query) > 0 {
+		route += "?" + query.Encode()
+	}
+	var res []*model.TypedEvent
+	if err := c.do(http.MethodGet, route, nil, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
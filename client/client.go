@@ -89,6 +89,31 @@ func (c *WaspClient) do(method string, route string, reqObj interface{}, resObj
 	return processResponse(res, resObj)
 }
 
+// doStream sends the request body as a binary stream and copies the binary response body to dst
+func (c *WaspClient) doStream(method string, route string, body io.Reader, dst io.Writer) error {
+	url := fmt.Sprintf("%s/%s", strings.TrimRight(c.baseURL, "/"), strings.TrimLeft(route, "/"))
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/octet-stream")
+	}
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("Request failed: %v", err)
+	}
+	if res.StatusCode != http.StatusOK || dst == nil {
+		return processResponse(res, nil)
+	}
+	defer res.Body.Close()
+	if _, err := io.Copy(dst, res.Body); err != nil {
+		return fmt.Errorf("unable to read response body: %w", err)
+	}
+	return nil
+}
+
 // BaseURL returns the baseURL of the client.
 func (c *WaspClient) BaseURL() string {
 	return c.baseURL
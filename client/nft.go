@@ -0,0 +1,28 @@
+package client
+
+import (
+	"net/http"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/packages/webapi/routes"
+)
+
+// GetNFT returns the NFT with the ID
+func (c *WaspClient) GetNFT(chainID *coretypes.ChainID, id balance.Color) (*model.NFT, error) {
+	res := &model.NFT{}
+	if err := c.do(http.MethodGet, routes.NFT(chainID.String(), id.String()), nil, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// GetNFTs returns NFTs owned by the agent, sorted by ID
+func (c *WaspClient) GetNFTs(chainID *coretypes.ChainID, agentID coretypes.AgentID) ([]*model.NFT, error) {
+	var res []*model.NFT
+	if err := c.do(http.MethodGet, routes.NFTsByOwner(chainID.String(), agentID.Base58()), nil, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
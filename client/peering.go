@@ -0,0 +1,40 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/packages/webapi/routes"
+)
+
+// PeeringTrustedList returns the list of peers trusted by the node.
+func (c *WaspClient) PeeringTrustedList() ([]*model.PeeringTrustedNode, error) {
+	var response []*model.PeeringTrustedNode
+	if err := c.do(http.MethodGet, routes.PeeringTrustedList(), nil, &response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// PeeringTrust makes the node trust the peer with the public key (base64) and NetID.
+func (c *WaspClient) PeeringTrust(pubKey, netID string) (*model.PeeringTrustedNode, error) {
+	request := model.PeeringTrustedNode{PubKey: pubKey, NetID: netID}
+	var response model.PeeringTrustedNode
+	if err := c.do(http.MethodPost, routes.PeeringTrustedList(), &request, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// PeeringDistrust removes the peer with the public key (base64) from the trusted peers of the node.
+func (c *WaspClient) PeeringDistrust(pubKey string) (*model.PeeringTrustedNode, error) {
+	var response model.PeeringTrustedNode
+	if err := c.do(http.MethodDelete, routes.PeeringTrustedDelete(url.PathEscape(pubKey)), nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
@@ -0,0 +1,21 @@
+package client
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/webapi/routes"
+)
+
+// ExportSnapshot streams the binary snapshot of the solid state of the chain from the wasp node to the writer.
+// The snapshot is incomplete if the solid state changed during the export, so it can't be read or imported
+func (c *WaspClient) ExportSnapshot(chainID *coretypes.ChainID, w io.Writer) error {
+	return c.doStream(http.MethodGet, routes.ChainSnapshot(chainID.String()), nil, w)
+}
+
+// ImportSnapshot streams the binary snapshot of the solid state of the chain from the reader to the wasp node.
+// The chain must not be active in the node
+func (c *WaspClient) ImportSnapshot(chainID *coretypes.ChainID, r io.Reader) error {
+	return c.doStream(http.MethodPost, routes.ChainSnapshot(chainID.String()), r, nil)
+}
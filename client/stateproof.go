@@ -0,0 +1,36 @@
+package client
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/http"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/state"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/packages/webapi/routes"
+)
+
+// GetStateProof returns the Merkle proof of inclusion of the key/value pair into the solid state of the chain
+func (c *WaspClient) GetStateProof(chainID *coretypes.ChainID, key kv.Key) (*model.StateProof, error) {
+	res := &model.StateProof{}
+	if err := c.do(http.MethodGet, routes.StateProof(chainID.String(), hex.EncodeToString([]byte(key))), nil, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// VerifyStateProof checks the proof against the state hash, taken from the anchoring state transaction.
+// The node which provided the proof does not have to be trusted
+func VerifyStateProof(stateHash hashing.HashValue, proof *model.StateProof) error {
+	merkleRoot := proof.MerkleRoot.HashValue()
+	if state.StateHashFromMerkleRoot(proof.ChainedHash.HashValue(), merkleRoot) != stateHash {
+		return fmt.Errorf("state hash mismatch")
+	}
+	if !state.VerifyProof(merkleRoot, kv.Key(proof.Key.Bytes()), proof.Value.Bytes(), proof.MerkleProof()) {
+		return fmt.Errorf("invalid proof of inclusion")
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package client
+
+import (
+	"net/http"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/packages/webapi/routes"
+)
+
+// GetNativeTokens returns all chain-native tokens of the chain, sorted by symbol
+func (c *WaspClient) GetNativeTokens(chainID *coretypes.ChainID) ([]*model.NativeToken, error) {
+	var res []*model.NativeToken
+	if err := c.do(http.MethodGet, routes.NativeTokens(chainID.String()), nil, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// GetTokenHoldings returns non-zero balances of chain-native tokens in the on-chain account of the agent
+func (c *WaspClient) GetTokenHoldings(chainID *coretypes.ChainID, agentID coretypes.AgentID) ([]*model.TokenHolding, error) {
+	var res []*model.TokenHolding
+	if err := c.do(http.MethodGet, routes.TokenHoldings(chainID.String(), agentID.Base58()), nil, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
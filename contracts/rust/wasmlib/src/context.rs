@@ -233,6 +233,13 @@ pub trait ScBaseContext {
         ROOT.get_map(&KEY_RESULTS)
     }
 
+    // read-only access to the public state of another smart contract on the chain
+    // panics if the contract does not declare the public state prefix
+    fn state_of(&self, contract: ScHname) -> ScImmutableMap {
+        ROOT.get_bytes(&KEY_STATE_OF).set_value(&contract.to_bytes());
+        ROOT.get_map(&KEY_RETURN).immutable()
+    }
+
     // deterministic time stamp fixed at the moment of calling the smart contract
     fn timestamp(&self) -> i64 {
         ROOT.get_int(&KEY_TIMESTAMP).value()
@@ -336,6 +343,23 @@ impl ScFuncContext {
         ROOT.get_map(&KEY_STATE)
     }
 
+    // signals a structured event with an optional payload
+    // the event is stored in the event log and can be queried by any of its topics
+    pub fn typed_event(&self, name: &str, payload: Option<ScMutableMap>, topics: &[&[u8]]) {
+        let mut encode = BytesEncoder::new();
+        encode.string(name);
+        if let Some(payload) = payload {
+            encode.int(payload.obj_id as i64);
+        } else {
+            encode.int(0);
+        }
+        encode.int(topics.len() as i64);
+        for topic in topics {
+            encode.bytes(topic);
+        }
+        ROOT.get_bytes(&KEY_TYPED_EVENT).set_value(&encode.data());
+    }
+
     // transfers the specified tokens to the specified Tangle ledger address
     pub fn transfer_to_address<T: Balances + ?Sized>(&self, address: &ScAddress, transfer: &T) {
         let transfers = ROOT.get_map_array(&KEY_TRANSFERS);
@@ -23,13 +23,17 @@ pub const CORE_BLOB_PARAM_FIELD: &str = "field";
 pub const CORE_BLOB_PARAM_HASH: &str = "hash";
 
 pub const CORE_EVENTLOG: ScHname = ScHname(0x661aa7d8);
+pub const CORE_EVENTLOG_VIEW_GET_EVENTS: ScHname = ScHname(0xa0603cc7);
+pub const CORE_EVENTLOG_VIEW_GET_EVENTS_BY_TOPIC: ScHname = ScHname(0xaa098cf2);
 pub const CORE_EVENTLOG_VIEW_GET_NUM_RECORDS: ScHname = ScHname(0x2f4b4a8c);
 pub const CORE_EVENTLOG_VIEW_GET_RECORDS: ScHname = ScHname(0xd01a8085);
 
 pub const CORE_EVENTLOG_PARAM_CONTRACT_HNAME: &str = "contractHname";
+pub const CORE_EVENTLOG_PARAM_EVENT_NAME: &str = "eventName";
 pub const CORE_EVENTLOG_PARAM_FROM_TS: &str = "fromTs";
 pub const CORE_EVENTLOG_PARAM_MAX_LAST_RECORDS: &str = "maxLastRecords";
 pub const CORE_EVENTLOG_PARAM_TO_TS: &str = "toTs";
+pub const CORE_EVENTLOG_PARAM_TOPIC: &str = "topic";
 
 pub const CORE_ROOT: ScHname = ScHname(0xcebf5908);
 pub const CORE_ROOT_FUNC_CLAIM_CHAIN_OWNERSHIP: ScHname = ScHname(0x03ff0fc0);
@@ -51,3 +55,83 @@ pub const CORE_ROOT_PARAM_NAME: &str = "$$name$$";
 pub const CORE_ROOT_PARAM_OWNER_FEE: &str = "$$ownerfee$$";
 pub const CORE_ROOT_PARAM_PROGRAM_HASH: &str = "$$proghash$$";
 pub const CORE_ROOT_PARAM_VALIDATOR_FEE: &str = "$$validatorfee$$";
+
+pub const CORE_SCHEDULER: ScHname = ScHname(0x9c305966);
+pub const CORE_SCHEDULER_FUNC_ADD_JOB: ScHname = ScHname(0x39a94021);
+pub const CORE_SCHEDULER_FUNC_REMOVE_JOB: ScHname = ScHname(0x373b3647);
+pub const CORE_SCHEDULER_VIEW_GET_JOB: ScHname = ScHname(0x6a9ecbe5);
+pub const CORE_SCHEDULER_VIEW_GET_JOBS: ScHname = ScHname(0xde70686f);
+
+pub const CORE_SCHEDULER_PARAM_ARGS: &str = "args";
+pub const CORE_SCHEDULER_PARAM_CONTRACT_HNAME: &str = "contractHname";
+pub const CORE_SCHEDULER_PARAM_CRON: &str = "cron";
+pub const CORE_SCHEDULER_PARAM_ENTRY_POINT: &str = "entryPoint";
+pub const CORE_SCHEDULER_PARAM_GAS_BUDGET: &str = "gasBudget";
+pub const CORE_SCHEDULER_PARAM_INTERVAL: &str = "interval";
+pub const CORE_SCHEDULER_PARAM_JOB: &str = "job";
+pub const CORE_SCHEDULER_PARAM_JOB_ID: &str = "jobID";
+pub const CORE_SCHEDULER_PARAM_JOBS: &str = "jobs";
+pub const CORE_SCHEDULER_PARAM_MAX_RUNS: &str = "maxRuns";
+pub const CORE_SCHEDULER_PARAM_START: &str = "start";
+
+pub const CORE_TOKENS: ScHname = ScHname(0xbab8b5a8);
+pub const CORE_TOKENS_FUNC_APPROVE: ScHname = ScHname(0xa0661268);
+pub const CORE_TOKENS_FUNC_BURN: ScHname = ScHname(0x7bc1efb1);
+pub const CORE_TOKENS_FUNC_CREATE_TOKEN: ScHname = ScHname(0xacfcb639);
+pub const CORE_TOKENS_FUNC_MINT: ScHname = ScHname(0xa29addcf);
+pub const CORE_TOKENS_FUNC_TRANSFER: ScHname = ScHname(0xa15da184);
+pub const CORE_TOKENS_FUNC_TRANSFER_FROM: ScHname = ScHname(0xd5e0a602);
+pub const CORE_TOKENS_VIEW_GET_ALLOWANCE: ScHname = ScHname(0x329aa88f);
+pub const CORE_TOKENS_VIEW_GET_HOLDINGS: ScHname = ScHname(0x8d5df2f2);
+pub const CORE_TOKENS_VIEW_GET_TOKEN: ScHname = ScHname(0x855b94ba);
+pub const CORE_TOKENS_VIEW_GET_TOKENS: ScHname = ScHname(0x414150d3);
+
+pub const CORE_TOKENS_PARAM_AGENT_ID: &str = "agentID";
+pub const CORE_TOKENS_PARAM_AMOUNT: &str = "amount";
+pub const CORE_TOKENS_PARAM_COLOR: &str = "color";
+pub const CORE_TOKENS_PARAM_DECIMALS: &str = "decimals";
+pub const CORE_TOKENS_PARAM_NAME: &str = "name";
+pub const CORE_TOKENS_PARAM_SPENDER: &str = "spender";
+pub const CORE_TOKENS_PARAM_SUPPLY: &str = "supply";
+pub const CORE_TOKENS_PARAM_SYMBOL: &str = "symbol";
+pub const CORE_TOKENS_PARAM_TARGET: &str = "target";
+pub const CORE_TOKENS_PARAM_TOKEN: &str = "token";
+pub const CORE_TOKENS_PARAM_TOKENS: &str = "tokens";
+
+pub const CORE_NFT: ScHname = ScHname(0xdfc7488e);
+pub const CORE_NFT_FUNC_APPROVE: ScHname = ScHname(0xa0661268);
+pub const CORE_NFT_FUNC_BURN: ScHname = ScHname(0x7bc1efb1);
+pub const CORE_NFT_FUNC_MINT: ScHname = ScHname(0xa29addcf);
+pub const CORE_NFT_FUNC_TRANSFER: ScHname = ScHname(0xa15da184);
+pub const CORE_NFT_VIEW_GET_APPROVED: ScHname = ScHname(0xbe34b6ba);
+pub const CORE_NFT_VIEW_GET_NFT: ScHname = ScHname(0x50f1f2c8);
+pub const CORE_NFT_VIEW_GET_NFTS: ScHname = ScHname(0x4684c634);
+
+pub const CORE_NFT_PARAM_AGENT_ID: &str = "agentID";
+pub const CORE_NFT_PARAM_ID: &str = "id";
+pub const CORE_NFT_PARAM_METADATA: &str = "metadata";
+pub const CORE_NFT_PARAM_NFT: &str = "nft";
+pub const CORE_NFT_PARAM_NFTS: &str = "nfts";
+pub const CORE_NFT_PARAM_SPENDER: &str = "spender";
+pub const CORE_NFT_PARAM_TARGET: &str = "target";
+
+pub const CORE_GOVERNANCE: ScHname = ScHname(0x17cf909f);
+pub const CORE_GOVERNANCE_FUNC_ENABLE: ScHname = ScHname(0xbb145b40);
+pub const CORE_GOVERNANCE_FUNC_EXECUTE: ScHname = ScHname(0x94c80ed0);
+pub const CORE_GOVERNANCE_FUNC_PROPOSE: ScHname = ScHname(0xb5b514cb);
+pub const CORE_GOVERNANCE_FUNC_SET_MEMBERS: ScHname = ScHname(0xc40b3ae6);
+pub const CORE_GOVERNANCE_FUNC_VOTE: ScHname = ScHname(0x60e23b08);
+pub const CORE_GOVERNANCE_VIEW_GET_MEMBERS: ScHname = ScHname(0x5d2c65f8);
+pub const CORE_GOVERNANCE_VIEW_GET_PROPOSAL: ScHname = ScHname(0xd828d59f);
+pub const CORE_GOVERNANCE_VIEW_GET_PROPOSALS: ScHname = ScHname(0xe3869158);
+
+pub const CORE_GOVERNANCE_PARAM_ARGS: &str = "args";
+pub const CORE_GOVERNANCE_PARAM_CONTRACT_HNAME: &str = "contractHname";
+pub const CORE_GOVERNANCE_PARAM_DESCRIPTION: &str = "description";
+pub const CORE_GOVERNANCE_PARAM_ENTRY_POINT: &str = "entryPoint";
+pub const CORE_GOVERNANCE_PARAM_EXPIRY: &str = "expiry";
+pub const CORE_GOVERNANCE_PARAM_MEMBERS: &str = "members";
+pub const CORE_GOVERNANCE_PARAM_PROPOSAL: &str = "proposal";
+pub const CORE_GOVERNANCE_PARAM_PROPOSAL_ID: &str = "proposalID";
+pub const CORE_GOVERNANCE_PARAM_PROPOSALS: &str = "proposals";
+pub const CORE_GOVERNANCE_PARAM_QUORUM: &str = "quorum";
@@ -64,6 +64,12 @@ impl ScExports {
         }
     }
 
+    // exposes the state keys which start with the prefix
+    // to other smart contracts on the chain
+    pub fn set_public_state_prefix(&self, prefix: &str) {
+        ROOT.get_string(&KEY_PUBLIC_STATE_PREFIX).set_value(prefix);
+    }
+
     // defines the external name of an immutable view function
     // and the entry point function associated with that name
     pub fn add_view(&self, name: &str, f: fn(&ScViewContext)) {
@@ -68,4 +68,9 @@ pub const KEY_VALID            : Key32 = Key32(-34);
 pub const KEY_VALID_BLS        : Key32 = Key32(-35);
 pub const KEY_VALID_ED25519    : Key32 = Key32(-36);
 pub const KEY_ZZZZZZZ          : Key32 = Key32(-37);
+
+// keys added after the version key
+pub const KEY_TYPED_EVENT      : Key32 = Key32(-38);
+pub const KEY_STATE_OF         : Key32 = Key32(-39);
+pub const KEY_PUBLIC_STATE_PREFIX: Key32 = Key32(-40);
 // @formatter:on
@@ -4,6 +4,8 @@ go 1.15
 
 require (
 	github.com/bytecodealliance/wasmtime-go v0.21.0
+	github.com/cockroachdb/pebble v0.0.0-20201130172119-f19faf8529d6
+	github.com/eclipse/paho.mqtt.golang v1.3.2
 	github.com/iotaledger/goshimmer v0.3.7-0.20210214081859-29e3f77b4364
 	github.com/iotaledger/hive.go v0.0.0-20210209113323-87572778f0d9
 	github.com/knadh/koanf v0.14.0
@@ -11,11 +13,13 @@ require (
 	github.com/mr-tron/base58 v1.2.0
 	github.com/pangpanglabs/echoswagger/v2 v2.1.0
 	github.com/pkg/errors v0.9.1
+	github.com/prometheus/client_golang v1.7.0
 	github.com/prometheus/common v0.10.0
 	github.com/spf13/pflag v1.0.5
 	github.com/spf13/viper v1.7.0
 	github.com/stretchr/testify v1.6.1
 	go.dedis.ch/kyber/v3 v3.0.13
+	go.etcd.io/bbolt v1.3.5
 	go.nanomsg.org/mangos/v3 v3.0.1
 	go.uber.org/atomic v1.7.0
 	go.uber.org/zap v1.16.0
@@ -15,6 +15,8 @@ import (
 	"github.com/iotaledger/wasp/plugins/globals"
 	"github.com/iotaledger/wasp/plugins/gracefulshutdown"
 	"github.com/iotaledger/wasp/plugins/logger"
+	"github.com/iotaledger/wasp/plugins/metrics"
+	"github.com/iotaledger/wasp/plugins/mqtt"
 	"github.com/iotaledger/wasp/plugins/nodeconn"
 	"github.com/iotaledger/wasp/plugins/peering"
 	"github.com/iotaledger/wasp/plugins/publisher"
@@ -49,6 +51,8 @@ func main() {
 		dashboard.Init(),
 		wasmtimevm.Init(),
 		globals.Init(),
+		metrics.Init(),
+		mqtt.Init(),
 	)
 
 	testPlugins := node.Plugins(
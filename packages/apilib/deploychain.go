@@ -32,6 +32,10 @@ type CreateChainParams struct {
 	Description           string
 	Textout               io.Writer
 	Prefix                string
+	// AccessApiHosts and AccessPeeringHosts are the nodes which follow the chain without
+	// participating in the consensus. Optional
+	AccessApiHosts     []string
+	AccessPeeringHosts []string
 }
 
 // DeployChain performs all actions needed to deploy the chain
@@ -111,11 +115,13 @@ func DeployChain(par CreateChainParams) (*coretypes.ChainID, *address.Address, *
 
 	chainColor := balance.Color(originTx.ID())
 	committee := multiclient.New(par.CommitteeApiHosts)
+	allNodes := multiclient.New(append(append([]string{}, par.CommitteeApiHosts...), par.AccessApiHosts...))
 	// ------------ put chain records to hosts
-	err = committee.PutChainRecord(&registry.ChainRecord{
+	err = allNodes.PutChainRecord(&registry.ChainRecord{
 		ChainID:        chainID,
 		Color:          chainColor,
 		CommitteeNodes: par.CommitteePeeringHosts,
+		AccessNodes:    par.AccessPeeringHosts,
 	})
 
 	fmt.Fprint(textout, par.Prefix)
@@ -126,7 +132,7 @@ func DeployChain(par CreateChainParams) (*coretypes.ChainID, *address.Address, *
 	fmt.Fprint(textout, "sending smart contract metadata to Wasp nodes.. OK.\n")
 
 	// ------------- activate chain
-	err = committee.ActivateChain(chainID)
+	err = allNodes.ActivateChain(chainID)
 
 	fmt.Fprint(textout, par.Prefix)
 	if err != nil {
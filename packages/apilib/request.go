@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"github.com/iotaledger/wasp/packages/coretypes/requestargs"
 
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address/signaturescheme"
 	"github.com/iotaledger/wasp/client/level1"
 	"github.com/iotaledger/wasp/packages/coretypes"
@@ -18,8 +19,11 @@ type RequestSectionParams struct {
 	TargetContractID coretypes.ContractID
 	EntryPointCode   coretypes.Hname
 	TimeLock         uint32
+	GasBudget        uint64                    // 0 means default gas budget
 	Transfer         coretypes.ColoredBalances // should not not include request token. It is added automatically
 	Args             requestargs.RequestArgs
+	// TargetAddress is the address of the current committee of the target chain. If nil, it is the chain ID
+	TargetAddress *address.Address
 }
 
 type CreateRequestTransactionParams struct {
@@ -45,10 +49,14 @@ func CreateRequestTransaction(par CreateRequestTransactionParams) (*sctransactio
 	for _, sectPar := range par.RequestSectionParams {
 		reqSect := sctransaction.NewRequestSectionByWallet(sectPar.TargetContractID, sectPar.EntryPointCode).
 			WithTimelock(sectPar.TimeLock).
+			WithGasBudget(sectPar.GasBudget).
 			WithTransfer(sectPar.Transfer)
 
 		reqSect.WithArgs(sectPar.Args)
 
+		if sectPar.TargetAddress != nil {
+			txb.SetChainAddress(sectPar.TargetContractID.ChainID(), *sectPar.TargetAddress)
+		}
 		err = txb.AddRequestSection(reqSect)
 		if err != nil {
 			return nil, err
@@ -10,6 +10,7 @@ import (
 	"github.com/iotaledger/hive.go/events"
 	"github.com/iotaledger/hive.go/logger"
 	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/hashing"
 	"github.com/iotaledger/wasp/packages/peering"
 	"github.com/iotaledger/wasp/packages/registry"
 	"github.com/iotaledger/wasp/packages/tcrypto"
@@ -32,14 +33,22 @@ type Chain interface {
 	HasQuorum() bool
 	PeerStatus() []*PeerStatus
 	BlobCache() coretypes.BlobCache
+	// RequestBlobs asks committee peers for blobs missing in the blob cache
+	RequestBlobs(hashes []hashing.HashValue)
 	//
 	SetReadyStateManager()
 	SetReadyConsensus()
 	Dismiss()
 	IsDismissed() bool
+	// HandOver dismisses the chain after the chain token has been moved to the address of another committee
+	// and notifies the owner of the chain object, so the new committee can be activated
+	HandOver(newAddress address.Address)
 	// requests
 	GetRequestProcessingStatus(*coretypes.RequestID) RequestProcessingStatus
 	EventRequestProcessed() *events.Event
+	// EventBlockCommitted is triggered with the hash of the new solid state and the block after the block is committed to the DB.
+	// It is triggered asynchronously, in the order of blocks, so handlers don't block the state manager
+	EventBlockCommitted() *events.Event
 	// chain processors
 	Processors() *processors.ProcessorCache
 }
@@ -61,12 +70,31 @@ const (
 	RequestProcessingStatusUnknown = RequestProcessingStatus(iota)
 	RequestProcessingStatusBacklog
 	RequestProcessingStatusCompleted
+	// request arguments are not solid yet: referenced off-tangle data is being received from peers
+	RequestProcessingStatusSolidifyingArgs
+	// referenced off-tangle data was not received in time. The request will be processed as failed and refunded
+	RequestProcessingStatusArgsTimeout
 )
 
+func (s RequestProcessingStatus) String() string {
+	switch s {
+	case RequestProcessingStatusBacklog:
+		return "backlog"
+	case RequestProcessingStatusCompleted:
+		return "completed"
+	case RequestProcessingStatusSolidifyingArgs:
+		return "solidifying_args"
+	case RequestProcessingStatusArgsTimeout:
+		return "args_timeout"
+	}
+	return "unknown"
+}
+
 type StateManager interface {
 	EvidenceStateIndex(idx uint32)
 	EventStateIndexPingPongMsg(msg *StateIndexPingPongMsg)
 	EventGetBlockMsg(msg *GetBlockMsg)
+	EventBlockNotAvailableMsg(msg *BlockNotAvailableMsg)
 	EventBlockHeaderMsg(msg *BlockHeaderMsg)
 	EventStateUpdateMsg(msg *StateUpdateMsg)
 	EventStateTransactionMsg(msg *StateTransactionMsg)
@@ -89,6 +117,7 @@ type Operator interface {
 	Close()
 	//
 	IsRequestInBacklog(*coretypes.RequestID) bool
+	GetRequestProcessingStatus(*coretypes.RequestID) RequestProcessingStatus
 }
 
 var ConstructorNew func(
@@ -98,6 +127,7 @@ var ConstructorNew func(
 	dksProvider tcrypto.RegistryProvider,
 	blobProvider coretypes.BlobCache,
 	onActivation func(),
+	onHandOver func(newAddress address.Address),
 ) Chain
 
 func New(
@@ -107,6 +137,7 @@ func New(
 	dksProvider tcrypto.RegistryProvider,
 	blobProvider coretypes.BlobCache,
 	onActivation func(),
+	onHandOver func(newAddress address.Address),
 ) Chain {
-	return ConstructorNew(chr, log, netProvider, dksProvider, blobProvider, onActivation)
+	return ConstructorNew(chr, log, netProvider, dksProvider, blobProvider, onActivation, onHandOver)
 }
@@ -0,0 +1,83 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package chainimpl
+
+import (
+	"time"
+
+	"github.com/iotaledger/wasp/packages/chain"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/util"
+)
+
+// RequestBlobs asks committee peers for blobs which are missing in the blob cache of the node.
+// Only blobs requested by the node are accepted from peers, until the request expires
+func (c *chainObj) RequestBlobs(hashes []hashing.HashValue) {
+	if len(hashes) == 0 {
+		return
+	}
+	expires := time.Now().Add(chain.RequestArgsSolidificationTimeout)
+	c.wantedBlobsMutex.Lock()
+	for _, h := range hashes {
+		c.wantedBlobs[h] = expires
+	}
+	c.wantedBlobsMutex.Unlock()
+
+	msg := &chain.GetBlobsMsg{BlobHashes: hashes}
+	c.SendMsgToCommitteePeers(chain.MsgGetBlobs, util.MustBytes(msg), time.Now().UnixNano())
+}
+
+// processGetBlobs responds to the peer with all requested blobs the node has in the blob cache
+func (c *chainObj) processGetBlobs(msg *chain.GetBlobsMsg) {
+	for _, h := range msg.BlobHashes {
+		data, ok, err := c.blobProvider.GetBlob(h)
+		if err != nil {
+			c.log.Errorf("processGetBlobs: %v", err)
+			return
+		}
+		if !ok {
+			continue
+		}
+		resp := &chain.BlobMsg{Data: data}
+		if err := c.SendMsg(msg.SenderIndex, chain.MsgBlob, util.MustBytes(resp)); err != nil {
+			c.log.Errorf("processGetBlobs: %v", err)
+			return
+		}
+	}
+}
+
+// processBlob stores the blob received from the peer if it was requested by the node
+func (c *chainObj) processBlob(msg *chain.BlobMsg) {
+	h := hashing.HashData(msg.Data)
+	c.wantedBlobsMutex.Lock()
+	expires, wanted := c.wantedBlobs[h]
+	delete(c.wantedBlobs, h)
+	c.cleanupWantedBlobs()
+	c.wantedBlobsMutex.Unlock()
+
+	if !wanted || time.Now().After(expires) {
+		return
+	}
+	blobCache, ok := c.blobProvider.(coretypes.BlobCacheFull)
+	if !ok {
+		c.log.Errorf("processBlob: blob cache is read only")
+		return
+	}
+	if _, err := blobCache.PutBlob(msg.Data); err != nil {
+		c.log.Errorf("processBlob: %v", err)
+		return
+	}
+	c.log.Debugf("received blob %s from peer #%d", h.String(), msg.SenderIndex)
+}
+
+// cleanupWantedBlobs removes expired requests for blobs. Must be called under the lock
+func (c *chainObj) cleanupWantedBlobs() {
+	nowis := time.Now()
+	for h, expires := range c.wantedBlobs {
+		if nowis.After(expires) {
+			delete(c.wantedBlobs, h)
+		}
+	}
+}
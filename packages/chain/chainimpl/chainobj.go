@@ -18,8 +18,10 @@ import (
 	"github.com/iotaledger/wasp/packages/chain/consensus"
 	"github.com/iotaledger/wasp/packages/chain/statemgr"
 	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/hashing"
 	"github.com/iotaledger/wasp/packages/peering"
 	"github.com/iotaledger/wasp/packages/registry"
+	"github.com/iotaledger/wasp/packages/state"
 	"github.com/iotaledger/wasp/packages/util"
 	"go.uber.org/atomic"
 )
@@ -34,8 +36,10 @@ type chainObj struct {
 	dismissed                    atomic.Bool
 	dismissOnce                  sync.Once
 	onActivation                 func()
+	onHandOver                   func(newAddress address.Address)
 	//
 	chainID         coretypes.ChainID
+	address         address.Address
 	procset         *processors.ProcessorCache
 	color           balance.Color
 	peers           peering.GroupProvider
@@ -48,11 +52,14 @@ type chainObj struct {
 	isCommitteeNode atomic.Bool
 	//
 	eventRequestProcessed *events.Event
+	eventBlockCommitted   *events.Event
 	log                   *logger.Logger
 	netProvider           peering.NetworkProvider
 	peersAttachRef        interface{}
 	dksProvider           tcrypto.RegistryProvider
 	blobProvider          coretypes.BlobCache
+	wantedBlobs           map[hashing.HashValue]time.Time
+	wantedBlobsMutex      sync.Mutex
 }
 
 func requestIDCaller(handler interface{}, params ...interface{}) {
@@ -66,33 +73,50 @@ func newCommitteeObj(
 	dksProvider tcrypto.RegistryProvider,
 	blobProvider coretypes.BlobCache,
 	onActivation func(),
+	onHandOver func(newAddress address.Address),
 ) chain.Chain {
 	var err error
 	log.Debugw("creating committee", "addr", chr.ChainID.String())
 
-	addr := address.Address(chr.ChainID)
-	if util.ContainsDuplicates(chr.CommitteeNodes) {
+	addr := chr.Address()
+	if util.ContainsDuplicates(chr.Peers()) {
 		log.Errorf("can't create chain object for %s: chain record contains duplicate node addresses. Chain nodes: %+v",
-			addr.String(), chr.CommitteeNodes)
+			addr.String(), chr.Peers())
 		return nil
 	}
-	dkshare, err := dksProvider.LoadDKShare(&addr)
-	if err != nil {
-		log.Error(err)
-		return nil
-	}
-	if dkshare.Index == nil || !iAmInTheCommittee(chr.CommitteeNodes, dkshare.N, *dkshare.Index, netProvider) {
-		log.Errorf(
-			"chain record inconsistency: the own node %s is not in the committee for %s: %+v",
-			netProvider.Self().NetID(), addr.String(), chr.CommitteeNodes,
-		)
-		return nil
+	var dkshare, chainIDDKShare *tcrypto.DKShare
+	ownIndex, isAccessNode := accessNodeIndex(chr, netProvider)
+	if isAccessNode {
+		log.Infof("the own node %s is an access node of %s", netProvider.Self().NetID(), addr.String())
+	} else {
+		dkshare, err = dksProvider.LoadDKShare(&addr)
+		if err != nil {
+			log.Error(err)
+			return nil
+		}
+		if dkshare.Index == nil || !iAmInTheCommittee(chr.CommitteeNodes, dkshare.N, *dkshare.Index, netProvider) {
+			log.Errorf(
+				"chain record inconsistency: the own node %s is not in the committee for %s: %+v",
+				netProvider.Self().NetID(), addr.String(), chr.CommitteeNodes,
+			)
+			return nil
+		}
+		ownIndex = *dkshare.Index
+
+		if chainIDAddr := address.Address(chr.ChainID); addr != chainIDAddr {
+			// after the committee rotation, members of the original committee sweep requests sent to the chain ID address
+			if chainIDDKShare, err = dksProvider.LoadDKShare(&chainIDAddr); err != nil {
+				log.Infof("the own node %s doesn't hold a key share of the chain ID address %s",
+					netProvider.Self().NetID(), chainIDAddr.String())
+				chainIDDKShare = nil
+			}
+		}
 	}
 	var peers peering.GroupProvider
-	if peers, err = netProvider.Group(chr.CommitteeNodes); err != nil {
+	if peers, err = netProvider.Group(chr.Peers()); err != nil {
 		log.Errorf(
 			"node %s failed to setup committee communication with %+v, reason=%+v",
-			netProvider.Self().NetID(), chr.CommitteeNodes, err,
+			netProvider.Self().NetID(), chr.Peers(), err,
 		)
 		return nil
 	}
@@ -101,28 +125,44 @@ func newCommitteeObj(
 		procset:      processors.MustNew(),
 		chMsg:        make(chan interface{}, 100),
 		chainID:      chr.ChainID,
+		address:      addr,
 		color:        chr.Color,
 		peers:        peers,
 		onActivation: onActivation,
+		onHandOver:   onHandOver,
 		eventRequestProcessed: events.NewEvent(func(handler interface{}, params ...interface{}) {
 			handler.(func(_ coretypes.RequestID))(params[0].(coretypes.RequestID))
 		}),
+		eventBlockCommitted: events.NewEvent(func(handler interface{}, params ...interface{}) {
+			handler.(func(_ hashing.HashValue, _ state.Block))(params[0].(hashing.HashValue), params[1].(state.Block))
+		}),
 		log:          chainLog,
 		netProvider:  netProvider,
 		dksProvider:  dksProvider,
 		blobProvider: blobProvider,
+		wantedBlobs:  make(map[hashing.HashValue]time.Time),
 	}
 	ret.peersAttachRef = peers.Attach(&ret.chainID, func(recv *peering.RecvEvent) {
 		ret.ReceiveMessage(recv.Msg)
 	})
 
-	ret.ownIndex = *dkshare.Index
-	ret.size = dkshare.N
-	ret.quorum = dkshare.T
+	ret.ownIndex = ownIndex
+	ret.size = uint16(len(chr.CommitteeNodes))
+	if isAccessNode {
+		// the access node only needs one committee peer to sync the state from
+		ret.quorum = 1
+	} else {
+		ret.quorum = dkshare.T
+	}
 
 	ret.stateMgr = statemgr.New(ret, ret.log)
-	ret.operator = consensus.NewOperator(ret, dkshare, ret.log)
-	ret.isCommitteeNode.Store(true)
+	if isAccessNode {
+		// the access node never participates in the consensus
+		ret.isReadyConsensus = true
+	} else {
+		ret.operator = consensus.NewOperator(ret, dkshare, chainIDDKShare, ret.log)
+	}
+	ret.isCommitteeNode.Store(!isAccessNode)
 	go func() {
 		for msg := range ret.chMsg {
 			ret.dispatchMessage(msg)
@@ -147,6 +187,17 @@ func newCommitteeObj(
 	return ret
 }
 
+// accessNodeIndex returns the peer index of the own node if it is an access node of the chain.
+// Access nodes are indexed after the committee nodes
+func accessNodeIndex(chr *registry.ChainRecord, netProvider peering.NetworkProvider) (uint16, bool) {
+	for i, netID := range chr.AccessNodes {
+		if netID == netProvider.Self().NetID() {
+			return uint16(len(chr.CommitteeNodes) + i), true
+		}
+	}
+	return 0, false
+}
+
 // iAmInTheCommittee checks if NetIDs makes sense
 func iAmInTheCommittee(committeeNodes []string, n, index uint16, netProvider peering.NetworkProvider) bool {
 	if len(committeeNodes) != int(n) {
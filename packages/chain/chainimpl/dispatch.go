@@ -5,9 +5,11 @@ package chainimpl
 
 import (
 	"bytes"
+	"strconv"
 
 	"github.com/iotaledger/wasp/packages/chain"
 	"github.com/iotaledger/wasp/packages/peering"
+	"github.com/iotaledger/wasp/packages/publisher"
 )
 
 func (c *chainObj) dispatchMessage(msg interface{}) {
@@ -74,6 +76,11 @@ func (c *chainObj) dispatchMessage(msg interface{}) {
 }
 
 func (c *chainObj) processPeerMessage(msg *peering.PeerMessage) {
+	if msg.SenderIndex >= c.size && isConsensusMsgType(msg.MsgType) {
+		c.log.Warnf("processPeerMessage: consensus message type %d from access peer #%d ignored", msg.MsgType, msg.SenderIndex)
+		publisher.Publish("ignored_consensus_msg", c.chainID.String(), strconv.Itoa(int(msg.SenderIndex)))
+		return
+	}
 
 	rdr := bytes.NewReader(msg.MsgData)
 
@@ -159,6 +166,17 @@ func (c *chainObj) processPeerMessage(msg *peering.PeerMessage) {
 
 		c.stateMgr.EventGetBlockMsg(msgt)
 
+	case chain.MsgBlockNotAvailable:
+		msgt := &chain.BlockNotAvailableMsg{}
+		if err := msgt.Read(rdr); err != nil {
+			c.log.Error(err)
+			return
+		}
+
+		msgt.SenderIndex = msg.SenderIndex
+
+		c.stateMgr.EventBlockNotAvailableMsg(msgt)
+
 	case chain.MsgBatchHeader:
 		msgt := &chain.BlockHeaderMsg{}
 		if err := msgt.Read(rdr); err != nil {
@@ -191,7 +209,36 @@ func (c *chainObj) processPeerMessage(msg *peering.PeerMessage) {
 		msgt.SenderIndex = msg.SenderIndex
 		c.testTrace(msgt)
 
+	case chain.MsgGetBlobs:
+		msgt := &chain.GetBlobsMsg{}
+		if err := msgt.Read(rdr); err != nil {
+			c.log.Error(err)
+			return
+		}
+
+		msgt.SenderIndex = msg.SenderIndex
+		c.processGetBlobs(msgt)
+
+	case chain.MsgBlob:
+		msgt := &chain.BlobMsg{}
+		if err := msgt.Read(rdr); err != nil {
+			c.log.Error(err)
+			return
+		}
+
+		msgt.SenderIndex = msg.SenderIndex
+		c.processBlob(msgt)
+
 	default:
 		c.log.Errorf("processPeerMessage: wrong msg type")
 	}
 }
+
+// isConsensusMsgType returns true for messages exchanged only between committee peers
+func isConsensusMsgType(msgType byte) bool {
+	switch msgType {
+	case chain.MsgNotifyRequests, chain.MsgNotifyFinalResultPosted, chain.MsgStartProcessingRequest, chain.MsgSignedHash:
+		return true
+	}
+	return false
+}
@@ -129,12 +129,21 @@ func (c *chainObj) Dismiss() {
 		c.peers.Close()
 
 		c.stateMgr.Close()
-		c.operator.Close()
+		if c.operator != nil {
+			c.operator.Close()
+		}
 	})
 
 	publisher.Publish("dismissed_committee", c.chainID.String())
 }
 
+func (c *chainObj) HandOver(newAddress address.Address) {
+	c.Dismiss()
+	if c.onHandOver != nil {
+		c.onHandOver(newAddress)
+	}
+}
+
 func (c *chainObj) IsDismissed() bool {
 	return c.dismissed.Load()
 }
@@ -148,7 +157,7 @@ func (c *chainObj) Color() *balance.Color {
 }
 
 func (c *chainObj) Address() address.Address {
-	return address.Address(c.chainID)
+	return c.address
 }
 
 func (c *chainObj) Size() uint16 {
@@ -196,8 +205,15 @@ func (c *chainObj) SendMsgToCommitteePeers(msgType byte, msgData []byte, ts int6
 		MsgType:     msgType,
 		MsgData:     msgData,
 	}
-	c.peers.Broadcast(msg, false)
-	return uint16(len(c.peers.OtherNodes())) // TODO: [KP] Reconsider this, we cannot guaranty if they are actually sent.
+	numSent := uint16(0)
+	for i, peer := range c.peers.OtherNodes() {
+		if i < c.size {
+			// access peers don't participate in the consensus
+			peer.SendMsg(msg)
+			numSent++
+		}
+	}
+	return numSent // TODO: [KP] Reconsider this, we cannot guaranty if they are actually sent.
 }
 
 // sends message to the peer seq[seqIndex]. If receives error, seqIndex = (seqIndex+1) % size and repeats
@@ -246,7 +262,13 @@ func (c *chainObj) NumPeers() uint16 {
 
 // first N peers are committee peers, the rest are access peers in any
 func (c *chainObj) committeePeers() map[uint16]peering.PeerSender {
-	return c.peers.AllNodes()
+	ret := make(map[uint16]peering.PeerSender)
+	for i, peer := range c.peers.AllNodes() {
+		if i < c.size {
+			ret[i] = peer
+		}
+	}
+	return ret
 }
 
 func (c *chainObj) HasQuorum() bool {
@@ -297,8 +319,8 @@ func (c *chainObj) GetRequestProcessingStatus(reqID *coretypes.RequestID) chain.
 		if c.IsDismissed() {
 			return chain.RequestProcessingStatusUnknown
 		}
-		if c.operator.IsRequestInBacklog(reqID) {
-			return chain.RequestProcessingStatusBacklog
+		if status := c.operator.GetRequestProcessingStatus(reqID); status != chain.RequestProcessingStatusUnknown {
+			return status
 		}
 	}
 	processed, err := state.IsRequestCompleted(c.ID(), reqID)
@@ -315,3 +337,7 @@ func (c *chainObj) Processors() *processors.ProcessorCache {
 func (c *chainObj) EventRequestProcessed() *events.Event {
 	return c.eventRequestProcessed
 }
+
+func (c *chainObj) EventBlockCommitted() *events.Event {
+	return c.eventBlockCommitted
+}
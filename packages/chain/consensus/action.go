@@ -7,10 +7,12 @@ import (
 	"time"
 
 	"github.com/iotaledger/wasp/packages/chain"
+	"github.com/iotaledger/wasp/packages/hashing"
 	"github.com/iotaledger/wasp/packages/sctransaction"
 	"github.com/iotaledger/wasp/packages/state"
 	"github.com/iotaledger/wasp/packages/util"
 	"github.com/iotaledger/wasp/packages/vm"
+	"github.com/iotaledger/wasp/packages/vm/core/scheduler"
 	"github.com/iotaledger/wasp/plugins/nodeconn"
 )
 
@@ -27,6 +29,7 @@ func (op *operator) takeAction() {
 }
 
 // solidifyRequestArgsIfNeeded runs through all requests and, if needed, attempts to solidify args
+// Blobs referenced by the arguments are requested from committee peers until the request is processed
 func (op *operator) solidifyRequestArgsIfNeeded() {
 	if time.Now().Before(op.nextArgSolidificationDeadline) {
 		return
@@ -35,20 +38,53 @@ func (op *operator) solidifyRequestArgsIfNeeded() {
 	reqs = filterRequests(reqs, func(r *request) bool {
 		return r.hasMessage() && !r.hasSolidArgs()
 	})
+	missing := make([]hashing.HashValue, 0)
 	for _, req := range reqs {
-		ok, err := req.reqTx.Requests()[req.reqId.Index()].SolidifyArgs(op.chain.BlobCache())
+		ok, err := op.solidifyArgs(req.reqTx.Requests()[req.reqId.Index()])
 		if err != nil {
 			req.log.Errorf("failed to solidify request arguments: %v", err)
-		} else {
-			req.argsSolid = ok
-			if ok {
-				req.log.Infof("solidified request arguments")
-			}
+			continue
+		}
+		req.argsSolid = ok
+		if ok {
+			req.log.Infof("solidified request arguments")
+			op.setRequestStatusConcurrent(&req.reqId, chain.RequestProcessingStatusBacklog)
+			continue
 		}
+		if !req.argsTimeout && req.isArgsTimeout(time.Now()) {
+			req.argsTimeout = true
+			req.log.Warnf("request arguments were not solidified in %v. The request will be processed as failed",
+				chain.RequestArgsSolidificationTimeout)
+			op.setRequestStatusConcurrent(&req.reqId, chain.RequestProcessingStatusArgsTimeout)
+		}
+		missing = append(missing, op.missingBlobs(req)...)
 	}
+	op.chain.RequestBlobs(missing)
 	op.nextArgSolidificationDeadline = time.Now().Add(chain.CheckArgSolidificationEvery)
 }
 
+// solidifyArgs decrypts arguments of the "stealth" request with the key share of the node
+// and resolves blob references.
+// Arguments which can't be decrypted by any committee member are solid: the request is rejected by the VM.
+// If only the node can't decrypt the arguments, they are not solid and the request is processed by other nodes
+func (op *operator) solidifyArgs(reqSection *sctransaction.RequestSection) (bool, error) {
+	if err := reqSection.DecryptArgs(op.dkshare.DecryptStealth); err != nil {
+		// the symmetric key is not available to the node
+		return false, nil
+	}
+	return reqSection.SolidifyArgs(op.chain.BlobCache())
+}
+
+// missingBlobs returns hashes of the off-tangle data referenced by the request which is not in the blob cache
+func (op *operator) missingBlobs(req *request) []hashing.HashValue {
+	ret, err := req.reqTx.Requests()[req.reqId.Index()].MissingBlobs(op.chain.BlobCache())
+	if err != nil {
+		req.log.Errorf("failed to find missing blobs: %v", err)
+		return nil
+	}
+	return ret
+}
+
 // pullInclusionLevel if it is known that result transaction was posted by the leader,
 // some updates from Goshimmer are expected about the status (inclusion level) of the transaction
 // If the update about the tx state didn't come as expected (timeout), send the query about it
@@ -105,17 +141,29 @@ func (op *operator) startCalculationsAsLeader() {
 		// no quorum, doesn't make sense to start
 		return
 	}
+	// determine timestamp. Must be max(local clock, prev timestamp+1).
+	// Adjustment enforced, when needed
+	ts := time.Now().UnixNano()
+	prevTs := op.stateTx.MustState().Timestamp()
+	if ts <= prevTs {
+		op.log.Warnf("local clock is not ahead the timestamp of the previous state. prevTs: %d, currentTs: %d, diff: %d ns",
+			prevTs, ts, prevTs-ts)
+		ts = prevTs + 1
+		op.log.Info("timestamp was adjusted to %d", ts)
+	}
 	// select requests for the batch
 	reqs := op.selectRequestsToProcess()
-	if len(reqs) == 0 {
-		// empty backlog or nothing is ready
+	if len(reqs) == 0 && !op.hasDueJobs(ts) {
+		// empty backlog or nothing is ready, and no scheduled jobs are due
 		return
 	}
 	reqIds := takeIds(reqs)
+	argsTimeout := takeArgsTimeouts(reqs)
 	reqIdsStr := idsShortStr(reqIds)
 
 	op.log.Debugf("requests selected to process. Current state: %d, Reqs: %+v", op.mustStateIndex(), reqIdsStr)
 	rewardAddress := op.getFeeDestination()
+	chainIDBalances := op.chainIDBalancesToSweep()
 
 	// send to subordinated peers requests to process the batch
 	msgData := util.MustBytes(&chain.StartProcessingBatchMsg{
@@ -123,22 +171,13 @@ func (op *operator) startCalculationsAsLeader() {
 			// timestamp is set by SendMsgToCommitteePeers
 			BlockIndex: op.stateTx.MustState().BlockIndex(),
 		},
-		FeeDestination: rewardAddress,
-		Balances:       op.balances,
-		RequestIds:     reqIds,
+		FeeDestination:  rewardAddress,
+		Balances:        op.balances,
+		ChainIDBalances: chainIDBalances,
+		RequestIds:      reqIds,
+		ArgsTimeout:     argsTimeout,
 	})
 
-	// determine timestamp. Must be max(local clock, prev timestamp+1).
-	// Adjustment enforced, when needed
-	ts := time.Now().UnixNano()
-	prevTs := op.stateTx.MustState().Timestamp()
-	if ts <= prevTs {
-		op.log.Warnf("local clock is not ahead the timestamp of the previous state. prevTs: %d, currentTs: %d, diff: %d ns",
-			prevTs, ts, prevTs-ts)
-		ts = prevTs + 1
-		op.log.Info("timestamp was adjusted to %d", ts)
-	}
-
 	numSucc := op.chain.SendMsgToCommitteePeers(chain.MsgStartProcessingRequest, msgData, ts)
 
 	op.log.Debugf("%d 'msgStartProcessingRequest' messages sent to peers", numSucc)
@@ -150,13 +189,14 @@ func (op *operator) startCalculationsAsLeader() {
 		return
 	}
 	// batchHash uniquely identifies inputs to calculations
-	batchHash := vm.BatchHash(reqIds, ts, op.peerIndex())
+	batchHash := vm.BatchHash(reqIds, argsTimeout, ts, op.peerIndex())
 	op.leaderStatus = &leaderStatus{
-		reqs:          reqs,
-		batchHash:     batchHash,
-		balances:      op.balances,
-		timestamp:     ts,
-		signedResults: make([]*signedResult, op.chain.Size()),
+		reqs:            reqs,
+		batchHash:       batchHash,
+		balances:        op.balances,
+		timestamp:       ts,
+		signedResults:   make([]*signedResult, op.chain.Size()),
+		chainIDBalances: chainIDBalances,
 	}
 	op.log.Debugw("runCalculationsAsync leader",
 		"batch hash", batchHash.String(),
@@ -166,8 +206,10 @@ func (op *operator) startCalculationsAsLeader() {
 	// process the batch on own (leader) side. Start calculations on VM in a separate thread
 	op.runCalculationsAsync(runCalculationsParams{
 		requests:        reqs,
+		argsTimeout:     argsTimeout,
 		leaderPeerIndex: op.chain.OwnPeerIndex(),
 		balances:        op.balances,
+		chainIDBalances: chainIDBalances,
 		timestamp:       ts,
 		accrueFeesTo:    rewardAddress,
 	})
@@ -176,6 +218,15 @@ func (op *operator) startCalculationsAsLeader() {
 	op.setNextConsensusStage(consensusStageLeaderCalculationsStarted)
 }
 
+// hasDueJobs returns true if some jobs of the 'scheduler' are due at the timestamp of the batch.
+// The leader starts the batch to run them even if there are no requests to process
+func (op *operator) hasDueJobs(ts int64) bool {
+	if op.currentState == nil {
+		return false
+	}
+	return scheduler.HasDueJobs(op.currentState.Variables(), ts)
+}
+
 // checkQuorum takes an action if quorum of results and partial signatures has been reached.
 // If so, it aggregates all signatures and produces final transaction.
 // The transaction is posted to goshimmer and peers are notified about the fact.
@@ -223,10 +274,15 @@ func (op *operator) checkQuorum() {
 		// the quorum has not been reached yet
 		return
 	}
+	chainIDSigShares, ok := op.collectChainIDSigShares()
+	if !ok {
+		// the result sweeps the chain ID address and the threshold of its key has not been reached yet
+		return
+	}
 	// quorum detected
 
 	// finalizing result transaction with signatures
-	if err := op.aggregateSigShares(sigShares); err != nil {
+	if err := op.aggregateSigShares(sigShares, chainIDSigShares); err != nil {
 		// should not normally happen
 		op.log.Errorf("aggregateSigShares returned: %v", err)
 		return
@@ -276,6 +332,27 @@ func (op *operator) checkQuorum() {
 	return
 }
 
+// collectChainIDSigShares collects valid signature shares of the chain ID address if the result transaction sweeps it.
+// Returns false if the threshold of the key of the chain ID address has not been reached yet
+func (op *operator) collectChainIDSigShares() ([][]byte, bool) {
+	if len(op.leaderStatus.chainIDBalances) == 0 {
+		return nil, true
+	}
+	ret := make([][]byte, 0, op.chainIDDKShare.N)
+	for i, res := range op.leaderStatus.signedResults {
+		if res == nil || len(res.chainIDSigShare) == 0 {
+			continue
+		}
+		if err := op.chainIDDKShare.VerifySigShare(op.leaderStatus.resultTx.EssenceBytes(), res.chainIDSigShare); err != nil {
+			op.log.Warnf("wrong signature of the chain ID address from peer #%d: %v", i, err)
+			res.chainIDSigShare = nil // ignoring
+			continue
+		}
+		ret = append(ret, res.chainIDSigShare)
+	}
+	return ret, len(ret) >= int(op.chainIDDKShare.T)
+}
+
 // sets new currentState transaction and initializes respective variables
 func (op *operator) setNewSCState(stateTx *sctransaction.Transaction, variableState state.VirtualState, synchronized bool) {
 	op.stateTx = stateTx
@@ -8,8 +8,10 @@ import (
 	"strings"
 	"time"
 
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
 	"github.com/iotaledger/goshimmer/dapps/waspconn/packages/waspconn"
 	"github.com/iotaledger/wasp/packages/chain"
+	"github.com/iotaledger/wasp/packages/metrics"
 	"github.com/iotaledger/wasp/packages/txutil"
 	"github.com/iotaledger/wasp/packages/vm"
 )
@@ -59,8 +61,16 @@ func (op *operator) eventBalancesMsg(reqMsg chain.BalancesMsg) {
 	//	op.log.Debugf("EventBalancesMsg: balances not included: %v", err)
 	//	return
 	//}
-	op.balances = reqMsg.Balances
-	op.requestBalancesDeadline = time.Now().Add(chain.RequestBalancesPeriod)
+	switch reqMsg.Address {
+	case op.chain.Address():
+		op.balances = reqMsg.Balances
+		op.requestBalancesDeadline = time.Now().Add(chain.RequestBalancesPeriod)
+	case address.Address(*op.chain.ID()):
+		// outputs of the chain ID address after the committee rotation. They are swept by the state transaction
+		op.chainIDBalances = reqMsg.Balances
+	default:
+		return
+	}
 	op.takeAction()
 }
 
@@ -110,7 +120,7 @@ func (op *operator) EventStartProcessingBatchMsg(msg *chain.StartProcessingBatch
 
 // eventStartProcessingBatchMsg internal handler
 func (op *operator) eventStartProcessingBatchMsg(msg *chain.StartProcessingBatchMsg) {
-	bh := vm.BatchHash(msg.RequestIds, msg.Timestamp, msg.SenderIndex)
+	bh := vm.BatchHash(msg.RequestIds, msg.ArgsTimeout, msg.Timestamp, msg.SenderIndex)
 
 	op.log.Debugw("EventStartProcessingBatchMsg",
 		"sender", msg.SenderIndex,
@@ -134,13 +144,20 @@ func (op *operator) eventStartProcessingBatchMsg(msg *chain.StartProcessingBatch
 		return
 	}
 	numOrig := len(msg.RequestIds)
-	reqs := op.collectProcessableBatch(msg.RequestIds)
+	reqs := op.collectProcessableBatch(msg.RequestIds, msg.ArgsTimeout, msg.Timestamp)
 	if len(reqs) != numOrig {
-		// some request were filtered out because not messages didn't reach the node yet.
-		// can't happen? Redundant? panic?
-		op.log.Warnf("node can't process the batch: some requests are not known to the node")
+		// some request were filtered out because not messages didn't reach the node yet
+		// or the node doesn't agree that arguments of the request were not solidified in time
+		op.log.Warnf("node can't process the batch: some requests are not known to the node or not ready")
 		return
 	}
+	for _, req := range reqs {
+		txid := req.reqTx.ID()
+		if !hasRequestToken(msg.Balances, txid) && !hasRequestToken(msg.ChainIDBalances, txid) {
+			op.log.Warnf("node can't process the batch: request token of %s is not in the balances", req.reqId.Short())
+			return
+		}
+	}
 	// TODO remove
 	//reqs = op.filterNotReadyYet(reqs)
 	//if len(reqs) != numOrig {
@@ -168,8 +185,10 @@ func (op *operator) eventStartProcessingBatchMsg(msg *chain.StartProcessingBatch
 	// start async calculation as requested by the leader
 	op.runCalculationsAsync(runCalculationsParams{
 		requests:        reqs,
+		argsTimeout:     msg.ArgsTimeout,
 		timestamp:       msg.Timestamp,
 		balances:        msg.Balances,
+		chainIDBalances: msg.ChainIDBalances,
 		accrueFeesTo:    msg.FeeDestination,
 		leaderPeerIndex: msg.SenderIndex,
 	})
@@ -250,8 +269,9 @@ func (op *operator) eventSignedHashMsg(msg *chain.SignedHashMsg) {
 		return
 	}
 	op.leaderStatus.signedResults[msg.SenderIndex] = &signedResult{
-		essenceHash: msg.EssenceHash,
-		sigShare:    msg.SigShare,
+		essenceHash:     msg.EssenceHash,
+		sigShare:        msg.SigShare,
+		chainIDSigShare: msg.ChainIDSigShare,
 	}
 	op.takeAction()
 }
@@ -304,6 +324,7 @@ func (op *operator) EventTimerMsg(msg chain.TimerTick) {
 
 // eventTimerMsg internal handler
 func (op *operator) eventTimerMsg(msg chain.TimerTick) {
+	metrics.SetBacklogSize(op.chain.ID().String(), len(op.requests))
 	if msg%40 == 0 {
 		blockIndex, ok := op.blockIndex()
 		si := int32(-1)
@@ -70,11 +70,16 @@ func (op *operator) requestFromMsg(reqMsg *chain.RequestMsg) (*request, bool) {
 	if newMsg {
 		// solidify arguments by resolving blob references from the registry
 		// the request will not be selected for processing until ret.argsSolid == true
-		ok, err := reqMsg.RequestBlock().SolidifyArgs(op.chain.BlobCache())
+		ok, err := op.solidifyArgs(reqMsg.RequestBlock())
 		if err != nil {
 			ret.log.Errorf("inconsistency: can't solidify args: %v", err)
 		} else {
 			ret.argsSolid = ok
+			if !ok {
+				// off-tangle data is requested from committee peers
+				op.setRequestStatusConcurrent(reqId, chain.RequestProcessingStatusSolidifyingArgs)
+				op.chain.RequestBlobs(op.missingBlobs(ret))
+			}
 		}
 	}
 	if newMsg {
@@ -116,6 +121,12 @@ func (req *request) hasSolidArgs() bool {
 	return req.argsSolid
 }
 
+// isArgsTimeout returns true if arguments of the request are not solid and the solidification timeout
+// has expired at the moment
+func (req *request) isArgsTimeout(nowis time.Time) bool {
+	return !req.argsSolid && nowis.Sub(req.whenMsgReceived) > chain.RequestArgsSolidificationTimeout
+}
+
 func (op *operator) isRequestProcessed(reqid *coretypes.RequestID) bool {
 	processed, err := state.IsRequestCompleted(op.chain.ID(), reqid)
 	if err != nil {
@@ -153,27 +164,25 @@ func idsShortStr(ids []coretypes.RequestID) []string {
 	return ret
 }
 
-func (op *operator) takeFromIds(reqIds []coretypes.RequestID) []*request {
-	ret := make([]*request, 0, len(reqIds))
-	for _, reqId := range reqIds {
-		req, ok := op.requestFromId(reqId)
-		if !ok || req == nil {
-			continue
-		}
-		ret = append(ret, req)
+func takeIds(reqs []*request) []coretypes.RequestID {
+	ret := make([]coretypes.RequestID, len(reqs))
+	for i := range ret {
+		ret[i] = reqs[i].reqId
 	}
 	return ret
 }
 
-func takeIds(reqs []*request) []coretypes.RequestID {
-	ret := make([]coretypes.RequestID, len(reqs))
+// takeArgsTimeouts returns flags of requests selected for the batch which arguments are not solid.
+// Requests are only selected with not solid arguments after the solidification timeout
+func takeArgsTimeouts(reqs []*request) []bool {
+	ret := make([]bool, len(reqs))
 	for i := range ret {
-		ret[i] = reqs[i].reqId
+		ret[i] = !reqs[i].hasSolidArgs()
 	}
 	return ret
 }
 
-func takeRefs(reqs []*request) []vm.RequestRefWithFreeTokens {
+func takeRefs(reqs []*request, argsTimeout []bool) []vm.RequestRefWithFreeTokens {
 	ret := make([]vm.RequestRefWithFreeTokens, len(reqs))
 	for i := range ret {
 		ret[i] = vm.RequestRefWithFreeTokens{
@@ -181,7 +190,8 @@ func takeRefs(reqs []*request) []vm.RequestRefWithFreeTokens {
 				Tx:    reqs[i].reqTx,
 				Index: reqs[i].reqId.Index(),
 			},
-			FreeTokens: reqs[i].freeTokens,
+			FreeTokens:  reqs[i].freeTokens,
+			ArgsTimeout: argsTimeout[i],
 		}
 	}
 	return ret
@@ -191,7 +201,17 @@ func (op *operator) addRequestIdConcurrent(reqId *coretypes.RequestID) {
 	op.concurrentAccessMutex.Lock()
 	defer op.concurrentAccessMutex.Unlock()
 
-	op.requestIdsProtected[*reqId] = true
+	op.requestIdsProtected[*reqId] = chain.RequestProcessingStatusBacklog
+}
+
+// setRequestStatusConcurrent updates status of the request in the backlog
+func (op *operator) setRequestStatusConcurrent(reqId *coretypes.RequestID, status chain.RequestProcessingStatus) {
+	op.concurrentAccessMutex.Lock()
+	defer op.concurrentAccessMutex.Unlock()
+
+	if _, ok := op.requestIdsProtected[*reqId]; ok {
+		op.requestIdsProtected[*reqId] = status
+	}
 }
 
 func (op *operator) removeRequestIdConcurrent(reqId *coretypes.RequestID) {
@@ -212,3 +232,15 @@ func (op *operator) hasRequestIdConcurrent(reqId *coretypes.RequestID) bool {
 func (op *operator) IsRequestInBacklog(reqId *coretypes.RequestID) bool {
 	return op.hasRequestIdConcurrent(reqId)
 }
+
+// GetRequestProcessingStatus returns status of the request in the backlog or RequestProcessingStatusUnknown
+func (op *operator) GetRequestProcessingStatus(reqId *coretypes.RequestID) chain.RequestProcessingStatus {
+	op.concurrentAccessMutex.RLock()
+	defer op.concurrentAccessMutex.RUnlock()
+
+	status, ok := op.requestIdsProtected[*reqId]
+	if !ok {
+		return chain.RequestProcessingStatusUnknown
+	}
+	return status
+}
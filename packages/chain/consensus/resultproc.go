@@ -12,6 +12,7 @@ import (
 	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/hashing"
 	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/tcrypto/tbdn"
 	"github.com/iotaledger/wasp/packages/util"
 	"github.com/iotaledger/wasp/packages/vm"
 	"github.com/iotaledger/wasp/packages/vm/runvm"
@@ -19,8 +20,10 @@ import (
 
 type runCalculationsParams struct {
 	requests        []*request
+	argsTimeout     []bool
 	leaderPeerIndex uint16
 	balances        map[valuetransaction.ID][]*balance.Balance
+	chainIDBalances map[valuetransaction.ID][]*balance.Balance
 	accrueFeesTo    coretypes.AgentID
 	timestamp       int64
 }
@@ -34,11 +37,13 @@ func (op *operator) runCalculationsAsync(par runCalculationsParams) {
 	ctx := &vm.VMTask{
 		Processors:         op.chain.Processors(),
 		ChainID:            *op.chain.ID(),
+		ChainAddress:       op.chain.Address(),
 		Color:              *op.chain.Color(),
 		Entropy:            (hashing.HashValue)(op.stateTx.ID()),
 		Balances:           par.balances,
+		ChainIDBalances:    par.chainIDBalances,
 		ValidatorFeeTarget: par.accrueFeesTo,
-		Requests:           takeRefs(par.requests),
+		Requests:           takeRefs(par.requests, par.argsTimeout),
 		Timestamp:          par.timestamp,
 		VirtualState:       op.currentState,
 		Log:                op.log,
@@ -71,14 +76,21 @@ func (op *operator) sendResultToTheLeader(result *vm.VMTask, leader uint16) {
 		op.log.Errorf("error while signing transaction %v", err)
 		return
 	}
+	chainIDSigShare, err := op.signChainIDInput(result)
+	if err != nil {
+		op.log.Errorf("error while signing transaction %v", err)
+		return
+	}
 
 	reqids := make([]coretypes.RequestID, len(result.Requests))
+	argsTimeout := make([]bool, len(result.Requests))
 	for i := range reqids {
 		reqids[i] = *result.Requests[i].RequestID()
+		argsTimeout[i] = result.Requests[i].ArgsTimeout
 	}
 
 	essenceHash := hashing.HashData(result.ResultTransaction.EssenceBytes())
-	batchHash := vm.BatchHash(reqids, result.Timestamp, leader)
+	batchHash := vm.BatchHash(reqids, argsTimeout, result.Timestamp, leader)
 
 	op.log.Debugw("sendResultToTheLeader",
 		"leader", leader,
@@ -91,10 +103,11 @@ func (op *operator) sendResultToTheLeader(result *vm.VMTask, leader uint16) {
 		PeerMsgHeader: chain.PeerMsgHeader{
 			BlockIndex: op.mustStateIndex(),
 		},
-		BatchHash:     batchHash,
-		OrigTimestamp: result.Timestamp,
-		EssenceHash:   essenceHash,
-		SigShare:      sigShare,
+		BatchHash:       batchHash,
+		OrigTimestamp:   result.Timestamp,
+		EssenceHash:     essenceHash,
+		SigShare:        sigShare,
+		ChainIDSigShare: chainIDSigShare,
 	})
 
 	if err := op.chain.SendMsg(leader, chain.MsgSignedHash, msgData); err != nil {
@@ -118,13 +131,20 @@ func (op *operator) saveOwnResult(result *vm.VMTask) {
 		op.log.Errorf("error while signing transaction %v", err)
 		return
 	}
+	chainIDSigShare, err := op.signChainIDInput(result)
+	if err != nil {
+		op.log.Errorf("error while signing transaction %v", err)
+		return
+	}
 
 	reqids := make([]coretypes.RequestID, len(result.Requests))
+	argsTimeout := make([]bool, len(result.Requests))
 	for i := range reqids {
 		reqids[i] = *result.Requests[i].RequestID()
+		argsTimeout[i] = result.Requests[i].ArgsTimeout
 	}
 
-	bh := vm.BatchHash(reqids, result.Timestamp, op.chain.OwnPeerIndex())
+	bh := vm.BatchHash(reqids, argsTimeout, result.Timestamp, op.chain.OwnPeerIndex())
 	if bh != op.leaderStatus.batchHash {
 		panic("bh != op.leaderStatus.batchHash")
 	}
@@ -142,13 +162,23 @@ func (op *operator) saveOwnResult(result *vm.VMTask) {
 	op.leaderStatus.resultTx = result.ResultTransaction
 	op.leaderStatus.batch = result.ResultBlock
 	op.leaderStatus.signedResults[op.chain.OwnPeerIndex()] = &signedResult{
-		essenceHash: essenceHash,
-		sigShare:    sigShare,
+		essenceHash:     essenceHash,
+		sigShare:        sigShare,
+		chainIDSigShare: chainIDSigShare,
 	}
 	op.setNextConsensusStage(consensusStageLeaderCalculationsFinished)
 }
 
-func (op *operator) aggregateSigShares(sigShares [][]byte) error {
+// signChainIDInput signs the result with the key share of the chain ID address if the result sweeps it.
+// Returns nil if it doesn't or if the node doesn't hold a key share of the chain ID address
+func (op *operator) signChainIDInput(result *vm.VMTask) (tbdn.SigShare, error) {
+	if len(result.ChainIDBalances) == 0 || op.chainIDDKShare == nil {
+		return nil, nil
+	}
+	return op.chainIDDKShare.SignShare(result.ResultTransaction.EssenceBytes())
+}
+
+func (op *operator) aggregateSigShares(sigShares, chainIDSigShares [][]byte) error {
 	resTx := op.leaderStatus.resultTx
 
 	finalSignature, err := op.dkshare.RecoverFullSignature(sigShares, resTx.EssenceBytes())
@@ -158,5 +188,15 @@ func (op *operator) aggregateSigShares(sigShares [][]byte) error {
 	if err := resTx.PutSignature(finalSignature); err != nil {
 		return fmt.Errorf("something wrong while aggregating final signature: %v", err)
 	}
+	if len(chainIDSigShares) == 0 {
+		return nil
+	}
+	chainIDSignature, err := op.chainIDDKShare.RecoverFullSignature(chainIDSigShares, resTx.EssenceBytes())
+	if err != nil {
+		return err
+	}
+	if err := resTx.PutSignature(chainIDSignature); err != nil {
+		return fmt.Errorf("something wrong while aggregating signature of the chain ID address: %v", err)
+	}
 	return nil
 }
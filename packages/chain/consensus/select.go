@@ -5,7 +5,12 @@
 package consensus
 
 import (
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	valuetransaction "github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
+	"github.com/iotaledger/wasp/packages/chain"
 	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/txutil"
 	"sort"
 	"time"
 )
@@ -54,14 +59,14 @@ func (op *operator) allRequests() []*request {
 
 // all requests from the backlog which:
 // - has known messages
-// - has solid arguments
+// - has solid arguments or the solidification of arguments timed out
 // - are not timelocked
 // sort by arrival time
 func (op *operator) requestCandidateList() []*request {
 	ret := op.allRequests()
 	nowis := time.Now()
 	ret = filterRequests(ret, func(r *request) bool {
-		return r.hasMessage() && !r.isTimeLocked(nowis) && r.hasSolidArgs()
+		return r.hasMessage() && op.hasRequestToken(r) && !r.isTimeLocked(nowis) && (r.hasSolidArgs() || r.isArgsTimeout(nowis))
 	})
 	sort.Slice(ret, func(i, j int) bool {
 		return ret[i].whenMsgReceived.Before(ret[j].whenMsgReceived)
@@ -69,6 +74,29 @@ func (op *operator) requestCandidateList() []*request {
 	return ret
 }
 
+// hasRequestToken returns true if the request token is in the outputs consumed by the state transaction.
+// A request sent to the chain ID address after the committee rotation can't be processed
+// unless the chain ID address is swept
+func (op *operator) hasRequestToken(r *request) bool {
+	txid := r.reqTx.ID()
+	return hasRequestToken(op.balances, txid) || hasRequestToken(op.chainIDBalancesToSweep(), txid)
+}
+
+func hasRequestToken(balances map[valuetransaction.ID][]*balance.Balance, txid valuetransaction.ID) bool {
+	bals, ok := balances[txid]
+	return ok && txutil.BalanceOfColor(bals, balance.Color(txid)) > 0
+}
+
+// chainIDBalancesToSweep returns outputs of the chain ID address to be swept by the state transaction.
+// The leader only proposes to sweep them if it holds a key share of the chain ID address,
+// because it has to recover the signature of the chain ID address from the shares of the peers
+func (op *operator) chainIDBalancesToSweep() map[valuetransaction.ID][]*balance.Balance {
+	if op.chainIDDKShare == nil || op.chain.Address() == address.Address(*op.chain.ID()) {
+		return nil
+	}
+	return op.chainIDBalances
+}
+
 func (op *operator) requestsTimeLocked() []*request {
 	ret := make([]*request, 0, len(op.requests))
 
@@ -114,11 +142,28 @@ func (op *operator) filterRequestsNotSeenQuorumTimes(candidates []*request) []*r
 	return ret
 }
 
-func (op *operator) collectProcessableBatch(reqIds []coretypes.RequestID) []*request {
+// collectProcessableBatch returns requests of the batch proposed by the leader if the node agrees to process them.
+// The request with not solid arguments is agreed to be processed as failed only if the solidification timeout,
+// adjusted by the allowed clock difference, has expired at the timestamp of the batch
+func (op *operator) collectProcessableBatch(reqIds []coretypes.RequestID, argsTimeout []bool, ts int64) []*request {
 	nowis := time.Now()
-	return filterRequests(op.takeFromIds(reqIds), func(r *request) bool {
-		return r.hasMessage() && !r.isTimeLocked(nowis) && r.hasSolidArgs()
-	})
+	batchTime := time.Unix(0, ts).Add(chain.MaxClockDifferenceAllowed)
+	ret := make([]*request, 0, len(reqIds))
+	for i, reqId := range reqIds {
+		r, ok := op.requestFromId(reqId)
+		if !ok || r == nil || !r.hasMessage() || r.isTimeLocked(nowis) {
+			continue
+		}
+		if argsTimeout[i] {
+			if batchTime.Sub(r.whenMsgReceived) <= chain.RequestArgsSolidificationTimeout {
+				continue
+			}
+		} else if !r.hasSolidArgs() {
+			continue
+		}
+		ret = append(ret, r)
+	}
+	return ret
 }
 
 func filterRequests(reqs []*request, fn func(r *request) bool) []*request {
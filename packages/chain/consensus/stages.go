@@ -6,6 +6,8 @@ package consensus
 import (
 	"fmt"
 	"time"
+
+	"github.com/iotaledger/wasp/packages/metrics"
 )
 
 // consensus goes through stages on the leader and on the subordinate side
@@ -186,8 +188,13 @@ func (op *operator) setNextConsensusStage(nextStage int) {
 			stages[op.consensusStage].name, nextStageParams.name, leader, op.iAmCurrentLeader())
 	}
 	saveStage := op.consensusStage
+	nowis := time.Now()
+	if !op.consensusStageStarted.IsZero() {
+		metrics.ObserveConsensusStage(op.chain.ID().String(), stages[saveStage].name, nowis.Sub(op.consensusStageStarted))
+	}
 	op.consensusStage = nextStage
-	op.consensusStageDeadline = time.Now().Add(nextStageParams.timeout)
+	op.consensusStageStarted = nowis
+	op.consensusStageDeadline = nowis.Add(nextStageParams.timeout)
 	timeout := "timeout: not set"
 	if nextStageParams.timeoutSet {
 		timeout = fmt.Sprintf("timeout: %v", nextStageParams.timeout)
@@ -25,14 +25,20 @@ type operator struct {
 	chain chain.Chain
 
 	dkshare *tcrypto.DKShare
+	// key share of the chain ID address. Not nil only after the committee rotation
+	// if the node was a member of the original committee
+	chainIDDKShare *tcrypto.DKShare
 	//currentState
 	currentState state.VirtualState
 	stateTx      *sctransaction.Transaction
 	balances     map[valuetransaction.ID][]*balance.Balance
+	// outputs of the chain ID address after the committee rotation
+	chainIDBalances map[valuetransaction.ID][]*balance.Balance
 
 	// consensus stage
 	consensusStage         int
 	consensusStageDeadline time.Time
+	consensusStageStarted  time.Time
 	//
 	requestBalancesDeadline time.Time
 
@@ -57,7 +63,7 @@ type operator struct {
 
 	// data for concurrent access, from APIs mostly
 	concurrentAccessMutex sync.RWMutex
-	requestIdsProtected   map[coretypes.RequestID]bool
+	requestIdsProtected   map[coretypes.RequestID]chain.RequestProcessingStatus
 
 	// Channels for accepting external events.
 	eventStateTransitionMsgCh           chan *chain.StateTransitionMsg
@@ -82,11 +88,14 @@ type leaderStatus struct {
 	resultTx      *sctransaction.Transaction
 	finalized     bool
 	signedResults []*signedResult
+	// not empty if the result transaction sweeps the chain ID address
+	chainIDBalances map[valuetransaction.ID][]*balance.Balance
 }
 
 type signedResult struct {
-	essenceHash hashing.HashValue
-	sigShare    tbdn.SigShare
+	essenceHash     hashing.HashValue
+	sigShare        tbdn.SigShare
+	chainIDSigShare tbdn.SigShare
 }
 
 // backlog entry. Keeps stateTx of the request
@@ -103,18 +112,22 @@ type request struct {
 	notifications []bool
 	// true if arguments were decoded/solidified already. If not, the request in not eligible for the batch
 	argsSolid bool
+	// true if arguments were not solidified in time. The request can be proposed to be processed as failed.
+	// Missing data is still requested from peers until the request is processed
+	argsTimeout bool
 
 	log *logger.Logger
 }
 
-func NewOperator(committee chain.Chain, dkshare *tcrypto.DKShare, log *logger.Logger) *operator {
+func NewOperator(committee chain.Chain, dkshare, chainIDDKShare *tcrypto.DKShare, log *logger.Logger) *operator {
 	defer committee.SetReadyConsensus()
 
 	ret := &operator{
 		chain:                               committee,
 		dkshare:                             dkshare,
+		chainIDDKShare:                      chainIDDKShare,
 		requests:                            make(map[coretypes.RequestID]*request),
-		requestIdsProtected:                 make(map[coretypes.RequestID]bool),
+		requestIdsProtected:                 make(map[coretypes.RequestID]chain.RequestProcessingStatus),
 		peerPermutation:                     util.NewPermutation16(committee.Size(), nil),
 		log:                                 log.Named("c"),
 		eventStateTransitionMsgCh:           make(chan *chain.StateTransitionMsg),
@@ -37,4 +37,9 @@ const (
 
 	// check arg solidification period
 	CheckArgSolidificationEvery = 1 * time.Second
+
+	// if arguments of the request are not solid after the timeout, the request is processed as failed
+	// and the transfer is returned to the sender. The leader proposes it in the batch and
+	// the other nodes check the timeout against the timestamp of the batch
+	RequestArgsSolidificationTimeout = 2 * time.Minute
 )
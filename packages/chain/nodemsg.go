@@ -4,6 +4,7 @@
 package chain
 
 import (
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
 	valuetransaction "github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
 	"github.com/iotaledger/wasp/packages/coretypes"
@@ -20,6 +21,8 @@ type TransactionInclusionLevelMsg struct {
 }
 
 type BalancesMsg struct {
+	// the address of the committee or the chain ID address after the committee has been rotated
+	Address  address.Address
 	Balances map[valuetransaction.ID][]*balance.Balance
 }
 
@@ -9,6 +9,7 @@ import (
 
 	"github.com/iotaledger/goshimmer/dapps/waspconn/packages/waspconn"
 	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/hashing"
 	"github.com/iotaledger/wasp/packages/state"
 	"github.com/iotaledger/wasp/packages/util"
 )
@@ -97,6 +98,9 @@ func (msg *StartProcessingBatchMsg) Write(w io.Writer) error {
 		if _, err := w.Write(msg.RequestIds[i][:]); err != nil {
 			return err
 		}
+		if err := util.WriteBoolByte(w, msg.ArgsTimeout[i]); err != nil {
+			return err
+		}
 	}
 	if _, err := w.Write(msg.FeeDestination[:]); err != nil {
 		return err
@@ -104,6 +108,9 @@ func (msg *StartProcessingBatchMsg) Write(w io.Writer) error {
 	if err := waspconn.WriteBalances(w, msg.Balances); err != nil {
 		return err
 	}
+	if err := waspconn.WriteBalances(w, msg.ChainIDBalances); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -116,10 +123,14 @@ func (msg *StartProcessingBatchMsg) Read(r io.Reader) error {
 		return err
 	}
 	msg.RequestIds = make([]coretypes.RequestID, size)
+	msg.ArgsTimeout = make([]bool, size)
 	for i := range msg.RequestIds {
 		if err := msg.RequestIds[i].Read(r); err != nil {
 			return err
 		}
+		if err := util.ReadBoolByte(r, &msg.ArgsTimeout[i]); err != nil {
+			return err
+		}
 	}
 	if err := coretypes.ReadAgentID(r, &msg.FeeDestination); err != nil {
 		return err
@@ -128,6 +139,9 @@ func (msg *StartProcessingBatchMsg) Read(r io.Reader) error {
 	if msg.Balances, err = waspconn.ReadBalances(r); err != nil {
 		return err
 	}
+	if msg.ChainIDBalances, err = waspconn.ReadBalances(r); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -147,6 +161,9 @@ func (msg *SignedHashMsg) Write(w io.Writer) error {
 	if err := util.WriteBytes16(w, msg.SigShare); err != nil {
 		return err
 	}
+	if err := util.WriteBytes16(w, msg.ChainIDSigShare); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -170,6 +187,9 @@ func (msg *SignedHashMsg) Read(r io.Reader) error {
 	if msg.SigShare, err = util.ReadBytes16(r); err != nil {
 		return err
 	}
+	if msg.ChainIDSigShare, err = util.ReadBytes16(r); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -181,6 +201,14 @@ func (msg *GetBlockMsg) Read(r io.Reader) error {
 	return util.ReadUint32(r, &msg.BlockIndex)
 }
 
+func (msg *BlockNotAvailableMsg) Write(w io.Writer) error {
+	return util.WriteUint32(w, msg.BlockIndex)
+}
+
+func (msg *BlockNotAvailableMsg) Read(r io.Reader) error {
+	return util.ReadUint32(r, &msg.BlockIndex)
+}
+
 func (msg *BlockHeaderMsg) Write(w io.Writer) error {
 	if err := util.WriteUint32(w, msg.BlockIndex); err != nil {
 		return err
@@ -285,3 +313,51 @@ func (msg *TestTraceMsg) Read(r io.Reader) error {
 	}
 	return nil
 }
+
+func (msg *GetBlobsMsg) Write(w io.Writer) error {
+	if err := util.WriteUint32(w, msg.BlockIndex); err != nil {
+		return err
+	}
+	if err := util.WriteUint16(w, uint16(len(msg.BlobHashes))); err != nil {
+		return err
+	}
+	for _, h := range msg.BlobHashes {
+		if _, err := w.Write(h[:]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (msg *GetBlobsMsg) Read(r io.Reader) error {
+	if err := util.ReadUint32(r, &msg.BlockIndex); err != nil {
+		return err
+	}
+	var size uint16
+	if err := util.ReadUint16(r, &size); err != nil {
+		return err
+	}
+	msg.BlobHashes = make([]hashing.HashValue, size)
+	for i := range msg.BlobHashes {
+		if err := util.ReadHashValue(r, &msg.BlobHashes[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (msg *BlobMsg) Write(w io.Writer) error {
+	if err := util.WriteUint32(w, msg.BlockIndex); err != nil {
+		return err
+	}
+	return util.WriteBytes32(w, msg.Data)
+}
+
+func (msg *BlobMsg) Read(r io.Reader) error {
+	if err := util.ReadUint32(r, &msg.BlockIndex); err != nil {
+		return err
+	}
+	var err error
+	msg.Data, err = util.ReadBytes32(r)
+	return err
+}
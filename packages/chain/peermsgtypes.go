@@ -26,6 +26,9 @@ const (
 	MsgStateUpdate             = 6 + peering.FirstUserMsgCode
 	MsgBatchHeader             = 7 + peering.FirstUserMsgCode
 	MsgTestTrace               = 8 + peering.FirstUserMsgCode
+	MsgGetBlobs                = 9 + peering.FirstUserMsgCode
+	MsgBlob                    = 10 + peering.FirstUserMsgCode
+	MsgBlockNotAvailable       = 11 + peering.FirstUserMsgCode
 )
 
 type TimerTick int
@@ -71,10 +74,15 @@ type StartProcessingBatchMsg struct {
 	Timestamp int64
 	// batch of request ids
 	RequestIds []coretypes.RequestID
+	// ArgsTimeout[i] is true if arguments of RequestIds[i] were not solidified in time by the leader
+	ArgsTimeout []bool
 	// reward address
 	FeeDestination coretypes.AgentID
 	// balances/outputs
 	Balances map[valuetransaction.ID][]*balance.Balance
+	// outputs of the chain ID address to be swept by the state transaction. Empty unless the committee
+	// has been rotated and the leader holds a key share of the chain ID address
+	ChainIDBalances map[valuetransaction.ID][]*balance.Balance
 }
 
 // after calculations the result peer responds to the start processing msg
@@ -91,6 +99,9 @@ type SignedHashMsg struct {
 	EssenceHash hashing.HashValue
 	// signature
 	SigShare tbdn.SigShare
+	// signature of the input from the chain ID address if the result sweeps it. Empty otherwise or if
+	// the node doesn't hold a key share of the chain ID address
+	ChainIDSigShare tbdn.SigShare
 }
 
 // request block of updates from peer. Used in syn process
@@ -98,6 +109,12 @@ type GetBlockMsg struct {
 	PeerMsgHeader
 }
 
+// response to GetBlockMsg if the peer doesn't have the requested block, for example because
+// the block was pruned or the peer was bootstrapped from a later snapshot. BlockIndex is the index of the requested block
+type BlockNotAvailableMsg struct {
+	PeerMsgHeader
+}
+
 // the header of the block message sent by peers in the process of syncing
 // it is sent as a first message while syncing a batch
 type BlockHeaderMsg struct {
@@ -124,6 +141,18 @@ type TestTraceMsg struct {
 	NumHops       uint16
 }
 
+// request of blobs from peer. Used to gossip off-tangle request payloads among committee nodes
+type GetBlobsMsg struct {
+	PeerMsgHeader
+	BlobHashes []hashing.HashValue
+}
+
+// blob sent to peer as a response to GetBlobsMsg. The hash of the blob is the hash of the data
+type BlobMsg struct {
+	PeerMsgHeader
+	Data []byte
+}
+
 // state manager notifies consensus operator about changed state
 // only sent internally within committee
 // state transition is always from state N to state N+1
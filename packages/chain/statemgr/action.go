@@ -8,6 +8,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
 	valuetransaction "github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
 	"github.com/iotaledger/wasp/packages/chain"
 	"github.com/iotaledger/wasp/packages/hashing"
@@ -135,6 +136,7 @@ func (sm *stateManager) checkStateApproval() bool {
 	}
 	sm.solidStateValid = true
 	sm.solidState = pending.nextState
+	sm.blockNotAvailable = make(map[uint16]bool)
 
 	sm.approvingTransaction = sm.nextStateTransaction
 
@@ -154,8 +156,21 @@ func (sm *stateManager) checkStateApproval() bool {
 		varStateHash.String(),
 		fmt.Sprintf("%d", pending.block.Timestamp()),
 	)
-	// publish processed requests
-	for i, reqid := range pending.block.RequestIDs() {
+	sm.publishCommittedBlock(sm.solidState.Hash(), pending.block)
+	// publish processed requests and runs of scheduled jobs
+	pending.block.ForEach(func(i uint16, su state.StateUpdate) bool {
+		if res := su.Result(); res != nil && res.Job {
+			publisher.Publish("job_run",
+				sm.chain.ID().String(),
+				res.Contract.String(),
+				strconv.Itoa(int(res.JobID)),
+				strconv.Itoa(int(sm.solidState.BlockIndex())),
+				strconv.Itoa(int(i)),
+				strconv.Itoa(int(pending.block.Size())),
+			)
+			return true
+		}
+		reqid := su.RequestID()
 
 		sm.chain.EventRequestProcessed().Trigger(*reqid)
 
@@ -164,9 +179,17 @@ func (sm *stateManager) checkStateApproval() bool {
 			reqid.TransactionID().String(),
 			fmt.Sprintf("%d", reqid.Index()),
 			strconv.Itoa(int(sm.solidState.BlockIndex())),
-			strconv.Itoa(i),
+			strconv.Itoa(int(i)),
 			strconv.Itoa(int(pending.block.Size())),
 		)
+		return true
+	})
+	sm.pruneState()
+
+	// the chain token has been moved away from the address of this committee by the committee itself
+	txProp := sm.approvingTransaction.MustProperties()
+	if txProp.ChainAddress() != sm.chain.Address() && *txProp.SenderAddress() == sm.chain.Address() {
+		sm.handOverToNewCommittee(txProp.ChainAddress())
 	}
 	return true
 }
@@ -187,12 +210,13 @@ func (sm *stateManager) requestStateUpdateFromPeerIfNeeded() {
 			BlockIndex: sm.solidState.BlockIndex() + 1,
 		},
 	})
-	// send messages until first without error
-	for i := uint16(0); i < sm.chain.Size(); i++ {
+	// send messages until first without error. Access peers may also have the block.
+	// If the peer doesn't respond with the block or with BlockNotAvailableMsg, the next peer is asked after the timeout
+	for i := uint16(0); i < sm.chain.NumPeers(); i++ {
+		sm.syncMessageDeadline = time.Now().Add(chain.PeriodBetweenSyncMessages)
 		if err := sm.chain.SendMsg(sm.permutation.Next(), chain.MsgGetBatch, data); err == nil {
 			break
 		}
-		sm.syncMessageDeadline = time.Now().Add(chain.PeriodBetweenSyncMessages)
 	}
 }
 
@@ -343,6 +367,10 @@ func (sm *stateManager) numPongsHasQuorum() bool {
 }
 
 func (sm *stateManager) pingPongReceived(senderIndex uint16) {
+	if int(senderIndex) >= len(sm.pingPong) {
+		// pings of access peers are answered but not counted
+		return
+	}
 	sm.pingPong[senderIndex] = true
 }
 
@@ -376,3 +404,94 @@ func (sm *stateManager) sendPingsToCommitteePeers() {
 	sm.log.Debugf("sent pings to %d committee peers", numSent)
 	sm.deadlineForPongQuorum = time.Now().Add(chain.RepeatPingAfter)
 }
+
+// handOverToNewCommittee is called when the chain token has been moved to the address of another
+// committee by the 'root.rotateCommittee' request. The current committee is dismissed and
+// the new committee is activated on the node if the node has the chain record for the new committee address.
+// At least one node of the old committee must remain in the new one, for the new nodes to sync the state
+func (sm *stateManager) handOverToNewCommittee(newAddress address.Address) {
+	sm.log.Infof("COMMITTEE ROTATED: %s --> %s. Handing over the chain to the new committee",
+		sm.chain.Address().String(), newAddress.String())
+	publisher.Publish("rotated_committee",
+		sm.chain.ID().String(),
+		sm.chain.Address().String(),
+		newAddress.String(),
+	)
+	go sm.chain.HandOver(newAddress)
+}
+
+// pruneState starts pruning of old blocks and records of processed requests, unless it is already running.
+// Blocks needed by syncing peers are kept
+func (sm *stateManager) pruneState() {
+	if !sm.pruning.Enabled() {
+		return
+	}
+	select {
+	case sm.pruneCh <- sm.syncFloor():
+	default:
+		// the previous pruning is not finished yet, the rest will be pruned after the next block
+	}
+}
+
+// pruneStateLoop prunes the state outside of the event loop: pruned blocks may be written to the archive files
+func (sm *stateManager) pruneStateLoop() {
+	for {
+		select {
+		case syncFloor := <-sm.pruneCh:
+			pruned, err := state.PruneState(sm.chain.ID(), sm.pruning, syncFloor, time.Now())
+			if err != nil {
+				sm.log.Errorf("failed to prune state: %v", err)
+				continue
+			}
+			if pruned > 0 {
+				sm.log.Debugf("pruned %d old blocks", pruned)
+			}
+		case <-sm.closeCh:
+			return
+		}
+	}
+}
+
+// peerStateIndexEvidenced records the solid state index of the peer
+func (sm *stateManager) peerStateIndexEvidenced(peerIndex uint16, stateIndex uint32) {
+	if int(peerIndex) >= len(sm.peerStateIndices) {
+		return
+	}
+	sm.peerStateIndices[peerIndex] = peerStateIndex{
+		index:     stateIndex,
+		evidenced: time.Now(),
+	}
+}
+
+// syncFloor returns the lowest state index the peers are syncing from: the lowest recently evidenced
+// state index of peers or the solid state index
+func (sm *stateManager) syncFloor() uint32 {
+	ret := sm.solidState.BlockIndex()
+	for _, p := range sm.peerStateIndices {
+		if time.Since(p.evidenced) < peerStateIndexTimeout && p.index < ret {
+			ret = p.index
+		}
+	}
+	return ret
+}
+
+// publishCommittedBlock queues the committed block to be published with EventBlockCommitted.
+// The block is not published if the queue is full
+func (sm *stateManager) publishCommittedBlock(stateHash hashing.HashValue, block state.Block) {
+	select {
+	case sm.committedBlocksCh <- &committedBlock{stateHash: stateHash, block: block}:
+	default:
+		sm.log.Warnf("publishCommittedBlock: queue is full, block #%d is not published", block.StateIndex())
+	}
+}
+
+func (sm *stateManager) publishCommittedBlocksLoop() {
+	for {
+		select {
+		case b := <-sm.committedBlocksCh:
+			sm.chain.EventBlockCommitted().Trigger(b.stateHash, b.block)
+		case <-sm.closeCh:
+			return
+		}
+	}
+}
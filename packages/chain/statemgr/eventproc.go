@@ -4,7 +4,10 @@
 package statemgr
 
 import (
+	"time"
+
 	"github.com/iotaledger/wasp/packages/chain"
+	"github.com/iotaledger/wasp/packages/metrics"
 	"github.com/iotaledger/wasp/packages/state"
 	"github.com/iotaledger/wasp/packages/util"
 )
@@ -16,6 +19,7 @@ func (sm *stateManager) EventStateIndexPingPongMsg(msg *chain.StateIndexPingPong
 func (sm *stateManager) eventStateIndexPingPongMsg(msg *chain.StateIndexPingPongMsg) {
 	before := sm.numPongsHasQuorum()
 	sm.pingPongReceived(msg.SenderIndex)
+	sm.peerStateIndexEvidenced(msg.SenderIndex, msg.BlockIndex)
 	after := sm.numPongsHasQuorum()
 
 	if msg.RSVP && sm.solidStateValid {
@@ -42,9 +46,26 @@ func (sm *stateManager) eventGetBlockMsg(msg *chain.GetBlockMsg) {
 		"sender index", msg.SenderIndex,
 		"block index", msg.BlockIndex,
 	)
+	if msg.BlockIndex > 0 {
+		// the peer is syncing from the previous state
+		sm.peerStateIndexEvidenced(msg.SenderIndex, msg.BlockIndex-1)
+	}
 	block, err := state.LoadBlock(sm.chain.ID(), msg.BlockIndex)
+	if err == nil && block == nil && sm.pruning.ArchiveDir != "" {
+		// the block may be pruned
+		block, err = state.LoadArchivedBlock(sm.pruning.ArchiveDir, sm.chain.ID(), msg.BlockIndex)
+	}
+	if err != nil {
+		sm.log.Errorf("EventGetBlockMsg: failed to load block #%d: %v", msg.BlockIndex, err)
+	}
 	if err != nil || block == nil {
-		// can't load block, can't respond
+		// the peer asks another peer without waiting for the timeout
+		sm.log.Debugf("EventGetBlockMsg: block #%d is not available --> peer %d", msg.BlockIndex, msg.SenderIndex)
+		_ = sm.chain.SendMsg(msg.SenderIndex, chain.MsgBlockNotAvailable, util.MustBytes(&chain.BlockNotAvailableMsg{
+			PeerMsgHeader: chain.PeerMsgHeader{
+				BlockIndex: msg.BlockIndex,
+			},
+		}))
 		return
 	}
 
@@ -74,6 +95,30 @@ func (sm *stateManager) eventGetBlockMsg(msg *chain.GetBlockMsg) {
 	})
 }
 
+// EventBlockNotAvailableMsg is a response of the peer which doesn't have the requested block
+func (sm *stateManager) EventBlockNotAvailableMsg(msg *chain.BlockNotAvailableMsg) {
+	sm.eventBlockNotAvailableMsgCh <- msg
+}
+func (sm *stateManager) eventBlockNotAvailableMsg(msg *chain.BlockNotAvailableMsg) {
+	sm.log.Debugw("EventBlockNotAvailableMsg",
+		"sender index", msg.SenderIndex,
+		"block index", msg.BlockIndex,
+	)
+	if !sm.solidStateValid || msg.BlockIndex != sm.solidState.BlockIndex()+1 {
+		return
+	}
+	if !sm.blockNotAvailable[msg.SenderIndex] {
+		sm.blockNotAvailable[msg.SenderIndex] = true
+		if len(sm.blockNotAvailable) == int(sm.chain.NumPeers())-1 {
+			sm.log.Errorf("block #%d was pruned by all peers. The node can only sync by importing a snapshot of the chain",
+				msg.BlockIndex)
+		}
+	}
+	// the block is requested from the next peer immediately
+	sm.syncMessageDeadline = time.Now()
+	sm.takeAction()
+}
+
 // EventBlockHeaderMsg
 func (sm *stateManager) EventBlockHeaderMsg(msg *chain.BlockHeaderMsg) {
 	sm.eventBlockHeaderMsgCh <- msg
@@ -167,6 +212,12 @@ func (sm *stateManager) eventStateTransactionMsg(msg *chain.StateTransactionMsg)
 		return
 	}
 
+	if *msg.Transaction.MustProperties().MustStateColor() != *sm.chain.Color() {
+		// the chain ID claimed by the state section is not backed by the chain token
+		sm.log.Warnf("EventStateTransactionMsg: wrong state color in tx %s", msg.ID().String())
+		return
+	}
+
 	vh := stateBlock.StateHash()
 	sm.log.Debugw("EventStateTransactionMsg",
 		"txid", msg.ID().String(),
@@ -230,5 +281,8 @@ func (sm *stateManager) EventTimerMsg(msg chain.TimerTick) {
 	}
 }
 func (sm *stateManager) eventTimerMsg(msg chain.TimerTick) {
+	if sm.solidState != nil {
+		metrics.SetStateIndices(sm.chain.ID().String(), sm.solidState.BlockIndex(), sm.largestEvidencedStateIndex)
+	}
 	sm.takeAction()
 }
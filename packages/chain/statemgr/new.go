@@ -12,11 +12,19 @@ import (
 	"github.com/iotaledger/hive.go/logger"
 	"github.com/iotaledger/wasp/packages/chain"
 	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/parameters"
 	"github.com/iotaledger/wasp/packages/sctransaction"
 	"github.com/iotaledger/wasp/packages/state"
 	"github.com/iotaledger/wasp/packages/util"
 )
 
+// maximum number of committed blocks waiting to be published with EventBlockCommitted
+const committedBlocksQueueSize = 100
+
+// the state index of the peer is taken into account by pruning for this period after it was evidenced.
+// The syncing peer requests blocks much more often
+const peerStateIndexTimeout = 1 * time.Minute
+
 type stateManager struct {
 	chain chain.Chain
 
@@ -62,6 +70,16 @@ type stateManager struct {
 	// for the pseudo-random sequence of peers
 	permutation *util.Permutation16
 
+	// which old blocks are deleted from the db
+	pruning *state.PruningParams
+
+	// solid state indices of peers, evidenced by their pings and block requests.
+	// Blocks after the lowest of them are not pruned, because the peer needs them to sync
+	peerStateIndices []peerStateIndex
+
+	// peers which don't have the block after the solid state
+	blockNotAvailable map[uint16]bool
+
 	// logger
 	log *logger.Logger
 
@@ -69,12 +87,29 @@ type stateManager struct {
 	evidenceStateIndexCh         chan uint32
 	eventStateIndexPingPongMsgCh chan *chain.StateIndexPingPongMsg
 	eventGetBlockMsgCh           chan *chain.GetBlockMsg
+	eventBlockNotAvailableMsgCh  chan *chain.BlockNotAvailableMsg
 	eventBlockHeaderMsgCh        chan *chain.BlockHeaderMsg
 	eventStateUpdateMsgCh        chan *chain.StateUpdateMsg
 	eventStateTransactionMsgCh   chan *chain.StateTransactionMsg
 	eventPendingBlockMsgCh       chan chain.PendingBlockMsg
 	eventTimerMsgCh              chan chain.TimerTick
 	closeCh                      chan bool
+
+	// old blocks are pruned by a separate goroutine. It receives the lowest state index of syncing peers
+	pruneCh chan uint32
+
+	// committed blocks are published by a separate goroutine
+	committedBlocksCh chan *committedBlock
+}
+
+type peerStateIndex struct {
+	index     uint32
+	evidenced time.Time
+}
+
+type committedBlock struct {
+	stateHash hashing.HashValue
+	block     state.Block
 }
 
 type syncedBatch struct {
@@ -99,22 +134,38 @@ func New(c chain.Chain, log *logger.Logger) chain.StateManager {
 		pingPong:                     make([]bool, c.Size()),
 		pendingBlocks:                make(map[hashing.HashValue]*pendingBlock),
 		permutation:                  util.NewPermutation16(c.NumPeers(), nil),
+		pruning:                      pruningParams(),
+		peerStateIndices:             make([]peerStateIndex, c.NumPeers()),
+		blockNotAvailable:            make(map[uint16]bool),
 		log:                          log.Named("s"),
 		evidenceStateIndexCh:         make(chan uint32),
 		eventStateIndexPingPongMsgCh: make(chan *chain.StateIndexPingPongMsg),
 		eventGetBlockMsgCh:           make(chan *chain.GetBlockMsg),
+		eventBlockNotAvailableMsgCh:  make(chan *chain.BlockNotAvailableMsg),
 		eventBlockHeaderMsgCh:        make(chan *chain.BlockHeaderMsg),
 		eventStateUpdateMsgCh:        make(chan *chain.StateUpdateMsg),
 		eventStateTransactionMsgCh:   make(chan *chain.StateTransactionMsg),
 		eventPendingBlockMsgCh:       make(chan chain.PendingBlockMsg),
 		eventTimerMsgCh:              make(chan chain.TimerTick),
 		closeCh:                      make(chan bool),
+		pruneCh:                      make(chan uint32, 1),
+		committedBlocksCh:            make(chan *committedBlock, committedBlocksQueueSize),
 	}
 	go ret.initLoadState()
+	go ret.publishCommittedBlocksLoop()
+	go ret.pruneStateLoop()
 
 	return ret
 }
 
+func pruningParams() *state.PruningParams {
+	return &state.PruningParams{
+		KeepBlocks:   uint32(parameters.GetInt(parameters.StatePruningKeepBlocks)),
+		KeepDuration: parameters.GetDuration(parameters.StatePruningKeepDuration),
+		ArchiveDir:   parameters.GetString(parameters.StatePruningArchiveDir),
+	}
+}
+
 func (sm *stateManager) Close() {
 	close(sm.closeCh)
 }
@@ -171,6 +222,10 @@ func (sm *stateManager) recvLoop() {
 			if ok {
 				sm.eventGetBlockMsg(msg)
 			}
+		case msg, ok := <-sm.eventBlockNotAvailableMsgCh:
+			if ok {
+				sm.eventBlockNotAvailableMsg(msg)
+			}
 		case msg, ok := <-sm.eventBlockHeaderMsgCh:
 			if ok {
 				sm.eventBlockHeaderMsg(msg)
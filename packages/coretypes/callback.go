@@ -0,0 +1,78 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package coretypes
+
+import (
+	"bytes"
+
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/util"
+)
+
+// parameters of the return request, which calls the callback entry point of the contract which posted the request
+const (
+	ParamCallbackRequestID = "$$cbreqid$$"
+	ParamCallbackArgs      = "$$cbargs$$"
+	ParamCallbackResults   = "$$cbresults$$"
+	ParamCallbackError     = "$$cberror$$"
+)
+
+// CallbackResult is the result of the request, passed by the target chain to the callback entry point
+// of the contract which posted the request. If the request failed and it was posted with RefundOnFailure,
+// the transfer of the request is returned with the callback
+type CallbackResult struct {
+	// RequestID of the processed request
+	RequestID RequestID
+	// Args are the arguments of the request, so the callback can match the result to the request
+	Args dict.Dict
+	// Results returned by the target entry point. Nil if the request failed
+	Results dict.Dict
+	// Error message if the request failed. Empty if succeeded
+	Error string
+}
+
+// Failed returns true if the request failed
+func (r *CallbackResult) Failed() bool {
+	return r.Error != ""
+}
+
+// Params encodes the result as parameters of the callback entry point
+func (r *CallbackResult) Params() dict.Dict {
+	ret := dict.New()
+	ret.Set(ParamCallbackRequestID, r.RequestID[:])
+	if r.Args != nil {
+		ret.Set(ParamCallbackArgs, util.MustBytes(r.Args))
+	}
+	if r.Results != nil {
+		ret.Set(ParamCallbackResults, util.MustBytes(r.Results))
+	}
+	if r.Error != "" {
+		ret.Set(ParamCallbackError, []byte(r.Error))
+	}
+	return ret
+}
+
+// NewCallbackResultFromParams decodes parameters of the callback entry point
+func NewCallbackResultFromParams(params dict.Dict) (*CallbackResult, error) {
+	ret := &CallbackResult{
+		Args: dict.New(),
+	}
+	var err error
+	if ret.RequestID, err = NewRequestIDFromBytes(params.MustGet(ParamCallbackRequestID)); err != nil {
+		return nil, err
+	}
+	if data := params.MustGet(ParamCallbackArgs); data != nil {
+		if err = ret.Args.Read(bytes.NewReader(data)); err != nil {
+			return nil, err
+		}
+	}
+	if data := params.MustGet(ParamCallbackResults); data != nil {
+		ret.Results = dict.New()
+		if err = ret.Results.Read(bytes.NewReader(data)); err != nil {
+			return nil, err
+		}
+	}
+	ret.Error = string(params.MustGet(ParamCallbackError))
+	return ret, nil
+}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv"
 	"github.com/iotaledger/wasp/packages/kv/dict"
 )
 
@@ -19,6 +20,9 @@ type ContractInterface struct {
 	Description string
 	ProgramHash hashing.HashValue
 	Functions   map[coretypes.Hname]ContractFunctionInterface
+	// PublicStatePrefix opts in to expose the keys of the contract state with the prefix to other contracts
+	// on the chain (see Sandbox.StateOf). Empty by default, i.e. the state is private
+	PublicStatePrefix kv.Key
 }
 
 // ContractFunctionInterface represents entry point interface
@@ -92,6 +96,10 @@ func (i *ContractInterface) GetDescription() string {
 	return i.Description
 }
 
+func (i *ContractInterface) GetPublicStatePrefix() kv.Key {
+	return i.PublicStatePrefix
+}
+
 // Hname caches the value
 func (i *ContractInterface) Hname() coretypes.Hname {
 	if i.hname == 0 {
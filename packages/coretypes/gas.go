@@ -0,0 +1,66 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package coretypes
+
+import "errors"
+
+// Gas is the deterministic measure of the work performed by the VM while processing a request.
+// Each request has a gas budget. When the budget is exhausted, the request fails with ErrOutOfGas
+// and all its state changes are rolled back
+const (
+	// DefaultGasBudget is used when the request does not declare the budget
+	DefaultGasBudget = uint64(1_000_000)
+	// MaxGasBudget is the upper limit of the budget a request can declare
+	MaxGasBudget = uint64(100_000_000)
+
+	// gas costs of sandbox calls
+	GasCall              = uint64(1_000)
+	GasDeployContract    = uint64(50_000)
+	GasStateSet          = uint64(100)
+	GasStateDel          = uint64(100)
+	GasStateGet          = uint64(10)
+	GasPerByte           = uint64(1)
+	GasPostRequest       = uint64(5_000)
+	GasTransferToAddress = uint64(5_000)
+	GasEvent             = uint64(500)
+	// GasWasmHostCall is charged for each call from the Wasm code to the host
+	GasWasmHostCall = uint64(10)
+	// WasmInstructionsPerGas is the number of Wasm instructions executed for one unit of gas
+	WasmInstructionsPerGas = uint64(10)
+
+	// GasPriceUnit is the number of gas units, the gas price of the chain is expressed for.
+	// The gas fee of the request is ceil(gas burned * gas price / GasPriceUnit) fee tokens
+	GasPriceUnit = uint64(1_000)
+)
+
+var ErrOutOfGas = errors.New("out of gas")
+
+// GasMeter accounts the gas burned by the request
+type GasMeter interface {
+	// GasBudget is the gas budget of the current request
+	GasBudget() uint64
+	// GasBurned is the gas consumed by the current request so far
+	GasBurned() uint64
+	// BurnGas consumes gas from the budget of the current request. Panics with ErrOutOfGas if the budget is exhausted
+	BurnGas(gas uint64)
+}
+
+// EffectiveGasBudget returns gas budget adjusted to the default and to the maximum
+func EffectiveGasBudget(budget uint64) uint64 {
+	if budget == 0 {
+		return DefaultGasBudget
+	}
+	if budget > MaxGasBudget {
+		return MaxGasBudget
+	}
+	return budget
+}
+
+// GasFee returns number of fee tokens to be charged for the gas with the gas price
+func GasFee(gas uint64, gasPrice int64) int64 {
+	if gasPrice <= 0 || gas == 0 {
+		return 0
+	}
+	return int64((gas*uint64(gasPrice) + GasPriceUnit - 1) / GasPriceUnit)
+}
@@ -6,6 +6,7 @@ package coretypes
 import (
 	"fmt"
 
+	"github.com/iotaledger/wasp/packages/kv"
 	"github.com/iotaledger/wasp/packages/kv/dict"
 )
 
@@ -17,6 +18,14 @@ type Processor interface {
 	GetDescription() string
 }
 
+// PublicStateProcessor is implemented by the processor of the contract which opts in to expose part of its state.
+// Keys of the contract state which start with the public prefix can be read by other contracts on the chain
+// with the StateOf sandbox call, without calling the contract
+type PublicStateProcessor interface {
+	// GetPublicStatePrefix returns the public prefix of the state keys. Empty prefix means the state is not public
+	GetPublicStatePrefix() kv.Key
+}
+
 // EntryPoint is an abstract interface by which VM is called by passing
 // the Sandbox interface
 type EntryPoint interface {
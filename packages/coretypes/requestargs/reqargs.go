@@ -49,6 +49,18 @@ func NewOptimizedRequestArgs(d dict.Dict, optSize ...int) (RequestArgs, map[kv.K
 	return ret, retOptimized
 }
 
+// NewOffTangleRequestArgs encodes every argument of the dictionary as a blob reference.
+// Only hashes of the data go into the request transaction, the data itself is delivered to the committee off-tangle
+func NewOffTangleRequestArgs(d dict.Dict) (RequestArgs, map[kv.Key][]byte) {
+	ret := New(nil)
+	retData := make(map[kv.Key][]byte)
+	for k, v := range d {
+		ret.AddAsBlobRef(k, v)
+		retData[k] = v
+	}
+	return ret, retData
+}
+
 // AddEncodeSimple add new ordinary argument. Encodes the key as "normal"
 func (a RequestArgs) AddEncodeSimple(name kv.Key, data []byte) RequestArgs {
 	a["-"+name] = data
@@ -89,6 +101,37 @@ func (a RequestArgs) HasBlobRef() bool {
 	return ret
 }
 
+// MissingBlobs returns hashes of referenced blobs which are not in the blob cache yet
+func (a RequestArgs) MissingBlobs(reg coretypes.BlobCache) ([]hashing.HashValue, error) {
+	ret := make([]hashing.HashValue, 0)
+	var err error
+	(dict.Dict(a)).ForEach(func(key kv.Key, value []byte) bool {
+		if len(key) == 0 || key[0] != '*' {
+			return true
+		}
+		if len(value) < hashing.HashSize {
+			err = fmt.Errorf("wrong request argument '%s'", key)
+			return false
+		}
+		var h hashing.HashValue
+		if h, err = hashing.HashValueFromBytes(value[:hashing.HashSize]); err != nil {
+			return false
+		}
+		var has bool
+		if has, err = reg.HasBlob(h); err != nil {
+			return false
+		}
+		if !has {
+			ret = append(ret, h)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
 func (a RequestArgs) String() string {
 	return (dict.Dict(a)).String()
 }
@@ -110,6 +153,7 @@ func (a RequestArgs) Read(r io.Reader) error {
 //  - if the value is '*' the data is a content reference. First 32 bytes always treated as data hash.
 //    The rest (if any) is a content address. It will be treated by a downloader
 //  - otherwise it is a raw data
+// Encrypted ("stealth") arguments can't be solidified
 func (a RequestArgs) SolidifyRequestArguments(reg coretypes.BlobCache) (dict.Dict, bool, error) {
 	ret := dict.New()
 	ok := true
@@ -123,6 +167,10 @@ func (a RequestArgs) SolidifyRequestArguments(reg coretypes.BlobCache) (dict.Dic
 			err = fmt.Errorf("wrong request argument key '%s'", key)
 			return false
 		}
+		if key == encryptedKey {
+			err = fmt.Errorf("encrypted request arguments must be decrypted before solidification")
+			return false
+		}
 		if d[0] != '*' {
 			ret.Set(kv.Key(d[1:]), value)
 			return true
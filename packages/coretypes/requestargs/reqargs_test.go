@@ -6,6 +6,7 @@ import (
 	"github.com/iotaledger/wasp/packages/dbprovider"
 	"github.com/iotaledger/wasp/packages/hashing"
 	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/packages/kv/kvdecoder"
 	"github.com/iotaledger/wasp/packages/registry"
 	"github.com/iotaledger/wasp/packages/testutil"
@@ -190,3 +191,70 @@ func TestRequestArgumentsDeterminism(t *testing.T) {
 	h2 := hashing.HashData(util.MustBytes(r1))
 	require.EqualValues(t, h1, h2)
 }
+
+func TestRequestArgumentsEncrypted(t *testing.T) {
+	r := New(nil)
+	r.AddEncodeSimple("arg1", []byte("data1"))
+	r.AddAsBlobRef("arg2", []byte("data2"))
+
+	xor := func(data []byte) ([]byte, error) {
+		ret := make([]byte, len(data))
+		for i := range data {
+			ret[i] = data[i] ^ 0x5a
+		}
+		return ret, nil
+	}
+	enc, err := NewEncryptedRequestArgs(r, xor)
+	require.NoError(t, err)
+	require.True(t, enc.IsEncrypted())
+	require.False(t, r.IsEncrypted())
+	require.Len(t, enc, 1)
+
+	_, _, err = enc.SolidifyRequestArguments(nil)
+	require.Error(t, err)
+
+	dec, err := enc.Decrypt(xor)
+	require.NoError(t, err)
+	require.EqualValues(t, r, dec)
+
+	_, err = r.Decrypt(xor)
+	require.Error(t, err)
+}
+
+func TestRequestArgumentsOffTangle(t *testing.T) {
+	d := dict.New()
+	d.Set("arg1", []byte("data1"))
+	d.Set("arg2", []byte("data2"))
+	r, data := NewOffTangleRequestArgs(d)
+
+	require.Len(t, r, 2)
+	require.Len(t, data, 2)
+	require.True(t, r.HasBlobRef())
+
+	log := testutil.NewLogger(t)
+	db := dbprovider.NewInMemoryDBProvider(log)
+	reg := registry.NewRegistry(nil, log, db)
+
+	missing, err := r.MissingBlobs(reg)
+	require.NoError(t, err)
+	require.Len(t, missing, 2)
+
+	_, err = reg.PutBlob([]byte("data1"))
+	require.NoError(t, err)
+
+	missing, err = r.MissingBlobs(reg)
+	require.NoError(t, err)
+	require.EqualValues(t, []hashing.HashValue{hashing.HashStrings("data2")}, missing)
+
+	_, ok, err := r.SolidifyRequestArguments(reg)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	_, err = reg.PutBlob([]byte("data2"))
+	require.NoError(t, err)
+
+	solid, ok, err := r.SolidifyRequestArguments(reg)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.EqualValues(t, d, solid)
+}
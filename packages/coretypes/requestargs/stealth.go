@@ -0,0 +1,54 @@
+package requestargs
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/iotaledger/wasp/packages/kv"
+)
+
+// encryptedKey is the only key of "stealth" request arguments. Its value is
+// the encrypted encoding of the original request arguments
+const encryptedKey = kv.Key("#")
+
+// NewEncryptedRequestArgs encrypts request arguments, for example for the committee of the chain.
+// The arguments may contain blob references, which are solidified after decryption
+func NewEncryptedRequestArgs(args RequestArgs, encrypt func([]byte) ([]byte, error)) (RequestArgs, error) {
+	var buf bytes.Buffer
+	if err := args.Write(&buf); err != nil {
+		return nil, err
+	}
+	data, err := encrypt(buf.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	ret := New(nil)
+	ret[encryptedKey] = data
+	return ret, nil
+}
+
+// IsEncrypted returns if request arguments are encrypted and must be decrypted before solidification
+func (a RequestArgs) IsEncrypted() bool {
+	_, ok := a[encryptedKey]
+	return ok
+}
+
+// Decrypt returns decrypted request arguments
+func (a RequestArgs) Decrypt(decrypt func([]byte) ([]byte, error)) (RequestArgs, error) {
+	data, ok := a[encryptedKey]
+	if !ok || len(a) != 1 {
+		return nil, errors.New("request arguments are not encrypted")
+	}
+	plain, err := decrypt(data)
+	if err != nil {
+		return nil, err
+	}
+	ret := New(nil)
+	if err := ret.Read(bytes.NewReader(plain)); err != nil {
+		return nil, err
+	}
+	if ret.IsEncrypted() {
+		return nil, errors.New("request arguments are encrypted twice")
+	}
+	return ret, nil
+}
@@ -26,6 +26,10 @@ type Sandbox interface {
 	Params() dict.Dict
 	// State k/v store of the current call (in the context of the smart contract)
 	State() kv.KVStore
+	// StateOf read-only access to the public part of the state of another contract on the chain.
+	// Returns nil if the contract does not exist or does not declare the public state prefix.
+	// Reading keys outside of the public prefix panics
+	StateOf(contractHname Hname) kv.KVStoreReader
 	// DeployContract deploys contract on the same chain. 'initParams' are passed to the 'init' entry point
 	DeployContract(programHash hashing.HashValue, name string, description string, initParams dict.Dict) error
 	// Call calls the entry point of the contract with parameters and transfer.
@@ -52,6 +56,11 @@ type Sandbox interface {
 	Log() LogInterface
 	// Event publishes "vmmsg" message through Publisher on nanomsg. It also logs locally, but it is not the same thing
 	Event(msg string)
+	// TypedEvent stores the structured event with the payload in the event log and publishes it.
+	// Event can be queried by any of its topics, e.g. bytes of AgentID, Hname or Color
+	TypedEvent(name string, payload dict.Dict, topics ...[]byte)
+	// GasMeter of the current request
+	GasMeter
 	//
 	Utils() Utils
 }
@@ -63,4 +72,12 @@ type PostRequestParams struct {
 	TimeLock         uint32
 	Params           dict.Dict
 	Transfer         ColoredBalances
+	// GasBudget of the posted request. 0 means DefaultGasBudget
+	GasBudget uint64
+	// Callback is the entry point of the posting contract. When the request is processed, the target chain
+	// calls it with the result of the request (see CallbackResult) by the return request. 0 means no callback
+	Callback Hname
+	// RefundOnFailure if true and the request fails, the transfer is returned to the posting contract by the
+	// return request: to the Callback entry point if specified, otherwise to the account of the posting contract
+	RefundOnFailure bool
 }
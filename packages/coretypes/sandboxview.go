@@ -22,6 +22,10 @@ type SandboxView interface {
 	Params() dict.Dict
 	// State immutable k/v store of the current call (in the context of the smart contract)
 	State() kv.KVStoreReader
+	// StateOf read-only access to the public part of the state of another contract on the chain.
+	// Returns nil if the contract does not exist or does not declare the public state prefix.
+	// Reading keys outside of the public prefix panics
+	StateOf(contractHname Hname) kv.KVStoreReader
 	// Call calls another contract. Only calls view entry points
 	Call(contractHname Hname, entryPoint Hname, params dict.Dict) (dict.Dict, error)
 	// Balances is colored balances owned by the contract
@@ -104,6 +104,7 @@ const tplChainContract = `
 				<dt>Hname</dt><dd><tt>{{.Hname}}</tt></dd>
 				<dt>Description</dt><dd><tt>{{trim 50 $c.Description}}</tt></dd>
 				<dt>Program hash</dt><dd><tt>{{$c.ProgramHash.String}}</tt></dd>
+				<dt>Status</dt><dd>{{if $c.Paused}}paused{{else}}active{{end}}</dd>
 				{{if $c.HasCreator}}<dt>Creator</dt><dd>{{ template "agentid" (args $chainid $c.Creator) }}</dd>{{end}}
 				<dt>Owner fee</dt><dd>
 					{{- if $c.OwnerFee -}}
@@ -36,8 +36,9 @@ func NewInMemoryDBProvider(log *logger.Logger) *DBProvider {
 	return newDBProvider(db, log)
 }
 
-func NewPersistentDBProvider(dbDir string, log *logger.Logger) *DBProvider {
-	db, err := database.NewDB(dbDir)
+// NewPersistentDBProvider opens the persistent database with the engine: DBEngineBadger, DBEngineBolt or DBEnginePebble
+func NewPersistentDBProvider(engine string, dbDir string, log *logger.Logger) *DBProvider {
+	db, err := NewDB(engine, dbDir)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -61,6 +62,11 @@ func (dbp *DBProvider) GetPartition(chainID *coretypes.ChainID) kvstore.KVStore
 	return dbp.partitions[*chainID]
 }
 
+// GetStore returns the whole KVStore. Keys of partitions are prefixed with the chain ID
+func (dbp *DBProvider) GetStore() kvstore.KVStore {
+	return dbp.store
+}
+
 func (dbp *DBProvider) GetRegistryPartition() kvstore.KVStore {
 	return dbp.GetPartition(&coretypes.NilChainID)
 }
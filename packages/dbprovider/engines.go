@@ -0,0 +1,139 @@
+package dbprovider
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/iotaledger/goshimmer/packages/database"
+	"github.com/iotaledger/hive.go/kvstore"
+	boltstore "github.com/iotaledger/hive.go/kvstore/bolt"
+	pebblestore "github.com/iotaledger/hive.go/kvstore/pebble"
+	"go.etcd.io/bbolt"
+)
+
+// names of the database engines of the persistent database
+const (
+	DBEngineBadger = "badger"
+	DBEngineBolt   = "bolt"
+	DBEnginePebble = "pebble"
+)
+
+const boltFileName = "wasp.db"
+
+// engineFileName is the file in the database directory which keeps the name of the engine the database was created with
+const engineFileName = "ENGINE"
+
+// NewDB opens the persistent database with the engine in the directory.
+// The engine can't be changed for the existing database
+func NewDB(engine string, dbDir string) (database.DB, error) {
+	if engine != DBEngineBadger && engine != DBEngineBolt && engine != DBEnginePebble {
+		return nil, errUnknownEngine(engine)
+	}
+	existing, err := dbEngine(dbDir)
+	if err != nil {
+		return nil, err
+	}
+	if existing != "" && existing != engine {
+		return nil, fmt.Errorf("the database in %s was created with the engine '%s' and can't be opened with '%s'",
+			dbDir, existing, engine)
+	}
+	db, err := openDB(engine, dbDir)
+	if err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(filepath.Join(dbDir, engineFileName), []byte(engine), 0644); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// dbEngine returns the engine of the database in the directory or an empty string if there is no database.
+// The database without the engine file was created before the engine was configurable, i.e. with badger
+func dbEngine(dbDir string) (string, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dbDir, engineFileName))
+	if err == nil {
+		return string(data), nil
+	}
+	if !os.IsNotExist(err) {
+		return "", err
+	}
+	files, err := ioutil.ReadDir(dbDir)
+	if os.IsNotExist(err) || len(files) == 0 {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return DBEngineBadger, nil
+}
+
+func openDB(engine string, dbDir string) (database.DB, error) {
+	switch engine {
+	case DBEngineBadger:
+		return database.NewDB(dbDir)
+	case DBEngineBolt:
+		db, err := boltstore.CreateDB(dbDir, boltFileName)
+		if err != nil {
+			return nil, err
+		}
+		return &boltDB{DB: db}, nil
+	case DBEnginePebble:
+		db, err := pebblestore.CreateDB(dbDir)
+		if err != nil {
+			return nil, err
+		}
+		return &pebbleDB{DB: db}, nil
+	}
+	return nil, errUnknownEngine(engine)
+}
+
+func errUnknownEngine(engine string) error {
+	return fmt.Errorf("unknown database engine '%s'. Supported engines: %s, %s, %s",
+		engine, DBEngineBadger, DBEngineBolt, DBEnginePebble)
+}
+
+type boltDB struct {
+	*bbolt.DB
+}
+
+func (db *boltDB) NewStore() kvstore.KVStore {
+	return boltstore.New(db.DB)
+}
+
+func (db *boltDB) Close() error {
+	return db.DB.Close()
+}
+
+// RequiresGC is false: bolt reuses the pages of deleted items
+func (db *boltDB) RequiresGC() bool {
+	return false
+}
+
+func (db *boltDB) GC() error {
+	return nil
+}
+
+type pebbleDB struct {
+	*pebble.DB
+}
+
+func (db *pebbleDB) NewStore() kvstore.KVStore {
+	return pebblestore.New(db.DB)
+}
+
+func (db *pebbleDB) Close() error {
+	return db.DB.Close()
+}
+
+// RequiresGC is false: pebble cleans deleted items during the compaction
+func (db *pebbleDB) RequiresGC() bool {
+	return false
+}
+
+func (db *pebbleDB) GC() error {
+	return nil
+}
@@ -0,0 +1,94 @@
+package dbprovider
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngines(t *testing.T) {
+	for _, engine := range []string{DBEngineBadger, DBEngineBolt, DBEnginePebble} {
+		t.Run(engine, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "wasp-"+engine)
+			require.NoError(t, err)
+			defer os.RemoveAll(dir)
+
+			db, err := NewDB(engine, dir)
+			require.NoError(t, err)
+			store := db.NewStore()
+
+			require.NoError(t, store.Set([]byte("key1"), []byte("value1")))
+			batch := store.Batched()
+			require.NoError(t, batch.Set([]byte("key2"), []byte("value2")))
+			require.NoError(t, batch.Set([]byte("other"), []byte("value3")))
+			require.NoError(t, batch.Commit())
+
+			v, err := store.Get([]byte("key2"))
+			require.NoError(t, err)
+			require.EqualValues(t, []byte("value2"), v)
+
+			n := 0
+			err = store.IterateKeys([]byte("key"), func(key kvstore.Key) bool {
+				n++
+				return true
+			})
+			require.NoError(t, err)
+			require.EqualValues(t, 2, n)
+
+			require.NoError(t, store.Delete([]byte("key1")))
+			_, err = store.Get([]byte("key1"))
+			require.Equal(t, kvstore.ErrKeyNotFound, err)
+			require.NoError(t, db.Close())
+		})
+	}
+}
+
+func TestUnknownEngine(t *testing.T) {
+	_, err := NewDB("leveldb", os.TempDir())
+	require.Error(t, err)
+}
+
+func TestEngineMismatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wasp-engine")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	db, err := NewDB(DBEngineBolt, dir)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	_, err = NewDB(DBEnginePebble, dir)
+	require.Error(t, err)
+	_, err = NewDB(DBEngineBadger, dir)
+	require.Error(t, err)
+
+	db, err = NewDB(DBEngineBolt, dir)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+}
+
+func TestEngineOfOldDatabase(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wasp-engine")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// the database created before the engine was persisted is a badger database
+	db, err := NewDB(DBEngineBadger, dir)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+	require.NoError(t, os.Remove(filepath.Join(dir, engineFileName)))
+
+	_, err = NewDB(DBEngineBolt, dir)
+	require.Error(t, err)
+
+	db, err = NewDB(DBEngineBadger, dir)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+	engine, err := dbEngine(dir)
+	require.NoError(t, err)
+	require.EqualValues(t, DBEngineBadger, engine)
+}
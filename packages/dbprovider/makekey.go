@@ -15,6 +15,13 @@ const (
 	ObjectTypeNodeIdentity
 	ObjectTypeBlobCache
 	ObjectTypeBlobCacheTTL
+	ObjectTypeFirstRetainedBlockIndex
+	ObjectTypeTrustedPeer
+	ObjectTypeMerkleNode
+	ObjectTypeSnapshotState
+	ObjectTypeSnapshotStateVariable
+	ObjectTypeSnapshotMerkleNode
+	ObjectTypeBlobCacheStats
 )
 
 // MakeKey makes key within the partition. It consists to one byte for object type
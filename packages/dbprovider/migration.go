@@ -0,0 +1,54 @@
+package dbprovider
+
+import (
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/wasp/packages/coretypes"
+)
+
+// Migration transforms the data stored with the previous version of the database schema
+// to the data of the schema Version
+type Migration struct {
+	// Version is the version of the database schema after the migration
+	Version     byte
+	Description string
+	// Migrate reads the data of the previous version from the whole store and writes the changes to the batch.
+	// The batch is committed together with the new version, so the migration is applied atomically
+	Migrate func(store kvstore.KVStore, batch kvstore.BatchedMutations) error
+}
+
+// TransformObjects returns the migration function which transforms each object of the type
+// in partitions of all chains, for example ObjectTypeStateVariable or ObjectTypeStateUpdateBatch.
+// The function f receives the key without the chain ID and the object type prefix and returns the new key
+// and value of the object. The object is deleted if the returned value is nil
+func TransformObjects(objType byte, f func(chainID coretypes.ChainID, key []byte, value []byte) ([]byte, []byte, error)) func(kvstore.KVStore, kvstore.BatchedMutations) error {
+	return func(store kvstore.KVStore, batch kvstore.BatchedMutations) error {
+		var err error
+		errIter := store.Iterate(kvstore.EmptyPrefix, func(key kvstore.Key, value kvstore.Value) bool {
+			if len(key) <= coretypes.ChainIDLength || key[coretypes.ChainIDLength] != objType {
+				return true
+			}
+			var chainID coretypes.ChainID
+			copy(chainID[:], key[:coretypes.ChainIDLength])
+			var newKey, newValue []byte
+			if newKey, newValue, err = f(chainID, key[coretypes.ChainIDLength+1:], value); err != nil {
+				return false
+			}
+			newKey = append(append(chainID[:], objType), newKey...)
+			if newValue == nil || string(newKey) != string(key) {
+				if err = batch.Delete(key); err != nil {
+					return false
+				}
+			}
+			if newValue != nil {
+				if err = batch.Set(newKey, newValue); err != nil {
+					return false
+				}
+			}
+			return true
+		})
+		if errIter != nil {
+			return errIter
+		}
+		return err
+	}
+}
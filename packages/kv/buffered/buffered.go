@@ -127,6 +127,9 @@ func (b *bufferedKVStore) Get(key kv.Key) ([]byte, error) {
 	if mut != nil {
 		return mut.Value(), nil
 	}
+	if b.db == nil {
+		return nil, nil
+	}
 	v, err := b.db.Get(kvstore.Key(key))
 	if err == kvstore.ErrKeyNotFound {
 		return nil, nil
@@ -143,6 +146,9 @@ func (b *bufferedKVStore) Has(key kv.Key) (bool, error) {
 	if mut != nil {
 		return mut.Value() != nil, nil
 	}
+	if b.db == nil {
+		return false, nil
+	}
 	v, err := b.db.Has(kvstore.Key(key))
 	return v, asDBError(err)
 }
@@ -153,7 +159,7 @@ func (b *bufferedKVStore) MustHas(key kv.Key) bool {
 
 func (b *bufferedKVStore) Iterate(prefix kv.Key, f func(key kv.Key, value []byte) bool) error {
 	seen, done := b.mutations.IterateValues(prefix, f)
-	if done {
+	if done || b.db == nil {
 		return nil
 	}
 	return b.db.Iterate([]byte(prefix), func(key kvstore.Key, value kvstore.Value) bool {
@@ -174,7 +180,7 @@ func (b *bufferedKVStore) IterateKeys(prefix kv.Key, f func(key kv.Key) bool) er
 	seen, done := b.mutations.IterateValues(prefix, func(key kv.Key, value []byte) bool {
 		return f(key)
 	})
-	if done {
+	if done || b.db == nil {
 		return nil
 	}
 	return b.db.IterateKeys([]byte(prefix), func(key kvstore.Key) bool {
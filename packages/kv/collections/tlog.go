@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/iotaledger/wasp/packages/kv"
 	"github.com/iotaledger/wasp/packages/util"
@@ -75,6 +76,11 @@ func (l *ImmutableTimestampedLog) getElemKey(idx uint32) kv.Key {
 	return kv.Key(buf.Bytes())
 }
 
+// IsElemKey checks if the key is the key of a record of the log
+func (l *ImmutableTimestampedLog) IsElemKey(key kv.Key) bool {
+	return len(key) == len(l.name)+1+4 && strings.HasPrefix(string(key), string(l.name)) && key[len(l.name)] == tslElemKeyCode
+}
+
 func (l *TimestampedLog) setSize(size uint32) {
 	if size == 0 {
 		l.kvw.Del(l.getSizeKey())
@@ -0,0 +1,139 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+// Package metrics defines Prometheus metrics of the Wasp node.
+// Metrics are updated by the components of the node. They are exported by the 'metrics' plugin only
+// if it is enabled, otherwise updating them has no effect other than the negligible overhead
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "wasp"
+
+var (
+	consensusStageDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "consensus",
+		Name:      "stage_duration_seconds",
+		Help:      "Time spent by the consensus operator in each stage.",
+		Buckets:   []float64{.01, .05, .1, .5, 1, 2.5, 5, 10, 30, 60, 120},
+	}, []string{"chain", "stage"})
+
+	backlogSize = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "consensus",
+		Name:      "backlog_size",
+		Help:      "Number of requests in the backlog of the consensus operator.",
+	}, []string{"chain"})
+
+	vmRunDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "vm",
+		Name:      "batch_run_duration_seconds",
+		Help:      "Time spent by the VM running a batch of requests.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"chain"})
+
+	vmRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "vm",
+		Name:      "requests_total",
+		Help:      "Number of requests run by the VM.",
+	}, []string{"chain"})
+
+	solidStateIndex = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "statemgr",
+		Name:      "solid_state_index",
+		Help:      "Block index of the solid state of the chain.",
+	}, []string{"chain"})
+
+	stateSyncLag = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "statemgr",
+		Name:      "sync_lag_blocks",
+		Help:      "Number of blocks the solid state is behind the largest state index evidenced by peers.",
+	}, []string{"chain"})
+
+	nodeconnReconnects = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "nodeconn",
+		Name:      "reconnects_total",
+		Help:      "Number of times the connection with the Goshimmer node was lost and reconnect was scheduled.",
+	})
+
+	webapiRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "webapi",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of web API requests.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "path", "status"})
+)
+
+// Collectors returns all metrics updated by the components of the node
+func Collectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		consensusStageDuration,
+		backlogSize,
+		vmRunDuration,
+		vmRequests,
+		solidStateIndex,
+		stateSyncLag,
+		nodeconnReconnects,
+		webapiRequestDuration,
+	}
+}
+
+// ObserveConsensusStage records the time spent by the consensus operator in the stage
+func ObserveConsensusStage(chainID string, stage string, duration time.Duration) {
+	consensusStageDuration.WithLabelValues(chainID, stage).Observe(duration.Seconds())
+}
+
+// SetBacklogSize records the current number of requests in the backlog of the chain
+func SetBacklogSize(chainID string, size int) {
+	backlogSize.WithLabelValues(chainID).Set(float64(size))
+}
+
+// ObserveVMRun records the run of the batch of requests by the VM
+func ObserveVMRun(chainID string, numRequests int, duration time.Duration) {
+	vmRunDuration.WithLabelValues(chainID).Observe(duration.Seconds())
+	vmRequests.WithLabelValues(chainID).Add(float64(numRequests))
+}
+
+// SetStateIndices records the solid state index of the chain and how far it is behind the evidenced state index
+func SetStateIndices(chainID string, solidIndex uint32, largestEvidencedIndex uint32) {
+	solidStateIndex.WithLabelValues(chainID).Set(float64(solidIndex))
+	lag := 0.0
+	if largestEvidencedIndex > solidIndex {
+		lag = float64(largestEvidencedIndex - solidIndex)
+	}
+	stateSyncLag.WithLabelValues(chainID).Set(lag)
+}
+
+// IncNodeconnReconnects counts reconnection with the Goshimmer node
+func IncNodeconnReconnects() {
+	nodeconnReconnects.Inc()
+}
+
+// ObserveWebAPIRequest records the latency of the web API request
+func ObserveWebAPIRequest(method string, path string, status int, duration time.Duration) {
+	webapiRequestDuration.WithLabelValues(method, path, statusLabel(status)).Observe(duration.Seconds())
+}
+
+func statusLabel(status int) string {
+	switch {
+	case status >= 500:
+		return "5xx"
+	case status >= 400:
+		return "4xx"
+	case status >= 300:
+		return "3xx"
+	default:
+		return "2xx"
+	}
+}
@@ -0,0 +1,299 @@
+// Package mqttbroker implements a minimal embedded MQTT 3.1.1 broker which is used to publish Wasp events.
+// Only the node itself publishes messages. Subscriptions are always granted with QoS 0 and
+// messages published by connected clients are discarded
+package mqttbroker
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eclipse/paho.mqtt.golang/packets"
+)
+
+const clientQueueSize = 100
+
+// Broker keeps track of connected clients, their subscriptions and retained messages
+type Broker struct {
+	mutex    sync.RWMutex
+	clients  map[*client]struct{}
+	retained map[string][]byte
+	closed   bool
+}
+
+type client struct {
+	conn          net.Conn
+	out           chan packets.ControlPacket
+	subscriptions map[string]struct{}
+	done          chan struct{}
+	closeOnce     sync.Once
+}
+
+func New() *Broker {
+	return &Broker{
+		clients:  make(map[*client]struct{}),
+		retained: make(map[string][]byte),
+	}
+}
+
+// Serve accepts connections on the listener until it is closed
+func (b *Broker) Serve(listener net.Listener) error {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			b.mutex.RLock()
+			closed := b.closed
+			b.mutex.RUnlock()
+			if closed {
+				return nil
+			}
+			return err
+		}
+		go b.handleConnection(conn)
+	}
+}
+
+// Close disconnects all clients. Serve returns after the listener is closed by the caller
+func (b *Broker) Close() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.closed = true
+	for c := range b.clients {
+		c.close()
+	}
+	b.clients = make(map[*client]struct{})
+}
+
+// Publish sends the message to all clients subscribed to the topic.
+// A retained message replaces the previous retained message of the topic and is
+// delivered to clients which subscribe later
+func (b *Broker) Publish(topic string, payload []byte, retain bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if retain {
+		b.retained[topic] = payload
+	}
+	for c := range b.clients {
+		for filter := range c.subscriptions {
+			if MatchTopic(filter, topic) {
+				c.send(newPublishPacket(topic, payload, false))
+				break
+			}
+		}
+	}
+}
+
+// Retained returns the retained message of the topic, if any
+func (b *Broker) Retained(topic string) ([]byte, bool) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	ret, ok := b.retained[topic]
+	return ret, ok
+}
+
+func (b *Broker) handleConnection(conn net.Conn) {
+	c := &client{
+		conn:          conn,
+		out:           make(chan packets.ControlPacket, clientQueueSize),
+		subscriptions: make(map[string]struct{}),
+		done:          make(chan struct{}),
+	}
+	defer c.close()
+
+	keepalive, err := b.handshake(c)
+	if err != nil {
+		return
+	}
+	go c.writeLoop()
+
+	if !b.addClient(c) {
+		return
+	}
+	defer b.removeClient(c)
+
+	for {
+		if keepalive > 0 {
+			_ = conn.SetReadDeadline(time.Now().Add(keepalive * 3 / 2))
+		}
+		cp, err := packets.ReadPacket(conn)
+		if err != nil {
+			return
+		}
+		switch p := cp.(type) {
+		case *packets.SubscribePacket:
+			b.subscribe(c, p)
+		case *packets.UnsubscribePacket:
+			b.unsubscribe(c, p)
+		case *packets.PublishPacket:
+			if p.Qos > 0 {
+				ack := packets.NewControlPacket(packets.Puback).(*packets.PubackPacket)
+				ack.MessageID = p.MessageID
+				c.send(ack)
+			}
+		case *packets.PingreqPacket:
+			c.send(packets.NewControlPacket(packets.Pingresp))
+		case *packets.DisconnectPacket:
+			return
+		default:
+			// unexpected packet type: protocol violation
+			return
+		}
+	}
+}
+
+// handshake reads the CONNECT packet and responds with CONNACK synchronously
+func (b *Broker) handshake(c *client) (time.Duration, error) {
+	_ = c.conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+	cp, err := packets.ReadPacket(c.conn)
+	if err != nil {
+		return 0, err
+	}
+	connect, ok := cp.(*packets.ConnectPacket)
+	if !ok {
+		return 0, errors.New("mqttbroker: expected CONNECT packet")
+	}
+	ack := packets.NewControlPacket(packets.Connack).(*packets.ConnackPacket)
+	ack.ReturnCode = connect.Validate()
+	if err := ack.Write(c.conn); err != nil {
+		return 0, err
+	}
+	if ack.ReturnCode != packets.Accepted {
+		return 0, packets.ConnErrors[ack.ReturnCode]
+	}
+	_ = c.conn.SetReadDeadline(time.Time{})
+	return time.Duration(connect.Keepalive) * time.Second, nil
+}
+
+func (b *Broker) addClient(c *client) bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.closed {
+		return false
+	}
+	b.clients[c] = struct{}{}
+	return true
+}
+
+func (b *Broker) removeClient(c *client) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	delete(b.clients, c)
+}
+
+func (b *Broker) subscribe(c *client, p *packets.SubscribePacket) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	ack := packets.NewControlPacket(packets.Suback).(*packets.SubackPacket)
+	ack.MessageID = p.MessageID
+	ack.ReturnCodes = make([]byte, len(p.Topics))
+	for i, filter := range p.Topics {
+		if !validFilter(filter) {
+			ack.ReturnCodes[i] = 0x80
+			continue
+		}
+		c.subscriptions[filter] = struct{}{}
+	}
+	c.send(ack)
+
+	for i, filter := range p.Topics {
+		if ack.ReturnCodes[i] != 0 {
+			continue
+		}
+		for topic, payload := range b.retained {
+			if MatchTopic(filter, topic) {
+				c.send(newPublishPacket(topic, payload, true))
+			}
+		}
+	}
+}
+
+func (b *Broker) unsubscribe(c *client, p *packets.UnsubscribePacket) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for _, filter := range p.Topics {
+		delete(c.subscriptions, filter)
+	}
+	ack := packets.NewControlPacket(packets.Unsuback).(*packets.UnsubackPacket)
+	ack.MessageID = p.MessageID
+	c.send(ack)
+}
+
+// send queues the packet to the client. The packet is dropped if the client does not keep up
+func (c *client) send(p packets.ControlPacket) {
+	select {
+	case c.out <- p:
+	default:
+	}
+}
+
+func (c *client) writeLoop() {
+	for {
+		select {
+		case p := <-c.out:
+			if err := p.Write(c.conn); err != nil {
+				c.close()
+				return
+			}
+		case <-c.done:
+			return
+		}
+	}
+}
+
+func (c *client) close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		c.conn.Close()
+	})
+}
+
+func newPublishPacket(topic string, payload []byte, retain bool) *packets.PublishPacket {
+	ret := packets.NewControlPacket(packets.Publish).(*packets.PublishPacket)
+	ret.TopicName = topic
+	ret.Payload = payload
+	ret.Retain = retain
+	return ret
+}
+
+// MatchTopic checks if the topic matches the subscription filter with '+' and '#' wildcards
+func MatchTopic(filter, topic string) bool {
+	f := strings.Split(filter, "/")
+	t := strings.Split(topic, "/")
+	for i := range f {
+		if f[i] == "#" {
+			return true
+		}
+		if i >= len(t) {
+			return false
+		}
+		if f[i] != "+" && f[i] != t[i] {
+			return false
+		}
+	}
+	return len(f) == len(t)
+}
+
+func validFilter(filter string) bool {
+	if filter == "" {
+		return false
+	}
+	levels := strings.Split(filter, "/")
+	for i, level := range levels {
+		if strings.Contains(level, "#") && (level != "#" || i != len(levels)-1) {
+			return false
+		}
+		if strings.Contains(level, "+") && level != "+" {
+			return false
+		}
+	}
+	return true
+}
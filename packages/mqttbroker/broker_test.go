@@ -0,0 +1,82 @@
+package mqttbroker
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/iotaledger/wasp/packages/publisher"
+	"github.com/iotaledger/wasp/packages/subscribe"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchTopic(t *testing.T) {
+	assert.True(t, MatchTopic("wasp/a/b/events", "wasp/a/b/events"))
+	assert.True(t, MatchTopic("wasp/+/b/events", "wasp/a/b/events"))
+	assert.True(t, MatchTopic("wasp/#", "wasp/a/b/events"))
+	assert.True(t, MatchTopic("wasp/a/#", "wasp/a"))
+	assert.False(t, MatchTopic("wasp/+", "wasp/a/b"))
+	assert.False(t, MatchTopic("wasp/a/b", "wasp/a"))
+	assert.False(t, MatchTopic("wasp/a/c/events", "wasp/a/b/events"))
+
+	assert.True(t, validFilter("wasp/+/state/#"))
+	assert.False(t, validFilter("wasp/#/state"))
+	assert.False(t, validFilter("wasp/a+"))
+}
+
+func receive(t *testing.T, messages chan *subscribe.MQTTMessage) *subscribe.MQTTMessage {
+	select {
+	case msg := <-messages:
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for MQTT message")
+		return nil
+	}
+}
+
+func TestPublishSubscribe(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	broker := New()
+	go func() { _ = broker.Serve(listener) }()
+	defer func() {
+		broker.Close()
+		listener.Close()
+	}()
+
+	publish := func(msgType string, parts ...string) {
+		for _, msg := range publisher.MQTTMessages(msgType, parts) {
+			broker.Publish(msg.Topic, msg.Payload, msg.Retain)
+		}
+	}
+	publish("state", "chain1", "5", "1", "txid", "hash", "ts")
+
+	messages := make(chan *subscribe.MQTTMessage, 10)
+	done := make(chan bool)
+	defer close(done)
+	err = subscribe.SubscribeMQTT(listener.Addr().String(), messages, done, false,
+		publisher.MQTTStateIndexTopic("+"),
+		publisher.MQTTEventsTopic("chain1", "+"),
+	)
+	require.NoError(t, err)
+
+	msg := receive(t, messages)
+	assert.Equal(t, "wasp/chain1/state/index", msg.Topic)
+	assert.Equal(t, "5", string(msg.Payload))
+	assert.True(t, msg.Retained)
+
+	publish("vmmsg", "chain2", "cafebabe", "ignored")
+	publish("vmmsg", "chain1", "cafebabe", "hello", "world")
+	msg = receive(t, messages)
+	assert.Equal(t, "wasp/chain1/cafebabe/events", msg.Topic)
+	assert.Equal(t, "hello world", string(msg.Payload))
+	assert.False(t, msg.Retained)
+
+	publish("state", "chain1", "6", "1", "txid", "hash", "ts")
+	msg = receive(t, messages)
+	assert.Equal(t, "6", string(msg.Payload))
+	retained, ok := broker.Retained("wasp/chain1/state/index")
+	assert.True(t, ok)
+	assert.Equal(t, "6", string(retained))
+}
@@ -1,6 +1,8 @@
 package parameters
 
 import (
+	"time"
+
 	"github.com/iotaledger/wasp/plugins/config"
 	flag "github.com/spf13/pflag"
 )
@@ -15,10 +17,16 @@ const (
 
 	DatabaseDir      = "database.directory"
 	DatabaseInMemory = "database.inMemory"
+	DatabaseEngine   = "database.engine"
+
+	StatePruningKeepBlocks   = "state.pruning.keepBlocks"
+	StatePruningKeepDuration = "state.pruning.keepDuration"
+	StatePruningArchiveDir   = "state.pruning.archiveDir"
 
-	WebAPIBindAddress    = "webapi.bindAddress"
-	WebAPIAdminWhitelist = "webapi.adminWhitelist"
-	WebAPIAuth           = "webapi.auth"
+	WebAPIBindAddress      = "webapi.bindAddress"
+	WebAPIAdminWhitelist   = "webapi.adminWhitelist"
+	WebAPIAuth             = "webapi.auth"
+	WebAPIMaxPastViewCalls = "webapi.maxPastViewCalls"
 
 	DashboardBindAddress       = "dashboard.bindAddress"
 	DashboardExploreAddressUrl = "dashboard.exploreAddressUrl"
@@ -30,6 +38,10 @@ const (
 	PeeringPort    = "peering.port"
 
 	NanomsgPublisherPort = "nanomsg.port"
+
+	MetricsBindAddress = "metrics.bindAddress"
+
+	MQTTBindAddress = "mqtt.bindAddress"
 )
 
 func InitFlags() {
@@ -42,10 +54,16 @@ func InitFlags() {
 
 	flag.String(DatabaseDir, "waspdb", "path to the database folder")
 	flag.Bool(DatabaseInMemory, false, "whether the database is only kept in memory and not persisted")
+	flag.String(DatabaseEngine, "badger", "engine of the persistent database: badger, bolt or pebble")
+
+	flag.Int(StatePruningKeepBlocks, 0, "number of the last blocks of each chain kept in the database. 0 means no limit")
+	flag.Duration(StatePruningKeepDuration, 0, "age of the oldest block of each chain kept in the database. 0 means no limit")
+	flag.String(StatePruningArchiveDir, "", "directory where pruned blocks are archived. Empty means pruned blocks are not archived")
 
 	flag.String(WebAPIBindAddress, "127.0.0.1:8080", "the bind address for the web API")
 	flag.StringSlice(WebAPIAdminWhitelist, []string{}, "IP whitelist for /adm wndpoints")
 	flag.StringToString(WebAPIAuth, nil, "authentication scheme for web API")
+	flag.Int(WebAPIMaxPastViewCalls, 2, "maximum number of concurrent view calls on past states of chains. 0 disables them")
 
 	flag.String(DashboardBindAddress, "127.0.0.1:7000", "the bind address for the node dashboard")
 	flag.String(DashboardExploreAddressUrl, "", "URL to add as href to addresses in the dashboard [default: <nodeconn.address>:8081/explorer/address]")
@@ -57,6 +75,10 @@ func InitFlags() {
 	flag.String(PeeringMyNetId, "127.0.0.1:4000", "node host address as it is recognized by other peers")
 
 	flag.Int(NanomsgPublisherPort, 5550, "the port for nanomsg even publisher")
+
+	flag.String(MetricsBindAddress, "127.0.0.1:2112", "the bind address for the Prometheus metrics endpoint")
+
+	flag.String(MQTTBindAddress, "127.0.0.1:1883", "the bind address for the embedded MQTT broker")
 }
 
 func GetBool(name string) bool {
@@ -75,6 +97,10 @@ func GetInt(name string) int {
 	return config.Node.Int(name)
 }
 
+func GetDuration(name string) time.Duration {
+	return config.Node.Duration(name)
+}
+
 func GetStringToString(name string) map[string]string {
 	return config.Node.StringMap(name)
 }
@@ -0,0 +1,191 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package peering
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/iotaledger/wasp/packages/hashing"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/util/key"
+)
+
+const (
+	seqNumSize       = 8
+	replayWindowSize = 1024 // The number of the last sequence numbers remembered by the receiver.
+)
+
+// PeerCipher encrypts and authenticates messages exchanged by a pair of peers in one session with AES-256-GCM.
+// The session keys are derived by the Diffie-Hellman key exchange from the ephemeral keys of the session
+// and the node identity keys of both peers, so only the peers which have done the handshake can produce
+// and read the messages, and the messages of other sessions are not accepted.
+// Each direction has its own key, so the message can't be reflected back to its sender.
+// The messages are numbered, a replayed message or a message too old for the replay window is rejected.
+// The messages reordered by the transport within the window are accepted.
+type PeerCipher struct {
+	mutex   *sync.Mutex
+	sendKey cipher.AEAD
+	recvKey cipher.AEAD
+	sendSeq uint64 // The sequence number of the last sent message.
+	recvWin replayWindow
+}
+
+// replayWindow remembers the sequence numbers of the accepted messages,
+// bit i of the bitmap stands for the sequence number last-i
+type replayWindow struct {
+	last   uint64 // The largest accepted sequence number.
+	bitmap [replayWindowSize / 64]uint64
+}
+
+func (w *replayWindow) check(seq uint64) error {
+	if seq == 0 {
+		return errors.New("wrong sequence number 0")
+	}
+	if seq > w.last {
+		return nil
+	}
+	i := w.last - seq
+	if i >= replayWindowSize {
+		return fmt.Errorf("message too old, seq=%d, last=%d", seq, w.last)
+	}
+	if w.bitmap[i/64]&(1<<(i%64)) != 0 {
+		return fmt.Errorf("replayed message, seq=%d", seq)
+	}
+	return nil
+}
+
+// must be called only for the sequence number passing the check
+func (w *replayWindow) accept(seq uint64) {
+	if seq > w.last {
+		w.shift(seq - w.last)
+		w.last = seq
+	}
+	i := w.last - seq
+	w.bitmap[i/64] |= 1 << (i % 64)
+}
+
+func (w *replayWindow) shift(n uint64) {
+	if n >= replayWindowSize {
+		w.bitmap = [replayWindowSize / 64]uint64{}
+		return
+	}
+	words, bits := int(n/64), n%64
+	for i := len(w.bitmap) - 1; i >= 0; i-- {
+		var v uint64
+		if j := i - words; j >= 0 {
+			v = w.bitmap[j] << bits
+			if bits > 0 && j > 0 {
+				v |= w.bitmap[j-1] >> (64 - bits)
+			}
+		}
+		w.bitmap[i] = v
+	}
+}
+
+// NewPeerCipher derives the cipher of the session from the own key pairs and the public keys of the peer.
+// Both peers derive the same keys.
+func NewPeerCipher(
+	suite kyber.Group,
+	myKeyPair *key.Pair,
+	myEphemeral *key.Pair,
+	peerPubKey kyber.Point,
+	peerEphPubKey kyber.Point,
+) (*PeerCipher, error) {
+	var err error
+	var sharedBin, ephSharedBin, myPubBin, peerPubBin, myEphBin, peerEphBin []byte
+	if sharedBin, err = suite.Point().Mul(myKeyPair.Private, peerPubKey).MarshalBinary(); err != nil {
+		return nil, err
+	}
+	if ephSharedBin, err = suite.Point().Mul(myEphemeral.Private, peerEphPubKey).MarshalBinary(); err != nil {
+		return nil, err
+	}
+	if myPubBin, err = myKeyPair.Public.MarshalBinary(); err != nil {
+		return nil, err
+	}
+	if peerPubBin, err = peerPubKey.MarshalBinary(); err != nil {
+		return nil, err
+	}
+	if myEphBin, err = myEphemeral.Public.MarshalBinary(); err != nil {
+		return nil, err
+	}
+	if peerEphBin, err = peerEphPubKey.MarshalBinary(); err != nil {
+		return nil, err
+	}
+	first, second := sorted(myPubBin, peerPubBin)
+	firstEph, secondEph := sorted(myEphBin, peerEphBin)
+	secret := hashing.HashData([]byte("wasp-peering"), sharedBin, ephSharedBin, first, second, firstEph, secondEph)
+	ret := &PeerCipher{mutex: &sync.Mutex{}}
+	if ret.sendKey, err = newSessionKey(secret, myEphBin); err != nil {
+		return nil, err
+	}
+	if ret.recvKey, err = newSessionKey(secret, peerEphBin); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// the key of the direction is identified by the ephemeral public key of the sender
+func newSessionKey(secret hashing.HashValue, senderEphBin []byte) (cipher.AEAD, error) {
+	sessionKey := hashing.HashData(secret[:], senderEphBin)
+	block, err := aes.NewCipher(sessionKey[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func sorted(a, b []byte) ([]byte, []byte) {
+	if bytes.Compare(a, b) > 0 {
+		return b, a
+	}
+	return a, b
+}
+
+// Seal encrypts the data sent to the peer. The sequence number of the message is prepended to the result.
+// The messages should be sent in the order they are sealed, the receiver tolerates the reordering only within the replay window.
+func (c *PeerCipher) Seal(data []byte) ([]byte, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.sendSeq == ^uint64(0) {
+		return nil, errors.New("session exhausted")
+	}
+	c.sendSeq++
+	seqBin := make([]byte, seqNumSize)
+	binary.BigEndian.PutUint64(seqBin, c.sendSeq)
+	return c.sendKey.Seal(seqBin, nonce(c.sendKey, seqBin), data, nil), nil
+}
+
+// Open decrypts and authenticates the data received from the peer.
+// Each sequence number is accepted only once, and only within the replay window.
+func (c *PeerCipher) Open(data []byte) ([]byte, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if len(data) < seqNumSize+c.recvKey.Overhead() {
+		return nil, errors.New("encrypted message too short")
+	}
+	seqBin := data[:seqNumSize]
+	seq := binary.BigEndian.Uint64(seqBin)
+	if err := c.recvWin.check(seq); err != nil {
+		return nil, err
+	}
+	ret, err := c.recvKey.Open(nil, nonce(c.recvKey, seqBin), data[seqNumSize:], nil)
+	if err != nil {
+		return nil, err
+	}
+	c.recvWin.accept(seq)
+	return ret, nil
+}
+
+// the nonce is the sequence number padded with zeros, it is unique for the key
+func nonce(aead cipher.AEAD, seqBin []byte) []byte {
+	ret := make([]byte, aead.NonceSize())
+	copy(ret[len(ret)-seqNumSize:], seqBin)
+	return ret
+}
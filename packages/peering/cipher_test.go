@@ -0,0 +1,126 @@
+package peering_test
+
+import (
+	"testing"
+
+	"github.com/iotaledger/wasp/packages/peering"
+	"github.com/iotaledger/wasp/packages/testutil"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/util/key"
+)
+
+func TestPeerCipher(t *testing.T) {
+	suite := pairing.NewSuiteBn256()
+	kp1 := key.NewKeyPair(suite)
+	kp2 := key.NewKeyPair(suite)
+	kp3 := key.NewKeyPair(suite)
+	eph1 := key.NewKeyPair(suite)
+	eph2 := key.NewKeyPair(suite)
+
+	c12, err := peering.NewPeerCipher(suite, kp1, eph1, kp2.Public, eph2.Public)
+	require.NoError(t, err)
+	c21, err := peering.NewPeerCipher(suite, kp2, eph2, kp1.Public, eph1.Public)
+	require.NoError(t, err)
+	c31, err := peering.NewPeerCipher(suite, kp3, eph2, kp1.Public, eph1.Public)
+	require.NoError(t, err)
+	// the next session with the same peer
+	c21next, err := peering.NewPeerCipher(suite, kp2, key.NewKeyPair(suite), kp1.Public, eph1.Public)
+	require.NoError(t, err)
+
+	data := []byte("consensus message")
+	sealed, err := c12.Seal(data)
+	require.NoError(t, err)
+	opened, err := c21.Open(sealed)
+	require.NoError(t, err)
+	require.EqualValues(t, data, opened)
+
+	// only the peer can open the message
+	_, err = c31.Open(sealed)
+	require.Error(t, err)
+	// the message of the other session is not accepted
+	_, err = c21next.Open(sealed)
+	require.Error(t, err)
+	// the message can't be reflected back to the sender
+	_, err = c12.Open(sealed)
+	require.Error(t, err)
+	// the message can't be replayed
+	_, err = c21.Open(sealed)
+	require.Error(t, err)
+	// the message can't be tampered
+	sealed4, err := c12.Seal(data)
+	require.NoError(t, err)
+	sealed4[len(sealed4)-1] ^= 1
+	_, err = c21.Open(sealed4)
+	require.Error(t, err)
+	_, err = c21.Open([]byte{1, 2, 3})
+	require.Error(t, err)
+	// the tampered message hasn't advanced the sequence
+	sealed4[len(sealed4)-1] ^= 1
+	_, err = c21.Open(sealed4)
+	require.NoError(t, err)
+}
+
+func TestTrustedPeers(t *testing.T) {
+	suite := pairing.NewSuiteBn256()
+	kp1 := key.NewKeyPair(suite)
+	kp2 := key.NewKeyPair(suite)
+	tnm := testutil.NewTrustedNetworkManager()
+
+	require.Error(t, peering.ValidateTrustedPeer(tnm, kp1.Public, "localhost:4000"))
+	require.Error(t, peering.ValidateTrustedNetIDs(tnm, []string{"localhost:4000", "localhost:4001"}, "localhost:4001"))
+
+	_, err := tnm.TrustPeer(kp1.Public, "localhost:4000")
+	require.NoError(t, err)
+	require.NoError(t, peering.ValidateTrustedPeer(tnm, kp1.Public, "localhost:4000"))
+	require.Error(t, peering.ValidateTrustedPeer(tnm, kp1.Public, "localhost:4002"))
+	require.Error(t, peering.ValidateTrustedPeer(tnm, kp2.Public, "localhost:4000"))
+	require.NoError(t, peering.ValidateTrustedNetIDs(tnm, []string{"localhost:4000", "localhost:4001"}, "localhost:4001"))
+
+	pubKey, err := peering.PubKeyFromString(peering.PubKeyToString(kp1.Public), suite)
+	require.NoError(t, err)
+	require.True(t, pubKey.Equal(kp1.Public))
+
+	_, err = tnm.DistrustPeer(kp1.Public)
+	require.NoError(t, err)
+	require.Error(t, peering.ValidateTrustedPeer(tnm, kp1.Public, "localhost:4000"))
+}
+
+func TestPeerCipherReorder(t *testing.T) {
+	suite := pairing.NewSuiteBn256()
+	kp1 := key.NewKeyPair(suite)
+	kp2 := key.NewKeyPair(suite)
+	eph1 := key.NewKeyPair(suite)
+	eph2 := key.NewKeyPair(suite)
+	c12, err := peering.NewPeerCipher(suite, kp1, eph1, kp2.Public, eph2.Public)
+	require.NoError(t, err)
+	c21, err := peering.NewPeerCipher(suite, kp2, eph2, kp1.Public, eph1.Public)
+	require.NoError(t, err)
+
+	const n = 1500
+	sealed := make([][]byte, n)
+	for i := range sealed {
+		sealed[i], err = c12.Seal([]byte{byte(i)})
+		require.NoError(t, err)
+	}
+	// the large message is overtaken by the later ones
+	for _, i := range []int{1, 2, 0, 70, 3, 69, 130, 4, 5} {
+		opened, err := c21.Open(sealed[i])
+		require.NoError(t, err)
+		require.EqualValues(t, []byte{byte(i)}, opened)
+	}
+	// each of them is accepted once
+	for _, i := range []int{0, 1, 70, 130} {
+		_, err = c21.Open(sealed[i])
+		require.Error(t, err)
+	}
+	// the messages older than the replay window are rejected
+	_, err = c21.Open(sealed[n-1])
+	require.NoError(t, err)
+	_, err = c21.Open(sealed[6])
+	require.Error(t, err)
+	_, err = c21.Open(sealed[n-2])
+	require.NoError(t, err)
+	_, err = c21.Open(sealed[n-1])
+	require.Error(t, err)
+}
@@ -0,0 +1,209 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package peering
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/util"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/sign/bls"
+	"go.dedis.ch/kyber/v3/util/key"
+)
+
+// Suite is the cryptographic suite of the peering. The node identity keys
+// are the points of its group, the handshake messages are signed with BLS.
+type Suite interface {
+	pairing.Suite
+	kyber.Group
+}
+
+// HandshakeMsg is exchanged by the peers to establish a session.
+// It carries a fresh ephemeral public key of the sender and is signed with its node identity key.
+// The challenge is the hash of the ephemeral public key of the receiver, the sender responds to.
+type HandshakeMsg struct {
+	SrcNetID  string      // NetID of the sender.
+	DstNetID  string      // NetID of the receiver.
+	PubKey    kyber.Point // Node identity key of the sender.
+	EphPubKey kyber.Point // Ephemeral key of the sender.
+	Challenge []byte      // Hash of the ephemeral key of the receiver, or nil.
+}
+
+// Bytes encodes the message signed with the private key of the sender.
+func (m *HandshakeMsg) Bytes(secKey kyber.Scalar, suite Suite) ([]byte, error) {
+	var err error
+	//
+	// Payload.
+	var payloadBuf bytes.Buffer
+	if err = util.WriteString16(&payloadBuf, m.SrcNetID); err != nil {
+		return nil, err
+	}
+	if err = util.WriteString16(&payloadBuf, m.DstNetID); err != nil {
+		return nil, err
+	}
+	if err = util.WriteMarshaled(&payloadBuf, m.PubKey); err != nil {
+		return nil, err
+	}
+	if err = util.WriteMarshaled(&payloadBuf, m.EphPubKey); err != nil {
+		return nil, err
+	}
+	if err = util.WriteBytes16(&payloadBuf, m.Challenge); err != nil {
+		return nil, err
+	}
+	var payload = payloadBuf.Bytes()
+	var signature []byte
+	if signature, err = bls.Sign(suite, secKey, payload); err != nil {
+		return nil, err
+	}
+	//
+	// Signed frame.
+	var signedBuf bytes.Buffer
+	if err = util.WriteBytes16(&signedBuf, signature); err != nil {
+		return nil, err
+	}
+	if err = util.WriteBytes16(&signedBuf, payload); err != nil {
+		return nil, err
+	}
+	return signedBuf.Bytes(), nil
+}
+
+// HandshakeMsgFromBytes decodes the message and verifies its signature.
+func HandshakeMsgFromBytes(buf []byte, suite Suite) (*HandshakeMsg, error) {
+	var err error
+	//
+	// Signed frame.
+	rSigned := bytes.NewReader(buf)
+	var payload []byte
+	var signature []byte
+	if signature, err = util.ReadBytes16(rSigned); err != nil {
+		return nil, err
+	}
+	if payload, err = util.ReadBytes16(rSigned); err != nil {
+		return nil, err
+	}
+	//
+	// Payload.
+	rPayload := bytes.NewReader(payload)
+	m := HandshakeMsg{}
+	if m.SrcNetID, err = util.ReadString16(rPayload); err != nil {
+		return nil, err
+	}
+	if m.DstNetID, err = util.ReadString16(rPayload); err != nil {
+		return nil, err
+	}
+	m.PubKey = suite.Point()
+	if err = util.ReadMarshaled(rPayload, m.PubKey); err != nil {
+		return nil, err
+	}
+	m.EphPubKey = suite.Point()
+	if err = util.ReadMarshaled(rPayload, m.EphPubKey); err != nil {
+		return nil, err
+	}
+	if m.Challenge, err = util.ReadBytes16(rPayload); err != nil {
+		return nil, err
+	}
+	//
+	// Verify the signature.
+	if err = bls.Verify(suite, m.PubKey, payload, signature); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// Handshake establishes the sessions with a peer. Each side offers its ephemeral key in the handshake
+// messages and signs the ephemeral key of the other side as the challenge. The session is established,
+// when the peer responds to the own ephemeral key, so a replayed handshake message can't establish it.
+// The ephemeral key is replaced by a fresh one for each session:
+//
+//	A -> B  {eA}          hello, B responds with its ephemeral key
+//	B -> A  {eB, eA}      A establishes the session (eA, eB)
+//	A -> B  {eA, eB}      B establishes the session (eB, eA)
+//	B -> A  {eB, eA}      confirmation, ignored by A
+//
+// Handshake is not thread-safe, the users must synchronize the access.
+type Handshake struct {
+	suite          Suite
+	myKeyPair      *key.Pair
+	myNetID        string
+	peerNetID      string
+	ephemeral      *key.Pair // Offered in the handshake messages until the session is established.
+	peerSessionEph []byte    // Ephemeral key of the peer in the current session.
+}
+
+// NewHandshake creates the handshake state with the peer.
+func NewHandshake(suite Suite, myKeyPair *key.Pair, myNetID, peerNetID string) *Handshake {
+	return &Handshake{
+		suite:     suite,
+		myKeyPair: myKeyPair,
+		myNetID:   myNetID,
+		peerNetID: peerNetID,
+		ephemeral: key.NewKeyPair(suite),
+	}
+}
+
+// Hello returns the handshake message starting a new session.
+func (h *Handshake) Hello() ([]byte, error) {
+	return h.message(h.ephemeral, nil)
+}
+
+// Receive processes the handshake message of the peer, with the verified signature.
+// It returns the cipher, if the session is established, and the handshake message to respond with, if any.
+func (h *Handshake) Receive(msg *HandshakeMsg) (*PeerCipher, []byte, error) {
+	if msg.SrcNetID != h.peerNetID || msg.DstNetID != h.myNetID {
+		return nil, nil, fmt.Errorf("handshake for %s from %s, expected from %s", msg.DstNetID, msg.SrcNetID, h.peerNetID)
+	}
+	var err error
+	var myEphBin, peerEphBin []byte
+	if myEphBin, err = h.ephemeral.Public.MarshalBinary(); err != nil {
+		return nil, nil, err
+	}
+	if peerEphBin, err = msg.EphPubKey.MarshalBinary(); err != nil {
+		return nil, nil, err
+	}
+	switch {
+	case bytes.Equal(msg.Challenge, challenge(myEphBin)):
+		// The peer has responded to the own ephemeral key, the session is established.
+		var c *PeerCipher
+		if c, err = NewPeerCipher(h.suite, h.myKeyPair, h.ephemeral, msg.PubKey, msg.EphPubKey); err != nil {
+			return nil, nil, err
+		}
+		var response []byte
+		if response, err = h.message(h.ephemeral, challenge(peerEphBin)); err != nil {
+			return nil, nil, err
+		}
+		h.peerSessionEph = peerEphBin
+		h.ephemeral = key.NewKeyPair(h.suite)
+		return c, response, nil
+	case bytes.Equal(peerEphBin, h.peerSessionEph):
+		// The confirmation of the current session or a replay.
+		return nil, nil, nil
+	default:
+		// The peer offers a new session.
+		var response []byte
+		if response, err = h.message(h.ephemeral, challenge(peerEphBin)); err != nil {
+			return nil, nil, err
+		}
+		return nil, response, nil
+	}
+}
+
+func (h *Handshake) message(ephemeral *key.Pair, challenge []byte) ([]byte, error) {
+	msg := HandshakeMsg{
+		SrcNetID:  h.myNetID,
+		DstNetID:  h.peerNetID,
+		PubKey:    h.myKeyPair.Public,
+		EphPubKey: ephemeral.Public,
+		Challenge: challenge,
+	}
+	return msg.Bytes(h.myKeyPair.Private, h.suite)
+}
+
+// the challenge is hashed to keep the handshake message in a single UDP datagram
+func challenge(ephBin []byte) []byte {
+	h := hashing.HashData(ephBin)
+	return h[:]
+}
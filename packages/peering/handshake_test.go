@@ -0,0 +1,136 @@
+package peering_test
+
+import (
+	"testing"
+
+	"github.com/iotaledger/wasp/packages/peering"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/util/key"
+)
+
+func TestHandshakeCodec(t *testing.T) {
+	suite := pairing.NewSuiteBn256()
+	pair := key.NewKeyPair(suite)
+	a := peering.HandshakeMsg{
+		SrcNetID:  "src",
+		DstNetID:  "dst",
+		PubKey:    pair.Public,
+		EphPubKey: key.NewKeyPair(suite).Public,
+		Challenge: []byte{1, 2, 3},
+	}
+	buf, err := a.Bytes(pair.Private, suite)
+	require.NoError(t, err)
+	//
+	// Correct message.
+	b, err := peering.HandshakeMsgFromBytes(buf, suite)
+	require.NoError(t, err)
+	require.Equal(t, a.SrcNetID, b.SrcNetID)
+	require.Equal(t, a.DstNetID, b.DstNetID)
+	require.True(t, a.PubKey.Equal(b.PubKey))
+	require.True(t, a.EphPubKey.Equal(b.EphPubKey))
+	require.Equal(t, a.Challenge, b.Challenge)
+	//
+	// Damaged message.
+	buf[len(buf)-1]++
+	_, err = peering.HandshakeMsgFromBytes(buf, suite)
+	require.Error(t, err)
+}
+
+// delivers the handshake message to the receiver, returns the cipher and the response
+func deliver(t *testing.T, suite peering.Suite, to *peering.Handshake, data []byte) (*peering.PeerCipher, []byte) {
+	msg, err := peering.HandshakeMsgFromBytes(data, suite)
+	require.NoError(t, err)
+	c, response, err := to.Receive(msg)
+	require.NoError(t, err)
+	return c, response
+}
+
+func requireSession(t *testing.T, ca, cb *peering.PeerCipher) {
+	sealed, err := ca.Seal([]byte("hello"))
+	require.NoError(t, err)
+	opened, err := cb.Open(sealed)
+	require.NoError(t, err)
+	require.EqualValues(t, "hello", opened)
+}
+
+func TestHandshake(t *testing.T) {
+	suite := pairing.NewSuiteBn256()
+	kpA := key.NewKeyPair(suite)
+	kpB := key.NewKeyPair(suite)
+	a := peering.NewHandshake(suite, kpA, "a", "b")
+	b := peering.NewHandshake(suite, kpB, "b", "a")
+
+	hello, err := a.Hello()
+	require.NoError(t, err)
+	helloOld := hello
+	c, reply := deliver(t, suite, b, hello)
+	require.Nil(t, c)
+	require.NotNil(t, reply)
+	ca, finish := deliver(t, suite, a, reply)
+	require.NotNil(t, ca)
+	cb, confirm := deliver(t, suite, b, finish)
+	require.NotNil(t, cb)
+	c, response := deliver(t, suite, a, confirm)
+	require.Nil(t, c)
+	require.Nil(t, response)
+	requireSession(t, ca, cb)
+	requireSession(t, cb, ca)
+
+	// the replayed messages don't establish a session
+	c, _ = deliver(t, suite, a, reply)
+	require.Nil(t, c)
+	c, _ = deliver(t, suite, b, finish)
+	require.Nil(t, c)
+	c, response = deliver(t, suite, b, hello)
+	require.Nil(t, c)
+	require.Nil(t, response)
+
+	// the next hello starts a new session
+	hello, err = a.Hello()
+	require.NoError(t, err)
+	_, reply = deliver(t, suite, b, hello)
+	ca2, finish := deliver(t, suite, a, reply)
+	require.NotNil(t, ca2)
+	cb2, _ := deliver(t, suite, b, finish)
+	require.NotNil(t, cb2)
+	requireSession(t, ca2, cb2)
+	// the replayed old hello is answered with the challenge, which the replaying party can't respond to
+	c, reply = deliver(t, suite, b, helloOld)
+	require.Nil(t, c)
+	require.NotNil(t, reply)
+
+	// the handshake is only accepted from the expected peer
+	other := peering.NewHandshake(suite, kpB, "c", "a")
+	hello, err = other.Hello()
+	require.NoError(t, err)
+	msg, err := peering.HandshakeMsgFromBytes(hello, suite)
+	require.NoError(t, err)
+	_, _, err = a.Receive(msg)
+	require.Error(t, err)
+}
+
+func TestHandshakeSimultaneous(t *testing.T) {
+	suite := pairing.NewSuiteBn256()
+	a := peering.NewHandshake(suite, key.NewKeyPair(suite), "a", "b")
+	b := peering.NewHandshake(suite, key.NewKeyPair(suite), "b", "a")
+
+	helloA, err := a.Hello()
+	require.NoError(t, err)
+	helloB, err := b.Hello()
+	require.NoError(t, err)
+	_, replyA := deliver(t, suite, a, helloB)
+	_, replyB := deliver(t, suite, b, helloA)
+	ca, finishA := deliver(t, suite, a, replyB)
+	require.NotNil(t, ca)
+	cb, finishB := deliver(t, suite, b, replyA)
+	require.NotNil(t, cb)
+	c, response := deliver(t, suite, a, finishB)
+	require.Nil(t, c)
+	require.Nil(t, response)
+	c, response = deliver(t, suite, b, finishA)
+	require.Nil(t, c)
+	require.Nil(t, response)
+	requireSession(t, ca, cb)
+	requireSession(t, cb, ca)
+}
@@ -26,6 +26,7 @@ const (
 	MsgTypeReserved  = byte(0)
 	MsgTypeHandshake = byte(1)
 	MsgTypeMsgChunk  = byte(2)
+	MsgTypeEncrypted = byte(3) // User message encrypted with the PeerCipher.
 
 	// FirstUserMsgCode is the first committee message type.
 	// All the equal and larger msg types are committee messages.
@@ -157,7 +158,7 @@ func NewPeerMessageFromBytes(buf []byte) (*PeerMessage, error) {
 		if m.MsgData, err = util.ReadBytes32(r); err != nil {
 			return nil, err
 		}
-	case MsgTypeMsgChunk:
+	case MsgTypeMsgChunk, MsgTypeEncrypted:
 		if m.MsgData, err = util.ReadBytes32(r); err != nil {
 			return nil, err
 		}
@@ -213,7 +214,7 @@ func (m *PeerMessage) Bytes() ([]byte, error) {
 		if err = util.WriteBytes32(&buf, m.MsgData); err != nil {
 			return nil, err
 		}
-	case MsgTypeMsgChunk:
+	case MsgTypeMsgChunk, MsgTypeEncrypted:
 		if err = util.WriteBytes32(&buf, m.MsgData); err != nil {
 			return nil, err
 		}
@@ -9,6 +9,7 @@ const (
 	msgTypeReserved  = byte(0)
 	msgTypeHandshake = byte(1)
 	msgTypeMsgChunk  = byte(2)
+	msgTypeEncrypted = byte(3)
 
 	restartAfter = 1 * time.Second
 	dialTimeout  = 1 * time.Second
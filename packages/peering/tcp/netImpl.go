@@ -27,13 +27,21 @@ type NetImpl struct {
 	events     *events.Event
 
 	nodeKeyPair *key.Pair
-	suite       kyber.Group
+	trusted     peering.TrustedNetworkManager
+	suite       peering.Suite
 	log         *logger.Logger
 }
 
 // NewNetworkProvider is a constructor for the TCP based
-// peering network implementation.
-func NewNetworkProvider(myNetID string, port int, nodeKeyPair *key.Pair, suite kyber.Group, log *logger.Logger) (*NetImpl, error) {
+// peering network implementation. Only the trusted peers are connected with.
+func NewNetworkProvider(
+	myNetID string,
+	port int,
+	nodeKeyPair *key.Pair,
+	trusted peering.TrustedNetworkManager,
+	suite peering.Suite,
+	log *logger.Logger,
+) (*NetImpl, error) {
 	if err := peering.CheckMyNetID(myNetID, port); err != nil {
 		// can't continue because NetID parameter is not correct
 		log.Panicf("checkMyNetworkID: '%v'. || Check the 'netid' parameter in config.json", err)
@@ -45,6 +53,7 @@ func NewNetworkProvider(myNetID string, port int, nodeKeyPair *key.Pair, suite k
 		peers:       make(map[string]*peer),
 		peersMutex:  &sync.RWMutex{},
 		nodeKeyPair: nodeKeyPair,
+		trusted:     trusted,
 		suite:       suite,
 		log:         log,
 	}
@@ -77,8 +86,12 @@ func (n *NetImpl) Self() peering.PeerSender {
 }
 
 // Group implements peering.NetworkProvider.
+// All the peers of the group must be trusted.
 func (n *NetImpl) Group(peerNetIDs []string) (peering.GroupProvider, error) {
 	var err error
+	if err = peering.ValidateTrustedNetIDs(n.trusted, peerNetIDs, n.myNetID); err != nil {
+		return nil, err
+	}
 	peers := make([]peering.PeerSender, len(peerNetIDs))
 	for i := range peerNetIDs {
 		if peers[i], err = n.PeerByNetID(peerNetIDs[i]); err != nil {
@@ -26,9 +26,15 @@ func TestBasic(t *testing.T) {
 	chain2 := coretypes.NewRandomChainID()
 	netIDs := []string{"localhost:9017", "localhost:9018", "localhost:9019"}
 	nodes := make([]peering.NetworkProvider, len(netIDs))
-	nodes[0], err0 = tcp.NewNetworkProvider(netIDs[0], 9017, key.NewKeyPair(suite), suite, log.Named("node0"))
-	nodes[1], err1 = tcp.NewNetworkProvider(netIDs[1], 9018, key.NewKeyPair(suite), suite, log.Named("node1"))
-	nodes[2], err2 = tcp.NewNetworkProvider(netIDs[2], 9019, key.NewKeyPair(suite), suite, log.Named("node2"))
+	keyPairs := []*key.Pair{key.NewKeyPair(suite), key.NewKeyPair(suite), key.NewKeyPair(suite)}
+	trusted := testutil.NewTrustedNetworkManager()
+	for i := range netIDs {
+		_, err := trusted.TrustPeer(keyPairs[i].Public, netIDs[i])
+		require.NoError(t, err)
+	}
+	nodes[0], err0 = tcp.NewNetworkProvider(netIDs[0], 9017, keyPairs[0], trusted, suite, log.Named("node0"))
+	nodes[1], err1 = tcp.NewNetworkProvider(netIDs[1], 9018, keyPairs[1], trusted, suite, log.Named("node1"))
+	nodes[2], err2 = tcp.NewNetworkProvider(netIDs[2], 9019, keyPairs[2], trusted, suite, log.Named("node2"))
 	require.Nil(t, err0)
 	require.Nil(t, err1)
 	require.Nil(t, err2)
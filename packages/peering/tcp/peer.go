@@ -33,8 +33,9 @@ type peer struct {
 	peerconn    *peeredConnection // nil means not connected
 	handshakeOk bool
 
-	remoteNetID  string // network locations as taken from the SC data
-	remotePubKey kyber.Point
+	remoteNetID  string      // network locations as taken from the SC data
+	remotePubKey kyber.Point // taken from the handshake
+	sendLock     *sync.Mutex // the messages are sent in the order they are sealed
 
 	startOnce *sync.Once
 	waitReady *sync.WaitGroup
@@ -49,6 +50,7 @@ func newPeer(remoteNetID string, net *NetImpl) *peer {
 	return &peer{
 		RWMutex:     &sync.RWMutex{},
 		remoteNetID: remoteNetID,
+		sendLock:    &sync.Mutex{},
 		startOnce:   &sync.Once{},
 		waitReady:   &waitReady,
 		numUsers:    1,
@@ -176,8 +178,8 @@ func (p *peer) runOutbound() {
 		return
 	}
 	p.peerconn = newPeeredConnection(conn, p.net, p)
-	if err := p.sendHandshake(); err != nil {
-		log.Errorf("error during sendHandshake: %v", err)
+	if err := p.peerconn.sendHello(); err != nil {
+		log.Errorf("error during sendHello: %v", err)
 		return
 	}
 	log.Infof("starting reading outbound %s", p.remoteNetID)
@@ -186,27 +188,6 @@ func (p *peer) runOutbound() {
 	p.closeConn()
 }
 
-// sends handshake message. It contains myNetID
-func (p *peer) sendHandshake() error {
-	var err error
-	msg := handshakeMsg{
-		peeringID: p.peeringID(),
-		srcNetID:  p.net.Self().NetID(),
-		pubKey:    p.net.nodeKeyPair.Public,
-	}
-	var msgData []byte
-	if msgData, err = msg.bytes(); err != nil {
-		return err
-	}
-	data := encodeMessage(&peering.PeerMessage{
-		MsgType: msgTypeHandshake,
-		MsgData: msgData,
-	}, time.Now().UnixNano())
-	_, err = p.peerconn.Write(data)
-	p.net.log.Debugf("sendHandshake '%s' --> '%s', id = %s", p.net.myNetID, p.remoteNetID, p.peeringID())
-	return err
-}
-
 func (p *peer) doSendMsg(msg *peering.PeerMessage) error {
 	if msg.MsgType < peering.FirstUserMsgCode {
 		return errors.New("reserved message code")
@@ -215,75 +196,54 @@ func (p *peer) doSendMsg(msg *peering.PeerMessage) error {
 	if ts == 0 {
 		ts = time.Now().UnixNano()
 	}
-	data := encodeMessage(msg, ts)
+	p.sendLock.Lock()
+	defer p.sendLock.Unlock()
 
-	choppedData, chopped, err := p.peerconn.msgChopper.ChopData(data, tangle.MaxMessageSize, chunkMessageOverhead)
+	p.RLock()
+	conn := p.peerconn
+	var c *peering.PeerCipher
+	if conn != nil {
+		c = conn.cipher
+	}
+	p.RUnlock()
+	if c == nil {
+		return fmt.Errorf("connection with %s is not handshaked", p.remoteNetID)
+	}
+	sealed, err := c.Seal(encodeMessage(msg, ts))
 	if err != nil {
 		return err
 	}
+	data := encodeMessage(&peering.PeerMessage{
+		MsgType: msgTypeEncrypted,
+		MsgData: sealed,
+	}, ts)
 
-	p.RLock()
-	defer p.RUnlock()
-
+	choppedData, chopped, err := conn.msgChopper.ChopData(data, tangle.MaxMessageSize, chunkMessageOverhead)
+	if err != nil {
+		return err
+	}
 	if !chopped {
-		return p.sendData(data)
+		return p.sendData(conn, data)
 	}
-	return p.sendChunks(choppedData)
+	return p.sendChunks(conn, choppedData)
 }
 
-func (p *peer) sendChunks(chopped [][]byte) error {
+func (p *peer) sendChunks(conn *peeredConnection, chopped [][]byte) error {
 	ts := time.Now().UnixNano()
 	for _, piece := range chopped {
 		d := encodeMessage(&peering.PeerMessage{
 			MsgType: msgTypeMsgChunk,
 			MsgData: piece,
 		}, ts)
-		if err := p.sendData(d); err != nil {
+		if err := p.sendData(conn, d); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-// SendMsgToPeers sends same msg to all peers in the slice which are not nil
-// with the same timestamp
-// return number of successfully sent messages and timestamp
-func SendMsgToPeers(msg *peering.PeerMessage, ts int64, peers ...*peer) uint16 { // TODO: [KP] Remove, unused.
-	if msg.MsgType < peering.FirstUserMsgCode {
-		return 0
-	}
-	// timestamped here, once
-	data := encodeMessage(msg, ts)
-
-	numSent := uint16(0)
-	for _, peer := range peers {
-		if peer == nil {
-			continue
-		}
-		peer.RLock()
-		choppedData, chopped, err := peer.peerconn.msgChopper.ChopData(data, tangle.MaxMessageSize, chunkMessageOverhead)
-		if err != nil {
-			return 0
-		}
-		if !chopped {
-			if err := peer.sendData(data); err == nil {
-				numSent++
-			}
-		} else {
-			if err := peer.sendChunks(choppedData); err == nil {
-				numSent++
-			}
-		}
-		peer.RUnlock()
-	}
-	return numSent
-}
-
-func (p *peer) sendData(data []byte) error {
-	if p.peerconn == nil {
-		return fmt.Errorf("no connection with %s", p.remoteNetID)
-	}
-	num, err := p.peerconn.Write(data)
+func (p *peer) sendData(conn *peeredConnection, data []byte) error {
+	num, err := conn.Write(data)
 	if num != len(data) {
 		return fmt.Errorf("not all bytes were written. err = %v", err)
 	}
@@ -4,7 +4,9 @@
 package tcp
 
 import (
+	"fmt"
 	"net"
+	"time"
 
 	"github.com/iotaledger/goshimmer/dapps/waspconn/packages/chopper"
 	"github.com/iotaledger/goshimmer/packages/tangle"
@@ -16,12 +18,15 @@ import (
 // extension of BufferedConnection from hive.go
 // BufferedConnection is a wrapper for net.Conn
 // peeredConnection first handles handshake and then links
-// with peer according to the handshake information
+// with peer according to the handshake information.
+// Each connection establishes its own session with the peer
 type peeredConnection struct {
 	*buffconn.BufferedConnection
 	peer        *peer
 	net         *NetImpl
 	msgChopper  *chopper.Chopper
+	handshake   *peering.Handshake  // nil until the peer of the inbound connection is known
+	cipher      *peering.PeerCipher // the session keys, set under the peer lock
 	handshakeOk bool
 }
 
@@ -39,8 +44,10 @@ func newPeeredConnection(conn net.Conn, net *NetImpl, peer *peer) *peeredConnect
 	c.Events.Close.Attach(events.NewClosure(func() {
 		if c.peer != nil {
 			c.peer.Lock()
-			c.peer.peerconn = nil
-			c.peer.handshakeOk = false
+			if c.peer.peerconn == c {
+				c.peer.peerconn = nil
+				c.peer.handshakeOk = false
+			}
 			c.peer.Unlock()
 		}
 		net.log.Debugw("closed buff connection", "conn", conn.RemoteAddr().String())
@@ -66,105 +73,126 @@ func (c *peeredConnection) receiveData(data []byte) {
 		if finalMsg != nil {
 			c.receiveData(finalMsg)
 		}
+		return
 	}
-	if c.peer != nil {
-		// it is peered but maybe not handshaked yet (can only be outbound)
-		if c.peer.handshakeOk {
-			// it is handshake-ed
-			c.net.events.Trigger(&peering.RecvEvent{
-				From: c.peer,
-				Msg:  msg,
-			})
-		} else {
-			// expected handshake msg
-			if msg.MsgType != peering.MsgTypeHandshake {
-				c.net.log.Errorf("peeredConnection.receiveData: unexpected message during handshake 1")
-				return
-			}
-			// not handshaked => do handshake
-			c.processHandShakeOutbound(msg)
-		}
-	} else {
-		// can only be inbound
-		// expected handshake msg
-		if msg.MsgType != peering.MsgTypeHandshake {
-			c.net.log.Errorf("peeredConnection.receiveData: unexpected message during handshake 2")
-			return
-		}
-		// not peered yet can be only inbound
-		// peer up and do handshake
-		c.processHandShakeInbound(msg)
+	if msg.MsgType == peering.MsgTypeHandshake {
+		c.processHandshake(msg)
+		return
+	}
+	if c.peer == nil || !c.handshakeOk {
+		c.net.log.Errorf("peeredConnection.receiveData: unexpected message during handshake")
+		return
+	}
+	// it is handshake-ed, only encrypted user messages are accepted
+	userMsg, err := c.decryptMessage(msg)
+	if err != nil {
+		c.net.log.Warnf("peeredConnection.receiveData: dropping message from %s: %v", c.peer.remoteNetID, err)
+		return
 	}
+	c.net.events.Trigger(&peering.RecvEvent{
+		From: c.peer,
+		Msg:  userMsg,
+	})
 }
 
-// receives handshake response from the outbound peer
-// assumes the connection is already peered (i can be only for outbound peers)
-func (c *peeredConnection) processHandShakeOutbound(msg *peering.PeerMessage) {
-	var err error
-	var hMsg *handshakeMsg
-	if hMsg, err = handshakeMsgFromBytes(msg.MsgData, c.net.suite); err != nil {
-		c.net.log.Errorf(
-			"closeConn the peer connection: wrong handshake message from outbound peer %v, error: %v",
-			c.peer.peeringID(), err,
-		)
-		panic(err)
-	}
-	c.net.log.Debugf("received handshake from outbound %s", hMsg.peeringID)
-	if hMsg.peeringID != c.peer.peeringID() {
-		c.net.log.Errorf(
-			"closeConn the peer connection: wrong handshake message from outbound peer: expected %s got '%s'",
-			c.peer.peeringID(), hMsg.peeringID,
-		)
-		if c.peer != nil {
-			// may ne be peered yet
-			c.peer.closeConn()
-		}
-	} else {
-		c.net.log.Infof("CONNECTED WITH PEER %s (outbound)", hMsg.peeringID)
-		c.peer.remotePubKey = hMsg.pubKey
-		c.peer.handshakeOk = true
-		c.peer.waitReady.Done()
+// starts the handshake on the outbound connection
+func (c *peeredConnection) sendHello() error {
+	c.handshake = peering.NewHandshake(c.net.suite, c.net.nodeKeyPair, c.net.myNetID, c.peer.remoteNetID)
+	hello, err := c.handshake.Hello()
+	if err != nil {
+		return err
 	}
+	c.net.log.Debugf("sendHello '%s' --> '%s', id = %s", c.net.myNetID, c.peer.remoteNetID, c.peer.peeringID())
+	return c.sendHandshake(hello)
+}
+
+func (c *peeredConnection) sendHandshake(data []byte) error {
+	_, err := c.Write(encodeMessage(&peering.PeerMessage{
+		MsgType: msgTypeHandshake,
+		MsgData: data,
+	}, time.Now().UnixNano()))
+	return err
 }
 
-// receives handshake from the inbound peer
-// links connection with the peer
-// sends response back to finish the handshake
-func (c *peeredConnection) processHandShakeInbound(msg *peering.PeerMessage) {
+// receives the signed handshake message of the peer.
+// The inbound connection is linked with the peer only after the session is established,
+// so the replayed handshake messages can't take over the peer
+func (c *peeredConnection) processHandshake(msg *peering.PeerMessage) {
 	var err error
-	var hMsg *handshakeMsg
-	if hMsg, err = handshakeMsgFromBytes(msg.MsgData, c.net.suite); err != nil {
-		c.net.log.Errorf(
-			"closeConn the peer connection: wrong handshake message from outbound peer %v, error: %v",
-			c.peer.peeringID(), err,
-		)
-		panic(err)
+	var hMsg *peering.HandshakeMsg
+	if hMsg, err = peering.HandshakeMsgFromBytes(msg.MsgData, c.net.suite); err != nil {
+		c.net.log.Warnf("wrong handshake message from %s: %v. Closing..", c.RemoteAddr().String(), err)
+		_ = c.Close()
+		return
 	}
+	peer := c.peer
+	if peer == nil {
+		// can only be inbound
+		var ok bool
+		c.net.peersMutex.RLock()
+		peer, ok = c.net.peers[c.net.peeringID(hMsg.SrcNetID)]
+		c.net.peersMutex.RUnlock()
 
-	c.net.log.Infof("received handshake from inbound id = %s, peers=%+v", hMsg.peeringID, c.net.peers)
-
-	c.net.peersMutex.RLock()
-	peer, ok := c.net.peers[hMsg.peeringID]
-	c.net.peersMutex.RUnlock()
-
-	if !ok || !peer.IsInbound() {
-		c.net.log.Warnf("inbound connection from unexpected peer id %s. Closing..", hMsg.peeringID)
+		if !ok || !peer.IsInbound() || peer.remoteNetID != hMsg.SrcNetID {
+			c.net.log.Warnf("inbound connection from unexpected peer %s. Closing..", hMsg.SrcNetID)
+			_ = c.Close()
+			return
+		}
+	}
+	if err = peering.ValidateTrustedPeer(c.net.trusted, hMsg.PubKey, peer.remoteNetID); err != nil {
+		c.net.log.Warnf("connection with %s rejected: %v. Closing..", peer.peeringID(), err)
 		_ = c.Close()
 		return
 	}
-	c.peer = peer
-
-	peer.Lock()
-	peer.peerconn = c
-	peer.remotePubKey = hMsg.pubKey
-	peer.handshakeOk = true
-	peer.waitReady.Done()
-	peer.Unlock()
-
-	c.net.log.Infof("CONNECTED WITH PEER %s (inbound)", hMsg.peeringID)
-
-	if err := peer.sendHandshake(); err != nil {
-		c.net.log.Errorf("error while responding to handshake: %v. Closing connection", err)
+	if c.handshake == nil {
+		c.handshake = peering.NewHandshake(c.net.suite, c.net.nodeKeyPair, c.net.myNetID, peer.remoteNetID)
+	}
+	cipher, response, err := c.handshake.Receive(hMsg)
+	if err != nil {
+		c.net.log.Warnf("handshake with %s failed: %v. Closing..", peer.peeringID(), err)
 		_ = c.Close()
+		return
+	}
+	if cipher != nil {
+		peer.Lock()
+		if c.peer == nil {
+			c.peer = peer
+			peer.peerconn = c
+		}
+		c.cipher = cipher
+		peer.remotePubKey = hMsg.PubKey
+		peer.handshakeOk = true
+		peer.Unlock()
+	}
+	if response != nil {
+		if err = c.sendHandshake(response); err != nil {
+			c.net.log.Errorf("error while responding to handshake: %v. Closing connection", err)
+			_ = c.Close()
+			return
+		}
+	}
+	if cipher != nil && !c.handshakeOk {
+		c.handshakeOk = true
+		c.net.log.Infof("CONNECTED WITH PEER %s (inbound=%v)", peer.peeringID(), peer.IsInbound())
+		peer.waitReady.Done()
+	}
+}
+
+// decrypts the user message sealed by the peer
+func (c *peeredConnection) decryptMessage(msg *peering.PeerMessage) (*peering.PeerMessage, error) {
+	if msg.MsgType != msgTypeEncrypted {
+		return nil, fmt.Errorf("unencrypted message, MsgType=%d", msg.MsgType)
+	}
+	data, err := c.cipher.Open(msg.MsgData)
+	if err != nil {
+		return nil, err
+	}
+	userMsg, err := decodeMessage(data)
+	if err != nil {
+		return nil, err
+	}
+	if userMsg.MsgType < peering.FirstUserMsgCode {
+		return nil, fmt.Errorf("unexpected MsgType=%d", userMsg.MsgType)
 	}
+	return userMsg, nil
 }
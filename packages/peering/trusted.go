@@ -0,0 +1,92 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package peering
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"go.dedis.ch/kyber/v3"
+)
+
+// TrustedPeer is a node the own node accepts peering connections from.
+// The peer is identified by the public key of its node identity.
+type TrustedPeer struct {
+	PubKey kyber.Point
+	NetID  string
+}
+
+// PubKeyString returns the base64 encoded public key of the peer.
+func (tp *TrustedPeer) PubKeyString() string {
+	return PubKeyToString(tp.PubKey)
+}
+
+// TrustedNetworkManager maintains the list of trusted peers.
+// It is implemented by the registry.
+type TrustedNetworkManager interface {
+	TrustPeer(pubKey kyber.Point, netID string) (*TrustedPeer, error)
+	DistrustPeer(pubKey kyber.Point) (*TrustedPeer, error)
+	TrustedPeers() ([]*TrustedPeer, error)
+}
+
+// ValidateTrustedPeer checks if the peer with the public key is trusted under the NetID.
+func ValidateTrustedPeer(tnm TrustedNetworkManager, pubKey kyber.Point, netID string) error {
+	trusted, err := tnm.TrustedPeers()
+	if err != nil {
+		return err
+	}
+	for _, tp := range trusted {
+		if !tp.PubKey.Equal(pubKey) {
+			continue
+		}
+		if tp.NetID != netID {
+			return fmt.Errorf("peer %s is trusted with NetID %s, not %s", tp.PubKeyString(), tp.NetID, netID)
+		}
+		return nil
+	}
+	return fmt.Errorf("peer %s with public key %s is not trusted", netID, PubKeyToString(pubKey))
+}
+
+// ValidateTrustedNetIDs checks if all the peers, except the own node, are trusted.
+func ValidateTrustedNetIDs(tnm TrustedNetworkManager, netIDs []string, myNetID string) error {
+	trusted, err := tnm.TrustedPeers()
+	if err != nil {
+		return err
+	}
+	trustedNetIDs := make(map[string]bool)
+	for _, tp := range trusted {
+		trustedNetIDs[tp.NetID] = true
+	}
+	for _, netID := range netIDs {
+		if netID != myNetID && !trustedNetIDs[netID] {
+			return fmt.Errorf("peer %s is not trusted", netID)
+		}
+	}
+	return nil
+}
+
+// PubKeyToString encodes the public key the same way as it is represented in the web API.
+func PubKeyToString(pubKey kyber.Point) string {
+	if pubKey == nil {
+		return "<nil>"
+	}
+	b, err := pubKey.MarshalBinary()
+	if err != nil {
+		return "<invalid>"
+	}
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+// PubKeyFromString decodes the base64 encoded public key.
+func PubKeyFromString(s string, suite kyber.Group) (kyber.Point, error) {
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	ret := suite.Point()
+	if err = ret.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
@@ -37,13 +37,21 @@ type NetImpl struct {
 	recvEvents  *events.Event
 	recvQueue   chan *peering.RecvEvent // A queue for received messages.
 	nodeKeyPair *key.Pair
+	trusted     peering.TrustedNetworkManager
 	suite       Suite
 	log         *logger.Logger
 }
 
-// NewNetworkProvider is a constructor for the TCP based
-// peering network implementation.
-func NewNetworkProvider(myNetID string, port int, nodeKeyPair *key.Pair, suite Suite, log *logger.Logger) (*NetImpl, error) {
+// NewNetworkProvider is a constructor for the UDP based
+// peering network implementation. Only the trusted peers are paired with.
+func NewNetworkProvider(
+	myNetID string,
+	port int,
+	nodeKeyPair *key.Pair,
+	trusted peering.TrustedNetworkManager,
+	suite Suite,
+	log *logger.Logger,
+) (*NetImpl, error) {
 	var err error
 	if err = peering.CheckMyNetID(myNetID, port); err != nil {
 		// can't continue because NetID parameter is not correct
@@ -64,6 +72,7 @@ func NewNetworkProvider(myNetID string, port int, nodeKeyPair *key.Pair, suite S
 		recvEvents:  nil, // Initialized bellow.
 		recvQueue:   make(chan *peering.RecvEvent, recvQueueSize),
 		nodeKeyPair: nodeKeyPair,
+		trusted:     trusted,
 		suite:       suite,
 		log:         log,
 	}
@@ -99,8 +108,12 @@ func (n *NetImpl) Self() peering.PeerSender {
 }
 
 // Group implements peering.NetworkProvider.
+// All the peers of the group must be trusted.
 func (n *NetImpl) Group(peerNetIDs []string) (peering.GroupProvider, error) {
 	var err error
+	if err = peering.ValidateTrustedNetIDs(n.trusted, peerNetIDs, n.myNetID); err != nil {
+		return nil, err
+	}
 	groupPeers := make([]peering.PeerSender, len(peerNetIDs))
 	for i := range peerNetIDs {
 		if groupPeers[i], err = n.usePeer(peerNetIDs[i]); err != nil {
@@ -257,19 +270,22 @@ func (n *NetImpl) receiveLoop(stopCh chan bool) {
 		case peering.MsgTypeReserved:
 			// Nothing
 		case peering.MsgTypeHandshake:
-			var h *handshakeMsg
-			if h, err = handshakeMsgFromBytes(peerMsg.MsgData, n.suite); err != nil {
+			var h *peering.HandshakeMsg
+			if h, err = peering.HandshakeMsgFromBytes(peerMsg.MsgData, n.suite); err != nil {
 				n.log.Warnf("Error while decoding a UDP handshake, reason=%v", err)
 				continue
 			}
+			if h.DstNetID != n.myNetID {
+				n.log.Warnf("Dropping UDP handshake from %v for %v", peerUDPAddr, h.DstNetID)
+				continue
+			}
+			if err = peering.ValidateTrustedPeer(n.trusted, h.PubKey, h.SrcNetID); err != nil {
+				n.log.Warnf("Dropping UDP handshake from %v, reason=%v", peerUDPAddr, err)
+				continue
+			}
 			n.peersLock.Lock()
-			if p, ok := n.peers[h.netID]; ok {
-				if oldUDPAddrStr, newUDPAddrStr := p.handleHandshake(h, peerUDPAddr); oldUDPAddrStr != newUDPAddrStr {
-					// Update the index to find the peer later on.
-					n.peersByAddr[newUDPAddrStr] = p
-					delete(n.peersByAddr, oldUDPAddrStr)
-				}
-			} else {
+			p, ok := n.peers[h.SrcNetID]
+			if !ok {
 				if p, err = newPeerFromHandshake(h, peerUDPAddr, n); err != nil {
 					n.log.Warnf("Error while creating a peer based on UDP handshake, reason=%v", err)
 					n.peersLock.Unlock()
@@ -278,6 +294,11 @@ func (n *NetImpl) receiveLoop(stopCh chan bool) {
 				n.peers[p.NetID()] = p
 				n.peersByAddr[p.remoteUDPAddr.String()] = p
 			}
+			if oldUDPAddrStr, newUDPAddrStr := p.handleHandshake(h, peerUDPAddr); oldUDPAddrStr != newUDPAddrStr {
+				// Update the index to find the peer later on.
+				n.peersByAddr[newUDPAddrStr] = p
+				delete(n.peersByAddr, oldUDPAddrStr)
+			}
 			n.peersLock.Unlock()
 		case peering.MsgTypeMsgChunk:
 			remoteUDPAddrStr := peerUDPAddr.String()
@@ -290,7 +311,7 @@ func (n *NetImpl) receiveLoop(stopCh chan bool) {
 					continue
 				}
 				if reconstructedMsg != nil {
-					n.receiveUserMsg(reconstructedMsg, peerUDPAddr)
+					n.receiveEncryptedMsg(reconstructedMsg, peerUDPAddr)
 				}
 			} else {
 				n.peersLock.RUnlock()
@@ -298,29 +319,39 @@ func (n *NetImpl) receiveLoop(stopCh chan bool) {
 				continue
 			}
 		default:
-			n.receiveUserMsg(peerMsg, peerUDPAddr)
+			n.receiveEncryptedMsg(peerMsg, peerUDPAddr)
 		}
 	}
 }
 
-func (n *NetImpl) receiveUserMsg(msg *peering.PeerMessage, peerUDPAddr *net.UDPAddr) {
-	if !msg.IsUserMessage() {
+// Only the user messages encrypted by the paired peer are accepted.
+func (n *NetImpl) receiveEncryptedMsg(msg *peering.PeerMessage, peerUDPAddr *net.UDPAddr) {
+	if msg.MsgType != peering.MsgTypeEncrypted {
 		n.log.Warnf("Dropping received message, unexpected MsgType=%v", msg.MsgType)
 		return
 	}
 	remoteUDPAddrStr := peerUDPAddr.String()
 	n.peersLock.RLock()
-	if p, ok := n.peersByAddr[remoteUDPAddrStr]; ok {
-		n.peersLock.RUnlock()
-		p.noteReceived()
-		n.recvQueue <- &peering.RecvEvent{
-			From: p,
-			Msg:  msg,
-		}
+	p, ok := n.peersByAddr[remoteUDPAddrStr]
+	n.peersLock.RUnlock()
+	if !ok {
+		n.log.Warnf("Dropping received message from unknown peer=%v", remoteUDPAddrStr)
 		return
 	}
-	n.peersLock.RUnlock()
-	n.log.Warnf("Dropping received message from unknown peer=%v", remoteUDPAddrStr)
+	userMsg, err := p.decryptMsg(msg)
+	if err != nil {
+		n.log.Warnf("Dropping received message from peer=%v, reason=%v", remoteUDPAddrStr, err)
+		return
+	}
+	if !userMsg.IsUserMessage() {
+		n.log.Warnf("Dropping received message, unexpected MsgType=%v", userMsg.MsgType)
+		return
+	}
+	p.noteReceived()
+	n.recvQueue <- &peering.RecvEvent{
+		From: p,
+		Msg:  userMsg,
+	}
 }
 
 func (n *NetImpl) maintenanceLoop(stopCh chan bool) {
@@ -5,6 +5,7 @@ package udp_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/peering"
@@ -25,9 +26,15 @@ func TestUDPPeeringImpl(t *testing.T) {
 	chain2 := coretypes.NewRandomChainID()
 	netIDs := []string{"localhost:9017", "localhost:9018", "localhost:9019"}
 	nodes := make([]peering.NetworkProvider, len(netIDs))
-	nodes[0], err0 = udp.NewNetworkProvider(netIDs[0], 9017, key.NewKeyPair(suite), suite, log.Named("node0"))
-	nodes[1], err1 = udp.NewNetworkProvider(netIDs[1], 9018, key.NewKeyPair(suite), suite, log.Named("node1"))
-	nodes[2], err2 = udp.NewNetworkProvider(netIDs[2], 9019, key.NewKeyPair(suite), suite, log.Named("node2"))
+	keyPairs := []*key.Pair{key.NewKeyPair(suite), key.NewKeyPair(suite), key.NewKeyPair(suite)}
+	trusted := testutil.NewTrustedNetworkManager()
+	for i := range netIDs {
+		_, err := trusted.TrustPeer(keyPairs[i].Public, netIDs[i])
+		require.NoError(t, err)
+	}
+	nodes[0], err0 = udp.NewNetworkProvider(netIDs[0], 9017, keyPairs[0], trusted, suite, log.Named("node0"))
+	nodes[1], err1 = udp.NewNetworkProvider(netIDs[1], 9018, keyPairs[1], trusted, suite, log.Named("node1"))
+	nodes[2], err2 = udp.NewNetworkProvider(netIDs[2], 9019, keyPairs[2], trusted, suite, log.Named("node2"))
 	require.Nil(t, err0)
 	require.Nil(t, err1)
 	require.Nil(t, err2)
@@ -52,3 +59,42 @@ func TestUDPPeeringImpl(t *testing.T) {
 
 	<-doneCh
 }
+
+func TestUDPPeeringUntrusted(t *testing.T) {
+	suite := pairing.NewSuiteBn256()
+	log := testutil.NewLogger(t)
+	defer log.Sync()
+	netIDs := []string{"localhost:9027", "localhost:9028"}
+	keyPairs := []*key.Pair{key.NewKeyPair(suite), key.NewKeyPair(suite)}
+	trusted0 := testutil.NewTrustedNetworkManager() // node0 doesn't trust node1.
+	trusted1 := testutil.NewTrustedNetworkManager()
+	_, err := trusted1.TrustPeer(keyPairs[0].Public, netIDs[0])
+	require.NoError(t, err)
+	node0, err := udp.NewNetworkProvider(netIDs[0], 9027, keyPairs[0], trusted0, suite, log.Named("node0"))
+	require.NoError(t, err)
+	node1, err := udp.NewNetworkProvider(netIDs[1], 9028, keyPairs[1], trusted1, suite, log.Named("node1"))
+	require.NoError(t, err)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	go node0.Run(stopCh)
+	go node1.Run(stopCh)
+
+	_, err = node0.Group(netIDs)
+	require.Error(t, err)
+	_, err = node1.Group(netIDs)
+	require.NoError(t, err)
+
+	received := make(chan bool, 1)
+	node0.Attach(nil, func(recv *peering.RecvEvent) {
+		received <- true
+	})
+	n1p0, err := node1.PeerByNetID(netIDs[0])
+	require.NoError(t, err)
+	require.Error(t, n1p0.Await(time.Second))
+	n1p0.SendMsg(&peering.PeerMessage{ChainID: coretypes.NewRandomChainID(), MsgType: 125})
+	select {
+	case <-received:
+		t.Fatal("message from the untrusted peer received")
+	case <-time.After(500 * time.Millisecond):
+	}
+}
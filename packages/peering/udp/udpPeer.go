@@ -29,8 +29,11 @@ type peer struct {
 	remoteNetID   string
 	remotePubKey  kyber.Point
 	remoteUDPAddr *net.UDPAddr
+	handshake     *peering.Handshake
+	cipher        *peering.PeerCipher // Keys of the current session.
 	waitReady     *util.WaitChan
 	accessLock    *sync.RWMutex
+	sendLock      *sync.Mutex // User messages are sent in the order they are sealed.
 	lastMsgSent   time.Time
 	lastMsgRecv   time.Time
 	numUsers      int
@@ -50,19 +53,13 @@ func newPeerOnUserRequest(remoteNetID string, n *NetImpl) (*peer, error) {
 		return nil, err
 	}
 	p.usePeer()
+	p.sendHello()
 	return p, nil
 }
 
-func newPeerFromHandshake(handshake *handshakeMsg, remoteUDPAddr *net.UDPAddr, n *NetImpl) (*peer, error) {
-	var err error
-	var p *peer
-	if p, err = newPeer(handshake.netID, remoteUDPAddr, n); err != nil {
-		return nil, err
-	}
-	if oldUDPAddrStr, newUDPAddrStr := p.handleHandshake(handshake, remoteUDPAddr); oldUDPAddrStr != newUDPAddrStr {
-		return nil, errors.New("inconsistent_udp_addr_on_create")
-	}
-	return p, nil
+// The received handshake is handled by the caller, the peer responds to it.
+func newPeerFromHandshake(handshake *peering.HandshakeMsg, remoteUDPAddr *net.UDPAddr, n *NetImpl) (*peer, error) {
+	return newPeer(handshake.SrcNetID, remoteUDPAddr, n)
 }
 
 // That's internal, called from other constructors.
@@ -72,8 +69,10 @@ func newPeer(remoteNetID string, remoteUDPAddr *net.UDPAddr, n *NetImpl) (*peer,
 		remoteNetID:   remoteNetID,
 		remotePubKey:  nil, // Will be retrieved on handshake.
 		remoteUDPAddr: remoteUDPAddr,
+		handshake:     peering.NewHandshake(n.suite, n.nodeKeyPair, n.myNetID, remoteNetID),
 		waitReady:     util.NewWaitChan(),
 		accessLock:    &sync.RWMutex{},
+		sendLock:      &sync.Mutex{},
 		lastMsgSent:   time.Time{},
 		lastMsgRecv:   time.Time{},
 		numUsers:      0,
@@ -81,7 +80,6 @@ func newPeer(remoteNetID string, remoteUDPAddr *net.UDPAddr, n *NetImpl) (*peer,
 		net:           n,
 		log:           log,
 	}
-	p.sendHandshake(true)
 	return p, nil
 }
 
@@ -91,53 +89,98 @@ func (p *peer) usePeer() {
 	p.numUsers++
 }
 
-func (p *peer) handleHandshake(handshake *handshakeMsg, remoteUDPAddr *net.UDPAddr) (string, string) {
+// Handles the handshake message with the verified signature. The remote UDPAddr is only
+// changed when a new session is established, so a replayed handshake can't redirect the messages.
+// The handshake is responded to the address it was received from.
+func (p *peer) handleHandshake(handshake *peering.HandshakeMsg, remoteUDPAddr *net.UDPAddr) (string, string) {
 	p.accessLock.Lock()
 	oldUDPAddrStr := p.remoteUDPAddr.String()
-	newUDPAddrStr := remoteUDPAddr.String()
-	if oldUDPAddrStr != newUDPAddrStr {
-		p.log.Warnf("Remote UDPAddr has changed, old=%v, new=%v", oldUDPAddrStr, newUDPAddrStr)
-		p.remoteUDPAddr = remoteUDPAddr
-	}
-	if p.remotePubKey == nil {
-		// That's the first received handshake, pairing established.
-		p.remotePubKey = handshake.pubKey
-		p.waitReady.Done()
-		p.log.Infof("Paired %v with %v", p.net.NetID(), p.remoteNetID)
-	} else if p.remotePubKey != nil && p.remotePubKey.Equal(handshake.pubKey) {
-		// It's just a ping.
-	} else {
-		// New PublicKey is used by the peer!
-		if !p.remotePubKey.Equal(handshake.pubKey) {
-			p.log.Warnf("Remote PubKey has changed, old=%v, new=%v", p.remotePubKey, handshake.pubKey)
+	newUDPAddrStr := oldUDPAddrStr
+	c, response, err := p.handshake.Receive(handshake)
+	if err != nil {
+		p.accessLock.Unlock()
+		p.log.Warnf("Dropping handshake from %v, reason=%v", remoteUDPAddr, err)
+		return oldUDPAddrStr, newUDPAddrStr
+	}
+	if c != nil {
+		if newUDPAddrStr = remoteUDPAddr.String(); oldUDPAddrStr != newUDPAddrStr {
+			p.log.Warnf("Remote UDPAddr has changed, old=%v, new=%v", oldUDPAddrStr, newUDPAddrStr)
+			p.remoteUDPAddr = remoteUDPAddr
 		}
-		p.remotePubKey = handshake.pubKey
+		if p.remotePubKey == nil {
+			// That's the first established session, pairing established.
+			p.waitReady.Done()
+			p.log.Infof("Paired %v with %v", p.net.NetID(), p.remoteNetID)
+		} else if !p.remotePubKey.Equal(handshake.PubKey) {
+			// New PublicKey is used by the peer!
+			p.log.Warnf("Remote PubKey has changed, old=%v, new=%v", p.remotePubKey, handshake.PubKey)
+		}
+		p.remotePubKey = handshake.PubKey
+		p.cipher = c
+		p.lastMsgRecv = time.Now()
 	}
-	p.lastMsgRecv = time.Now()
 	p.accessLock.Unlock()
-	if handshake.respond {
-		// Respond to the handshake, if asked.
-		p.sendHandshake(false)
+	if response != nil {
+		p.sendHandshake(response, remoteUDPAddr)
 	}
 	return oldUDPAddrStr, newUDPAddrStr
 }
 
-func (p *peer) sendHandshake(respond bool) {
+func (p *peer) encryptMsg(msg *peering.PeerMessage) (*peering.PeerMessage, error) {
+	p.accessLock.RLock()
+	c := p.cipher
+	p.accessLock.RUnlock()
+	if c == nil {
+		return nil, errors.New("peering not established")
+	}
 	var err error
-	handshake := handshakeMsg{
-		netID:   p.net.NetID(),
-		pubKey:  p.net.PubKey(),
-		respond: respond,
+	var msgBin, sealed []byte
+	if msgBin, err = msg.Bytes(); err != nil {
+		return nil, err
+	}
+	if sealed, err = c.Seal(msgBin); err != nil {
+		return nil, err
+	}
+	return &peering.PeerMessage{
+		Timestamp: msg.Timestamp,
+		MsgType:   peering.MsgTypeEncrypted,
+		MsgData:   sealed,
+	}, nil
+}
+
+func (p *peer) decryptMsg(msg *peering.PeerMessage) (*peering.PeerMessage, error) {
+	p.accessLock.RLock()
+	c := p.cipher
+	p.accessLock.RUnlock()
+	if c == nil {
+		return nil, errors.New("peering not established")
 	}
-	var msgDataBin []byte
-	if msgDataBin, err = handshake.bytes(p.net.nodeKeyPair.Private, p.net.suite); err != nil {
+	msgBin, err := c.Open(msg.MsgData)
+	if err != nil {
+		return nil, err
+	}
+	return peering.NewPeerMessageFromBytes(msgBin)
+}
+
+// Starts a new session, the handshake is also used as a ping.
+func (p *peer) sendHello() {
+	p.accessLock.Lock()
+	hello, err := p.handshake.Hello()
+	remoteUDPAddr := p.remoteUDPAddr
+	p.accessLock.Unlock()
+	if err != nil {
 		p.log.Errorf("Unable to encode outgoing handshake msg, reason=%v", err)
+		return
 	}
-	p.SendMsg(&peering.PeerMessage{
+	p.sendHandshake(hello, remoteUDPAddr)
+}
+
+func (p *peer) sendHandshake(msgDataBin []byte, remoteUDPAddr *net.UDPAddr) {
+	p.sendMsgTo(&peering.PeerMessage{
 		Timestamp: time.Now().UnixNano(),
 		MsgType:   peering.MsgTypeHandshake,
 		MsgData:   msgDataBin,
-	})
+	}, remoteUDPAddr)
 }
 
 func (p *peer) noteReceived() {
@@ -153,7 +196,7 @@ func (p *peer) maintenanceCheck() {
 	p.accessLock.RLock()
 	if p.numUsers > 0 && p.lastMsgRecv.Before(old) {
 		p.accessLock.RUnlock()
-		p.sendHandshake(true)
+		p.sendHello()
 	} else {
 		p.accessLock.RUnlock()
 	}
@@ -176,22 +219,37 @@ func (p *peer) PubKey() kyber.Point {
 }
 
 // SendMsg implements peering.PeerSender interface for the remote peers.
+// User messages are encrypted, so they can only be sent after the peering is established.
 func (p *peer) SendMsg(msg *peering.PeerMessage) {
 	var err error
-	var msgChunks [][]byte
 	if msg.IsUserMessage() {
 		if !p.waitReady.WaitTimeout(sendMsgSyncTimeout) {
-			// Just log a warning and try to send a message anyway.
-			p.log.Warn("Sending a message despite the peering is not established yet, MsgType=%v", msg.MsgType)
+			p.log.Warnf("Dropping outgoing message, the peering is not established yet, MsgType=%v", msg.MsgType)
+			return
+		}
+		p.sendLock.Lock()
+		defer p.sendLock.Unlock()
+		if msg, err = p.encryptMsg(msg); err != nil {
+			p.log.Warnf("Dropping outgoing message, unable to encrypt, reason=%v", err)
+			return
 		}
 	}
+	p.accessLock.RLock()
+	remoteUDPAddr := p.remoteUDPAddr
+	p.accessLock.RUnlock()
+	p.sendMsgTo(msg, remoteUDPAddr)
+}
+
+func (p *peer) sendMsgTo(msg *peering.PeerMessage, remoteUDPAddr *net.UDPAddr) {
+	var err error
+	var msgChunks [][]byte
 	if msgChunks, err = msg.ChunkedBytes(maxChunkSize, p.msgChopper); err != nil {
 		p.log.Warnf("Dropping outgoing message, unable to encode, reason=%v", err)
 		return
 	}
 	for i := range msgChunks {
 		var n int
-		if n, err = p.net.myUDPConn.WriteTo(msgChunks[i], p.remoteUDPAddr); err != nil {
+		if n, err = p.net.myUDPConn.WriteTo(msgChunks[i], remoteUDPAddr); err != nil {
 			p.log.Warnf("Dropping outgoing message, unable to send, reason=%v", err)
 			return
 		}
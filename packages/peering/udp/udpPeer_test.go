@@ -0,0 +1,19 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package udp
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUDPAddrString(t *testing.T) {
+	var err error
+	var addr *net.UDPAddr
+	addr, err = net.ResolveUDPAddr("udp", "localhost:1248")
+	require.Nil(t, err)
+	require.Equal(t, "127.0.0.1:1248", addr.String())
+}
@@ -0,0 +1,83 @@
+package publisher
+
+import "strings"
+
+// MQTT topic hierarchy of the published messages:
+//   wasp/{chainID}/{contractHname}/events   - events generated by the smart contract ('vmmsg')
+//   wasp/{chainID}/{contractHname}/events/{eventName} - structured events generated by the smart contract ('vmevent')
+//   wasp/{chainID}/state/index              - retained index of the last committed state
+//   wasp/{chainID}/{msgType}                - any other message type, e.g. 'state' or 'request_out'
+// The payload is the rest of the message after the chain ID (and contract hname), separated by spaces
+
+const MQTTTopicRoot = "wasp"
+
+// MQTTMessage is a message of the Wasp publisher mapped to the MQTT topic hierarchy
+type MQTTMessage struct {
+	Topic   string
+	Payload []byte
+	Retain  bool
+}
+
+// MQTTEventsTopic is the topic of events published by the smart contract
+func MQTTEventsTopic(chainID, contractHname string) string {
+	return strings.Join([]string{MQTTTopicRoot, chainID, contractHname, "events"}, "/")
+}
+
+// MQTTTypedEventsTopic is the topic of structured events with the name published by the smart contract
+func MQTTTypedEventsTopic(chainID, contractHname, eventName string) string {
+	return strings.Join([]string{MQTTTopicRoot, chainID, contractHname, "events", eventName}, "/")
+}
+
+// MQTTStateIndexTopic is the topic of the retained message with the last state index of the chain
+func MQTTStateIndexTopic(chainID string) string {
+	return strings.Join([]string{MQTTTopicRoot, chainID, "state", "index"}, "/")
+}
+
+// MQTTMessageTopic is the topic of messages of the type msgType for the chain
+func MQTTMessageTopic(chainID, msgType string) string {
+	return strings.Join([]string{MQTTTopicRoot, chainID, msgType}, "/")
+}
+
+// MQTTMessages maps the published message to MQTT messages.
+// Every message published by Wasp has the chain ID as the first part
+func MQTTMessages(msgType string, parts []string) []*MQTTMessage {
+	if len(parts) == 0 {
+		return nil
+	}
+	chainID := parts[0]
+	switch msgType {
+	case "vmmsg":
+		if len(parts) < 2 {
+			return nil
+		}
+		return []*MQTTMessage{{
+			Topic:   MQTTEventsTopic(chainID, parts[1]),
+			Payload: []byte(strings.Join(parts[2:], " ")),
+		}}
+	case "vmevent":
+		if len(parts) < 3 {
+			return nil
+		}
+		return []*MQTTMessage{{
+			Topic:   MQTTTypedEventsTopic(chainID, parts[1], parts[2]),
+			Payload: []byte(strings.Join(parts[3:], " ")),
+		}}
+	case "state":
+		ret := []*MQTTMessage{{
+			Topic:   MQTTMessageTopic(chainID, msgType),
+			Payload: []byte(strings.Join(parts[1:], " ")),
+		}}
+		if len(parts) > 1 {
+			ret = append(ret, &MQTTMessage{
+				Topic:   MQTTStateIndexTopic(chainID),
+				Payload: []byte(parts[1]),
+				Retain:  true,
+			})
+		}
+		return ret
+	}
+	return []*MQTTMessage{{
+		Topic:   MQTTMessageTopic(chainID, msgType),
+		Payload: []byte(strings.Join(parts[1:], " ")),
+	}}
+}
@@ -1,12 +1,15 @@
 package registry
 
 import (
+	"bytes"
+	"time"
+
 	"github.com/iotaledger/hive.go/kvstore"
 	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/dbprovider"
 	"github.com/iotaledger/wasp/packages/hashing"
 	"github.com/iotaledger/wasp/packages/kv/codec"
-	"time"
+	"github.com/iotaledger/wasp/packages/util"
 )
 
 // implements BlobCacheProvide interface
@@ -21,12 +24,15 @@ func dbKeyForBlobTTL(h hashing.HashValue) []byte {
 	return dbprovider.MakeKey(dbprovider.ObjectTypeBlobCacheTTL)
 }
 
+func dbKeyForBlobCacheStats() []byte {
+	return dbprovider.MakeKey(dbprovider.ObjectTypeBlobCacheStats)
+}
+
 // PutBlob Writes data into the registry with the key of its hash
 // Also stores TTL if provided
 func (r *Impl) PutBlob(data []byte, ttl ...time.Duration) (hashing.HashValue, error) {
 	h := hashing.HashData(data)
-	err := r.dbProvider.GetRegistryPartition().Set(dbKeyForBlob(h), data)
-	if err != nil {
+	if err := r.storeBlob(h, data); err != nil {
 		return hashing.HashValue{}, err
 	}
 	nowis := time.Now()
@@ -35,7 +41,7 @@ func (r *Impl) PutBlob(data []byte, ttl ...time.Duration) (hashing.HashValue, er
 		cleanAfter = nowis.Add(ttl[0]).UnixNano()
 	}
 	if cleanAfter > 0 {
-		err = r.dbProvider.GetRegistryPartition().Set(dbKeyForBlobTTL(h), codec.EncodeInt64(cleanAfter))
+		err := r.dbProvider.GetRegistryPartition().Set(dbKeyForBlobTTL(h), codec.EncodeInt64(cleanAfter))
 		if err != nil {
 			return hashing.HashValue{}, err
 		}
@@ -56,3 +62,81 @@ func (r *Impl) GetBlob(h hashing.HashValue) ([]byte, bool, error) {
 func (r *Impl) HasBlob(h hashing.HashValue) (bool, error) {
 	return r.dbProvider.GetRegistryPartition().Has(dbKeyForBlob(h))
 }
+
+// BlobCacheSize returns number of blobs in the cache and their total size in bytes.
+// The counters are maintained when the blobs are stored
+func (r *Impl) BlobCacheSize() (int, int, error) {
+	r.blobMutex.Lock()
+	defer r.blobMutex.Unlock()
+	num, size, err := r.blobCacheStats()
+	return int(num), int(size), err
+}
+
+// stores the new blob together with the updated counters
+func (r *Impl) storeBlob(h hashing.HashValue, data []byte) error {
+	r.blobMutex.Lock()
+	defer r.blobMutex.Unlock()
+
+	db := r.dbProvider.GetRegistryPartition()
+	exists, err := db.Has(dbKeyForBlob(h))
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+	num, size, err := r.blobCacheStats()
+	if err != nil {
+		return err
+	}
+	batch := db.Batched()
+	if err = batch.Set(dbKeyForBlob(h), data); err != nil {
+		return err
+	}
+	if err = batch.Set(dbKeyForBlobCacheStats(), encodeBlobCacheStats(num+1, size+uint64(len(data)))); err != nil {
+		return err
+	}
+	return batch.Commit()
+}
+
+// reads the counters of the blob cache. The database created before the counters
+// were introduced is counted once. Must be called under the blobMutex
+func (r *Impl) blobCacheStats() (uint64, uint64, error) {
+	db := r.dbProvider.GetRegistryPartition()
+	data, err := db.Get(dbKeyForBlobCacheStats())
+	if err == nil {
+		return decodeBlobCacheStats(data)
+	}
+	if err != kvstore.ErrKeyNotFound {
+		return 0, 0, err
+	}
+	var num, size uint64
+	err = db.Iterate(dbprovider.MakeKey(dbprovider.ObjectTypeBlobCache), func(_ kvstore.Key, value kvstore.Value) bool {
+		num++
+		size += uint64(len(value))
+		return true
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	return num, size, db.Set(dbKeyForBlobCacheStats(), encodeBlobCacheStats(num, size))
+}
+
+func encodeBlobCacheStats(num, size uint64) []byte {
+	var buf bytes.Buffer
+	buf.Write(util.Uint64To8Bytes(num))
+	buf.Write(util.Uint64To8Bytes(size))
+	return buf.Bytes()
+}
+
+func decodeBlobCacheStats(data []byte) (uint64, uint64, error) {
+	r := bytes.NewReader(data)
+	var num, size uint64
+	if err := util.ReadUint64(r, &num); err != nil {
+		return 0, 0, err
+	}
+	if err := util.ReadUint64(r, &size); err != nil {
+		return 0, 0, err
+	}
+	return num, size, nil
+}
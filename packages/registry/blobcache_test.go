@@ -24,4 +24,43 @@ func TestBlobPutGet(t *testing.T) {
 	require.NoError(t, err)
 	require.True(t, ok)
 	require.EqualValues(t, data, back)
+
+	num, size, err := reg.BlobCacheSize()
+	require.NoError(t, err)
+	require.EqualValues(t, 1, num)
+	require.EqualValues(t, len(data), size)
+}
+
+func TestBlobCacheSize(t *testing.T) {
+	log := testutil.NewLogger(t)
+	db := dbprovider.NewInMemoryDBProvider(log)
+	reg := NewRegistry(nil, log, db)
+
+	data1 := []byte("data-data-data")
+	data2 := []byte("other-data")
+	_, err := reg.PutBlob(data1)
+	require.NoError(t, err)
+	_, err = reg.PutBlob(data2)
+	require.NoError(t, err)
+	// the same blob is counted once
+	_, err = reg.PutBlob(data1)
+	require.NoError(t, err)
+
+	num, size, err := reg.BlobCacheSize()
+	require.NoError(t, err)
+	require.EqualValues(t, 2, num)
+	require.EqualValues(t, len(data1)+len(data2), size)
+
+	// the counters are persisted
+	num, size, err = NewRegistry(nil, log, db).BlobCacheSize()
+	require.NoError(t, err)
+	require.EqualValues(t, 2, num)
+	require.EqualValues(t, len(data1)+len(data2), size)
+
+	// the blobs stored before the counters were introduced are counted
+	require.NoError(t, db.GetRegistryPartition().Delete(dbKeyForBlobCacheStats()))
+	num, size, err = reg.BlobCacheSize()
+	require.NoError(t, err)
+	require.EqualValues(t, 2, num)
+	require.EqualValues(t, len(data1)+len(data2), size)
 }
@@ -6,6 +6,7 @@ import (
 	"github.com/iotaledger/wasp/packages/dbprovider"
 	"io"
 
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
 	"github.com/iotaledger/hive.go/kvstore"
 	"github.com/iotaledger/wasp/packages/coretypes"
@@ -22,6 +23,27 @@ type ChainRecord struct {
 	Color          balance.Color // origin tx hash
 	CommitteeNodes []string      // "host_addr:port"
 	Active         bool
+	// CommitteeAddress is the address of the current committee. It is only set after
+	// the committee of the chain has been rotated. Otherwise it is equal to the chain ID
+	CommitteeAddress address.Address
+	// AccessNodes are the nodes which follow the chain without participating in the consensus.
+	// They sync the state from the committee nodes and serve views and status queries
+	AccessNodes []string // "host_addr:port"
+}
+
+// Peers returns the committee nodes followed by the access nodes of the chain
+func (bd *ChainRecord) Peers() []string {
+	ret := make([]string, 0, len(bd.CommitteeNodes)+len(bd.AccessNodes))
+	ret = append(ret, bd.CommitteeNodes...)
+	return append(ret, bd.AccessNodes...)
+}
+
+// Address returns the address which holds the chain token and balances of the chain
+func (bd *ChainRecord) Address() address.Address {
+	if bd.CommitteeAddress == (address.Address{}) {
+		return address.Address(bd.ChainID)
+	}
+	return bd.CommitteeAddress
 }
 
 func dbkeyChainRecord(chainID *coretypes.ChainID) []byte {
@@ -127,7 +149,16 @@ func (bd *ChainRecord) Write(w io.Writer) error {
 	if err := util.WriteBoolByte(w, bd.Active); err != nil {
 		return err
 	}
-	return nil
+	if bd.CommitteeAddress == (address.Address{}) && len(bd.AccessNodes) == 0 {
+		return nil
+	}
+	if _, err := w.Write(bd.CommitteeAddress[:]); err != nil {
+		return err
+	}
+	if len(bd.AccessNodes) == 0 {
+		return nil
+	}
+	return util.WriteStrings16(w, bd.AccessNodes)
 }
 
 func (bd *ChainRecord) Read(r io.Reader) error {
@@ -144,12 +175,26 @@ func (bd *ChainRecord) Read(r io.Reader) error {
 	if err = util.ReadBoolByte(r, &bd.Active); err != nil {
 		return err
 	}
-	return nil
+	// committee address is optional for backward compatibility with records stored before committee rotation
+	bd.CommitteeAddress = address.Address{}
+	if _, err = io.ReadFull(r, bd.CommitteeAddress[:]); err != nil {
+		if err == io.EOF {
+			return nil
+		}
+		return err
+	}
+	// access nodes are optional for the same reason
+	bd.AccessNodes, err = util.ReadStrings16(r)
+	return err
 }
 
 func (bd *ChainRecord) String() string {
 	ret := "      Target: " + bd.ChainID.String() + "\n"
 	ret += "      Color: " + bd.Color.String() + "\n"
+	ret += "      Committee address: " + bd.Address().String() + "\n"
 	ret += fmt.Sprintf("      Committee nodes: %+v\n", bd.CommitteeNodes)
+	if len(bd.AccessNodes) > 0 {
+		ret += fmt.Sprintf("      Access nodes: %+v\n", bd.AccessNodes)
+	}
 	return ret
 }
@@ -0,0 +1,43 @@
+package registry
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChainRecordMarshaling(t *testing.T) {
+	chr := &ChainRecord{
+		ChainID:        coretypes.ChainID{1, 2, 3},
+		Color:          balance.Color{4, 5, 6},
+		CommitteeNodes: []string{"wasp1:4000", "wasp2:4000"},
+		Active:         true,
+	}
+	check := func(expected *ChainRecord) {
+		back := new(ChainRecord)
+		require.NoError(t, back.Read(bytes.NewReader(util.MustBytes(expected))))
+		require.EqualValues(t, expected.ChainID, back.ChainID)
+		require.EqualValues(t, expected.CommitteeNodes, back.CommitteeNodes)
+		require.EqualValues(t, expected.CommitteeAddress, back.CommitteeAddress)
+		require.EqualValues(t, len(expected.AccessNodes), len(back.AccessNodes))
+		for i := range expected.AccessNodes {
+			require.EqualValues(t, expected.AccessNodes[i], back.AccessNodes[i])
+		}
+		require.EqualValues(t, expected.Peers(), back.Peers())
+	}
+	check(chr)
+
+	chr.AccessNodes = []string{"wasp3:4000"}
+	check(chr)
+	require.EqualValues(t, []string{"wasp1:4000", "wasp2:4000", "wasp3:4000"}, chr.Peers())
+	require.EqualValues(t, address.Address(chr.ChainID), chr.Address())
+
+	chr.CommitteeAddress = address.Address{7, 8, 9}
+	check(chr)
+	require.EqualValues(t, chr.CommitteeAddress, chr.Address())
+}
@@ -4,6 +4,8 @@
 package registry
 
 import (
+	"sync"
+
 	"github.com/iotaledger/hive.go/logger"
 	"github.com/iotaledger/wasp/packages/dbprovider"
 	"github.com/iotaledger/wasp/packages/tcrypto"
@@ -16,13 +18,15 @@ type Impl struct {
 	suite      tcrypto.Suite
 	log        *logger.Logger
 	dbProvider *dbprovider.DBProvider
+	blobMutex  *sync.Mutex // serializes the updates of the blob cache counters
 }
 
 // New creates new instance of the registry implementation.
 func NewRegistry(suite tcrypto.Suite, log *logger.Logger, dbp ...*dbprovider.DBProvider) *Impl {
 	ret := &Impl{
-		suite: suite,
-		log:   log.Named("registry"),
+		suite:     suite,
+		log:       log.Named("registry"),
+		blobMutex: &sync.Mutex{},
 	}
 	if len(dbp) == 0 {
 		ret.dbProvider = database.GetInstance()
@@ -0,0 +1,78 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"fmt"
+
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/wasp/packages/dbprovider"
+	"github.com/iotaledger/wasp/packages/peering"
+	"go.dedis.ch/kyber/v3"
+)
+
+// TrustPeer implements peering.TrustedNetworkManager.
+// Trusting the already trusted peer updates its NetID.
+func (r *Impl) TrustPeer(pubKey kyber.Point, netID string) (*peering.TrustedPeer, error) {
+	dbKey, err := dbKeyForTrustedPeer(pubKey)
+	if err != nil {
+		return nil, err
+	}
+	if err = r.dbProvider.GetRegistryPartition().Set(dbKey, []byte(netID)); err != nil {
+		return nil, err
+	}
+	r.log.Infof("peer %s with public key %s is trusted", netID, peering.PubKeyToString(pubKey))
+	return &peering.TrustedPeer{PubKey: pubKey, NetID: netID}, nil
+}
+
+// DistrustPeer implements peering.TrustedNetworkManager.
+func (r *Impl) DistrustPeer(pubKey kyber.Point) (*peering.TrustedPeer, error) {
+	dbKey, err := dbKeyForTrustedPeer(pubKey)
+	if err != nil {
+		return nil, err
+	}
+	partition := r.dbProvider.GetRegistryPartition()
+	netID, err := partition.Get(dbKey)
+	if err == kvstore.ErrKeyNotFound {
+		return nil, fmt.Errorf("peer with public key %s is not trusted", peering.PubKeyToString(pubKey))
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = partition.Delete(dbKey); err != nil {
+		return nil, err
+	}
+	r.log.Infof("peer %s with public key %s is not trusted anymore", string(netID), peering.PubKeyToString(pubKey))
+	return &peering.TrustedPeer{PubKey: pubKey, NetID: string(netID)}, nil
+}
+
+// TrustedPeers implements peering.TrustedNetworkManager.
+func (r *Impl) TrustedPeers() ([]*peering.TrustedPeer, error) {
+	ret := make([]*peering.TrustedPeer, 0)
+	prefix := dbprovider.MakeKey(dbprovider.ObjectTypeTrustedPeer)
+	var errUnmarshal error
+	err := r.dbProvider.GetRegistryPartition().Iterate(prefix, func(key kvstore.Key, value kvstore.Value) bool {
+		pubKey := r.suite.Point()
+		if errUnmarshal = pubKey.UnmarshalBinary(key[len(prefix):]); errUnmarshal != nil {
+			return false
+		}
+		ret = append(ret, &peering.TrustedPeer{PubKey: pubKey, NetID: string(value)})
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if errUnmarshal != nil {
+		return nil, errUnmarshal
+	}
+	return ret, nil
+}
+
+func dbKeyForTrustedPeer(pubKey kyber.Point) ([]byte, error) {
+	pubKeyBin, err := pubKey.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return dbprovider.MakeKey(dbprovider.ObjectTypeTrustedPeer, pubKeyBin), nil
+}
@@ -0,0 +1,46 @@
+package registry
+
+import (
+	"testing"
+
+	"github.com/iotaledger/wasp/packages/dbprovider"
+	"github.com/iotaledger/wasp/packages/peering"
+	"github.com/iotaledger/wasp/packages/testutil"
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/util/key"
+)
+
+func TestTrustedPeers(t *testing.T) {
+	log := testutil.NewLogger(t)
+	suite := pairing.NewSuiteBn256()
+	reg := NewRegistry(suite, log, dbprovider.NewInMemoryDBProvider(log))
+	var _ peering.TrustedNetworkManager = reg
+
+	kp1 := key.NewKeyPair(suite)
+	kp2 := key.NewKeyPair(suite)
+
+	trusted, err := reg.TrustedPeers()
+	require.NoError(t, err)
+	require.EqualValues(t, 0, len(trusted))
+
+	_, err = reg.TrustPeer(kp1.Public, "wasp1:4000")
+	require.NoError(t, err)
+	_, err = reg.TrustPeer(kp2.Public, "wasp2:4000")
+	require.NoError(t, err)
+	_, err = reg.TrustPeer(kp2.Public, "wasp3:4000")
+	require.NoError(t, err)
+
+	trusted, err = reg.TrustedPeers()
+	require.NoError(t, err)
+	require.EqualValues(t, 2, len(trusted))
+	require.NoError(t, peering.ValidateTrustedPeer(reg, kp1.Public, "wasp1:4000"))
+	require.NoError(t, peering.ValidateTrustedPeer(reg, kp2.Public, "wasp3:4000"))
+
+	tp, err := reg.DistrustPeer(kp1.Public)
+	require.NoError(t, err)
+	require.EqualValues(t, "wasp1:4000", tp.NetID)
+	_, err = reg.DistrustPeer(kp1.Public)
+	require.Error(t, err)
+	require.Error(t, peering.ValidateTrustedPeer(reg, kp1.Public, "wasp1:4000"))
+}
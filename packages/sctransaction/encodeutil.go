@@ -10,6 +10,12 @@ import (
 
 const stateBlockMask = byte(0x80)
 
+// flags of the optional fields, which are written after all sections
+const (
+	optionalStateChainID         = byte(0x01)
+	optionalRequestTargetAddress = byte(0x02)
+)
+
 func encodeMetaByte(hasState bool, numRequests byte) (byte, error) {
 	if numRequests > 127 {
 		return 0, errors.New("can't be more than 127 requests")
@@ -26,7 +26,7 @@ type Properties struct {
 	isOrigin bool
 	// if isState == true: chainID
 	chainID coretypes.ChainID
-	// chainAddress == chainID
+	// address which holds the chain token. It is equal to chainID unless the committee has been rotated
 	chainAddress address.Address
 	// if isState == true: smart contract color
 	stateColor balance.Color
@@ -56,13 +56,10 @@ func (tx *Transaction) calcProperties() (*Properties, error) {
 	if !tx.SignaturesValid() {
 		return nil, fmt.Errorf("invalid signatures")
 	}
-	if len(tx.Signatures()) > 1 {
-		return nil, fmt.Errorf("number of signatures > 1")
-	}
-	if err := ret.analyzeSender(tx); err != nil {
+	if err := ret.analyzeStateBlock(tx); err != nil {
 		return nil, err
 	}
-	if err := ret.analyzeStateBlock(tx); err != nil {
+	if err := ret.analyzeSender(tx); err != nil {
 		return nil, err
 	}
 	if err := ret.analyzeRequestBlocks(tx); err != nil {
@@ -83,19 +80,34 @@ func (prop *Properties) calcNumMinted(tx *Transaction) {
 func (prop *Properties) analyzeSender(tx *Transaction) error {
 	// check if the senderAddress is exactly one
 	// only value transaction with one input address can be parsed as smart contract transactions
-	// because we always need to deterministically identify the senderAddress
-	senderFound := false
-	var err error
+	// because we always need to deterministically identify the senderAddress.
+	// The exception is the state transaction of the chain with the rotated committee, which also
+	// sweeps outputs of the chain ID address. The sender is the other input address then
+	inputAddrs := make([]address.Address, 0, 2)
 	tx.Transaction.Inputs().ForEachAddress(func(addr address.Address) bool {
-		if senderFound {
-			err = errors.New("smart contract transaction must contain exactly 1 input address")
-			return false
-		}
-		prop.senderAddress = addr
-		senderFound = true
-		return true
+		inputAddrs = append(inputAddrs, addr)
+		return len(inputAddrs) <= 2
 	})
-	return err
+	switch {
+	case len(inputAddrs) == 1:
+		prop.senderAddress = inputAddrs[0]
+	case len(inputAddrs) == 2 && prop.isState && !prop.isOrigin && prop.chainAddress != address.Address(prop.chainID):
+		chainIDAddr := address.Address(prop.chainID)
+		switch chainIDAddr {
+		case inputAddrs[0]:
+			prop.senderAddress = inputAddrs[1]
+		case inputAddrs[1]:
+			prop.senderAddress = inputAddrs[0]
+		default:
+			return errors.New("state transaction with 2 input addresses must sweep the chain ID address")
+		}
+	default:
+		return errors.New("smart contract transaction must contain exactly 1 input address")
+	}
+	if len(tx.Signatures()) > len(inputAddrs) {
+		return fmt.Errorf("number of signatures > %d", len(inputAddrs))
+	}
+	return nil
 }
 
 func (prop *Properties) analyzeStateBlock(tx *Transaction) error {
@@ -135,6 +147,18 @@ func (prop *Properties) analyzeStateBlock(tx *Transaction) error {
 	if err != nil {
 		return err
 	}
+	// the chain ID in the state section is only a claim: anyone can put it into a transaction.
+	// It is bound to the chain by the state color, which must be checked against the color
+	// of the chain by the consumer of the transaction
+	if chainID, ok := stateSection.ChainID(); ok {
+		if prop.isOrigin {
+			return errors.New("origin transaction can't contain chain ID in the state section")
+		}
+		if chainID == prop.chainID {
+			return errors.New("chain ID in the state section must be omitted when it is equal to the chain address")
+		}
+		prop.chainID = chainID
+	}
 	if prop.isOrigin {
 		prop.stateColor = balance.Color(prop.txid)
 	} else {
@@ -155,14 +179,19 @@ func (prop *Properties) analyzeRequestBlocks(tx *Transaction) error {
 	}
 	prop.numRequests = len(tx.Requests())
 
-	// sum up transfers of requests by target chain
-	reqTransfersByTargetChain := make(map[coretypes.ChainID]map[balance.Color]int64)
+	// sum up transfers of requests by target address. It is the chain ID address unless the request
+	// is sent to the address of the rotated committee
+	reqTransfersByTargetAddress := make(map[address.Address]map[balance.Color]int64)
 	for _, req := range tx.Requests() {
-		chainid := req.Target().ChainID()
-		m, ok := reqTransfersByTargetChain[chainid]
+		targetAddr := req.TargetAddress()
+		if prop.isState && req.Target().ChainID() == prop.chainID {
+			// the request to itself goes to the current committee
+			targetAddr = prop.chainAddress
+		}
+		m, ok := reqTransfersByTargetAddress[targetAddr]
 		if !ok {
 			m = make(map[balance.Color]int64)
-			reqTransfersByTargetChain[chainid] = m
+			reqTransfersByTargetAddress[targetAddr] = m
 		}
 		req.Transfer().AddToMap(m)
 		// add one request token
@@ -172,7 +201,7 @@ func (prop *Properties) analyzeRequestBlocks(tx *Transaction) error {
 	var err error
 	// validate all outputs against request transfers
 	tx.Transaction.Outputs().ForEach(func(addr address.Address, bals []*balance.Balance) bool {
-		m, ok := reqTransfersByTargetChain[coretypes.ChainID(addr)]
+		m, ok := reqTransfersByTargetAddress[addr]
 		if !ok {
 			// ignore outputs to outside addresses
 			return true
@@ -2,7 +2,10 @@ package sctransaction
 
 import (
 	"bytes"
+	"errors"
 	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/coretypes/requestargs"
+	"github.com/iotaledger/wasp/packages/tcrypto"
 	"github.com/iotaledger/wasp/packages/vm/core/root"
 	"github.com/stretchr/testify/require"
 	"testing"
@@ -20,3 +23,42 @@ func TestWriteRead(t *testing.T) {
 	require.NoError(t, err)
 	require.EqualValues(t, buf1.Bytes(), buf.Bytes())
 }
+
+func TestWriteReadCallback(t *testing.T) {
+	cid := coretypes.NewContractID(coretypes.ChainID{}, root.Interface.Hname())
+	rsec := NewRequestSection(coretypes.Hn("sender"), cid, coretypes.Hn("target")).
+		WithCallback(coretypes.Hn("callback")).
+		WithRefundOnFailure(true)
+	var buf bytes.Buffer
+	err := rsec.Write(&buf)
+	require.NoError(t, err)
+	rsec1 := &RequestSection{}
+	err = rsec1.Read(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	require.EqualValues(t, coretypes.Hn("callback"), rsec1.Callback())
+	require.True(t, rsec1.RefundOnFailure())
+	require.EqualValues(t, coretypes.Hn("callback"), rsec1.Clone().Callback())
+	require.True(t, rsec1.Clone().RefundOnFailure())
+}
+
+func TestDecryptArgsFailure(t *testing.T) {
+	cid := coretypes.NewContractID(coretypes.ChainID{}, root.Interface.Hname())
+	encrypt := func(data []byte) ([]byte, error) { return data, nil }
+	args, err := requestargs.NewEncryptedRequestArgs(requestargs.New(nil), encrypt)
+	require.NoError(t, err)
+
+	// the key is not available to the node: the args are not solid
+	rsec := NewRequestSectionByWallet(cid, coretypes.EntryPointInit).WithArgs(args)
+	err = rsec.DecryptArgs(func([]byte) ([]byte, error) { return nil, tcrypto.ErrStealthKeyUnavailable })
+	require.Error(t, err)
+	require.NoError(t, rsec.ArgsError())
+
+	// the data can't be decrypted by any committee member: the args are solid, the request is rejected
+	rsec = NewRequestSectionByWallet(cid, coretypes.EntryPointInit).WithArgs(args)
+	err = rsec.DecryptArgs(func([]byte) ([]byte, error) { return nil, errors.New("wrong data") })
+	require.NoError(t, err)
+	require.Error(t, rsec.ArgsError())
+	ok, err := rsec.SolidifyArgs(nil)
+	require.NoError(t, err)
+	require.True(t, ok)
+}
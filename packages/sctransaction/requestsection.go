@@ -1,6 +1,7 @@
 package sctransaction
 
 import (
+	"errors"
 	"fmt"
 	"github.com/iotaledger/wasp/packages/coretypes/requestargs"
 	"io"
@@ -9,7 +10,9 @@ import (
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
 	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/coretypes/cbalances"
+	"github.com/iotaledger/wasp/packages/hashing"
 	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/tcrypto"
 	"github.com/iotaledger/wasp/packages/util"
 )
 
@@ -34,13 +37,29 @@ type RequestSection struct {
 	// settles the request is greater or equal to the request timelock.
 	// 0 timelock naturally means it has no effect
 	timelock uint32
+	// gas budget declared by the sender. 0 means default budget
+	gasBudget uint64
 	// request arguments, not decoded yet wrt blobRefs
 	args requestargs.RequestArgs
+	// decrypted args, if args are encrypted ("stealth" request) and were decrypted by the committee member.
+	// Not serialized
+	decryptedArgs requestargs.RequestArgs
 	// decoded args, if not nil. If nil, it means it wasn't
 	// successfully decoded yet and can't be used in the batch for calculations in VM
 	solidArgs dict.Dict
+	// error of decryption of the args which is the same for all committee members.
+	// The request is processed as failed. Not serialized
+	argsError error
 	// all tokens transferred with the request EXCEPT the 1 minted request token
 	transfer coretypes.ColoredBalances
+	// entry point of the sending contract, which is called with the result of the request by the return request.
+	// 0 means no callback
+	callback coretypes.Hname
+	// if true, the transfer is returned to the sending contract with the return request if the request fails
+	refundOnFailure bool
+	// address of the current committee of the target chain if the request is not sent to the chain ID address.
+	// It is serialized after all sections of the transaction
+	targetAddress *address.Address
 }
 
 type RequestRef struct {
@@ -75,8 +94,14 @@ func (req *RequestSection) Clone() *RequestSection {
 	}
 	ret := NewRequestSection(req.senderContractHname, req.targetContractID, req.entryPoint).
 		WithTimelock(req.timelock).
-		WithTransfer(req.transfer)
+		WithGasBudget(req.gasBudget).
+		WithTransfer(req.transfer).
+		WithCallback(req.callback).
+		WithRefundOnFailure(req.refundOnFailure)
 	ret.args = req.args.Clone()
+	if req.targetAddress != nil {
+		ret.WithTargetAddress(*req.targetAddress)
+	}
 	return ret
 }
 
@@ -88,6 +113,25 @@ func (req *RequestSection) Target() coretypes.ContractID {
 	return req.targetContractID
 }
 
+// TargetAddress returns the address the request token is sent to. It is the chain ID address of the target chain
+// unless the request is sent to the address of the current committee after the committee rotation
+func (req *RequestSection) TargetAddress() address.Address {
+	if req.targetAddress != nil {
+		return *req.targetAddress
+	}
+	return address.Address(req.targetContractID.ChainID())
+}
+
+// WithTargetAddress sets the address the request token is sent to
+func (req *RequestSection) WithTargetAddress(addr address.Address) *RequestSection {
+	if addr == address.Address(req.targetContractID.ChainID()) {
+		req.targetAddress = nil
+		return req
+	}
+	req.targetAddress = &addr
+	return req
+}
+
 // WithArgs sets encoded args
 func (req *RequestSection) WithArgs(args requestargs.RequestArgs) *RequestSection {
 	req.args = args
@@ -99,12 +143,70 @@ func (req *RequestSection) SolidArgs() dict.Dict {
 	return req.solidArgs
 }
 
+// IsStealth returns if request arguments are encrypted for the committee
+func (req *RequestSection) IsStealth() bool {
+	return req.args.IsEncrypted()
+}
+
+// DecryptArgs decrypts arguments of the "stealth" request. It does nothing if arguments are not encrypted
+// or were already decrypted.
+// The error is only returned if the symmetric key is not available to the node (tcrypto.ErrStealthKeyUnavailable).
+// Other committee members may still decrypt the args. Any other error is the same for all committee members:
+// it is recorded as ArgsError and the request is processed as failed
+func (req *RequestSection) DecryptArgs(decrypt func([]byte) ([]byte, error)) error {
+	if !req.args.IsEncrypted() || req.decryptedArgs != nil || req.argsError != nil {
+		return nil
+	}
+	args, err := req.args.Decrypt(decrypt)
+	if errors.Is(err, tcrypto.ErrStealthKeyUnavailable) {
+		return err
+	}
+	if err != nil {
+		req.argsError = err
+		return nil
+	}
+	req.decryptedArgs = args
+	return nil
+}
+
+// ArgsError returns the error of decryption of the "stealth" request args, if any.
+// The request with the args error is rejected by the VM and the transfer is returned to the sender
+func (req *RequestSection) ArgsError() error {
+	return req.argsError
+}
+
+// MissingBlobs returns hashes of blobs referenced by the arguments which are not in the blob cache
+// Arguments of the "stealth" request must be decrypted first with DecryptArgs
+func (req *RequestSection) MissingBlobs(reg coretypes.BlobCache) ([]hashing.HashValue, error) {
+	if req.solidArgs != nil || req.argsError != nil {
+		return nil, nil
+	}
+	args := req.args
+	if req.decryptedArgs != nil {
+		args = req.decryptedArgs
+	}
+	if args.IsEncrypted() {
+		return nil, nil
+	}
+	return args.MissingBlobs(reg)
+}
+
 // SolidifyArgs return true if solidified successfully
+// Arguments of the "stealth" request must be decrypted first with DecryptArgs
 func (req *RequestSection) SolidifyArgs(reg coretypes.BlobCache) (bool, error) {
 	if req.solidArgs != nil {
 		return true, nil
 	}
-	solid, ok, err := req.args.SolidifyRequestArguments(reg)
+	if req.argsError != nil {
+		// nothing to solidify, the request will be rejected
+		req.solidArgs = dict.New()
+		return true, nil
+	}
+	args := req.args
+	if req.decryptedArgs != nil {
+		args = req.decryptedArgs
+	}
+	solid, ok, err := args.SolidifyRequestArguments(reg)
 	if err != nil || !ok {
 		return ok, err
 	}
@@ -123,15 +225,36 @@ func (req *RequestSection) Timelock() uint32 {
 	return req.timelock
 }
 
+// GasBudget returns the gas budget declared by the sender. 0 means default budget
+func (req *RequestSection) GasBudget() uint64 {
+	return req.gasBudget
+}
+
 func (req *RequestSection) Transfer() coretypes.ColoredBalances {
 	return req.transfer
 }
 
+// Callback returns the entry point of the sending contract, which is called with the result of the request.
+// 0 means no callback
+func (req *RequestSection) Callback() coretypes.Hname {
+	return req.callback
+}
+
+// RefundOnFailure returns true if the transfer must be returned to the sending contract if the request fails
+func (req *RequestSection) RefundOnFailure() bool {
+	return req.refundOnFailure
+}
+
 func (req *RequestSection) WithTimelock(tl uint32) *RequestSection {
 	req.timelock = tl
 	return req
 }
 
+func (req *RequestSection) WithGasBudget(budget uint64) *RequestSection {
+	req.gasBudget = budget
+	return req
+}
+
 func (req *RequestSection) WithTransfer(transfer coretypes.ColoredBalances) *RequestSection {
 	if transfer == nil {
 		transfer = cbalances.NewFromMap(nil)
@@ -140,6 +263,16 @@ func (req *RequestSection) WithTransfer(transfer coretypes.ColoredBalances) *Req
 	return req
 }
 
+func (req *RequestSection) WithCallback(callback coretypes.Hname) *RequestSection {
+	req.callback = callback
+	return req
+}
+
+func (req *RequestSection) WithRefundOnFailure(refund bool) *RequestSection {
+	req.refundOnFailure = refund
+	return req
+}
+
 func (req *RequestSection) WithTimelockUntil(deadline time.Time) *RequestSection {
 	return req.WithTimelock(uint32(deadline.Unix()))
 }
@@ -156,6 +289,9 @@ func (req *RequestSection) Write(w io.Writer) error {
 	if err := util.WriteUint32(w, req.timelock); err != nil {
 		return err
 	}
+	if err := util.WriteUint64(w, req.gasBudget); err != nil {
+		return err
+	}
 	if err := req.entryPoint.Write(w); err != nil {
 		return err
 	}
@@ -165,6 +301,12 @@ func (req *RequestSection) Write(w io.Writer) error {
 	if err := cbalances.WriteColoredBalances(w, req.transfer); err != nil {
 		return err
 	}
+	if err := req.callback.Write(w); err != nil {
+		return err
+	}
+	if err := util.WriteBoolByte(w, req.refundOnFailure); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -178,6 +320,9 @@ func (req *RequestSection) Read(r io.Reader) error {
 	if err := util.ReadUint32(r, &req.timelock); err != nil {
 		return err
 	}
+	if err := util.ReadUint64(r, &req.gasBudget); err != nil {
+		return err
+	}
 	if err := req.entryPoint.Read(r); err != nil {
 		return err
 	}
@@ -189,6 +334,12 @@ func (req *RequestSection) Read(r io.Reader) error {
 	if req.transfer, err = cbalances.ReadColoredBalance(r); err != nil {
 		return err
 	}
+	if err := req.callback.Read(r); err != nil {
+		return err
+	}
+	if err := util.ReadBoolByte(r, &req.refundOnFailure); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -216,7 +367,8 @@ func (ref *RequestRef) SenderContractID() (ret coretypes.ContractID, err error)
 		err = fmt.Errorf("request wasn't sent by the smart contract: %s", ref.RequestID().String())
 		return
 	}
-	ret = coretypes.NewContractID((coretypes.ChainID)(*ref.SenderAddress()), ref.SenderContractHname())
+	// the sender address is the address of the committee, which differs from the chain ID after the rotation
+	ret = coretypes.NewContractID(*ref.Tx.MustProperties().MustChainID(), ref.SenderContractHname())
 	return
 }
 
@@ -3,6 +3,7 @@ package sctransaction
 import (
 	"fmt"
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/hashing"
 	"github.com/iotaledger/wasp/packages/util"
 	"io"
@@ -22,6 +23,9 @@ type StateSection struct {
 	timestamp int64
 	// stateHash is hash of the state it is locked in the transaction
 	stateHash hashing.HashValue
+	// chainID is only present when the chain token is held by an address other than the chain ID,
+	// i.e. after the committee of the chain has been rotated. Otherwise chain ID is the address of the chain token
+	chainID *coretypes.ChainID
 }
 
 type NewStateSectionParams struct {
@@ -29,15 +33,21 @@ type NewStateSectionParams struct {
 	BlockIndex uint32
 	StateHash  hashing.HashValue
 	Timestamp  int64
+	ChainID    *coretypes.ChainID
 }
 
 func NewStateSection(par NewStateSectionParams) *StateSection {
-	return &StateSection{
+	ret := &StateSection{
 		color:      par.Color,
 		blockIndex: par.BlockIndex,
 		stateHash:  par.StateHash,
 		timestamp:  par.Timestamp,
 	}
+	if par.ChainID != nil {
+		chainID := *par.ChainID
+		ret.chainID = &chainID
+	}
+	return ret
 }
 
 func (sb *StateSection) Clone() *StateSection {
@@ -49,6 +59,7 @@ func (sb *StateSection) Clone() *StateSection {
 		BlockIndex: sb.blockIndex,
 		StateHash:  sb.stateHash,
 		Timestamp:  sb.timestamp,
+		ChainID:    sb.chainID,
 	})
 }
 
@@ -72,6 +83,25 @@ func (sb *StateSection) StateHash() hashing.HashValue {
 	return sb.stateHash
 }
 
+// ChainID returns the chain ID if it is present in the state section
+func (sb *StateSection) ChainID() (coretypes.ChainID, bool) {
+	if sb.chainID == nil {
+		return coretypes.ChainID{}, false
+	}
+	return *sb.chainID, true
+}
+
+// WithChainID sets the chain ID of the chain which token is held by an address other than the chain ID
+func (sb *StateSection) WithChainID(chainID *coretypes.ChainID) *StateSection {
+	if chainID == nil {
+		sb.chainID = nil
+		return sb
+	}
+	c := *chainID
+	sb.chainID = &c
+	return sb
+}
+
 func (sb *StateSection) WithStateParams(stateIndex uint32, h hashing.HashValue, ts int64) *StateSection {
 	sb.blockIndex = stateIndex
 	sb.stateHash = h
@@ -9,6 +9,7 @@ import (
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
 	valuetransaction "github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
+	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/util"
 	"io"
 )
@@ -138,6 +139,55 @@ func (tx *Transaction) writeDataPayload(w io.Writer) error {
 			return err
 		}
 	}
+	// the optional fields go after all sections, so the meta byte and the layout
+	// of the sections stay the same as for transactions without them
+	return tx.writeOptionalFields(w)
+}
+
+// writeOptionalFields writes the flags of the optional fields followed by the chain ID of the state section
+// and the target addresses of requests. Nothing is written if there are no optional fields
+func (tx *Transaction) writeOptionalFields(w io.Writer) error {
+	var flags byte
+	if tx.stateSection != nil && tx.stateSection.chainID != nil {
+		flags |= optionalStateChainID
+	}
+	numTargetAddresses := 0
+	for _, req := range tx.requestSection {
+		if req.targetAddress != nil {
+			numTargetAddresses++
+		}
+	}
+	if numTargetAddresses > 0 {
+		flags |= optionalRequestTargetAddress
+	}
+	if flags == 0 {
+		return nil
+	}
+	if err := util.WriteByte(w, flags); err != nil {
+		return err
+	}
+	if flags&optionalStateChainID != 0 {
+		if err := tx.stateSection.chainID.Write(w); err != nil {
+			return err
+		}
+	}
+	if flags&optionalRequestTargetAddress == 0 {
+		return nil
+	}
+	if err := util.WriteByte(w, byte(numTargetAddresses)); err != nil {
+		return err
+	}
+	for i, req := range tx.requestSection {
+		if req.targetAddress == nil {
+			continue
+		}
+		if err := util.WriteByte(w, byte(i)); err != nil {
+			return err
+		}
+		if _, err := w.Write(req.targetAddress[:]); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -166,6 +216,53 @@ func (tx *Transaction) readDataPayload(r io.Reader) error {
 	}
 	tx.stateSection = stateBlock
 	tx.requestSection = reqBlks
+	return tx.readOptionalFields(r)
+}
+
+func (tx *Transaction) readOptionalFields(r io.Reader) error {
+	var flags [1]byte
+	if n, _ := io.ReadFull(r, flags[:]); n == 0 {
+		// no optional fields
+		return nil
+	}
+	if flags[0] & ^(optionalStateChainID|optionalRequestTargetAddress) != 0 {
+		return fmt.Errorf("wrong flags of the optional fields: %x", flags[0])
+	}
+	if flags[0]&optionalStateChainID != 0 {
+		if tx.stateSection == nil {
+			return errors.New("chain ID of the state section in the transaction without state section")
+		}
+		var chainID coretypes.ChainID
+		if _, err := io.ReadFull(r, chainID[:]); err != nil {
+			return fmt.Errorf("wrong chain ID of the state section: %v", err)
+		}
+		tx.stateSection.chainID = &chainID
+	}
+	if flags[0]&optionalRequestTargetAddress == 0 {
+		return nil
+	}
+	var num byte
+	var err error
+	if num, err = util.ReadByte(r); err != nil {
+		return err
+	}
+	for i := 0; i < int(num); i++ {
+		var idx byte
+		if idx, err = util.ReadByte(r); err != nil {
+			return err
+		}
+		if int(idx) >= len(tx.requestSection) || tx.requestSection[idx].targetAddress != nil {
+			return fmt.Errorf("wrong index of the request target address: %d", idx)
+		}
+		var addr address.Address
+		if _, err = io.ReadFull(r, addr[:]); err != nil {
+			return fmt.Errorf("wrong target address of the request: %v", err)
+		}
+		if addr == address.Address(tx.requestSection[idx].Target().ChainID()) {
+			return errors.New("target address of the request must be omitted when it is equal to the chain ID")
+		}
+		tx.requestSection[idx].targetAddress = &addr
+	}
 	return nil
 }
 
@@ -7,6 +7,7 @@ import (
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
 	valuetransaction "github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
+	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/hashing"
 	"github.com/iotaledger/wasp/packages/sctransaction"
 	"github.com/iotaledger/wasp/packages/txutil/vtxbuilder"
@@ -16,6 +17,8 @@ type Builder struct {
 	*vtxbuilder.Builder
 	stateBlock    *sctransaction.StateSection
 	requestBlocks []*sctransaction.RequestSection
+	// addresses of the committees of chains which have been rotated
+	chainAddresses map[coretypes.ChainID]address.Address
 }
 
 var (
@@ -28,20 +31,25 @@ func NewFromOutputBalances(outputBalances map[valuetransaction.OutputID][]*balan
 		return nil, err
 	}
 	return &Builder{
-		Builder:       vtxb,
-		requestBlocks: make([]*sctransaction.RequestSection, 0),
+		Builder:        vtxb,
+		requestBlocks:  make([]*sctransaction.RequestSection, 0),
+		chainAddresses: make(map[coretypes.ChainID]address.Address),
 	}, nil
 }
 
 func (txb *Builder) Clone() *Builder {
 	ret := &Builder{
-		Builder:       txb.Builder.Clone(),
-		stateBlock:    txb.stateBlock.Clone(),
-		requestBlocks: make([]*sctransaction.RequestSection, len(txb.requestBlocks)),
+		Builder:        txb.Builder.Clone(),
+		stateBlock:     txb.stateBlock.Clone(),
+		requestBlocks:  make([]*sctransaction.RequestSection, len(txb.requestBlocks)),
+		chainAddresses: make(map[coretypes.ChainID]address.Address, len(txb.chainAddresses)),
 	}
 	for i := range ret.requestBlocks {
 		ret.requestBlocks[i] = txb.requestBlocks[i].Clone()
 	}
+	for chainID, addr := range txb.chainAddresses {
+		ret.chainAddresses[chainID] = addr
+	}
 	return ret
 }
 
@@ -73,10 +81,31 @@ func (txb *Builder) SetStateParams(stateIndex uint32, stateHash hashing.HashValu
 	return nil
 }
 
+// SetChainAddress sets the address of the current committee of the chain, as returned by 'root.getChainInfo'.
+// Requests to the chain are sent to that address instead of the chain ID
+func (txb *Builder) SetChainAddress(chainID coretypes.ChainID, addr address.Address) {
+	if addr == address.Address(chainID) {
+		delete(txb.chainAddresses, chainID)
+		return
+	}
+	txb.chainAddresses[chainID] = addr
+}
+
 // AddRequestSectionWithTransfer adds request block with the request
-// token and adds respective outputs for the colored transfers
+// token and adds respective outputs for the colored transfers.
+// The request token is sent to the address of the current committee of the target chain
 func (txb *Builder) AddRequestSection(req *sctransaction.RequestSection) error {
-	targetAddr := (address.Address)(req.Target().ChainID())
+	targetAddr, ok := txb.chainAddresses[req.Target().ChainID()]
+	if !ok {
+		targetAddr = (address.Address)(req.Target().ChainID())
+	}
+	return txb.AddRequestSectionToAddress(req, targetAddr)
+}
+
+// AddRequestSectionToAddress adds request block with the request token sent to the explicitly specified address.
+// It is used to send requests to the chain which has been rotated to the committee with a different address.
+// The address is recorded in the request section, so the outputs can be validated against the transfer
+func (txb *Builder) AddRequestSectionToAddress(req *sctransaction.RequestSection, targetAddr address.Address) error {
 	if err := txb.MintColor(targetAddr, balance.ColorIOTA, 1); err != nil {
 		return err
 	}
@@ -90,7 +119,7 @@ func (txb *Builder) AddRequestSection(req *sctransaction.RequestSection) error {
 			return true
 		})
 	}
-	txb.requestBlocks = append(txb.requestBlocks, req)
+	txb.requestBlocks = append(txb.requestBlocks, req.WithTargetAddress(targetAddr))
 	return nil
 }
 
@@ -5,8 +5,10 @@ import (
 
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address/signaturescheme"
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	valuetransaction "github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
 	"github.com/iotaledger/goshimmer/dapps/waspconn/packages/utxodb"
 	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/coretypes/cbalances"
 	"github.com/iotaledger/wasp/packages/hashing"
 	"github.com/iotaledger/wasp/packages/sctransaction"
 	"github.com/iotaledger/wasp/packages/txutil"
@@ -271,3 +273,45 @@ func TestDeterminism(t *testing.T) {
 
 	assert.EqualValues(t, tx.ID(), txClone.ID())
 }
+
+func TestRequestToRotatedCommittee(t *testing.T) {
+	u := utxodb.New()
+	ownerSigSheme := signaturescheme.RandBLS()
+	ownerAddress := ownerSigSheme.Address()
+	chainID := coretypes.ChainID(signaturescheme.RandBLS().Address())
+	committeeAddress := signaturescheme.RandBLS().Address()
+	_, err := u.RequestFunds(ownerAddress)
+	assert.NoError(t, err)
+
+	build := func(claimedTransfer int64) *sctransaction.Transaction {
+		txb, err := NewFromOutputBalances(u.GetAddressOutputs(ownerAddress))
+		assert.NoError(t, err)
+		txb.SetChainAddress(chainID, committeeAddress)
+		req := sctransaction.NewRequestSection(0, coretypes.NewContractID(chainID, 0), 1).
+			WithTransfer(cbalances.NewFromMap(map[balance.Color]int64{balance.ColorIOTA: 5}))
+		err = txb.AddRequestSection(req)
+		assert.NoError(t, err)
+		req.WithTransfer(cbalances.NewFromMap(map[balance.Color]int64{balance.ColorIOTA: claimedTransfer}))
+		tx, err := txb.Build(false)
+		assert.NoError(t, err)
+		tx.Sign(ownerSigSheme)
+		return tx
+	}
+	parse := func(tx *sctransaction.Transaction) (*sctransaction.Transaction, error) {
+		vtx, _, err := valuetransaction.FromBytes(tx.Transaction.Bytes())
+		assert.NoError(t, err)
+		return sctransaction.ParseValueTransaction(vtx)
+	}
+
+	tx, err := parse(build(5))
+	assert.NoError(t, err)
+	assert.EqualValues(t, committeeAddress, tx.Requests()[0].TargetAddress())
+	assert.EqualValues(t, chainID, tx.Requests()[0].Target().ChainID())
+	bals, ok := tx.OutputBalancesByAddress(committeeAddress)
+	assert.True(t, ok)
+	assert.EqualValues(t, 5+1, txutil.BalancesSumTotal(bals))
+
+	// the outputs to the committee address are validated against the transfer of the request
+	_, err = parse(build(10))
+	assert.Error(t, err)
+}
@@ -14,7 +14,7 @@ func TestExample1(t *testing.T) {
 	chain := env.NewChain(nil, "ex1")
 
 	chainInfo, coreContracts := chain.GetInfo()   // calls view root::GetInfo
-	require.EqualValues(t, 4, len(coreContracts)) // 4 core contracts deployed by default
+	require.EqualValues(t, 8, len(coreContracts)) // 8 core contracts deployed by default
 
 	t.Logf("chainID: %s", chainInfo.ChainID)
 	t.Logf("chain owner ID: %s", chainInfo.ChainOwnerID)
@@ -17,12 +17,17 @@ import (
 	"github.com/iotaledger/wasp/packages/kv/codec"
 	"github.com/iotaledger/wasp/packages/kv/collections"
 	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/tcrypto"
 	"github.com/iotaledger/wasp/packages/vm/core/accounts"
 	"github.com/iotaledger/wasp/packages/vm/core/blob"
 	"github.com/iotaledger/wasp/packages/vm/core/eventlog"
+	"github.com/iotaledger/wasp/packages/vm/core/nft"
 	"github.com/iotaledger/wasp/packages/vm/core/root"
+	"github.com/iotaledger/wasp/packages/vm/core/scheduler"
+	"github.com/iotaledger/wasp/packages/vm/core/tokens"
 	"github.com/iotaledger/wasp/plugins/wasmtimevm"
 	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3"
 	"io/ioutil"
 )
 
@@ -253,6 +258,101 @@ func (ch *Chain) DeployWasmContract(sigScheme signaturescheme.SignatureScheme, n
 	return ch.DeployContract(sigScheme, name, hprog, params...)
 }
 
+// UpgradeContract replaces the program of the contract with the given name by 'programHash'.
+// 'sigScheme' must be the creator of the contract or the chain owner (nil defaults to chain originator).
+// The contract keeps its hname, state and balances. Optional 'params' are passed to the 'migrate' entry point
+func (ch *Chain) UpgradeContract(sigScheme signaturescheme.SignatureScheme, name string, programHash hashing.HashValue, params ...interface{}) error {
+	par := []interface{}{root.ParamHname, coretypes.Hn(name), root.ParamProgramHash, programHash}
+	par = append(par, params...)
+	req := NewCallParams(root.Interface.Name, root.FuncUpgradeContract, par...)
+	_, err := ch.PostRequest(req, sigScheme)
+	return err
+}
+
+// UpgradeWasmContract is syntactic sugar for uploading Wasm binary from file and
+// upgrading the smart contract in one call
+func (ch *Chain) UpgradeWasmContract(sigScheme signaturescheme.SignatureScheme, name string, fname string, params ...interface{}) error {
+	hprog, err := ch.UploadWasmFromFile(sigScheme, fname)
+	if err != nil {
+		return err
+	}
+	return ch.UpgradeContract(sigScheme, name, hprog, params...)
+}
+
+// GetUpgradeHistory returns upgrade history of the contract, oldest first
+func (ch *Chain) GetUpgradeHistory(name string) ([]*root.UpgradeRecord, error) {
+	res, err := ch.CallView(root.Interface.Name, root.FuncGetUpgradeHistory, root.ParamHname, coretypes.Hn(name))
+	if err != nil {
+		return nil, err
+	}
+	return root.DecodeUpgradeHistory(res)
+}
+
+// GetScheduledJobs returns jobs of the 'scheduler' contract, sorted by ID.
+// If the name of the contract is given, only jobs of the contract are returned
+func (ch *Chain) GetScheduledJobs(name ...string) ([]*scheduler.JobRecord, error) {
+	params := make([]interface{}, 0, 2)
+	if len(name) > 0 {
+		params = append(params, scheduler.ParamContractHname, coretypes.Hn(name[0]))
+	}
+	res, err := ch.CallView(scheduler.Interface.Name, scheduler.FuncGetJobs, params...)
+	if err != nil {
+		return nil, err
+	}
+	return scheduler.DecodeJobRecords(res)
+}
+
+// GetNativeTokens returns chain-native tokens of the 'tokens' contract, sorted by the symbol
+func (ch *Chain) GetNativeTokens() ([]*tokens.TokenRecord, error) {
+	res, err := ch.CallView(tokens.Interface.Name, tokens.FuncGetTokens)
+	if err != nil {
+		return nil, err
+	}
+	return tokens.DecodeTokenRecords(res)
+}
+
+// GetTokenHoldings returns balances of chain-native tokens in the on-chain account controlled by the 'agentID'
+func (ch *Chain) GetTokenHoldings(agentID coretypes.AgentID) ([]*tokens.Holding, error) {
+	res, err := ch.CallView(tokens.Interface.Name, tokens.FuncGetHoldings, tokens.ParamAgentID, agentID)
+	if err != nil {
+		return nil, err
+	}
+	return tokens.DecodeHoldings(res)
+}
+
+// MintNFT uploads the blob with metadata of the NFT and mints the NFT to the account of the 'sigScheme'.
+// The metadata is given as pairs of field names and values, like in UploadBlob. Returns the ID of the NFT
+func (ch *Chain) MintNFT(sigScheme signaturescheme.SignatureScheme, metadata ...interface{}) (balance.Color, error) {
+	metadataHash, err := ch.UploadBlob(sigScheme, metadata...)
+	if err != nil {
+		return balance.Color{}, err
+	}
+	res, err := ch.PostRequest(NewCallParams(nft.Interface.Name, nft.FuncMint, nft.ParamMetadata, metadataHash), sigScheme)
+	if err != nil {
+		return balance.Color{}, err
+	}
+	id, _, err := codec.DecodeColor(res.MustGet(nft.ParamID))
+	return id, err
+}
+
+// GetNFT returns the NFT with the ID
+func (ch *Chain) GetNFT(id balance.Color) (*nft.NFTRecord, error) {
+	res, err := ch.CallView(nft.Interface.Name, nft.FuncGetNFT, nft.ParamID, id)
+	if err != nil {
+		return nil, err
+	}
+	return nft.DecodeNFTRecord(res.MustGet(nft.ParamNFT))
+}
+
+// GetNFTs returns NFTs owned by the 'agentID', sorted by the ID
+func (ch *Chain) GetNFTs(agentID coretypes.AgentID) ([]*nft.NFTRecord, error) {
+	res, err := ch.CallView(nft.Interface.Name, nft.FuncGetNFTs, nft.ParamAgentID, agentID)
+	if err != nil {
+		return nil, err
+	}
+	return nft.DecodeNFTRecords(res)
+}
+
 type ChainInfo struct {
 	ChainID      coretypes.ChainID
 	ChainOwnerID coretypes.AgentID
@@ -294,6 +394,33 @@ func (ch *Chain) GetInfo() (ChainInfo, map[coretypes.Hname]*root.ContractRecord)
 	}, contracts
 }
 
+// RotateCommittee moves the chain to the new committee, represented by the 'newChainSigScheme'.
+// It posts the 'root.rotateCommittee' request signed by 'sigScheme' (nil defaults to chain originator),
+// which must be the chain owner. The resulting state transaction moves the chain token and all
+// balances of the chain to the new address. The chain ID remains the same
+func (ch *Chain) RotateCommittee(sigScheme, newChainSigScheme signaturescheme.SignatureScheme) error {
+	req := NewCallParams(root.Interface.Name, root.FuncRotateCommittee,
+		root.ParamChainAddress, newChainSigScheme.Address())
+	if _, err := ch.PostRequest(req, sigScheme); err != nil {
+		return err
+	}
+	ch.ChainSigScheme = newChainSigScheme
+	ch.ChainAddress = newChainSigScheme.Address()
+	return nil
+}
+
+// StealthKeys returns the keys of the chain's committee to be used with CallParams.WithStealth
+func (ch *Chain) StealthKeys() *tcrypto.StealthKeys {
+	return &tcrypto.StealthKeys{
+		Suite:        stealthSuite,
+		PubKeyShares: []kyber.Point{ch.StealthKeyPair.Public},
+	}
+}
+
+func (ch *Chain) decryptStealth(data []byte) ([]byte, error) {
+	return tcrypto.DecryptStealth(stealthSuite, 0, ch.StealthKeyPair.Private, data)
+}
+
 // GetAddressBalance returns number of tokens of given color contained in the given address
 // on the UTXODB ledger
 func (env *Solo) GetAddressBalance(addr address.Address, col balance.Color) int64 {
@@ -422,6 +549,28 @@ func (ch *Chain) GetEventLogRecordsString(name string) (string, error) {
 	return ret, nil
 }
 
+// GetEvents returns the last structured events of the contract, in time descending order.
+// Optional parameters of the 'getEvents' view, e.g. eventlog.ParamEventName, can be given as key/value pairs
+func (ch *Chain) GetEvents(name string, params ...interface{}) ([]*eventlog.EventRecord, error) {
+	params = append(params, eventlog.ParamContractHname, coretypes.Hn(name))
+	res, err := ch.CallView(eventlog.Interface.Name, eventlog.FuncGetEvents, params...)
+	if err != nil {
+		return nil, err
+	}
+	return eventlog.DecodeEventRecords(res)
+}
+
+// GetEventsByTopic returns the last structured events of the chain with the topic, in time descending order.
+// Optional parameters of the 'getEventsByTopic' view, e.g. eventlog.ParamContractHname, can be given as key/value pairs
+func (ch *Chain) GetEventsByTopic(topic []byte, params ...interface{}) ([]*eventlog.EventRecord, error) {
+	params = append(params, eventlog.ParamTopic, topic)
+	res, err := ch.CallView(eventlog.Interface.Name, eventlog.FuncGetEventsByTopic, params...)
+	if err != nil {
+		return nil, err
+	}
+	return eventlog.DecodeEventRecords(res)
+}
+
 // GetEventLogNumRecords returns total number of eventlog records for the given contact.
 func (ch *Chain) GetEventLogNumRecords(name string) int {
 	res, err := ch.CallView(eventlog.Interface.Name, eventlog.FuncGetNumRecords,
@@ -18,6 +18,8 @@ import (
 	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/packages/sctransaction"
 	"github.com/iotaledger/wasp/packages/sctransaction/txbuilder"
+	"github.com/iotaledger/wasp/packages/state"
+	"github.com/iotaledger/wasp/packages/tcrypto"
 	"github.com/iotaledger/wasp/packages/vm/viewcontext"
 	"github.com/stretchr/testify/require"
 )
@@ -29,6 +31,8 @@ type CallParams struct {
 	entryPoint coretypes.Hname
 	transfer   coretypes.ColoredBalances
 	args       requestargs.RequestArgs
+	gasBudget  uint64
+	stealth    *tcrypto.StealthKeys
 }
 
 func NewCallParamsFromDic(scName, funName string, par dict.Dict) *CallParams {
@@ -86,6 +90,20 @@ func (r *CallParams) WithTransfers(transfer map[balance.Color]int64) *CallParams
 	return r
 }
 
+// WithGasBudget sets maximum amount of gas the request is allowed to burn.
+// If not set, coretypes.DefaultGasBudget is in effect
+func (r *CallParams) WithGasBudget(gasBudget uint64) *CallParams {
+	r.gasBudget = gasBudget
+	return r
+}
+
+// WithStealth makes the request "stealth": request arguments are encrypted with
+// the keys of the committee, so only committee nodes can read them. See Chain.StealthKeys
+func (r *CallParams) WithStealth(keys *tcrypto.StealthKeys) *CallParams {
+	r.stealth = keys
+	return r
+}
+
 // makes map without hashing
 func toMap(params ...interface{}) map[string]interface{} {
 	par := make(map[string]interface{})
@@ -127,11 +145,17 @@ func (ch *Chain) PostRequest(req *CallParams, sigScheme signaturescheme.Signatur
 	txb, err := txbuilder.NewFromOutputBalances(allOuts)
 	require.NoError(ch.Env.T, err)
 
+	args := req.args
+	if req.stealth != nil {
+		args, err = requestargs.NewEncryptedRequestArgs(args, req.stealth.Encrypt)
+		require.NoError(ch.Env.T, err)
+	}
 	reqSect := sctransaction.NewRequestSectionByWallet(coretypes.NewContractID(ch.ChainID, req.target), req.entryPoint).
 		WithTransfer(req.transfer).
-		WithArgs(req.args)
+		WithArgs(args).
+		WithGasBudget(req.gasBudget)
 
-	err = txb.AddRequestSection(reqSect)
+	err = txb.AddRequestSectionToAddress(reqSect, ch.ChainAddress)
 	require.NoError(ch.Env.T, err)
 
 	tx, err := txb.Build(false)
@@ -180,6 +204,27 @@ func (ch *Chain) CallView(scName string, funName string, params ...interface{})
 	return vctx.CallView(coretypes.Hn(scName), coretypes.Hn(funName), p)
 }
 
+// CallViewAtBlock calls the view entry point of the smart contract as of the past state of the chain,
+// right after the block with index blockIndex. The past state is reconstructed from the blocks of the chain
+func (ch *Chain) CallViewAtBlock(blockIndex uint32, scName string, funName string, params ...interface{}) (dict.Dict, error) {
+	ch.Log.Infof("callViewAtBlock #%d: %s::%s", blockIndex, scName, funName)
+
+	p := codec.MakeDict(toMap(params...))
+
+	ch.runVMMutex.Lock()
+	defer ch.runVMMutex.Unlock()
+
+	pastState, _, ok, err := state.LoadStateAtFromDB(ch.stateDB, &ch.ChainID, blockIndex)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("state #%d not found", blockIndex)
+	}
+	vctx := viewcontext.New(ch.ChainID, pastState.Variables(), pastState.Timestamp(), ch.proc, ch.Log)
+	return vctx.CallView(coretypes.Hn(scName), coretypes.Hn(funName), p)
+}
+
 // WaitForEmptyBacklog waits until the backlog queue of the chain becomes empty.
 // It is useful when smart contract(s) in the test are posting asynchronous requests
 // between chains.
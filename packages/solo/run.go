@@ -5,6 +5,7 @@ package solo
 
 import (
 	"fmt"
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
 	"github.com/iotaledger/goshimmer/dapps/waspconn/packages/waspconn"
 	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/hashing"
@@ -12,6 +13,7 @@ import (
 	"github.com/iotaledger/wasp/packages/sctransaction"
 	"github.com/iotaledger/wasp/packages/state"
 	"github.com/iotaledger/wasp/packages/vm"
+	"github.com/iotaledger/wasp/packages/vm/core/scheduler"
 	"github.com/iotaledger/wasp/packages/vm/runvm"
 	"github.com/stretchr/testify/require"
 	"strings"
@@ -23,16 +25,29 @@ func (ch *Chain) runBatch(batch []vm.RequestRefWithFreeTokens, trace string) (di
 	ch.runVMMutex.Lock()
 	defer ch.runVMMutex.Unlock()
 
-	// solidify arguments
-	for _, reqRef := range batch {
-		if ok, err := reqRef.RequestSection().SolidifyArgs(ch.Env.registry); err != nil || !ok {
-			return nil, fmt.Errorf("solo inconsistency: failed to solidify request args")
+	// decrypt arguments of stealth requests and solidify arguments.
+	// Solo has no peers to receive missing data from, so the request is processed
+	// as if the solidification of arguments timed out
+	for i, reqRef := range batch {
+		if err := reqRef.RequestSection().DecryptArgs(ch.decryptStealth); err != nil {
+			ch.Log.Warnf("solo: failed to decrypt request args: %v", err)
+			batch[i].ArgsTimeout = true
+			continue
+		}
+		ok, err := reqRef.RequestSection().SolidifyArgs(ch.Env.registry)
+		if err != nil {
+			return nil, fmt.Errorf("solo inconsistency: failed to solidify request args: %v", err)
+		}
+		if !ok {
+			ch.Log.Warnf("solo: request args are not solid")
+			batch[i].ArgsTimeout = true
 		}
 	}
 
 	task := &vm.VMTask{
 		Processors:         ch.proc,
 		ChainID:            ch.ChainID,
+		ChainAddress:       ch.ChainAddress,
 		Color:              ch.ChainColor,
 		Entropy:            hashing.RandomHash(nil),
 		ValidatorFeeTarget: ch.ValidatorFeeTarget,
@@ -42,6 +57,10 @@ func (ch *Chain) runBatch(batch []vm.RequestRefWithFreeTokens, trace string) (di
 		VirtualState:       ch.State.Clone(),
 		Log:                ch.Log,
 	}
+	if ch.ChainAddress != address.Address(ch.ChainID) {
+		// requests sent to the chain ID address after the committee rotation are swept by the state transaction
+		task.ChainIDBalances = waspconn.OutputsToBalances(ch.Env.utxoDB.GetAddressOutputs(address.Address(ch.ChainID)))
+	}
 	var err error
 	var wg sync.WaitGroup
 	var callRes dict.Dict
@@ -59,11 +78,26 @@ func (ch *Chain) runBatch(batch []vm.RequestRefWithFreeTokens, trace string) (di
 
 	wg.Wait()
 	task.ResultTransaction.Sign(ch.ChainSigScheme)
+	if len(task.ChainIDBalances) > 0 {
+		task.ResultTransaction.Sign(ch.chainIDSigScheme)
+	}
 
 	ch.settleStateTransition(task.VirtualState, task.ResultBlock, task.ResultTransaction)
 	return callRes, callErr
 }
 
+// RunScheduledJobs runs the block with jobs of the 'scheduler' which are due at the current logical time,
+// the same way the committee leader starts the block when jobs are due and there are no requests.
+// Due jobs are also run at the beginning of each block with requests.
+// Returns false if there were no due jobs
+func (ch *Chain) RunScheduledJobs() bool {
+	if !scheduler.HasDueJobs(ch.State.Variables(), ch.Env.LogicalTime().UnixNano()) {
+		return false
+	}
+	_, _ = ch.runBatch(nil, "scheduled jobs")
+	return true
+}
+
 func (ch *Chain) settleStateTransition(newState state.VirtualState, block state.Block, stateTx *sctransaction.Transaction) {
 	err := ch.Env.utxoDB.AddTransaction(stateTx.Transaction)
 	require.NoError(ch.Env.T, err)
@@ -13,6 +13,7 @@ import (
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
 	"github.com/iotaledger/goshimmer/dapps/waspconn/packages/utxodb"
 	"github.com/iotaledger/hive.go/crypto/ed25519"
+	"github.com/iotaledger/hive.go/kvstore"
 	"github.com/iotaledger/hive.go/kvstore/mapdb"
 	"github.com/iotaledger/hive.go/logger"
 	"github.com/iotaledger/wasp/packages/coretypes"
@@ -30,6 +31,8 @@ import (
 	"github.com/iotaledger/wasp/packages/vm/wasmproc"
 	"github.com/iotaledger/wasp/plugins/wasmtimevm"
 	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/util/key"
 	"go.uber.org/zap/zapcore"
 )
 
@@ -71,10 +74,10 @@ type Chain struct {
 	// It is a default signature scheme in many of 'solo' calls which require private key.
 	OriginatorSigScheme signaturescheme.SignatureScheme
 
-	// ChainID is the ID of the chain (in this version alias of the ChainAddress)
+	// ChainID is the ID of the chain. It is equal to the address of the initial committee
 	ChainID coretypes.ChainID
 
-	// ChainAddress is the alias of ChainSigScheme.Address()
+	// ChainAddress is the alias of ChainSigScheme.Address(). It differs from ChainID after the committee rotation
 	ChainAddress address.Address
 
 	// ChainColor is the color of the non-fungible token of the chain.
@@ -96,12 +99,23 @@ type Chain struct {
 	// State ia an interface to access virtual state of the chain: the collection of key/value pairs
 	State state.VirtualState
 
+	// StealthKeyPair is the key of the committee used to decrypt arguments of "stealth" requests.
+	// In Solo the committee consists of one member (in full Wasp environment it is a distributed key share)
+	StealthKeyPair *key.Pair
+
 	// Log is the named logger of the chain
 	Log *logger.Logger
 
 	// processor cache
 	proc *processors.ProcessorCache
 
+	// the signature scheme of the chain ID address. After the committee rotation it is used
+	// to sweep requests sent to the chain ID address
+	chainIDSigScheme signaturescheme.SignatureScheme
+
+	// the db partition of the chain. It keeps all blocks, so any past state can be reconstructed
+	stateDB kvstore.KVStore
+
 	// related to asynchronous backlog processing
 	runVMMutex   *sync.Mutex
 	chPosted     sync.WaitGroup
@@ -113,8 +127,9 @@ type Chain struct {
 }
 
 var (
-	doOnce    = sync.Once{}
-	glbLogger *logger.Logger
+	doOnce       = sync.Once{}
+	glbLogger    *logger.Logger
+	stealthSuite = pairing.NewSuiteBn256()
 )
 
 // New creates an instance of the `solo` environment for the test instances.
@@ -174,17 +189,21 @@ func (env *Solo) NewChain(chainOriginator signaturescheme.SignatureScheme, name
 	if len(validatorFeeTarget) > 0 {
 		feeTarget = validatorFeeTarget[0]
 	}
+	stateDB := mapdb.NewMapDB()
 	ret := &Chain{
 		Env:                 env,
 		Name:                name,
 		ChainSigScheme:      chSig,
+		chainIDSigScheme:    chSig,
 		OriginatorSigScheme: chainOriginator,
 		ChainAddress:        chSig.Address(),
 		OriginatorAddress:   chainOriginator.Address(),
 		OriginatorAgentID:   originatorAgentID,
 		ValidatorFeeTarget:  feeTarget,
 		ChainID:             chainID,
-		State:               state.NewVirtualState(mapdb.NewMapDB(), &chainID),
+		State:               state.NewVirtualState(stateDB, &chainID),
+		StealthKeyPair:      key.NewKeyPair(stealthSuite),
+		stateDB:             stateDB,
 		proc:                processors.MustNew(),
 		Log:                 env.logger.Named(name),
 		//
@@ -27,3 +27,29 @@ func (ch *Chain) RevokeDeployPermission(sigScheme signaturescheme.SignatureSchem
 	_, err := ch.PostRequest(req, sigScheme)
 	return err
 }
+
+// PauseContract stops processing of requests to the contract. 'sigScheme' must be the chain owner
+func (ch *Chain) PauseContract(sigScheme signaturescheme.SignatureScheme, name string) error {
+	req := NewCallParams(root.Interface.Name, root.FuncPauseContract, root.ParamHname, coretypes.Hn(name))
+	_, err := ch.PostRequest(req, sigScheme)
+	return err
+}
+
+// UnpauseContract resumes processing of requests to the paused contract. 'sigScheme' must be the chain owner
+func (ch *Chain) UnpauseContract(sigScheme signaturescheme.SignatureScheme, name string) error {
+	req := NewCallParams(root.Interface.Name, root.FuncUnpauseContract, root.ParamHname, coretypes.Hn(name))
+	_, err := ch.PostRequest(req, sigScheme)
+	return err
+}
+
+// RemoveContract removes the contract from the chain. Funds of the contract are moved to 'sweepTo'
+// (defaults to the chain owner). 'sigScheme' must be the chain owner
+func (ch *Chain) RemoveContract(sigScheme signaturescheme.SignatureScheme, name string, sweepTo ...coretypes.AgentID) error {
+	par := []interface{}{root.ParamHname, coretypes.Hn(name)}
+	if len(sweepTo) > 0 {
+		par = append(par, root.ParamSweepTo, sweepTo[0])
+	}
+	req := NewCallParams(root.Interface.Name, root.FuncRemoveContract, par...)
+	_, err := ch.PostRequest(req, sigScheme)
+	return err
+}
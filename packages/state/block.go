@@ -107,10 +107,14 @@ func (b *block) Size() uint16 {
 	return uint16(len(b.stateUpdates))
 }
 
+// RequestIDs returns IDs of the requests processed in the block. Runs of scheduled jobs are not included
 func (b *block) RequestIDs() []*coretypes.RequestID {
-	ret := make([]*coretypes.RequestID, b.Size())
-	for i, su := range b.stateUpdates {
-		ret[i] = su.RequestID()
+	ret := make([]*coretypes.RequestID, 0, b.Size())
+	for _, su := range b.stateUpdates {
+		if res := su.Result(); res != nil && res.Job {
+			continue
+		}
+		ret = append(ret, su.RequestID())
 	}
 	return ret
 }
@@ -183,7 +187,11 @@ func dbkeyBatch(stateIndex uint32) []byte {
 }
 
 func LoadBlock(chainID *coretypes.ChainID, stateIndex uint32) (Block, error) {
-	data, err := database.GetPartition(chainID).Get(dbkeyBatch(stateIndex))
+	return loadBlock(database.GetPartition(chainID), stateIndex)
+}
+
+func loadBlock(db kvstore.KVStore, stateIndex uint32) (Block, error) {
+	data, err := db.Get(dbkeyBatch(stateIndex))
 	if err == kvstore.ErrKeyNotFound {
 		return nil, nil
 	}
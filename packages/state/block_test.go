@@ -5,11 +5,13 @@ import (
 
 	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/kv/buffered"
+	"github.com/iotaledger/wasp/packages/kv/dict"
 
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
 	"github.com/iotaledger/wasp/packages/hashing"
 	"github.com/iotaledger/wasp/packages/util"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestBatches(t *testing.T) {
@@ -69,3 +71,46 @@ func TestBatchMarshaling(t *testing.T) {
 
 	assert.EqualValues(t, util.GetHashValue(batch1), util.GetHashValue(batch2))
 }
+
+func TestBatchMarshalingResults(t *testing.T) {
+	txid1 := (transaction.ID)(hashing.HashStrings("test string 1"))
+	reqid1 := coretypes.NewRequestID(txid1, 0)
+	reqid2 := coretypes.NewRequestID(txid1, 2)
+	reqid3 := coretypes.NewRequestID(txid1, 3)
+	reqid4 := coretypes.NewRequestID(txid1, 4)
+	su1 := NewStateUpdate(&reqid1).WithResult(&RequestResult{
+		Contract:  coretypes.Hn("contract"),
+		Result:    dict.Dict{"k": []byte{1}},
+		GasBurned: 10,
+	})
+	su2 := NewStateUpdate(&reqid2).WithResult(&RequestResult{
+		Contract: coretypes.Hn("contract"),
+		Error:    "failed",
+	})
+	su3 := NewStateUpdate(&reqid3)
+	su4 := NewStateUpdate(&reqid4).WithResult(&RequestResult{
+		Contract: coretypes.Hn("contract"),
+		Job:      true,
+		JobID:    5,
+	})
+	batch1, err := NewBlock([]StateUpdate{su1, su2, su3, su4})
+	require.NoError(t, err)
+
+	b, err := util.Bytes(batch1)
+	require.NoError(t, err)
+	batch2, err := NewBlockFromBytes(b)
+	require.NoError(t, err)
+
+	results := make([]*RequestResult, 0)
+	batch2.ForEach(func(_ uint16, su StateUpdate) bool {
+		results = append(results, su.Result())
+		return true
+	})
+	assert.EqualValues(t, []*RequestResult{su1.Result(), su2.Result(), nil, su4.Result()}, results)
+	// runs of scheduled jobs are not requests
+	assert.EqualValues(t, []*coretypes.RequestID{&reqid1, &reqid2, &reqid3}, batch2.RequestIDs())
+
+	// the results are part of the essence of the block
+	su1.Result().GasBurned = 11
+	assert.NotEqual(t, batch1.EssenceHash(), batch2.EssenceHash())
+}
@@ -0,0 +1,218 @@
+package state
+
+import (
+	"sync"
+
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/dbprovider"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/buffered"
+	"github.com/iotaledger/wasp/packages/util"
+)
+
+const (
+	// maximum total size in bytes of the variables and Merkle nodes of the reconstructed past states kept in memory
+	historyCacheMaxBytes = 64 * 1024 * 1024
+	// while the blocks are re-played, the state after each block with the index multiple of
+	// historyCheckpointInterval is cached as a checkpoint
+	historyCheckpointInterval = 100
+)
+
+// historyCache keeps the recently reconstructed past states. Past states never change,
+// so the cached state is re-used as the starting point of the reconstruction of any later state.
+// The cache is limited by the total size of the cached states, least recently used states are evicted first
+type historyCache struct {
+	mutex    sync.Mutex
+	entries  []*historyCacheEntry
+	clock    uint64
+	size     int
+	maxBytes int
+}
+
+type historyCacheEntry struct {
+	db       kvstore.KVStore
+	chainID  coretypes.ChainID
+	state    VirtualState
+	block    Block
+	lastUsed uint64
+	size     int
+}
+
+var pastStates = &historyCache{maxBytes: historyCacheMaxBytes}
+
+// LoadStateAt reconstructs the virtual state of the chain as it was after the block with the given index.
+// Returns false if the block index is ahead of the solid state or if the state can't be reconstructed
+// because the blocks before it were pruned or the state is before the imported snapshot
+func LoadStateAt(chainID *coretypes.ChainID, blockIndex uint32) (VirtualState, Block, bool, error) {
+	return LoadStateAtFromDB(getSCPartition(chainID), chainID, blockIndex)
+}
+
+// LoadStateAtFromDB reconstructs the past virtual state from the blocks stored in the chain partition db.
+// The solid state is returned as is. Any other state is re-played in memory from the closest cached
+// state before it or, if there's none or the blocks following it were pruned, from the origin block
+// or from the state imported from the snapshot.
+// The reconstructed state is detached from the db, so it can't be committed
+func LoadStateAtFromDB(db kvstore.KVStore, chainID *coretypes.ChainID, blockIndex uint32) (VirtualState, Block, bool, error) {
+	solidIndexBin, err := db.Get(dbprovider.MakeKey(dbprovider.ObjectTypeSolidStateIndex))
+	if err == kvstore.ErrKeyNotFound {
+		return nil, nil, false, nil
+	}
+	if err != nil {
+		return nil, nil, false, err
+	}
+	solidIndex := util.MustUint32From4Bytes(solidIndexBin)
+	if blockIndex > solidIndex {
+		return nil, nil, false, nil
+	}
+	if blockIndex == solidIndex {
+		return loadSolidState(db, chainID)
+	}
+	vs, block := pastStates.closest(db, chainID, blockIndex)
+	if vs != nil && vs.BlockIndex() == blockIndex {
+		return vs, block, true, nil
+	}
+	first, err := firstRetainedBlockIndex(db)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	if vs != nil && vs.BlockIndex()+1 < first {
+		// the blocks following the cached state were pruned, the state is re-played from the snapshot base instead
+		vs = nil
+	}
+	var from uint32
+	if vs == nil && first > 0 {
+		// the state imported from the snapshot is the earliest state which can be re-played
+		var ok bool
+		if vs, ok, err = loadSnapshotBase(db, chainID); err != nil {
+			return nil, nil, false, err
+		}
+		if ok && vs.BlockIndex() == blockIndex {
+			if block, err = loadBlock(db, blockIndex); err != nil || block == nil {
+				return nil, nil, false, err
+			}
+			return vs, block, true, nil
+		}
+		if ok && vs.BlockIndex() > blockIndex {
+			return nil, nil, false, nil
+		}
+	}
+	if vs != nil {
+		from = vs.BlockIndex() + 1
+	} else {
+		vs = NewVirtualState(mapdb.NewMapDB(), chainID)
+	}
+	if from < first {
+		// blocks needed to re-play the state are not in the db
+		return nil, nil, false, nil
+	}
+	for i := from; i <= blockIndex; i++ {
+		block, err = loadBlock(db, i)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		if block == nil {
+			// the block was pruned in the meantime
+			return nil, nil, false, nil
+		}
+		if err = vs.applyBlock(block); err != nil {
+			return nil, nil, false, err
+		}
+		if i%historyCheckpointInterval == 0 && i != blockIndex {
+			vs.updateMerkleRoot()
+			pastStates.add(db, chainID, vs.Clone(), block)
+		}
+	}
+	vs.updateMerkleRoot()
+	pastStates.add(db, chainID, vs.Clone(), block)
+	return vs, block, true, nil
+}
+
+// closest returns the copy of the cached state of the chain with the largest index not greater than blockIndex
+func (c *historyCache) closest(db kvstore.KVStore, chainID *coretypes.ChainID, blockIndex uint32) (*virtualState, Block) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var ret *historyCacheEntry
+	for _, e := range c.entries {
+		if e.db != db || e.chainID != *chainID || e.state.BlockIndex() > blockIndex {
+			continue
+		}
+		if ret == nil || e.state.BlockIndex() > ret.state.BlockIndex() {
+			ret = e
+		}
+	}
+	if ret == nil {
+		return nil, nil
+	}
+	c.clock++
+	ret.lastUsed = c.clock
+	return ret.state.Clone().(*virtualState), ret.block
+}
+
+// forget removes all cached states of the chain
+func (c *historyCache) forget(db kvstore.KVStore, chainID *coretypes.ChainID) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entries := c.entries[:0]
+	for _, e := range c.entries {
+		if e.db != db || e.chainID != *chainID {
+			entries = append(entries, e)
+		} else {
+			c.size -= e.size
+		}
+	}
+	c.entries = entries
+}
+
+func (c *historyCache) add(db kvstore.KVStore, chainID *coretypes.ChainID, vs VirtualState, block Block) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.clock++
+	for _, e := range c.entries {
+		if e.db == db && e.chainID == *chainID && e.state.BlockIndex() == vs.BlockIndex() {
+			e.lastUsed = c.clock
+			return
+		}
+	}
+	entry := &historyCacheEntry{
+		db:       db,
+		chainID:  *chainID,
+		state:    vs,
+		block:    block,
+		lastUsed: c.clock,
+		size:     stateSize(vs),
+	}
+	if entry.size > c.maxBytes {
+		// the state alone doesn't fit
+		return
+	}
+	for c.size+entry.size > c.maxBytes {
+		lru := 0
+		for i, e := range c.entries {
+			if e.lastUsed < c.entries[lru].lastUsed {
+				lru = i
+			}
+		}
+		c.size -= c.entries[lru].size
+		c.entries = append(c.entries[:lru], c.entries[lru+1:]...)
+	}
+	c.entries = append(c.entries, entry)
+	c.size += entry.size
+}
+
+// stateSize is the number of bytes of the keys and values the state keeps in memory
+func stateSize(vs VirtualState) int {
+	s := vs.(*virtualState)
+	ret := 0
+	count := func(key kv.Key, mut buffered.Mutation) bool {
+		ret += len(key) + len(mut.Value())
+		return true
+	}
+	s.variables.Mutations().IterateLatest(count)
+	s.merkleNodes.Mutations().IterateLatest(count)
+	return ret
+}
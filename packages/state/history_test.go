@@ -0,0 +1,187 @@
+package state
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv/buffered"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadStateAt(t *testing.T) {
+	chainID := coretypes.ChainID{1, 3, 3, 7}
+	db := mapdb.NewMapDB()
+	vs := NewVirtualState(db, &chainID)
+
+	_, _, ok, err := LoadStateAtFromDB(db, &chainID, 0)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	hashes := make([]hashing.HashValue, 0)
+	for i := uint32(0); i < 4; i++ {
+		txid := (transaction.ID)(hashing.HashStrings(fmt.Sprintf("test string %d", i)))
+		reqid := coretypes.NewRequestID(txid, 0)
+		su := NewStateUpdate(&reqid)
+		su.Mutations().Add(buffered.NewMutationSet("counter", []byte{byte(i)}))
+		if i == 2 {
+			su.Mutations().Add(buffered.NewMutationDel("counter"))
+		}
+		block, err := NewBlock([]StateUpdate{su})
+		require.NoError(t, err)
+		block.WithBlockIndex(i)
+
+		require.NoError(t, vs.ApplyBlock(block))
+		require.NoError(t, vs.CommitToDb(block))
+		hashes = append(hashes, vs.Hash())
+	}
+
+	for i := uint32(0); i < 4; i++ {
+		past, block, ok, err := LoadStateAtFromDB(db, &chainID, i)
+		require.NoError(t, err)
+		require.True(t, ok)
+		assert.EqualValues(t, i, past.BlockIndex())
+		assert.EqualValues(t, i, block.StateIndex())
+		assert.EqualValues(t, hashes[i], past.Hash())
+
+		v := past.Variables().MustGet("counter")
+		if i == 2 {
+			assert.Nil(t, v)
+		} else {
+			assert.EqualValues(t, []byte{byte(i)}, v)
+		}
+	}
+
+	_, _, ok, err = LoadStateAtFromDB(db, &chainID, 4)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestLoadStateAtCached(t *testing.T) {
+	chainID := coretypes.ChainID{1, 3, 3, 7}
+	db := mapdb.NewMapDB()
+	commitBlocks(t, db, &chainID, historyCheckpointInterval+10, time.Now())
+
+	past, _, ok, err := LoadStateAtFromDB(db, &chainID, historyCheckpointInterval+5)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.EqualValues(t, []byte{historyCheckpointInterval + 5}, past.Variables().MustGet("counter"))
+
+	// the past states re-played before the blocks were pruned are still available from the cache
+	for {
+		pruned, err := pruneState(db, &chainID, &PruningParams{KeepBlocks: 3}, math.MaxUint32, time.Now())
+		require.NoError(t, err)
+		if pruned == 0 {
+			break
+		}
+	}
+	first, err := firstRetainedBlockIndex(db)
+	require.NoError(t, err)
+	require.EqualValues(t, historyCheckpointInterval+7, first)
+
+	for _, i := range []uint32{historyCheckpointInterval, historyCheckpointInterval + 5} {
+		cached, block, ok, err := LoadStateAtFromDB(db, &chainID, i)
+		require.NoError(t, err)
+		require.True(t, ok)
+		assert.EqualValues(t, i, block.StateIndex())
+		assert.EqualValues(t, []byte{byte(i)}, cached.Variables().MustGet("counter"))
+	}
+	cached, _, _, err := LoadStateAtFromDB(db, &chainID, historyCheckpointInterval+5)
+	require.NoError(t, err)
+	assert.EqualValues(t, past.Hash(), cached.Hash())
+
+	_, _, ok, err = LoadStateAtFromDB(db, &chainID, historyCheckpointInterval-1)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestLoadStateAtPrunedCache(t *testing.T) {
+	chainID := coretypes.ChainID{1, 3, 3, 7}
+	db := mapdb.NewMapDB()
+	commitBlocks(t, db, &chainID, 10, time.Now())
+	cached, cachedBlock, ok, err := LoadStateAtFromDB(db, &chainID, 5)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	vs, block, ok, err := loadSolidState(db, &chainID)
+	require.NoError(t, err)
+	require.True(t, ok)
+	s, err := NewSnapshot(&chainID, vs, block)
+	require.NoError(t, err)
+	db1 := mapdb.NewMapDB()
+	require.NoError(t, importSnapshot(db1, s))
+	vs1, _, ok, err := loadSolidState(db1, &chainID)
+	require.NoError(t, err)
+	require.True(t, ok)
+	txid := (transaction.ID)(hashing.HashStrings("test string 10"))
+	reqid := coretypes.NewRequestID(txid, 0)
+	for i := uint32(10); i < 12; i++ {
+		su := NewStateUpdate(&reqid)
+		su.Mutations().Add(buffered.NewMutationSet("counter", []byte{byte(i)}))
+		tail, err := NewBlock([]StateUpdate{su})
+		require.NoError(t, err)
+		tail.WithBlockIndex(i)
+		require.NoError(t, vs1.ApplyBlock(tail))
+		require.NoError(t, vs1.CommitToDb(tail))
+	}
+
+	// the blocks after the cached state are not in the db, the state is re-played from the snapshot base
+	pastStates.add(db1, &chainID, cached, cachedBlock)
+	past, _, ok, err := LoadStateAtFromDB(db1, &chainID, 10)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.EqualValues(t, []byte{10}, past.Variables().MustGet("counter"))
+
+	// the cached state itself is still available
+	past, _, ok, err = LoadStateAtFromDB(db1, &chainID, 5)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.EqualValues(t, []byte{5}, past.Variables().MustGet("counter"))
+}
+
+func TestHistoryCacheSize(t *testing.T) {
+	chainID := coretypes.ChainID{1, 3, 3, 7}
+	db := mapdb.NewMapDB()
+	// the solid state is the last one, it is not kept in memory
+	commitBlocks(t, db, &chainID, 5, time.Now())
+	states := make([]VirtualState, 4)
+	for i := range states {
+		vs, _, ok, err := LoadStateAtFromDB(db, &chainID, uint32(i))
+		require.NoError(t, err)
+		require.True(t, ok)
+		states[i] = vs
+	}
+	size := stateSize(states[3])
+	require.True(t, size > 0)
+
+	c := &historyCache{maxBytes: 2 * size}
+	c.add(db, &chainID, states[1], nil)
+	c.add(db, &chainID, states[2], nil)
+	c.closest(db, &chainID, 1)
+	// the least recently used state is evicted to fit the total size
+	c.add(db, &chainID, states[3], nil)
+	assert.True(t, c.size <= c.maxBytes)
+	vs, _ := c.closest(db, &chainID, 2)
+	require.NotNil(t, vs)
+	assert.EqualValues(t, 1, vs.BlockIndex())
+	vs, _ = c.closest(db, &chainID, 3)
+	require.NotNil(t, vs)
+	assert.EqualValues(t, 3, vs.BlockIndex())
+
+	// the state larger than the cache is not cached
+	c = &historyCache{maxBytes: size - 1}
+	c.add(db, &chainID, states[3], nil)
+	assert.Empty(t, c.entries)
+	assert.Zero(t, c.size)
+
+	c = &historyCache{maxBytes: 2 * size}
+	c.add(db, &chainID, states[3], nil)
+	c.forget(db, &chainID)
+	assert.Zero(t, c.size)
+}
@@ -0,0 +1,323 @@
+package state
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/util"
+)
+
+// The virtual state commits to a sparse binary Merkle tree over all key/value pairs of Variables().
+// The position of the key/value pair in the tree is given by the bits of the hash of the key.
+// A subtree with exactly one leaf is represented by the leaf itself, i.e. the leaf is located at the
+// shortest prefix of the key hash which is unique among all keys. An empty subtree is hashing.NilHash.
+// Leaf and internal node hashes are domain separated by a prefix byte.
+// The tree does not depend on the order of updates, and the update of one key only changes
+// the nodes on its path, so the cost of the update is logarithmic in the size of the state.
+// The Merkle root of an empty state is hashing.NilHash
+
+const (
+	merkleLeafPrefix = byte(0)
+	merkleNodePrefix = byte(1)
+	// maximum depth of the leaf: leaves of two keys with hashes different only in the last bit
+	merkleMaxDepth = hashing.HashSize * 8
+)
+
+// MerkleProof is a proof of inclusion of a key/value pair into the Merkle tree of the virtual state
+type MerkleProof struct {
+	// hashes of siblings on the path from the leaf to the root. The length of the path is the depth of the leaf
+	Path []hashing.HashValue
+}
+
+func merkleLeafHash(key kv.Key, value []byte) hashing.HashValue {
+	return hashing.HashData([]byte{merkleLeafPrefix}, util.Uint16To2Bytes(uint16(len(key))), []byte(key), value)
+}
+
+func merkleNodeHash(left, right *hashing.HashValue) hashing.HashValue {
+	return hashing.HashData([]byte{merkleNodePrefix}, left[:], right[:])
+}
+
+func merkleKeyHash(key kv.Key) hashing.HashValue {
+	return hashing.HashData([]byte(key))
+}
+
+// merkleBit is the i-th bit of the path, the most significant bit first
+func merkleBit(path *hashing.HashValue, i int) byte {
+	return (path[i/8] >> (7 - i%8)) & 1
+}
+
+// merkleSibling returns the path to the sibling of the node at the depth
+func merkleSibling(path *hashing.HashValue, depth int) hashing.HashValue {
+	ret := *path
+	ret[(depth-1)/8] ^= 1 << (7 - (depth-1)%8)
+	return ret
+}
+
+// merkleNodeKey is the key of the node at the depth on the path: the depth and the prefix of the path
+func merkleNodeKey(path *hashing.HashValue, depth int) kv.Key {
+	n := (depth + 7) / 8
+	ret := make([]byte, 2+n)
+	copy(ret, util.Uint16To2Bytes(uint16(depth)))
+	copy(ret[2:], path[:n])
+	if depth%8 != 0 {
+		ret[1+n] &= byte(0xff << (8 - depth%8))
+	}
+	return kv.Key(ret)
+}
+
+// merkleNode is either a leaf with the hash of its key or an internal node with at least two leaves below it
+type merkleNode struct {
+	leaf    bool
+	keyHash hashing.HashValue
+	hash    hashing.HashValue
+}
+
+func (n *merkleNode) Bytes() []byte {
+	if n.leaf {
+		return append(append([]byte{merkleLeafPrefix}, n.keyHash[:]...), n.hash[:]...)
+	}
+	return append([]byte{merkleNodePrefix}, n.hash[:]...)
+}
+
+func merkleNodeFromBytes(data []byte) *merkleNode {
+	ret := &merkleNode{}
+	switch {
+	case len(data) == 1+2*hashing.HashSize && data[0] == merkleLeafPrefix:
+		ret.leaf = true
+		copy(ret.keyHash[:], data[1:])
+		copy(ret.hash[:], data[1+hashing.HashSize:])
+	case len(data) == 1+hashing.HashSize && data[0] == merkleNodePrefix:
+		copy(ret.hash[:], data[1:])
+	default:
+		panic("corrupted Merkle tree node")
+	}
+	return ret
+}
+
+// merkleTree keeps the nodes of the tree in the key/value store
+type merkleTree struct {
+	nodes kv.KVStore
+}
+
+func (t *merkleTree) get(path *hashing.HashValue, depth int) *merkleNode {
+	data := t.nodes.MustGet(merkleNodeKey(path, depth))
+	if data == nil {
+		return nil
+	}
+	return merkleNodeFromBytes(data)
+}
+
+func (t *merkleTree) set(path *hashing.HashValue, depth int, n *merkleNode) {
+	t.nodes.Set(merkleNodeKey(path, depth), n.Bytes())
+}
+
+func (t *merkleTree) del(path *hashing.HashValue, depth int) {
+	t.nodes.Del(merkleNodeKey(path, depth))
+}
+
+func (t *merkleTree) hashAt(path *hashing.HashValue, depth int) hashing.HashValue {
+	n := t.get(path, depth)
+	if n == nil {
+		return hashing.NilHash
+	}
+	return n.hash
+}
+
+func (t *merkleTree) root() hashing.HashValue {
+	return t.hashAt(&hashing.NilHash, 0)
+}
+
+// find goes down the path to the first node which is empty or a leaf. Returns the node (nil if empty) and its depth
+func (t *merkleTree) find(path *hashing.HashValue) (*merkleNode, int) {
+	for depth := 0; ; depth++ {
+		n := t.get(path, depth)
+		if n == nil || n.leaf {
+			return n, depth
+		}
+	}
+}
+
+// update sets or, if the value is nil, deletes the key/value pair and recalculates hashes on the path of the key
+func (t *merkleTree) update(key kv.Key, value []byte) {
+	path := merkleKeyHash(key)
+	n, depth := t.find(&path)
+	if value == nil {
+		if n == nil || n.keyHash != path {
+			// the key is not in the tree
+			return
+		}
+		t.del(&path, depth)
+		depth = t.collapse(&path, depth)
+	} else {
+		if n != nil && n.keyHash != path {
+			// another leaf occupies the position. Both leaves are moved below the first bit where their paths differ.
+			// Internal nodes above them are created by rehash
+			split := depth
+			for merkleBit(&path, split) == merkleBit(&n.keyHash, split) {
+				split++
+			}
+			depth = split + 1
+			t.set(&n.keyHash, depth, n)
+		}
+		t.set(&path, depth, &merkleNode{
+			leaf:    true,
+			keyHash: path,
+			hash:    merkleLeafHash(key, value),
+		})
+	}
+	t.rehash(&path, depth)
+}
+
+// collapse moves the only remaining leaf of the subtree up to the root of the subtree
+// after a leaf was deleted at the depth. Returns the depth of the topmost changed node
+func (t *merkleTree) collapse(path *hashing.HashValue, depth int) int {
+	for depth > 0 {
+		sibPath := merkleSibling(path, depth)
+		cur := t.get(path, depth)
+		sib := t.get(&sibPath, depth)
+		var single *merkleNode
+		switch {
+		case cur == nil && sib == nil:
+		case cur == nil && sib.leaf:
+			single = sib
+		case sib == nil && cur.leaf:
+			single = cur
+		default:
+			return depth
+		}
+		t.del(path, depth)
+		t.del(&sibPath, depth)
+		depth--
+		if single != nil {
+			t.set(path, depth, single)
+		} else {
+			t.del(path, depth)
+		}
+	}
+	return depth
+}
+
+// rehash recalculates internal nodes on the path above the depth
+func (t *merkleTree) rehash(path *hashing.HashValue, depth int) {
+	for d := depth - 1; d >= 0; d-- {
+		var left, right hashing.HashValue
+		if merkleBit(path, d) == 0 {
+			left = t.hashAt(path, d+1)
+			sib := merkleSibling(path, d+1)
+			right = t.hashAt(&sib, d+1)
+		} else {
+			sib := merkleSibling(path, d+1)
+			left = t.hashAt(&sib, d+1)
+			right = t.hashAt(path, d+1)
+		}
+		t.set(path, d, &merkleNode{hash: merkleNodeHash(&left, &right)})
+	}
+}
+
+// proof collects hashes of siblings on the path from the leaf of the key to the root
+func (t *merkleTree) proof(key kv.Key) (*MerkleProof, error) {
+	path := merkleKeyHash(key)
+	n, depth := t.find(&path)
+	if n == nil || n.keyHash != path {
+		return nil, fmt.Errorf("GetProof: key not found in the state")
+	}
+	ret := &MerkleProof{Path: make([]hashing.HashValue, 0, depth)}
+	for d := depth; d > 0; d-- {
+		sib := merkleSibling(&path, d)
+		ret.Path = append(ret.Path, t.hashAt(&sib, d))
+	}
+	return ret, nil
+}
+
+// merkleTreeOf builds the Merkle tree of the key/value store in memory
+func merkleTreeOf(vars kv.KVStoreReader) (*merkleTree, error) {
+	ret := &merkleTree{nodes: dict.New()}
+	err := vars.Iterate(kv.EmptyPrefix, func(key kv.Key, value []byte) bool {
+		ret.update(key, value)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// MerkleRoot calculates root of the Merkle tree over all key/value pairs in the store from scratch.
+// The virtual state maintains its tree incrementally
+func MerkleRoot(vars kv.KVStoreReader) (hashing.HashValue, error) {
+	t, err := merkleTreeOf(vars)
+	if err != nil {
+		return hashing.NilHash, err
+	}
+	return t.root(), nil
+}
+
+// GetProof creates proof of inclusion of the key into the Merkle tree of the store, building the tree from scratch
+func GetProof(vars kv.KVStoreReader, key kv.Key) (*MerkleProof, error) {
+	t, err := merkleTreeOf(vars)
+	if err != nil {
+		return nil, err
+	}
+	return t.proof(key)
+}
+
+// VerifyProof checks if the proof is a valid proof of inclusion of the key/value pair into the Merkle tree with the root
+func VerifyProof(root hashing.HashValue, key kv.Key, value []byte, proof *MerkleProof) bool {
+	if proof == nil || len(proof.Path) > merkleMaxDepth {
+		return false
+	}
+	path := merkleKeyHash(key)
+	h := merkleLeafHash(key, value)
+	for i := range proof.Path {
+		if merkleBit(&path, len(proof.Path)-i-1) == 0 {
+			h = merkleNodeHash(&h, &proof.Path[i])
+		} else {
+			h = merkleNodeHash(&proof.Path[i], &h)
+		}
+	}
+	return h == root
+}
+
+// StateHashFromMerkleRoot calculates the hash of the virtual state, committed to the state transaction,
+// from the chained hash of state updates and the Merkle root of the state variables
+func StateHashFromMerkleRoot(chainedHash, merkleRoot hashing.HashValue) hashing.HashValue {
+	return hashing.HashData(chainedHash[:], merkleRoot[:])
+}
+
+func (p *MerkleProof) Write(w io.Writer) error {
+	if err := util.WriteUint16(w, uint16(len(p.Path))); err != nil {
+		return err
+	}
+	for i := range p.Path {
+		if err := p.Path[i].Write(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *MerkleProof) Read(r io.Reader) error {
+	var size uint16
+	if err := util.ReadUint16(r, &size); err != nil {
+		return err
+	}
+	p.Path = make([]hashing.HashValue, size)
+	for i := range p.Path {
+		if err := util.ReadHashValue(r, &p.Path[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func NewMerkleProofFromBytes(data []byte) (*MerkleProof, error) {
+	ret := &MerkleProof{}
+	if err := ret.Read(bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
@@ -0,0 +1,147 @@
+package state
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/buffered"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerkleRootEmpty(t *testing.T) {
+	root, err := MerkleRoot(dict.New())
+	require.NoError(t, err)
+	assert.EqualValues(t, hashing.NilHash, root)
+
+	_, err = GetProof(dict.New(), "a")
+	assert.Error(t, err)
+}
+
+func TestMerkleProofs(t *testing.T) {
+	for _, n := range []int{1, 2, 3, 5, 8, 13, 100} {
+		t.Run(fmt.Sprintf("%d leaves", n), func(t *testing.T) {
+			vars := dict.New()
+			for i := 0; i < n; i++ {
+				vars.Set(kv.Key(fmt.Sprintf("key%d", i)), []byte(fmt.Sprintf("value%d", i)))
+			}
+			root, err := MerkleRoot(vars)
+			require.NoError(t, err)
+
+			for i := 0; i < n; i++ {
+				key := kv.Key(fmt.Sprintf("key%d", i))
+				proof, err := GetProof(vars, key)
+				require.NoError(t, err)
+				assert.True(t, VerifyProof(root, key, vars.MustGet(key), proof))
+				assert.False(t, VerifyProof(root, key, []byte("wrong value"), proof))
+				assert.False(t, VerifyProof(root, "wrong key", vars.MustGet(key), proof))
+
+				back, err := NewMerkleProofFromBytes(util.MustBytes(proof))
+				require.NoError(t, err)
+				assert.EqualValues(t, proof, back)
+				assert.True(t, VerifyProof(root, key, vars.MustGet(key), back))
+			}
+		})
+	}
+}
+
+func TestMerkleRootOrderIndependent(t *testing.T) {
+	vars1 := dict.New()
+	vars1.Set("a", []byte("1"))
+	vars1.Set("b", []byte("2"))
+	vars1.Set("c", []byte("3"))
+
+	vars2 := dict.New()
+	vars2.Set("c", []byte("3"))
+	vars2.Set("a", []byte("1"))
+	vars2.Set("b", []byte("2"))
+
+	root1, err := MerkleRoot(vars1)
+	require.NoError(t, err)
+	root2, err := MerkleRoot(vars2)
+	require.NoError(t, err)
+	assert.EqualValues(t, root1, root2)
+
+	vars2.Set("b", []byte("22"))
+	root2, err = MerkleRoot(vars2)
+	require.NoError(t, err)
+	assert.NotEqualValues(t, root1, root2)
+}
+
+func TestMerkleTreeIncremental(t *testing.T) {
+	vars := dict.New()
+	tree := &merkleTree{nodes: dict.New()}
+	for i := 0; i < 200; i++ {
+		key := kv.Key(fmt.Sprintf("key%d", i%70))
+		if i%3 == 2 {
+			vars.Del(key)
+			tree.update(key, nil)
+		} else {
+			value := []byte(fmt.Sprintf("value%d", i))
+			vars.Set(key, value)
+			tree.update(key, value)
+		}
+		root, err := MerkleRoot(vars)
+		require.NoError(t, err)
+		require.EqualValues(t, root, tree.root())
+	}
+	// deleting all keys leaves no nodes
+	for i := 0; i < 70; i++ {
+		tree.update(kv.Key(fmt.Sprintf("key%d", i)), nil)
+	}
+	assert.EqualValues(t, hashing.NilHash, tree.root())
+	assert.EqualValues(t, 0, len(tree.nodes.(dict.Dict)))
+}
+
+func TestVirtualStateProof(t *testing.T) {
+	chainID := coretypes.ChainID{1, 3, 3, 7}
+	vs := NewVirtualState(mapdb.NewMapDB(), &chainID)
+
+	txid := (transaction.ID)(hashing.HashStrings("test string 1"))
+	reqid := coretypes.NewRequestID(txid, 0)
+	su := NewStateUpdate(&reqid)
+	su.Mutations().Add(buffered.NewMutationSet("x", []byte("1")))
+	su.Mutations().Add(buffered.NewMutationSet("y", []byte("2")))
+	block, err := NewBlock([]StateUpdate{su})
+	require.NoError(t, err)
+
+	require.NoError(t, vs.ApplyBlock(block))
+	require.NoError(t, vs.CommitToDb(block))
+
+	assert.EqualValues(t, StateHashFromMerkleRoot(vs.ChainedHash(), vs.MerkleRoot()), vs.Hash())
+
+	proof, err := vs.GetProof("y")
+	require.NoError(t, err)
+	assert.True(t, VerifyProof(vs.MerkleRoot(), "y", []byte("2"), proof))
+
+	// the tree is stored in the db and updated only with the changed keys
+	txid = (transaction.ID)(hashing.HashStrings("test string 2"))
+	reqid = coretypes.NewRequestID(txid, 0)
+	su = NewStateUpdate(&reqid)
+	su.Mutations().Add(buffered.NewMutationDel("x"))
+	su.Mutations().Add(buffered.NewMutationSet("z", []byte("3")))
+	block, err = NewBlock([]StateUpdate{su})
+	require.NoError(t, err)
+	block.WithBlockIndex(1)
+	require.NoError(t, vs.ApplyBlock(block))
+	require.NoError(t, vs.CommitToDb(block))
+
+	vs1, _, ok, err := loadSolidState(vs.db, &chainID)
+	require.NoError(t, err)
+	require.True(t, ok)
+	root, err := MerkleRoot(vs1.Variables())
+	require.NoError(t, err)
+	assert.EqualValues(t, root, vs1.MerkleRoot())
+	proof, err = vs1.GetProof("z")
+	require.NoError(t, err)
+	assert.True(t, VerifyProof(vs1.MerkleRoot(), "z", []byte("3"), proof))
+	_, err = vs1.GetProof("x")
+	assert.Error(t, err)
+}
@@ -0,0 +1,138 @@
+package state
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/dbprovider"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/buffered"
+	"github.com/iotaledger/wasp/packages/util"
+	"github.com/iotaledger/wasp/plugins/database"
+)
+
+func init() {
+	database.MigrateStateToV1 = migrateToV1
+}
+
+// migrateToV1 migrates the state of all chains from the database of version 0:
+// the blocks are rewritten with the results of requests, the Merkle tree of the state variables is built
+// and its root is stored in the solid state.
+// The hash of the migrated solid state, anchored on the tangle, doesn't commit to the Merkle root,
+// so the state is marked as legacy until the next block is committed
+func migrateToV1(store kvstore.KVStore, batch kvstore.BatchedMutations) error {
+	migrateBlocks := dbprovider.TransformObjects(dbprovider.ObjectTypeStateUpdateBatch, func(_ coretypes.ChainID, key, value []byte) ([]byte, []byte, error) {
+		b, err := blockFromBytesV0(value)
+		if err != nil {
+			return nil, nil, err
+		}
+		data, err := util.Bytes(b)
+		if err != nil {
+			return nil, nil, err
+		}
+		return key, data, nil
+	})
+	if err := migrateBlocks(store, batch); err != nil {
+		return err
+	}
+	chains := make([]coretypes.ChainID, 0)
+	err := store.IterateKeys(kvstore.EmptyPrefix, func(key kvstore.Key) bool {
+		if len(key) == coretypes.ChainIDLength+1 && key[coretypes.ChainIDLength] == dbprovider.ObjectTypeSolidState {
+			var chainID coretypes.ChainID
+			copy(chainID[:], key[:coretypes.ChainIDLength])
+			chains = append(chains, chainID)
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	for i := range chains {
+		if err := migrateSolidStateV0(store, batch, &chains[i]); err != nil {
+			return fmt.Errorf("migrating the state of %s: %w", chains[i].String(), err)
+		}
+	}
+	return nil
+}
+
+// migrateSolidStateV0 builds the Merkle tree of the state variables of the chain and writes it
+// to the batch together with the solid state of version 1
+func migrateSolidStateV0(store kvstore.KVStore, batch kvstore.BatchedMutations, chainID *coretypes.ChainID) error {
+	db := store.WithRealm(chainID[:])
+	data, err := db.Get(dbprovider.MakeKey(dbprovider.ObjectTypeSolidState))
+	if err != nil {
+		return err
+	}
+	vs := NewVirtualState(db, chainID)
+	if err := vs.readV0(bytes.NewReader(data)); err != nil {
+		return err
+	}
+	err = db.IterateKeys(kvstore.KeyPrefix{dbprovider.ObjectTypeStateVariable}, func(key kvstore.Key) bool {
+		vs.merkleDirty[kv.Key(key[1:])] = true
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	vs.updateMerkleRoot()
+	vs.legacyHash = true
+
+	vs.merkleNodes.Mutations().IterateLatest(func(k kv.Key, mut buffered.Mutation) bool {
+		key := append(chainID[:], dbkeyMerkleNode(k)...)
+		if mut.Value() == nil {
+			err = batch.Delete(key)
+		} else {
+			err = batch.Set(key, mut.Value())
+		}
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+	return batch.Set(append(chainID[:], dbprovider.MakeKey(dbprovider.ObjectTypeSolidState)...), util.MustBytes(vs))
+}
+
+// readV0 reads the solid state stored in the database of version 0, without the Merkle root
+func (vs *virtualState) readV0(r io.Reader) error {
+	if err := util.ReadUint32(r, &vs.blockIndex); err != nil {
+		return err
+	}
+	var ts uint64
+	if err := util.ReadUint64(r, &ts); err != nil {
+		return err
+	}
+	vs.timestamp = int64(ts)
+	if err := util.ReadHashValue(r, &vs.stateHash); err != nil {
+		return err
+	}
+	vs.empty = false
+	return nil
+}
+
+// blockFromBytesV0 reads the block stored in the database of version 0. Its state updates have no results
+func blockFromBytesV0(data []byte) (Block, error) {
+	r := bytes.NewReader(data)
+	ret := new(block)
+	if err := util.ReadUint32(r, &ret.stateIndex); err != nil {
+		return nil, err
+	}
+	var size uint16
+	if err := util.ReadUint16(r, &size); err != nil {
+		return nil, err
+	}
+	ret.stateUpdates = make([]StateUpdate, size)
+	for i := range ret.stateUpdates {
+		su := NewStateUpdate(nil).(*stateUpdate)
+		if err := su.readLegacy(r); err != nil {
+			return nil, err
+		}
+		ret.stateUpdates[i] = su
+	}
+	if err := util.ReadTransactionId(r, &ret.stateTxId); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
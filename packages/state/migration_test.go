@@ -0,0 +1,104 @@
+package state
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/dbprovider"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv/buffered"
+	"github.com/iotaledger/wasp/packages/util"
+	"github.com/stretchr/testify/require"
+)
+
+// blockBytesV0 encodes the block as it was stored in the database of version 0
+func blockBytesV0(t *testing.T, b Block) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, util.WriteUint32(&buf, b.StateIndex()))
+	require.NoError(t, util.WriteUint16(&buf, b.Size()))
+	b.ForEach(func(_ uint16, su StateUpdate) bool {
+		require.NoError(t, su.RequestID().Write(&buf))
+		require.NoError(t, su.Mutations().Write(&buf))
+		require.NoError(t, util.WriteUint64(&buf, uint64(su.Timestamp())))
+		return true
+	})
+	txid := b.StateTransactionID()
+	buf.Write(txid[:])
+	return buf.Bytes()
+}
+
+// downgradeToV0 converts the state of the chain in the db to the format of version 0
+func downgradeToV0(t *testing.T, db kvstore.KVStore, n uint32) {
+	for i := uint32(0); i < n; i++ {
+		block, err := loadBlock(db, i)
+		require.NoError(t, err)
+		require.NoError(t, db.Set(dbkeyBatch(i), blockBytesV0(t, block)))
+	}
+	// the solid state of version 0 has no Merkle root
+	data, err := db.Get(dbprovider.MakeKey(dbprovider.ObjectTypeSolidState))
+	require.NoError(t, err)
+	require.NoError(t, db.Set(dbprovider.MakeKey(dbprovider.ObjectTypeSolidState), data[:4+8+hashing.HashSize]))
+	require.NoError(t, db.DeletePrefix(kvstore.KeyPrefix{dbprovider.ObjectTypeMerkleNode}))
+}
+
+func TestMigrateToV1(t *testing.T) {
+	chainID := coretypes.ChainID{1, 3, 3, 7}
+	store := mapdb.NewMapDB()
+	db := store.WithRealm(chainID[:])
+	commitBlocks(t, db, &chainID, 5, time.Now())
+
+	expected, expectedBlock, ok, err := loadSolidState(db, &chainID)
+	require.NoError(t, err)
+	require.True(t, ok)
+	expectedBlockData := util.MustBytes(expectedBlock)
+
+	downgradeToV0(t, db, 5)
+	_, _, _, err = loadSolidState(db, &chainID)
+	require.Error(t, err)
+
+	batch := store.Batched()
+	require.NoError(t, migrateToV1(store, batch))
+	require.NoError(t, batch.Commit())
+
+	vs, block, ok, err := loadSolidState(db, &chainID)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.EqualValues(t, expectedBlockData, util.MustBytes(block))
+	require.EqualValues(t, expected.BlockIndex(), vs.BlockIndex())
+	require.EqualValues(t, expected.Timestamp(), vs.Timestamp())
+	require.EqualValues(t, expected.MerkleRoot(), vs.MerkleRoot())
+	// the hash anchored on the tangle by version 0 is the chained hash
+	require.EqualValues(t, expected.ChainedHash(), vs.Hash())
+
+	proof, err := vs.GetProof("counter")
+	require.NoError(t, err)
+	require.True(t, VerifyProof(vs.MerkleRoot(), "counter", []byte{4}, proof))
+
+	// the snapshot of the migrated state can't be verified
+	_, err = exportSnapshot(db, &chainID, &bytes.Buffer{})
+	require.Error(t, err)
+
+	// the next block commits to the Merkle root again
+	txid := (transaction.ID)(hashing.HashStrings("next block"))
+	reqid := coretypes.NewRequestID(txid, 0)
+	su := NewStateUpdate(&reqid).WithTimestamp(time.Now().UnixNano())
+	su.Mutations().Add(buffered.NewMutationSet("counter", []byte{5}))
+	next, err := NewBlock([]StateUpdate{su})
+	require.NoError(t, err)
+	next.WithBlockIndex(5).WithStateTransaction(txid)
+	require.NoError(t, vs.ApplyBlock(next))
+	require.EqualValues(t, StateHashFromMerkleRoot(vs.ChainedHash(), vs.MerkleRoot()), vs.Hash())
+	require.NoError(t, vs.CommitToDb(next))
+
+	vs, _, ok, err = loadSolidState(db, &chainID)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.EqualValues(t, StateHashFromMerkleRoot(vs.ChainedHash(), vs.MerkleRoot()), vs.Hash())
+	_, err = exportSnapshot(db, &chainID, &bytes.Buffer{})
+	require.NoError(t, err)
+}
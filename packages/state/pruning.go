@@ -0,0 +1,159 @@
+package state
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/dbprovider"
+	"github.com/iotaledger/wasp/packages/util"
+)
+
+// maximum number of blocks deleted from the db in one atomic batch
+const maxBlocksPrunedAtOnce = 100
+
+// PruningParams defines which blocks and records of processed requests are kept in the db.
+// The block is retained if it is among the last KeepBlocks blocks or if it is younger than KeepDuration.
+// The solid state and the block of the solid state are never pruned
+type PruningParams struct {
+	// number of the last blocks to keep. 0 means the number of blocks is not limited
+	KeepBlocks uint32
+	// age of the oldest block to keep. 0 means the age of blocks is not limited
+	KeepDuration time.Duration
+	// if not empty, pruned blocks are saved to files in the directory
+	ArchiveDir string
+}
+
+// Enabled returns false if all blocks are kept
+func (p *PruningParams) Enabled() bool {
+	return p != nil && (p.KeepBlocks > 0 || p.KeepDuration > 0)
+}
+
+func (p *PruningParams) retained(block Block, solidIndex uint32, now time.Time) bool {
+	if p.KeepBlocks > 0 && solidIndex-block.StateIndex() < p.KeepBlocks {
+		return true
+	}
+	if p.KeepDuration > 0 && now.Sub(time.Unix(0, block.Timestamp())) < p.KeepDuration {
+		return true
+	}
+	return false
+}
+
+// PruneState deletes blocks of the chain and records of requests processed in them, which are not retained
+// by the pruning parameters. At most maxBlocksPrunedAtOnce blocks are pruned at once, the rest is pruned by the next call.
+// After the request is pruned, IsRequestCompleted returns false for it. It is safe because the request
+// token of the processed request was consumed by the state transaction, so the request never appears in the backlog again.
+// Blocks after syncFloor, the lowest state index peers are syncing from, are never pruned: the peers need them to sync.
+// Returns the number of pruned blocks
+func PruneState(chainID *coretypes.ChainID, params *PruningParams, syncFloor uint32, now time.Time) (int, error) {
+	return pruneState(getSCPartition(chainID), chainID, params, syncFloor, now)
+}
+
+func pruneState(db kvstore.KVStore, chainID *coretypes.ChainID, params *PruningParams, syncFloor uint32, now time.Time) (int, error) {
+	if !params.Enabled() {
+		return 0, nil
+	}
+	solidIndexBin, err := db.Get(dbprovider.MakeKey(dbprovider.ObjectTypeSolidStateIndex))
+	if err == kvstore.ErrKeyNotFound {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	solidIndex := util.MustUint32From4Bytes(solidIndexBin)
+	first, err := firstRetainedBlockIndex(db)
+	if err != nil {
+		return 0, err
+	}
+	keys := make([][]byte, 0)
+	values := make([][]byte, 0)
+	pruned := 0
+	i := first
+	for ; i < solidIndex && i <= syncFloor && pruned < maxBlocksPrunedAtOnce; i++ {
+		block, err := loadBlock(db, i)
+		if err != nil {
+			return 0, err
+		}
+		if block == nil {
+			// nothing to prune
+			continue
+		}
+		if params.retained(block, solidIndex, now) {
+			break
+		}
+		if params.ArchiveDir != "" {
+			if err := archiveBlock(params.ArchiveDir, chainID, block); err != nil {
+				return 0, err
+			}
+		}
+		keys = append(keys, dbkeyBatch(i))
+		values = append(values, nil)
+		for _, rid := range block.RequestIDs() {
+			keys = append(keys, dbkeyRequest(rid))
+			values = append(values, nil)
+		}
+		pruned++
+	}
+	if i == first {
+		return 0, nil
+	}
+	keys = append(keys, dbkeyFirstRetainedBlockIndex())
+	values = append(values, util.Uint32To4Bytes(i))
+	if err := util.DbSetMulti(db, keys, values); err != nil {
+		return 0, err
+	}
+	return pruned, nil
+}
+
+// FirstRetainedBlockIndex returns the index of the oldest block kept in the db.
+// All blocks before it were pruned or, if the state was imported from the snapshot, never stored
+func FirstRetainedBlockIndex(chainID *coretypes.ChainID) (uint32, error) {
+	return firstRetainedBlockIndex(getSCPartition(chainID))
+}
+
+func firstRetainedBlockIndex(db kvstore.KVStore) (uint32, error) {
+	data, err := db.Get(dbkeyFirstRetainedBlockIndex())
+	if err == kvstore.ErrKeyNotFound {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return util.Uint32From4Bytes(data)
+}
+
+func dbkeyFirstRetainedBlockIndex() []byte {
+	return dbprovider.MakeKey(dbprovider.ObjectTypeFirstRetainedBlockIndex)
+}
+
+func archiveFileName(dir string, chainID *coretypes.ChainID, blockIndex uint32) string {
+	return filepath.Join(dir, chainID.String(), fmt.Sprintf("%010d.block", blockIndex))
+}
+
+func archiveBlock(dir string, chainID *coretypes.ChainID, block Block) error {
+	data, err := util.Bytes(block)
+	if err != nil {
+		return err
+	}
+	fname := archiveFileName(dir, chainID, block.StateIndex())
+	if err := os.MkdirAll(filepath.Dir(fname), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(fname, data, 0644)
+}
+
+// LoadArchivedBlock loads the pruned block from the archive directory. Returns nil if the block wasn't archived
+func LoadArchivedBlock(dir string, chainID *coretypes.ChainID, blockIndex uint32) (Block, error) {
+	data, err := ioutil.ReadFile(archiveFileName(dir, chainID, blockIndex))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return NewBlockFromBytes(data)
+}
@@ -0,0 +1,155 @@
+package state
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv/buffered"
+	"github.com/stretchr/testify/require"
+)
+
+func commitBlocks(t *testing.T, db kvstore.KVStore, chainID *coretypes.ChainID, n uint32, start time.Time) []coretypes.RequestID {
+	vs := NewVirtualState(db, chainID)
+	ret := make([]coretypes.RequestID, n)
+	for i := uint32(0); i < n; i++ {
+		txid := (transaction.ID)(hashing.HashStrings(fmt.Sprintf("test string %d", i)))
+		ret[i] = coretypes.NewRequestID(txid, 0)
+		su := NewStateUpdate(&ret[i]).WithTimestamp(start.Add(time.Duration(i) * time.Hour).UnixNano())
+		su.Mutations().Add(buffered.NewMutationSet("counter", []byte{byte(i)}))
+		block, err := NewBlock([]StateUpdate{su})
+		require.NoError(t, err)
+		block.WithBlockIndex(i).WithStateTransaction(txid)
+		require.NoError(t, vs.ApplyBlock(block))
+		require.NoError(t, vs.CommitToDb(block))
+	}
+	return ret
+}
+
+func requireBlocks(t *testing.T, db kvstore.KVStore, reqids []coretypes.RequestID, first uint32) {
+	for i := range reqids {
+		block, err := loadBlock(db, uint32(i))
+		require.NoError(t, err)
+		completed, err := db.Has(dbkeyRequest(&reqids[i]))
+		require.NoError(t, err)
+		require.EqualValues(t, uint32(i) >= first, block != nil)
+		require.EqualValues(t, uint32(i) >= first, completed)
+	}
+	f, err := firstRetainedBlockIndex(db)
+	require.NoError(t, err)
+	require.EqualValues(t, first, f)
+}
+
+func TestPruneKeepBlocks(t *testing.T) {
+	chainID := coretypes.ChainID{1, 3, 3, 7}
+	db := mapdb.NewMapDB()
+	now := time.Now()
+	reqids := commitBlocks(t, db, &chainID, 10, now)
+
+	pruned, err := pruneState(db, &chainID, &PruningParams{}, math.MaxUint32, now)
+	require.NoError(t, err)
+	require.EqualValues(t, 0, pruned)
+	requireBlocks(t, db, reqids, 0)
+
+	pruned, err = pruneState(db, &chainID, &PruningParams{KeepBlocks: 3}, math.MaxUint32, now)
+	require.NoError(t, err)
+	require.EqualValues(t, 7, pruned)
+	requireBlocks(t, db, reqids, 7)
+
+	pruned, err = pruneState(db, &chainID, &PruningParams{KeepBlocks: 3}, math.MaxUint32, now)
+	require.NoError(t, err)
+	require.EqualValues(t, 0, pruned)
+
+	// the solid state is never pruned
+	pruned, err = pruneState(db, &chainID, &PruningParams{KeepBlocks: 1, KeepDuration: time.Nanosecond}, math.MaxUint32, now.Add(time.Hour*100))
+	require.NoError(t, err)
+	require.EqualValues(t, 2, pruned)
+	requireBlocks(t, db, reqids, 9)
+
+	vs, block, ok, err := loadSolidState(db, &chainID)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.EqualValues(t, 9, block.StateIndex())
+	require.EqualValues(t, []byte{9}, vs.Variables().MustGet("counter"))
+
+	_, _, ok, err = LoadStateAtFromDB(db, &chainID, 5)
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func TestPruneKeepDuration(t *testing.T) {
+	chainID := coretypes.ChainID{1, 3, 3, 7}
+	db := mapdb.NewMapDB()
+	start := time.Now()
+	reqids := commitBlocks(t, db, &chainID, 10, start)
+
+	// blocks #0..#4 are older than 5 hours
+	params := &PruningParams{KeepDuration: 5 * time.Hour}
+	pruned, err := pruneState(db, &chainID, params, math.MaxUint32, start.Add(9*time.Hour+time.Minute))
+	require.NoError(t, err)
+	require.EqualValues(t, 5, pruned)
+	requireBlocks(t, db, reqids, 5)
+
+	// the block is retained if it is either among last blocks or young enough
+	params = &PruningParams{KeepBlocks: 4, KeepDuration: time.Hour}
+	pruned, err = pruneState(db, &chainID, params, math.MaxUint32, start.Add(9*time.Hour+time.Minute))
+	require.NoError(t, err)
+	require.EqualValues(t, 1, pruned)
+	requireBlocks(t, db, reqids, 6)
+}
+
+func TestPruneSyncFloor(t *testing.T) {
+	chainID := coretypes.ChainID{1, 3, 3, 7}
+	db := mapdb.NewMapDB()
+	now := time.Now()
+	reqids := commitBlocks(t, db, &chainID, 10, now)
+
+	// a peer is syncing from the state #4, it needs blocks from #5
+	pruned, err := pruneState(db, &chainID, &PruningParams{KeepBlocks: 1}, 4, now)
+	require.NoError(t, err)
+	require.EqualValues(t, 5, pruned)
+	requireBlocks(t, db, reqids, 5)
+
+	pruned, err = pruneState(db, &chainID, &PruningParams{KeepBlocks: 1}, 4, now)
+	require.NoError(t, err)
+	require.EqualValues(t, 0, pruned)
+
+	// the peer has synced
+	pruned, err = pruneState(db, &chainID, &PruningParams{KeepBlocks: 1}, 9, now)
+	require.NoError(t, err)
+	require.EqualValues(t, 4, pruned)
+	requireBlocks(t, db, reqids, 9)
+}
+
+func TestPruneArchive(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wasp-archive")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	chainID := coretypes.ChainID{1, 3, 3, 7}
+	db := mapdb.NewMapDB()
+	now := time.Now()
+	commitBlocks(t, db, &chainID, 5, now)
+
+	pruned, err := pruneState(db, &chainID, &PruningParams{KeepBlocks: 2, ArchiveDir: dir}, math.MaxUint32, now)
+	require.NoError(t, err)
+	require.EqualValues(t, 3, pruned)
+
+	for i := uint32(0); i < 3; i++ {
+		block, err := LoadArchivedBlock(dir, &chainID, i)
+		require.NoError(t, err)
+		require.NotNil(t, block)
+		require.EqualValues(t, i, block.StateIndex())
+	}
+	block, err := LoadArchivedBlock(dir, &chainID, 3)
+	require.NoError(t, err)
+	require.Nil(t, block)
+}
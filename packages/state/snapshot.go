@@ -0,0 +1,406 @@
+package state
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	valuetransaction "github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/dbprovider"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/buffered"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/sctransaction"
+	"github.com/iotaledger/wasp/packages/util"
+)
+
+const snapshotVersion = byte(1)
+
+// each state variable in the snapshot is preceded by snapshotNextVariable, the last one is followed by snapshotEnd
+const (
+	snapshotEnd          = byte(0)
+	snapshotNextVariable = byte(1)
+)
+
+// Snapshot is the solid state of the chain with all state variables, the last block and the ID of
+// the state transaction which approves it (Block.StateTransactionID()).
+// A node imports the snapshot instead of replaying all blocks from the origin and syncs only the blocks after it
+type Snapshot struct {
+	ChainID    coretypes.ChainID
+	BlockIndex uint32
+	Timestamp  int64
+	// ChainedHash is the hash of the chain of all state updates up to the snapshot
+	ChainedHash hashing.HashValue
+	// StateHash is the hash of the state, as it is anchored in the state section of the approving transaction
+	StateHash hashing.HashValue
+	Block     Block
+	Variables dict.Dict
+}
+
+// NewSnapshot takes the snapshot of the virtual state. All state variables must be committed to the db
+func NewSnapshot(chainID *coretypes.ChainID, vs VirtualState, block Block) (*Snapshot, error) {
+	if vs.BlockIndex() != block.StateIndex() {
+		return nil, fmt.Errorf("NewSnapshot: state index #%d and block index #%d must be equal",
+			vs.BlockIndex(), block.StateIndex())
+	}
+	return &Snapshot{
+		ChainID:     *chainID,
+		BlockIndex:  vs.BlockIndex(),
+		Timestamp:   vs.Timestamp(),
+		ChainedHash: vs.ChainedHash(),
+		StateHash:   vs.Hash(),
+		Block:       block,
+		Variables:   vs.Variables().DangerouslyDumpToDict(),
+	}, nil
+}
+
+// ExportSnapshot writes the snapshot of the solid state of the chain to the writer. The state variables are
+// streamed from the db one by one, so the snapshot is never kept in memory as a whole.
+// Returns false if there is no solid state.
+// If the solid state changes while the snapshot is written, the export fails and the snapshot
+// is left incomplete, so it can't be read
+func ExportSnapshot(chainID *coretypes.ChainID, w io.Writer) (bool, error) {
+	return exportSnapshot(getSCPartition(chainID), chainID, w)
+}
+
+func exportSnapshot(db kvstore.KVStore, chainID *coretypes.ChainID, w io.Writer) (bool, error) {
+	vs, block, ok, err := loadSolidState(db, chainID)
+	if err != nil || !ok {
+		return ok, err
+	}
+	if vs.(*virtualState).legacyHash {
+		return false, fmt.Errorf("the state #%d was migrated from the database of version 0 and can't be verified by its hash. "+
+			"Export the snapshot after the next block is committed", vs.BlockIndex())
+	}
+	// the state variables are streamed from the db below
+	s := &Snapshot{
+		ChainID:     *chainID,
+		BlockIndex:  vs.BlockIndex(),
+		Timestamp:   vs.Timestamp(),
+		ChainedHash: vs.ChainedHash(),
+		StateHash:   vs.Hash(),
+		Block:       block,
+	}
+	if err := s.writeHeader(w); err != nil {
+		return false, err
+	}
+	err1 := db.Iterate(kvstore.KeyPrefix{dbprovider.ObjectTypeStateVariable}, func(key kvstore.Key, value kvstore.Value) bool {
+		err = writeSnapshotVariable(w, kv.Key(key[1:]), value)
+		return err == nil
+	})
+	if err1 != nil {
+		return false, err1
+	}
+	if err != nil {
+		return false, err
+	}
+	solidIndexBin, err := db.Get(dbprovider.MakeKey(dbprovider.ObjectTypeSolidStateIndex))
+	if err != nil {
+		return false, err
+	}
+	if solidIndex := util.MustUint32From4Bytes(solidIndexBin); solidIndex != s.BlockIndex {
+		return false, fmt.Errorf("solid state changed from #%d to #%d during the export", s.BlockIndex, solidIndex)
+	}
+	return true, util.WriteByte(w, snapshotEnd)
+}
+
+func SnapshotFromBytes(data []byte) (*Snapshot, error) {
+	ret := new(Snapshot)
+	if err := ret.Read(bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (s *Snapshot) String() string {
+	return fmt.Sprintf("snapshot of %s #%d, state hash: %s, state tx: %s, variables: %d",
+		s.ChainID.String(), s.BlockIndex, s.StateHash.String(), s.Block.StateTransactionID().String(), len(s.Variables))
+}
+
+// Verify checks the consistency of the snapshot: the state hash must commit to the chained hash
+// and to the Merkle root of the state variables
+func (s *Snapshot) Verify() error {
+	if s.Block.StateIndex() != s.BlockIndex {
+		return fmt.Errorf("inconsistent snapshot: block index #%d, state index #%d", s.Block.StateIndex(), s.BlockIndex)
+	}
+	root, err := MerkleRoot(s.Variables)
+	if err != nil {
+		return err
+	}
+	if h := StateHashFromMerkleRoot(s.ChainedHash, root); h != s.StateHash {
+		return fmt.Errorf("inconsistent snapshot: state hash %s, expected %s", h.String(), s.StateHash.String())
+	}
+	return nil
+}
+
+// VerifyAnchor checks that the snapshot is approved by the confirmed anchoring state transaction
+// of the chain with the color. The snapshot must be verified with Verify too
+func (s *Snapshot) VerifyAnchor(tx *sctransaction.Transaction, color *balance.Color) error {
+	if tx.ID() != s.Block.StateTransactionID() {
+		return fmt.Errorf("wrong anchoring transaction %s, expected %s", tx.ID().String(), s.Block.StateTransactionID().String())
+	}
+	stateSection, ok := tx.State()
+	if !ok {
+		return fmt.Errorf("transaction %s doesn't anchor the state", tx.ID().String())
+	}
+	// the origin transaction mints the chain token, so its ID is the color of the chain
+	isOrigin := stateSection.Color() == balance.ColorNew && tx.ID() == (valuetransaction.ID)(*color)
+	if stateSection.Color() != *color && !isOrigin {
+		return fmt.Errorf("transaction %s anchors the state of another chain", tx.ID().String())
+	}
+	if stateSection.BlockIndex() != s.BlockIndex {
+		return fmt.Errorf("transaction %s anchors the state #%d, snapshot is #%d",
+			tx.ID().String(), stateSection.BlockIndex(), s.BlockIndex)
+	}
+	if h := stateSection.StateHash(); h != s.StateHash {
+		return fmt.Errorf("transaction %s anchors the state hash %s, snapshot has %s",
+			tx.ID().String(), h.String(), s.StateHash.String())
+	}
+	return nil
+}
+
+// ImportSnapshot verifies the snapshot and commits it to the db as the solid state of the chain.
+// The snapshot must be verified with VerifyAnchor before the import.
+// The state of the chain in the db, if any, is replaced atomically: either the whole snapshot is imported
+// or the db is left intact, so a failed import can be repeated with another snapshot.
+// When the chain is activated, the state manager loads the imported state and validates it by the
+// anchoring state transaction, the same way as the solid state after the restart of the node
+func ImportSnapshot(s *Snapshot) error {
+	return importSnapshot(getSCPartition(&s.ChainID), s)
+}
+
+// stateObjectTypes are the types of db objects which belong to the state of the chain
+var stateObjectTypes = []byte{
+	dbprovider.ObjectTypeSolidState,
+	dbprovider.ObjectTypeStateUpdateBatch,
+	dbprovider.ObjectTypeProcessedRequestId,
+	dbprovider.ObjectTypeSolidStateIndex,
+	dbprovider.ObjectTypeStateVariable,
+	dbprovider.ObjectTypeFirstRetainedBlockIndex,
+	dbprovider.ObjectTypeMerkleNode,
+	dbprovider.ObjectTypeSnapshotState,
+	dbprovider.ObjectTypeSnapshotStateVariable,
+	dbprovider.ObjectTypeSnapshotMerkleNode,
+}
+
+func importSnapshot(db kvstore.KVStore, s *Snapshot) error {
+	if err := s.Verify(); err != nil {
+		return err
+	}
+	// the imported state is built in memory and then replaces the state in the db in one batch
+	mem := mapdb.NewMapDB()
+	vs := NewVirtualState(mem, &s.ChainID)
+	for k, v := range s.Variables {
+		vs.variables.Set(k, v)
+		vs.merkleDirty[k] = true
+	}
+	vs.blockIndex = s.BlockIndex
+	vs.timestamp = s.Timestamp
+	vs.stateHash = s.ChainedHash
+	vs.empty = false
+	vs.updateMerkleRoot()
+	if vs.Hash() != s.StateHash {
+		return fmt.Errorf("can't import snapshot: state hash %s, expected %s", vs.Hash().String(), s.StateHash.String())
+	}
+	if err := vs.CommitToDb(s.Block); err != nil {
+		return err
+	}
+	// blocks before the snapshot are not in the db
+	if err := mem.Set(dbkeyFirstRetainedBlockIndex(), util.Uint32To4Bytes(s.BlockIndex)); err != nil {
+		return err
+	}
+	if err := storeSnapshotBase(mem, vs); err != nil {
+		return err
+	}
+
+	keys := make([][]byte, 0)
+	values := make([][]byte, 0)
+	for _, objType := range stateObjectTypes {
+		var err error
+		err1 := db.IterateKeys(kvstore.KeyPrefix{objType}, func(key kvstore.Key) bool {
+			var imported bool
+			if imported, err = mem.Has(key); err != nil {
+				return false
+			}
+			if !imported {
+				keys = append(keys, key)
+				values = append(values, nil)
+			}
+			return true
+		})
+		if err1 != nil {
+			return err1
+		}
+		if err != nil {
+			return err
+		}
+	}
+	err := mem.Iterate(kvstore.EmptyPrefix, func(key kvstore.Key, value kvstore.Value) bool {
+		keys = append(keys, key)
+		values = append(values, value)
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	if err := util.DbSetMulti(db, keys, values); err != nil {
+		return err
+	}
+	// states replayed from the replaced state are not valid anymore
+	pastStates.forget(db, &s.ChainID)
+	return nil
+}
+
+// storeSnapshotBase stores the copy of the imported state, which is never updated. Past states after the snapshot
+// are replayed from it, because the blocks before the snapshot are not in the db
+func storeSnapshotBase(db kvstore.KVStore, vs *virtualState) error {
+	copies := map[byte]byte{
+		dbprovider.ObjectTypeStateVariable: dbprovider.ObjectTypeSnapshotStateVariable,
+		dbprovider.ObjectTypeMerkleNode:    dbprovider.ObjectTypeSnapshotMerkleNode,
+	}
+	for from, to := range copies {
+		var err error
+		err1 := db.Iterate(kvstore.KeyPrefix{from}, func(key kvstore.Key, value kvstore.Value) bool {
+			err = db.Set(dbprovider.MakeKey(to, key[1:]), value)
+			return err == nil
+		})
+		if err1 != nil {
+			return err1
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return db.Set(dbprovider.MakeKey(dbprovider.ObjectTypeSnapshotState), util.MustBytes(vs))
+}
+
+// loadSnapshotBase loads the copy of the imported state. Returns false if the state was not imported from the snapshot.
+// The loaded state is detached from the db, so it can't be committed
+func loadSnapshotBase(db kvstore.KVStore, chainID *coretypes.ChainID) (*virtualState, bool, error) {
+	data, err := db.Get(dbprovider.MakeKey(dbprovider.ObjectTypeSnapshotState))
+	if err == kvstore.ErrKeyNotFound {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	vs := NewVirtualState(mapdb.NewMapDB(), chainID)
+	vs.variables = buffered.NewBufferedKVStore(subRealm(db, []byte{dbprovider.ObjectTypeSnapshotStateVariable}))
+	vs.merkleNodes = buffered.NewBufferedKVStore(subRealm(db, []byte{dbprovider.ObjectTypeSnapshotMerkleNode}))
+	if err := vs.Read(bytes.NewReader(data)); err != nil {
+		return nil, false, err
+	}
+	return vs, true, nil
+}
+
+func (s *Snapshot) Write(w io.Writer) error {
+	if err := s.writeHeader(w); err != nil {
+		return err
+	}
+	var err error
+	s.Variables.ForEachDeterministic(func(key kv.Key, value []byte) bool {
+		err = writeSnapshotVariable(w, key, value)
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+	return util.WriteByte(w, snapshotEnd)
+}
+
+func (s *Snapshot) writeHeader(w io.Writer) error {
+	if err := util.WriteByte(w, snapshotVersion); err != nil {
+		return err
+	}
+	if err := s.ChainID.Write(w); err != nil {
+		return err
+	}
+	if err := util.WriteUint32(w, s.BlockIndex); err != nil {
+		return err
+	}
+	if err := util.WriteInt64(w, s.Timestamp); err != nil {
+		return err
+	}
+	if _, err := w.Write(s.ChainedHash[:]); err != nil {
+		return err
+	}
+	if _, err := w.Write(s.StateHash[:]); err != nil {
+		return err
+	}
+	blockData, err := util.Bytes(s.Block)
+	if err != nil {
+		return err
+	}
+	return util.WriteBytes32(w, blockData)
+}
+
+func writeSnapshotVariable(w io.Writer, key kv.Key, value []byte) error {
+	if err := util.WriteByte(w, snapshotNextVariable); err != nil {
+		return err
+	}
+	if err := util.WriteBytes16(w, []byte(key)); err != nil {
+		return err
+	}
+	return util.WriteBytes32(w, value)
+}
+
+// Read reads the snapshot written by Write or by ExportSnapshot. It fails if the snapshot is incomplete
+func (s *Snapshot) Read(r io.Reader) error {
+	version, err := util.ReadByte(r)
+	if err != nil {
+		return err
+	}
+	if version != snapshotVersion {
+		return fmt.Errorf("unsupported snapshot version %d", version)
+	}
+	if err := s.ChainID.Read(r); err != nil {
+		return err
+	}
+	if err := util.ReadUint32(r, &s.BlockIndex); err != nil {
+		return err
+	}
+	if err := util.ReadInt64(r, &s.Timestamp); err != nil {
+		return err
+	}
+	if err := util.ReadHashValue(r, &s.ChainedHash); err != nil {
+		return err
+	}
+	if err := util.ReadHashValue(r, &s.StateHash); err != nil {
+		return err
+	}
+	blockData, err := util.ReadBytes32(r)
+	if err != nil {
+		return err
+	}
+	if s.Block, err = NewBlockFromBytes(blockData); err != nil {
+		return err
+	}
+	s.Variables = dict.New()
+	for {
+		next, err := util.ReadByte(r)
+		if err != nil {
+			return err
+		}
+		switch next {
+		case snapshotEnd:
+			return nil
+		case snapshotNextVariable:
+		default:
+			return fmt.Errorf("wrong snapshot format")
+		}
+		key, err := util.ReadBytes16(r)
+		if err != nil {
+			return err
+		}
+		value, err := util.ReadBytes32(r)
+		if err != nil {
+			return err
+		}
+		s.Variables.Set(kv.Key(key), value)
+	}
+}
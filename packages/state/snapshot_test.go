@@ -0,0 +1,196 @@
+package state
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/buffered"
+	"github.com/iotaledger/wasp/packages/sctransaction"
+	"github.com/iotaledger/wasp/packages/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshot(t *testing.T) {
+	chainID := coretypes.ChainID{1, 3, 3, 7}
+	db := mapdb.NewMapDB()
+	vs := NewVirtualState(db, &chainID)
+
+	var block Block
+	var s0 *Snapshot
+	for i := uint32(0); i < 4; i++ {
+		txid := (transaction.ID)(hashing.HashStrings(fmt.Sprintf("test string %d", i)))
+		reqid := coretypes.NewRequestID(txid, 0)
+		su := NewStateUpdate(&reqid)
+		su.Mutations().Add(buffered.NewMutationSet("counter", []byte{byte(i)}))
+		su.Mutations().Add(buffered.NewMutationSet(kv.Key(fmt.Sprintf("key%d", i)), []byte{byte(i)}))
+		var err error
+		block, err = NewBlock([]StateUpdate{su})
+		require.NoError(t, err)
+		block.WithBlockIndex(i).WithStateTransaction(txid)
+
+		require.NoError(t, vs.ApplyBlock(block))
+		require.NoError(t, vs.CommitToDb(block))
+		if i == 0 {
+			var err error
+			s0, err = NewSnapshot(&chainID, vs, block)
+			require.NoError(t, err)
+		}
+	}
+
+	_, _, ok, err := loadSolidState(db, &chainID)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	s, err := NewSnapshot(&chainID, vs, block)
+	require.NoError(t, err)
+	require.NoError(t, s.Verify())
+	assert.EqualValues(t, 5, len(s.Variables))
+
+	s1, err := SnapshotFromBytes(util.MustBytes(s))
+	require.NoError(t, err)
+	require.NoError(t, s1.Verify())
+	assert.EqualValues(t, 3, s1.BlockIndex)
+	assert.EqualValues(t, vs.Hash(), s1.StateHash)
+	assert.EqualValues(t, block.StateTransactionID(), s1.Block.StateTransactionID())
+
+	var buf bytes.Buffer
+	ok, err = exportSnapshot(db, &chainID, &buf)
+	require.NoError(t, err)
+	require.True(t, ok)
+	s2, err := SnapshotFromBytes(buf.Bytes())
+	require.NoError(t, err)
+	require.NoError(t, s2.Verify())
+	assert.EqualValues(t, s.StateHash, s2.StateHash)
+	assert.EqualValues(t, s.Variables, s2.Variables)
+
+	// the incomplete snapshot can't be read
+	_, err = SnapshotFromBytes(buf.Bytes()[:buf.Len()-1])
+	require.Error(t, err)
+
+	// the import replaces the state imported before
+	db1 := mapdb.NewMapDB()
+	require.NoError(t, importSnapshot(db1, s0))
+	require.NoError(t, importSnapshot(db1, s1))
+	b, err := loadBlock(db1, 0)
+	require.NoError(t, err)
+	assert.Nil(t, b)
+
+	vs1, block1, ok, err := loadSolidState(db1, &chainID)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.EqualValues(t, vs.Hash(), vs1.Hash())
+	assert.EqualValues(t, 3, block1.StateIndex())
+	assert.EqualValues(t, []byte{3}, vs1.Variables().MustGet("counter"))
+	assert.EqualValues(t, []byte{1}, vs1.Variables().MustGet("key1"))
+	first, err := firstRetainedBlockIndex(db1)
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, first)
+
+	// the tail is applied on top of the imported state
+	txid := (transaction.ID)(hashing.HashStrings("test string 4"))
+	reqid := coretypes.NewRequestID(txid, 0)
+	su := NewStateUpdate(&reqid)
+	su.Mutations().Add(buffered.NewMutationSet("counter", []byte{4}))
+	tail, err := NewBlock([]StateUpdate{su})
+	require.NoError(t, err)
+	tail.WithBlockIndex(4)
+	require.NoError(t, vs.ApplyBlock(tail))
+	require.NoError(t, vs1.ApplyBlock(tail))
+	assert.EqualValues(t, vs.Hash(), vs1.Hash())
+	require.NoError(t, vs1.CommitToDb(tail))
+	hash4 := vs1.Hash()
+
+	su = NewStateUpdate(&reqid)
+	su.Mutations().Add(buffered.NewMutationSet("counter", []byte{5}))
+	tail, err = NewBlock([]StateUpdate{su})
+	require.NoError(t, err)
+	tail.WithBlockIndex(5)
+	require.NoError(t, vs1.ApplyBlock(tail))
+	require.NoError(t, vs1.CommitToDb(tail))
+
+	// past states are re-played from the imported state
+	past, _, ok, err := LoadStateAtFromDB(db1, &chainID, 4)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.EqualValues(t, hash4, past.Hash())
+	assert.EqualValues(t, []byte{4}, past.Variables().MustGet("counter"))
+	past, pastBlock, ok, err := LoadStateAtFromDB(db1, &chainID, 3)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.EqualValues(t, s1.StateHash, past.Hash())
+	assert.EqualValues(t, 3, pastBlock.StateIndex())
+	_, _, ok, err = LoadStateAtFromDB(db1, &chainID, 2)
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func TestSnapshotTampered(t *testing.T) {
+	chainID := coretypes.ChainID{1, 3, 3, 7}
+	vs := NewVirtualState(mapdb.NewMapDB(), &chainID)
+
+	txid := (transaction.ID)(hashing.HashStrings("test string"))
+	reqid := coretypes.NewRequestID(txid, 0)
+	su := NewStateUpdate(&reqid)
+	su.Mutations().Add(buffered.NewMutationSet("counter", []byte{1}))
+	block, err := NewBlock([]StateUpdate{su})
+	require.NoError(t, err)
+	block.WithBlockIndex(0).WithStateTransaction(txid)
+	require.NoError(t, vs.ApplyBlock(block))
+	require.NoError(t, vs.CommitToDb(block))
+
+	s, err := NewSnapshot(&chainID, vs, block)
+	require.NoError(t, err)
+	require.NoError(t, s.Verify())
+
+	s.Variables.Set("counter", []byte{2})
+	require.Error(t, s.Verify())
+	require.Error(t, importSnapshot(mapdb.NewMapDB(), s))
+}
+
+func TestSnapshotVerifyAnchor(t *testing.T) {
+	chainID := coretypes.ChainID{1, 3, 3, 7}
+	color := balance.Color{1, 2, 3}
+	vs := NewVirtualState(mapdb.NewMapDB(), &chainID)
+
+	reqid := coretypes.NewRequestID(transaction.ID{}, 0)
+	su := NewStateUpdate(&reqid)
+	su.Mutations().Add(buffered.NewMutationSet("counter", []byte{1}))
+	block, err := NewBlock([]StateUpdate{su})
+	require.NoError(t, err)
+	block.WithBlockIndex(0)
+	require.NoError(t, vs.ApplyBlock(block))
+
+	anchor := func(color balance.Color, blockIndex uint32, stateHash hashing.HashValue) *sctransaction.Transaction {
+		vtx := transaction.New(transaction.NewInputs(), transaction.NewOutputs(map[address.Address][]*balance.Balance{}))
+		tx, err := sctransaction.NewTransaction(vtx, sctransaction.NewStateSection(sctransaction.NewStateSectionParams{
+			Color:      color,
+			BlockIndex: blockIndex,
+			StateHash:  stateHash,
+		}), nil)
+		require.NoError(t, err)
+		return tx
+	}
+	tx := anchor(color, 0, vs.Hash())
+	block.WithStateTransaction(tx.ID())
+	s, err := NewSnapshot(&chainID, vs, block)
+	require.NoError(t, err)
+	require.NoError(t, s.Verify())
+	require.NoError(t, s.VerifyAnchor(tx, &color))
+
+	require.Error(t, s.VerifyAnchor(tx, &balance.Color{3, 2, 1}))
+	require.Error(t, s.VerifyAnchor(anchor(color, 0, hashing.HashStrings("other")), &color))
+
+	// the transaction anchors the same state hash with another state index
+	tx = anchor(color, 1, vs.Hash())
+	block.WithStateTransaction(tx.ID())
+	require.Error(t, s.VerifyAnchor(tx, &color))
+}
@@ -22,15 +22,25 @@ type virtualState struct {
 	timestamp  int64
 	empty      bool
 	stateHash  hashing.HashValue
+	merkleRoot hashing.HashValue
 	variables  buffered.BufferedKVStore
+	// nodes of the Merkle tree of the state variables
+	merkleNodes buffered.BufferedKVStore
+	// keys updated after the Merkle root was calculated last time
+	merkleDirty map[kv.Key]bool
+	// legacyHash is set for the state migrated from the database of version 0. The hash of such state,
+	// anchored on the tangle, doesn't commit to the Merkle root. It is reset by the next block
+	legacyHash bool
 }
 
 func NewVirtualState(db kvstore.KVStore, chainID *coretypes.ChainID) *virtualState {
 	return &virtualState{
-		chainID:   *chainID,
-		db:        db,
-		variables: buffered.NewBufferedKVStore(subRealm(db, []byte{dbprovider.ObjectTypeStateVariable})),
-		empty:     true,
+		chainID:     *chainID,
+		db:          db,
+		variables:   buffered.NewBufferedKVStore(subRealm(db, []byte{dbprovider.ObjectTypeStateVariable})),
+		merkleNodes: buffered.NewBufferedKVStore(subRealm(db, []byte{dbprovider.ObjectTypeMerkleNode})),
+		merkleDirty: make(map[kv.Key]bool),
+		empty:       true,
 	}
 }
 
@@ -50,22 +60,31 @@ func subRealm(db kvstore.KVStore, realm []byte) kvstore.KVStore {
 }
 
 func (vs *virtualState) Clone() VirtualState {
+	dirty := make(map[kv.Key]bool, len(vs.merkleDirty))
+	for k := range vs.merkleDirty {
+		dirty[k] = true
+	}
 	return &virtualState{
-		chainID:    vs.chainID,
-		db:         vs.db,
-		blockIndex: vs.blockIndex,
-		timestamp:  vs.timestamp,
-		empty:      vs.empty,
-		stateHash:  vs.stateHash,
-		variables:  vs.variables.Clone(),
+		chainID:     vs.chainID,
+		db:          vs.db,
+		blockIndex:  vs.blockIndex,
+		timestamp:   vs.timestamp,
+		empty:       vs.empty,
+		stateHash:   vs.stateHash,
+		merkleRoot:  vs.merkleRoot,
+		variables:   vs.variables.Clone(),
+		merkleNodes: vs.merkleNodes.Clone(),
+		merkleDirty: dirty,
+		legacyHash:  vs.legacyHash,
 	}
 }
 
 func (vs *virtualState) DangerouslyConvertToString() string {
-	return fmt.Sprintf("#%d, ts: %d, hash, %s\n%s",
+	return fmt.Sprintf("#%d, ts: %d, hash, %s, merkle root: %s\n%s",
 		vs.blockIndex,
 		vs.timestamp,
-		vs.stateHash.String(),
+		vs.Hash().String(),
+		vs.merkleRoot.String(),
 		vs.Variables().DangerouslyDumpToString(),
 	)
 }
@@ -78,11 +97,31 @@ func (vs *virtualState) BlockIndex() uint32 {
 	return vs.blockIndex
 }
 
+// ApplyBlockIndex sets the state index and updates the Merkle tree with the state variables changed since the last update
 func (vs *virtualState) ApplyBlockIndex(blockIndex uint32) {
-	vh := vs.Hash()
-	vs.stateHash = hashing.HashData(vh[:], util.Uint32To4Bytes(blockIndex))
+	vs.applyBlockIndex(blockIndex)
+	vs.updateMerkleRoot()
+}
+
+func (vs *virtualState) applyBlockIndex(blockIndex uint32) {
+	vs.stateHash = hashing.HashData(vs.stateHash[:], util.Uint32To4Bytes(blockIndex))
 	vs.empty = false
 	vs.blockIndex = blockIndex
+	vs.legacyHash = false
+}
+
+// updateMerkleRoot updates the Merkle tree only with the keys changed since the previous update
+func (vs *virtualState) updateMerkleRoot() {
+	t := vs.merkleTree()
+	for k := range vs.merkleDirty {
+		t.update(k, vs.variables.MustGet(k))
+	}
+	vs.merkleDirty = make(map[kv.Key]bool)
+	vs.merkleRoot = t.root()
+}
+
+func (vs *virtualState) merkleTree() *merkleTree {
+	return &merkleTree{nodes: vs.merkleNodes}
 }
 
 func (vs *virtualState) Timestamp() int64 {
@@ -91,6 +130,15 @@ func (vs *virtualState) Timestamp() int64 {
 
 // applies block of state updates. Increases state index
 func (vs *virtualState) ApplyBlock(batch Block) error {
+	if err := vs.applyBlock(batch); err != nil {
+		return err
+	}
+	vs.updateMerkleRoot()
+	return nil
+}
+
+// applyBlock applies the block without recalculating the Merkle root
+func (vs *virtualState) applyBlock(batch Block) error {
 	if !vs.empty {
 		if batch.StateIndex() != vs.blockIndex+1 {
 			return fmt.Errorf("ApplyBlock: block state index #%d can't be applied to the state #%d",
@@ -105,24 +153,44 @@ func (vs *virtualState) ApplyBlock(batch Block) error {
 		vs.ApplyStateUpdate(stateUpd)
 		return true
 	})
-	vs.ApplyBlockIndex(batch.StateIndex())
+	vs.applyBlockIndex(batch.StateIndex())
 	return nil
 }
 
 // applies one state update. Doesn't change state index
 func (vs *virtualState) ApplyStateUpdate(stateUpd StateUpdate) {
 	stateUpd.Mutations().ApplyTo(vs.Variables())
+	stateUpd.Mutations().Iterate(func(mut buffered.Mutation) bool {
+		vs.merkleDirty[mut.Key()] = true
+		return true
+	})
 	vs.timestamp = stateUpd.Timestamp()
-	vh := vs.Hash()
 	sh := util.GetHashValue(stateUpd)
-	vs.stateHash = hashing.HashData(vh[:], sh[:], util.Uint64To8Bytes(uint64(vs.timestamp)))
+	vs.stateHash = hashing.HashData(vs.stateHash[:], sh[:], util.Uint64To8Bytes(uint64(vs.timestamp)))
 	vs.empty = false
 }
 
+// Hash commits both to the chain of state updates and to the Merkle root of the state variables.
+// The Merkle root is recalculated only when block index is applied
 func (vs *virtualState) Hash() hashing.HashValue {
+	if vs.empty || vs.legacyHash {
+		return vs.stateHash
+	}
+	return StateHashFromMerkleRoot(vs.stateHash, vs.merkleRoot)
+}
+
+func (vs *virtualState) ChainedHash() hashing.HashValue {
 	return vs.stateHash
 }
 
+func (vs *virtualState) MerkleRoot() hashing.HashValue {
+	return vs.merkleRoot
+}
+
+func (vs *virtualState) GetProof(key kv.Key) (*MerkleProof, error) {
+	return vs.merkleTree().proof(key)
+}
+
 func (vs *virtualState) Write(w io.Writer) error {
 	if _, err := w.Write(util.Uint32To4Bytes(vs.blockIndex)); err != nil {
 		return err
@@ -133,7 +201,13 @@ func (vs *virtualState) Write(w io.Writer) error {
 	if _, err := w.Write(vs.stateHash[:]); err != nil {
 		return err
 	}
-	return nil
+	if _, err := w.Write(vs.merkleRoot[:]); err != nil {
+		return err
+	}
+	if !vs.legacyHash {
+		return nil
+	}
+	return util.WriteBoolByte(w, true)
 }
 
 func (vs *virtualState) Read(r io.Reader) error {
@@ -145,7 +219,15 @@ func (vs *virtualState) Read(r io.Reader) error {
 		return err
 	}
 	vs.timestamp = int64(ts)
-	if _, err := r.Read(vs.stateHash[:]); err != nil {
+	if err := util.ReadHashValue(r, &vs.stateHash); err != nil {
+		return err
+	}
+	if err := util.ReadHashValue(r, &vs.merkleRoot); err != nil {
+		return err
+	}
+	// the flag is only stored for the state migrated from the database of version 0
+	vs.legacyHash = false
+	if err := util.ReadBoolByte(r, &vs.legacyHash); err != nil && err != io.EOF {
 		return err
 	}
 	// after reading something, the state is not empty
@@ -188,12 +270,18 @@ func (vs *virtualState) CommitToDb(b Block) error {
 		values = append(values, mut.Value())
 		return true
 	})
+	vs.merkleNodes.Mutations().IterateLatest(func(k kv.Key, mut buffered.Mutation) bool {
+		keys = append(keys, dbkeyMerkleNode(k))
+		values = append(values, mut.Value())
+		return true
+	})
 
 	err = util.DbSetMulti(vs.db, keys, values)
 	if err != nil {
 		return err
 	}
 	vs.variables.ClearMutations()
+	vs.merkleNodes.ClearMutations()
 	return nil
 }
 
@@ -236,6 +324,10 @@ func dbkeyStateVariable(key kv.Key) []byte {
 	return dbprovider.MakeKey(dbprovider.ObjectTypeStateVariable, []byte(key))
 }
 
+func dbkeyMerkleNode(key kv.Key) []byte {
+	return dbprovider.MakeKey(dbprovider.ObjectTypeMerkleNode, []byte(key))
+}
+
 func dbkeyRequest(reqid *coretypes.RequestID) []byte {
 	return dbprovider.MakeKey(dbprovider.ObjectTypeProcessedRequestId, reqid[:])
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/kv/buffered"
+	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/packages/util"
 )
 
@@ -13,6 +14,22 @@ type stateUpdate struct {
 	requestID coretypes.RequestID
 	timestamp int64
 	mutations buffered.MutationSequence
+	result    *RequestResult
+}
+
+// RequestResult is the outcome of the call from the request, recorded by the VM in the state update
+type RequestResult struct {
+	// target contract of the request
+	Contract coretypes.Hname
+	// values returned by the entry point. Nil if the call failed
+	Result dict.Dict
+	// error message. Empty if the call succeeded
+	Error     string
+	GasBurned uint64
+	// Job is true if the state update is a run of the scheduled job with JobID, not a request.
+	// The request ID of such state update is synthetic, it does not correspond to any request on the tangle
+	Job   bool
+	JobID uint32
 }
 
 func NewStateUpdate(reqid *coretypes.RequestID) StateUpdate {
@@ -36,6 +53,13 @@ func NewStateUpdateRead(r io.Reader) (StateUpdate, error) {
 func (su *stateUpdate) Clone() StateUpdate {
 	ret := *su
 	ret.mutations = su.mutations.Clone()
+	if su.result != nil {
+		res := *su.result
+		if su.result.Result != nil {
+			res.Result = su.result.Result.Clone()
+		}
+		ret.result = &res
+	}
 	return &ret
 }
 
@@ -61,6 +85,15 @@ func (su *stateUpdate) Mutations() buffered.MutationSequence {
 	return su.mutations
 }
 
+func (su *stateUpdate) Result() *RequestResult {
+	return su.result
+}
+
+func (su *stateUpdate) WithResult(res *RequestResult) StateUpdate {
+	su.result = res
+	return su
+}
+
 func (su *stateUpdate) Write(w io.Writer) error {
 	if err := su.requestID.Write(w); err != nil {
 		return err
@@ -68,10 +101,85 @@ func (su *stateUpdate) Write(w io.Writer) error {
 	if err := su.mutations.Write(w); err != nil {
 		return err
 	}
-	return util.WriteUint64(w, uint64(su.timestamp))
+	if err := util.WriteUint64(w, uint64(su.timestamp)); err != nil {
+		return err
+	}
+	if err := util.WriteBoolByte(w, su.result != nil); err != nil {
+		return err
+	}
+	if su.result == nil {
+		return nil
+	}
+	if err := su.result.Contract.Write(w); err != nil {
+		return err
+	}
+	if err := util.WriteBytes32(w, []byte(su.result.Error)); err != nil {
+		return err
+	}
+	if err := util.WriteUint64(w, su.result.GasBurned); err != nil {
+		return err
+	}
+	if err := util.WriteBoolByte(w, su.result.Job); err != nil {
+		return err
+	}
+	if su.result.Job {
+		if err := util.WriteUint32(w, su.result.JobID); err != nil {
+			return err
+		}
+	}
+	if err := util.WriteBoolByte(w, su.result.Result != nil); err != nil {
+		return err
+	}
+	if su.result.Result == nil {
+		return nil
+	}
+	return su.result.Result.Write(w)
 }
 
 func (su *stateUpdate) Read(r io.Reader) error {
+	if err := su.readLegacy(r); err != nil {
+		return err
+	}
+	var hasResult bool
+	if err := util.ReadBoolByte(r, &hasResult); err != nil {
+		return err
+	}
+	if !hasResult {
+		su.result = nil
+		return nil
+	}
+	su.result = &RequestResult{}
+	if err := su.result.Contract.Read(r); err != nil {
+		return err
+	}
+	errMsg, err := util.ReadBytes32(r)
+	if err != nil {
+		return err
+	}
+	su.result.Error = string(errMsg)
+	if err := util.ReadUint64(r, &su.result.GasBurned); err != nil {
+		return err
+	}
+	if err := util.ReadBoolByte(r, &su.result.Job); err != nil {
+		return err
+	}
+	if su.result.Job {
+		if err := util.ReadUint32(r, &su.result.JobID); err != nil {
+			return err
+		}
+	}
+	if err := util.ReadBoolByte(r, &hasResult); err != nil {
+		return err
+	}
+	if !hasResult {
+		return nil
+	}
+	su.result.Result = dict.New()
+	return su.result.Result.Read(r)
+}
+
+// readLegacy reads the state update without the result, as it was stored in the database of version 0
+func (su *stateUpdate) readLegacy(r io.Reader) error {
 	if err := su.requestID.Read(r); err != nil {
 		return err
 	}
@@ -83,5 +191,6 @@ func (su *stateUpdate) Read(r io.Reader) error {
 		return err
 	}
 	su.timestamp = int64(ts)
+	su.result = nil
 	return nil
 }
@@ -6,6 +6,7 @@ import (
 	valuetransaction "github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
 	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv"
 	"github.com/iotaledger/wasp/packages/kv/buffered"
 )
 
@@ -23,9 +24,15 @@ type VirtualState interface {
 	ApplyBlock(Block) error
 	// commit means saving virtual state to sc db, making it persistent (solid)
 	CommitToDb(batch Block) error
-	// return hash of the variable state. It is a root of the Merkle chain of all
-	// state updates starting from the origin
+	// return hash of the variable state. It commits to the chain of all
+	// state updates starting from the origin and to the Merkle root of the state variables
 	Hash() hashing.HashValue
+	// hash of the chain of all state updates and block indices starting from the origin
+	ChainedHash() hashing.HashValue
+	// root of the Merkle tree over all state variables
+	MerkleRoot() hashing.HashValue
+	// proof of inclusion of the key into the Merkle tree of the state variables
+	GetProof(key kv.Key) (*MerkleProof, error)
 	// the storage of variable/value pairs
 	Variables() buffered.BufferedKVStore
 	Clone() VirtualState
@@ -45,6 +52,9 @@ type StateUpdate interface {
 	// the payload of variables/values
 	String() string
 	Mutations() buffered.MutationSequence
+	// outcome of the request, nil if not recorded (e.g. in the origin block)
+	Result() *RequestResult
+	WithResult(*RequestResult) StateUpdate
 	Clone() StateUpdate
 	Write(io.Writer) error
 	Read(io.Reader) error
@@ -59,6 +69,7 @@ type Block interface {
 	WithStateTransaction(valuetransaction.ID) Block
 	Timestamp() int64
 	Size() uint16
+	// IDs of the processed requests, without runs of scheduled jobs
 	RequestIDs() []*coretypes.RequestID
 	EssenceHash() hashing.HashValue // except state transaction id
 	String() string
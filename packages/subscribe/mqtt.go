@@ -0,0 +1,63 @@
+package subscribe
+
+import (
+	"fmt"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+// MQTTMessage is a message received from the MQTT broker of the Wasp node
+type MQTTMessage struct {
+	Topic    string
+	Payload  []byte
+	Retained bool
+}
+
+// SubscribeMQTT connects to the embedded MQTT broker of the Wasp node at host and sends
+// received messages of the topics to the channel until done is closed.
+// Topics may contain MQTT wildcards, see publisher.MQTTEventsTopic and other topic
+// functions for the topic hierarchy. For example, 'wasp/+/state/index' subscribes to retained
+// last state indices of all chains
+func SubscribeMQTT(host string, messages chan<- *MQTTMessage, done <-chan bool, keepTrying bool, topics ...string) error {
+	opts := mqtt.NewClientOptions().
+		AddBroker("tcp://" + host).
+		SetAutoReconnect(keepTrying).
+		SetConnectRetry(keepTrying).
+		SetConnectRetryInterval(200 * time.Millisecond)
+	client := mqtt.NewClient(opts)
+
+	token := client.Connect()
+	token.Wait()
+	if err := token.Error(); err != nil {
+		return fmt.Errorf("can't connect to MQTT broker %s: %v", host, err)
+	}
+
+	filters := make(map[string]byte)
+	for _, topic := range topics {
+		filters[topic] = 0
+	}
+	token = client.SubscribeMultiple(filters, func(_ mqtt.Client, msg mqtt.Message) {
+		m := &MQTTMessage{
+			Topic:    msg.Topic(),
+			Payload:  msg.Payload(),
+			Retained: msg.Retained(),
+		}
+		select {
+		case messages <- m:
+		case <-done:
+		}
+	})
+	token.Wait()
+	if err := token.Error(); err != nil {
+		client.Disconnect(0)
+		return fmt.Errorf("can't subscribe to MQTT topics %v: %v", topics, err)
+	}
+
+	go func() {
+		<-done
+		client.Disconnect(250)
+	}()
+
+	return nil
+}
@@ -0,0 +1,149 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package tcrypto
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/util"
+	"go.dedis.ch/kyber/v3"
+	"go.dedis.ch/kyber/v3/encrypt/ecies"
+)
+
+// StealthKeys are public key shares of the committee members.
+// Data encrypted with StealthKeys ("stealth" data) can only be decrypted by committee members:
+//  - the data is encrypted with the random symmetric key (AES-256 GCM)
+//  - the symmetric key is encrypted for each committee member with its public key share (ECIES)
+// Every committee member decrypts the data independently with its private share
+type StealthKeys struct {
+	Suite        kyber.Group
+	PubKeyShares []kyber.Point
+}
+
+// ErrStealthKeyUnavailable means the symmetric key wasn't encrypted correctly for the committee member.
+// Other members may still be able to decrypt the data, so the failure is specific to the node.
+// Any other decryption error is the same for all committee members
+var ErrStealthKeyUnavailable = errors.New("stealth: symmetric key is not available to the committee member")
+
+// NewStealthKeys returns the stealth keys of the committee which shares the key of the DKShare
+func (s *DKShare) NewStealthKeys() *StealthKeys {
+	return &StealthKeys{
+		Suite:        s.suite,
+		PubKeyShares: s.PublicShares,
+	}
+}
+
+// Encrypt encrypts data for the committee
+func (k *StealthKeys) Encrypt(data []byte) ([]byte, error) {
+	if len(k.PubKeyShares) == 0 {
+		return nil, errors.New("stealth: no public key shares")
+	}
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	ciphertext, err := sealAESGCM(key, data)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	// hash of the symmetric key commits the sender to the same key for all committee members
+	keyHash := hashing.HashData(key)
+	buf.Write(keyHash[:])
+	if err = util.WriteUint16(&buf, uint16(len(k.PubKeyShares))); err != nil {
+		return nil, err
+	}
+	for _, pub := range k.PubKeyShares {
+		wrapped, err := ecies.Encrypt(k.Suite, pub, key, sha256.New)
+		if err != nil {
+			return nil, err
+		}
+		if err = util.WriteBytes16(&buf, wrapped); err != nil {
+			return nil, err
+		}
+	}
+	if err = util.WriteBytes32(&buf, ciphertext); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// DecryptStealth decrypts data encrypted with StealthKeys of the committee by the private share of the node
+func (s *DKShare) DecryptStealth(data []byte) ([]byte, error) {
+	if s.suite == nil || s.Index == nil {
+		return nil, fmt.Errorf("%w: key share can't be used for decryption", ErrStealthKeyUnavailable)
+	}
+	return DecryptStealth(s.suite, *s.Index, s.PrivateShare, data)
+}
+
+// DecryptStealth decrypts data encrypted with StealthKeys by the private key share of the committee member with the index
+func DecryptStealth(suite kyber.Group, index uint16, privateShare kyber.Scalar, data []byte) ([]byte, error) {
+	r := bytes.NewReader(data)
+	var keyHash hashing.HashValue
+	if _, err := io.ReadFull(r, keyHash[:]); err != nil {
+		return nil, errors.New("stealth: wrong data format")
+	}
+	var num uint16
+	if err := util.ReadUint16(r, &num); err != nil {
+		return nil, err
+	}
+	if index >= num {
+		return nil, fmt.Errorf("%w: data is not encrypted for the committee member #%d", ErrStealthKeyUnavailable, index)
+	}
+	var wrapped []byte
+	for i := uint16(0); i < num; i++ {
+		w, err := util.ReadBytes16(r)
+		if err != nil {
+			return nil, err
+		}
+		if i == index {
+			wrapped = w
+		}
+	}
+	ciphertext, err := util.ReadBytes32(r)
+	if err != nil {
+		return nil, err
+	}
+	key, err := ecies.Decrypt(suite, privateShare, wrapped, sha256.New)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrStealthKeyUnavailable, err)
+	}
+	if hashing.HashData(key) != keyHash {
+		return nil, fmt.Errorf("%w: wrong symmetric key", ErrStealthKeyUnavailable)
+	}
+	return openAESGCM(key, ciphertext)
+}
+
+// the symmetric key is never reused, so the nonce is always zero
+func sealAESGCM(key, data []byte) ([]byte, error) {
+	aead, err := newAESGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	return aead.Seal(nil, make([]byte, aead.NonceSize()), data, nil), nil
+}
+
+func openAESGCM(key, ciphertext []byte) ([]byte, error) {
+	aead, err := newAESGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	return aead.Open(nil, make([]byte, aead.NonceSize()), ciphertext, nil)
+}
+
+func newAESGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,44 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package tcrypto
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.dedis.ch/kyber/v3/pairing"
+	"go.dedis.ch/kyber/v3/util/key"
+)
+
+func TestStealth(t *testing.T) {
+	suite := pairing.NewSuiteBn256()
+	pairs := []*key.Pair{key.NewKeyPair(suite), key.NewKeyPair(suite), key.NewKeyPair(suite)}
+	keys := &StealthKeys{Suite: suite}
+	for _, p := range pairs {
+		keys.PubKeyShares = append(keys.PubKeyShares, p.Public)
+	}
+	data := []byte("secret request data")
+	encrypted, err := keys.Encrypt(data)
+	require.NoError(t, err)
+	require.NotContains(t, string(encrypted), string(data))
+
+	for i, p := range pairs {
+		decrypted, err := DecryptStealth(suite, uint16(i), p.Private, encrypted)
+		require.NoError(t, err)
+		require.EqualValues(t, data, decrypted)
+	}
+	// wrong private key
+	_, err = DecryptStealth(suite, 0, pairs[1].Private, encrypted)
+	require.True(t, errors.Is(err, ErrStealthKeyUnavailable))
+	// not a committee member
+	_, err = DecryptStealth(suite, 3, pairs[0].Private, encrypted)
+	require.True(t, errors.Is(err, ErrStealthKeyUnavailable))
+	// corrupted data fails for all committee members alike
+	corrupted := append([]byte{}, encrypted...)
+	corrupted[len(corrupted)-1] ^= 0xff
+	_, err = DecryptStealth(suite, 0, pairs[0].Private, corrupted)
+	require.Error(t, err)
+	require.False(t, errors.Is(err, ErrStealthKeyUnavailable))
+}
@@ -0,0 +1,62 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package testutil
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/iotaledger/wasp/packages/peering"
+	"go.dedis.ch/kyber/v3"
+)
+
+// TrustedNetworkManager stands for a mock for peering.TrustedNetworkManager.
+type TrustedNetworkManager struct {
+	trusted []*peering.TrustedPeer
+	mutex   sync.Mutex
+}
+
+// NewTrustedNetworkManager creates new mocked trusted network manager.
+func NewTrustedNetworkManager() *TrustedNetworkManager {
+	return &TrustedNetworkManager{
+		trusted: make([]*peering.TrustedPeer, 0),
+	}
+}
+
+// TrustPeer implements peering.TrustedNetworkManager.
+func (tnm *TrustedNetworkManager) TrustPeer(pubKey kyber.Point, netID string) (*peering.TrustedPeer, error) {
+	tnm.mutex.Lock()
+	defer tnm.mutex.Unlock()
+	for _, tp := range tnm.trusted {
+		if tp.PubKey.Equal(pubKey) {
+			tp.NetID = netID
+			return tp, nil
+		}
+	}
+	tp := &peering.TrustedPeer{PubKey: pubKey, NetID: netID}
+	tnm.trusted = append(tnm.trusted, tp)
+	return tp, nil
+}
+
+// DistrustPeer implements peering.TrustedNetworkManager.
+func (tnm *TrustedNetworkManager) DistrustPeer(pubKey kyber.Point) (*peering.TrustedPeer, error) {
+	tnm.mutex.Lock()
+	defer tnm.mutex.Unlock()
+	for i, tp := range tnm.trusted {
+		if tp.PubKey.Equal(pubKey) {
+			tnm.trusted = append(tnm.trusted[:i], tnm.trusted[i+1:]...)
+			return tp, nil
+		}
+	}
+	return nil, fmt.Errorf("peer %s is not trusted", peering.PubKeyToString(pubKey))
+}
+
+// TrustedPeers implements peering.TrustedNetworkManager.
+func (tnm *TrustedNetworkManager) TrustedPeers() ([]*peering.TrustedPeer, error) {
+	tnm.mutex.Lock()
+	defer tnm.mutex.Unlock()
+	ret := make([]*peering.TrustedPeer, len(tnm.trusted))
+	copy(ret, tnm.trusted)
+	return ret, nil
+}
@@ -2,10 +2,13 @@ package accounts
 
 import (
 	"fmt"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
 	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/coretypes/assert"
 	"github.com/iotaledger/wasp/packages/coretypes/cbalances"
 	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/collections"
 	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/packages/kv/kvdecoder"
 )
@@ -57,8 +60,17 @@ func deposit(ctx coretypes.Sandbox) (dict.Dict, error) {
 
 	// funds currently are at the disposition of accounts, they are moved to the target
 	succ := MoveBetweenAccounts(state, coretypes.NewAgentIDFromContractID(ctx.ContractID()), targetAgentID, ctx.IncomingTransfer())
-	assert.NewAssert(ctx.Log()).Require(succ,
-		"internal error: failed to deposit to %s", ctx.Caller().String())
+	a := assert.NewAssert(ctx.Log())
+	a.Require(succ, "internal error: failed to deposit to %s", ctx.Caller().String())
+
+	if collections.NewMapReadOnly(ctx.Params(), ParamNFTs).MustLen() > 0 {
+		// NFTs withdrawn from another chain. Only the 'accounts' contract of another chain can bring them
+		caller := ctx.Caller()
+		a.Require(!caller.IsAddress() && caller.MustContractID().Hname() == Interface.Hname() &&
+			caller.MustContractID().ChainID() != ctx.ContractID().ChainID(),
+			"accounts.deposit: NFTs can only be deposited by the 'accounts' contract of another chain")
+		mustImportNFTs(ctx, a, targetAgentID, ctx.Params())
+	}
 
 	ctx.Log().Debugf("accounts.deposit.success: target: %s\n%s", targetAgentID, ctx.IncomingTransfer().String())
 	return nil, nil
@@ -81,6 +93,11 @@ func withdrawToAddress(ctx coretypes.Sandbox) (dict.Dict, error) {
 		// empty balance, nothing to withdraw
 		return nil, nil
 	}
+	// chain-native tokens can't leave the chain, they stay in the account
+	bals = withoutNativeTokens(state, bals)
+	if len(bals) == 0 {
+		return nil, nil
+	}
 	ctx.Log().Debugf("accounts.withdrawToAddress.begin: caller agentID: %s myContractId: %s",
 		caller.String(), ctx.ContractID().String())
 
@@ -118,6 +135,13 @@ func withdrawToChain(ctx coretypes.Sandbox) (dict.Dict, error) {
 		// empty balance, nothing to withdraw
 		return nil, nil
 	}
+	// NFTs are carried to the target chain by the 'deposit' request
+	nfts := nftsOf(state, bals)
+	// chain-native tokens can't leave the chain, they stay in the account
+	bals = withoutNativeTokens(state, bals)
+	if len(bals) == 0 && len(nfts) == 0 {
+		return nil, nil
+	}
 	toWithdraw := cbalances.NewFromMap(bals)
 	callerContract := caller.MustContractID()
 	if callerContract.ChainID() == ctx.ContractID().ChainID() {
@@ -129,14 +153,247 @@ func withdrawToChain(ctx coretypes.Sandbox) (dict.Dict, error) {
 	succ := MoveBetweenAccounts(ctx.State(), caller, coretypes.NewAgentIDFromContractID(ctx.ContractID()), toWithdraw)
 	a.Require(succ, "accounts.withdrawToChain.inconsistency to move tokens between accounts")
 
+	params := codec.MakeDict(map[string]interface{}{
+		ParamAgentID: caller,
+	})
+	if len(nfts) > 0 {
+		mustExportNFTs(ctx, a, caller, callerContract.ChainID(), nfts, params)
+	}
 	succ = ctx.PostRequest(coretypes.PostRequestParams{
 		TargetContractID: Interface.ContractID(callerContract.ChainID()),
 		EntryPoint:       coretypes.Hn(FuncDeposit),
-		Params: codec.MakeDict(map[string]interface{}{
-			ParamAgentID: caller,
-		}),
-		Transfer: toWithdraw,
+		Params:           params,
+		Transfer:         toWithdraw,
+		RefundOnFailure:  true,
 	})
 	a.Require(succ, "accounts.withdrawToChain.inconsistency: failed to post 'deposit' request")
 	return nil, nil
 }
+
+// withdrawToChainCallback is called by the return request of the failed 'deposit' request posted by withdrawToChain.
+// The refunded tokens are credited back to the account of the agent.
+// It can only be called by the 'accounts' contract of the chain the agent belongs to
+func withdrawToChainCallback(ctx coretypes.Sandbox) (dict.Dict, error) {
+	state := ctx.State()
+	mustCheckLedger(state, "accounts.withdrawToChainCallback.begin")
+	defer mustCheckLedger(state, "accounts.withdrawToChainCallback.exit")
+
+	a := assert.NewAssert(ctx.Log())
+	caller := ctx.Caller()
+	a.Require(!caller.IsAddress() && caller.MustContractID().Hname() == Interface.Hname(),
+		"accounts.withdrawToChainCallback: caller must be the 'accounts' contract")
+
+	result, err := coretypes.NewCallbackResultFromParams(ctx.Params())
+	a.RequireNoError(err)
+	a.Require(result.Failed(), "accounts.withdrawToChainCallback: the 'deposit' request didn't fail")
+	args := kvdecoder.New(result.Args, ctx.Log())
+	agentID := args.MustGetAgentID(ParamAgentID)
+	// the tokens were withdrawn to the chain of the agent, only that chain can return them
+	a.Require(!agentID.IsAddress() && agentID.MustContractID().ChainID() == caller.MustContractID().ChainID(),
+		"accounts.withdrawToChainCallback: the refund must come from the chain of %s", agentID)
+	refund := ctx.IncomingTransfer()
+	a.Require(MoveBetweenAccounts(state, coretypes.NewAgentIDFromContractID(ctx.ContractID()), agentID, refund),
+		"accounts.withdrawToChainCallback.inconsistency: failed to move tokens between accounts")
+	if collections.NewMapReadOnly(result.Args, ParamNFTs).MustLen() > 0 {
+		// NFTs are restored from the arguments of the failed 'deposit' request
+		mustImportNFTs(ctx, a, agentID, result.Args)
+	}
+
+	ctx.Log().Debugf("accounts.withdrawToChainCallback: deposit failed: %s. Refunded to %s: %s",
+		result.Error, agentID, refund.String())
+	return nil, nil
+}
+
+// rootContractName is the name of the 'root' contract. The 'root' package can't be imported because of the import cycle
+const rootContractName = "root"
+
+// sweep moves all funds of the account of the smart contract on the same chain to the target account.
+// Can only be called by the 'root' contract upon removal of the smart contract
+// Params:
+// - ParamAgentID the account to sweep
+// - ParamTarget the target account
+func sweep(ctx coretypes.Sandbox) (dict.Dict, error) {
+	state := ctx.State()
+	mustCheckLedger(state, "accounts.sweep.begin")
+	defer mustCheckLedger(state, "accounts.sweep.exit")
+
+	a := assert.NewAssert(ctx.Log())
+	caller := ctx.Caller()
+	a.Require(!caller.IsAddress() && caller.MustContractID() == coretypes.NewContractID(ctx.ContractID().ChainID(), coretypes.Hn(rootContractName)),
+		"accounts.sweep: not authorized")
+
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+	agentID := params.MustGetAgentID(ParamAgentID)
+	target := params.MustGetAgentID(ParamTarget)
+
+	bals, ok := GetAccountBalances(state, agentID)
+	if !ok {
+		// empty balance, nothing to sweep
+		return nil, nil
+	}
+	transfer := cbalances.NewFromMap(bals)
+	a.Require(MoveBetweenAccounts(state, agentID, target, transfer),
+		"accounts.sweep.inconsistency: failed to move tokens between accounts")
+
+	ctx.Log().Debugf("accounts.sweep.success: %s --> %s: %s", agentID, target, transfer.String())
+	return nil, nil
+}
+
+// names of the 'tokens' and 'nft' contracts and of the entry points of 'nft' called by 'accounts'.
+// The packages can't be imported because of the import cycle
+const (
+	tokensContractName = "tokens"
+	nftContractName    = "nft"
+	nftFuncExportNFTs  = "exportNFTs"
+	nftFuncImportNFTs  = "importNFTs"
+)
+
+func callerIsCoreContract(ctx coretypes.Sandbox, name string) bool {
+	caller := ctx.Caller()
+	return !caller.IsAddress() && caller.MustContractID() == coretypes.NewContractID(ctx.ContractID().ChainID(), coretypes.Hn(name))
+}
+
+// requireCallerIsIssuer checks if the caller is the 'tokens' or the 'nft' contract
+func requireCallerIsIssuer(ctx coretypes.Sandbox, a assert.Assert, fname string) {
+	a.Require(callerIsCoreContract(ctx, tokensContractName) || callerIsCoreContract(ctx, nftContractName),
+		"accounts.%s: not authorized", fname)
+}
+
+// mustExportNFTs removes NFTs from the account and their records from the 'nft' contract.
+// Records are added to params of the 'deposit' request to the target chain
+func mustExportNFTs(ctx coretypes.Sandbox, a assert.Assert, agentID coretypes.AgentID, target coretypes.ChainID, nfts []balance.Color, params dict.Dict) {
+	bals := make(map[balance.Color]int64)
+	exportParams := codec.MakeDict(map[string]interface{}{
+		ParamChainID: target,
+	})
+	ids := collections.NewArray(exportParams, ParamNFTs)
+	for _, col := range nfts {
+		bals[col] = 1
+		ids.MustPush(col[:])
+	}
+	a.Require(DebitFromAccount(ctx.State(), agentID, cbalances.NewFromMap(bals)),
+		"accounts.withdrawToChain.inconsistency: failed to debit NFTs")
+	records, err := ctx.Call(coretypes.Hn(nftContractName), coretypes.Hn(nftFuncExportNFTs), exportParams, nil)
+	a.RequireNoError(err)
+	for k, v := range records {
+		params.Set(k, v)
+	}
+}
+
+// mustImportNFTs stores NFT records from params in the 'nft' contract and credits NFTs to the account.
+// NFTs come from the chain of the caller, the 'nft' contract checks the ID and the origin of each NFT.
+// Colors of IOTA, of chain-native fungible tokens and of any tokens held on the chain can't be imported as NFTs
+func mustImportNFTs(ctx coretypes.Sandbox, a assert.Assert, agentID coretypes.AgentID, params dict.Dict) {
+	state := ctx.State()
+	totals := getTotalAssetsIntern(state)
+	records := codec.MakeDict(map[string]interface{}{
+		ParamChainID: ctx.Caller().MustContractID().ChainID(),
+	})
+	bals := make(map[balance.Color]int64)
+	collections.NewMapReadOnly(params, ParamNFTs).MustIterate(func(elemKey []byte, value []byte) bool {
+		col, _, err := balance.ColorFromBytes(elemKey)
+		a.RequireNoError(err)
+		a.Require(col != balance.ColorIOTA && col != balance.ColorNew,
+			"accounts: color %s can't be imported as NFT", col)
+		a.Require(!IsNativeColor(state, col) || IsNFTColor(state, col),
+			"accounts: %s is a chain-native token, it can't be imported as NFT", col)
+		a.Require(totals.Balance(col) == 0,
+			"accounts: tokens of color %s are held on the chain, it can't be imported as NFT", col)
+		bals[col] = 1
+		collections.NewMap(records, ParamNFTs).MustSetAt(elemKey, value)
+		return true
+	})
+	_, err := ctx.Call(coretypes.Hn(nftContractName), coretypes.Hn(nftFuncImportNFTs), records, nil)
+	a.RequireNoError(err)
+	for col := range bals {
+		RegisterNFTColor(state, col)
+	}
+	CreditToAccount(state, agentID, cbalances.NewFromMap(bals))
+}
+
+func nativeTransfer(a assert.Assert, color balance.Color, amount int64) coretypes.ColoredBalances {
+	a.Require(amount > 0, "accounts: amount must be positive")
+	return cbalances.NewFromMap(map[balance.Color]int64{color: amount})
+}
+
+// mintNative creates new chain-native tokens in the account. The color becomes the chain-native token.
+// Can only be called by the 'tokens' or the 'nft' contract. Tokens minted by 'nft' are NFTs
+// Params:
+// - ParamAgentID the account
+// - ParamColor color of the token
+// - ParamAmount number of tokens
+func mintNative(ctx coretypes.Sandbox) (dict.Dict, error) {
+	state := ctx.State()
+	mustCheckLedger(state, "accounts.mintNative.begin")
+	defer mustCheckLedger(state, "accounts.mintNative.exit")
+
+	a := assert.NewAssert(ctx.Log())
+	requireCallerIsIssuer(ctx, a, "mintNative")
+
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+	agentID := params.MustGetAgentID(ParamAgentID)
+	color := params.MustGetColor(ParamColor)
+	transfer := nativeTransfer(a, color, params.MustGetInt64(ParamAmount))
+
+	if callerIsCoreContract(ctx, nftContractName) {
+		a.Require(transfer.Balance(color) == 1 && !IsNativeColor(state, color),
+			"accounts.mintNative: NFT must be unique")
+		RegisterNFTColor(state, color)
+	} else {
+		a.Require(!IsNFTColor(state, color), "accounts.mintNative: can't mint NFT")
+		RegisterNativeColor(state, color)
+	}
+	CreditToAccount(state, agentID, transfer)
+	ctx.Log().Debugf("accounts.mintNative.success: %s: %s", agentID, transfer.String())
+	return nil, nil
+}
+
+// burnNative destroys chain-native tokens in the account.
+// Can only be called by the 'tokens' or the 'nft' contract
+// Params:
+// - ParamAgentID the account
+// - ParamColor color of the token
+// - ParamAmount number of tokens
+func burnNative(ctx coretypes.Sandbox) (dict.Dict, error) {
+	state := ctx.State()
+	mustCheckLedger(state, "accounts.burnNative.begin")
+	defer mustCheckLedger(state, "accounts.burnNative.exit")
+
+	a := assert.NewAssert(ctx.Log())
+	requireCallerIsIssuer(ctx, a, "burnNative")
+
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+	agentID := params.MustGetAgentID(ParamAgentID)
+	transfer := nativeTransfer(a, params.MustGetColor(ParamColor), params.MustGetInt64(ParamAmount))
+	a.Require(HasNativeTokens(state, transfer), "accounts.burnNative: not a chain-native token")
+	a.Require(DebitFromAccount(state, agentID, transfer), "accounts.burnNative: not enough tokens")
+
+	ctx.Log().Debugf("accounts.burnNative.success: %s: %s", agentID, transfer.String())
+	return nil, nil
+}
+
+// moveNative moves chain-native tokens between accounts on the chain.
+// Can only be called by the 'tokens' or the 'nft' contract
+// Params:
+// - ParamAgentID the source account
+// - ParamTarget the target account
+// - ParamColor color of the token
+// - ParamAmount number of tokens
+func moveNative(ctx coretypes.Sandbox) (dict.Dict, error) {
+	state := ctx.State()
+	mustCheckLedger(state, "accounts.moveNative.begin")
+	defer mustCheckLedger(state, "accounts.moveNative.exit")
+
+	a := assert.NewAssert(ctx.Log())
+	requireCallerIsIssuer(ctx, a, "moveNative")
+
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+	agentID := params.MustGetAgentID(ParamAgentID)
+	target := params.MustGetAgentID(ParamTarget)
+	transfer := nativeTransfer(a, params.MustGetColor(ParamColor), params.MustGetInt64(ParamAmount))
+	a.Require(HasNativeTokens(state, transfer), "accounts.moveNative: not a chain-native token")
+	a.Require(MoveBetweenAccounts(state, agentID, target, transfer), "accounts.moveNative: not enough tokens")
+
+	ctx.Log().Debugf("accounts.moveNative.success: %s --> %s: %s", agentID, target, transfer.String())
+	return nil, nil
+}
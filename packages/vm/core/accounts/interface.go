@@ -26,16 +26,33 @@ func init() {
 		coreutil.Func(FuncDeposit, deposit),
 		coreutil.Func(FuncWithdrawToAddress, withdrawToAddress),
 		coreutil.Func(FuncWithdrawToChain, withdrawToChain),
+		coreutil.Func(FuncWithdrawToChainCallback, withdrawToChainCallback),
+		coreutil.Func(FuncSweep, sweep),
+		coreutil.Func(FuncMintNative, mintNative),
+		coreutil.Func(FuncBurnNative, burnNative),
+		coreutil.Func(FuncMoveNative, moveNative),
 	})
 }
 
 const (
-	FuncBalance           = "balance"
-	FuncTotalAssets       = "totalAssets"
-	FuncDeposit           = "deposit"
-	FuncWithdrawToAddress = "withdrawToAddress"
-	FuncWithdrawToChain   = "withdrawToChain"
-	FuncAccounts          = "accounts"
+	FuncBalance                 = "balance"
+	FuncTotalAssets             = "totalAssets"
+	FuncDeposit                 = "deposit"
+	FuncWithdrawToAddress       = "withdrawToAddress"
+	FuncWithdrawToChain         = "withdrawToChain"
+	FuncWithdrawToChainCallback = "withdrawToChainCallback"
+	FuncAccounts                = "accounts"
+	FuncSweep                   = "sweep"
+	FuncMintNative              = "mintNative"
+	FuncBurnNative              = "burnNative"
+	FuncMoveNative              = "moveNative"
 
 	ParamAgentID = "a"
+	ParamTarget  = "t"
+	ParamColor   = "c"
+	ParamAmount  = "m"
+	// ParamNFTs is the map of NFT records, carried by the 'deposit' request of withdrawToChain
+	ParamNFTs = "f"
+	// ParamChainID is the chain NFTs are exported to or imported from, passed to the 'nft' contract
+	ParamChainID = "h"
 )
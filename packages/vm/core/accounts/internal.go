@@ -1,7 +1,9 @@
 package accounts
 
 import (
+	"bytes"
 	"fmt"
+	"sort"
 
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
 	"github.com/iotaledger/wasp/packages/coretypes"
@@ -14,8 +16,10 @@ import (
 )
 
 const (
-	varStateAccounts    = "a"
-	varStateTotalAssets = "t"
+	varStateAccounts     = "a"
+	varStateTotalAssets  = "t"
+	varStateNativeColors = "n"
+	varStateNFTColors    = "f"
 )
 
 func getAccountsMap(state kv.KVStore) *collections.Map {
@@ -205,6 +209,70 @@ func mustCheckLedger(state kv.KVStore, checkpoint string) {
 	}
 }
 
+// RegisterNativeColor marks the color as the chain-native token, which exists only in the on-chain ledger
+func RegisterNativeColor(state kv.KVStore, color balance.Color) {
+	collections.NewMap(state, varStateNativeColors).MustSetAt(color[:], []byte{0xFF})
+}
+
+// IsNativeColor returns true if the color is the chain-native token, i.e. it can't leave the chain
+func IsNativeColor(state kv.KVStoreReader, color balance.Color) bool {
+	return collections.NewMapReadOnly(state, varStateNativeColors).MustHasAt(color[:])
+}
+
+// HasNativeTokens returns true if there are chain-native tokens in the transfer
+func HasNativeTokens(state kv.KVStoreReader, transfer coretypes.ColoredBalances) bool {
+	if transfer == nil {
+		return false
+	}
+	ret := false
+	transfer.Iterate(func(col balance.Color, bal int64) bool {
+		if bal > 0 && IsNativeColor(state, col) {
+			ret = true
+			return false
+		}
+		return true
+	})
+	return ret
+}
+
+// withoutNativeTokens filters out chain-native tokens from the balances.
+// Only the rest of tokens can be sent out of the chain
+func withoutNativeTokens(state kv.KVStoreReader, bals map[balance.Color]int64) map[balance.Color]int64 {
+	ret := make(map[balance.Color]int64)
+	for col, bal := range bals {
+		if !IsNativeColor(state, col) {
+			ret[col] = bal
+		}
+	}
+	return ret
+}
+
+// RegisterNFTColor marks the color as the non-fungible token. NFTs are chain-native tokens, however
+// unlike chain-native fungible tokens they can be withdrawn to another chain
+func RegisterNFTColor(state kv.KVStore, color balance.Color) {
+	RegisterNativeColor(state, color)
+	collections.NewMap(state, varStateNFTColors).MustSetAt(color[:], []byte{0xFF})
+}
+
+// IsNFTColor returns true if the color is the non-fungible token
+func IsNFTColor(state kv.KVStoreReader, color balance.Color) bool {
+	return collections.NewMapReadOnly(state, varStateNFTColors).MustHasAt(color[:])
+}
+
+// nftsOf returns colors of NFTs in the balances, in deterministic order
+func nftsOf(state kv.KVStoreReader, bals map[balance.Color]int64) []balance.Color {
+	ret := make([]balance.Color, 0)
+	for col, bal := range bals {
+		if bal > 0 && IsNFTColor(state, col) {
+			ret = append(ret, col)
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return bytes.Compare(ret[i][:], ret[j][:]) < 0
+	})
+	return ret
+}
+
 func getAccountBalanceDict(ctx coretypes.SandboxView, account *collections.ImmutableMap, tag string) dict.Dict {
 	balances := getAccountBalances(account)
 	ctx.Log().Debugf("%s. balance = %s\n", tag, cbalances.NewFromMap(balances).String())
@@ -6,7 +6,11 @@ import (
 	"github.com/iotaledger/wasp/packages/vm/core/accounts"
 	"github.com/iotaledger/wasp/packages/vm/core/blob"
 	"github.com/iotaledger/wasp/packages/vm/core/eventlog"
+	"github.com/iotaledger/wasp/packages/vm/core/governance"
+	"github.com/iotaledger/wasp/packages/vm/core/nft"
 	"github.com/iotaledger/wasp/packages/vm/core/root"
+	"github.com/iotaledger/wasp/packages/vm/core/scheduler"
+	"github.com/iotaledger/wasp/packages/vm/core/tokens"
 )
 
 func init() {
@@ -20,6 +24,10 @@ func printWellKnownHnames() {
 	fmt.Printf("    %10s: '%s'\n", accounts.Interface.Hname().String(), accounts.Interface.Name)
 	fmt.Printf("    %10s: '%s'\n", blob.Interface.Hname().String(), blob.Interface.Name)
 	fmt.Printf("    %10s: '%s'\n", eventlog.Interface.Hname().String(), eventlog.Interface.Name)
+	fmt.Printf("    %10s: '%s'\n", scheduler.Interface.Hname().String(), scheduler.Interface.Name)
+	fmt.Printf("    %10s: '%s'\n", tokens.Interface.Hname().String(), tokens.Interface.Name)
+	fmt.Printf("    %10s: '%s'\n", nft.Interface.Hname().String(), nft.Interface.Name)
+	fmt.Printf("    %10s: '%s'\n", governance.Interface.Hname().String(), governance.Interface.Name)
 	fmt.Printf("    %10s: '%s'\n", coretypes.EntryPointInit.String(), coretypes.FuncInit)
 	fmt.Printf("--------------- well known hnames ------------------\n")
 }
@@ -0,0 +1,271 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package eventlog
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/collections"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/util"
+)
+
+// EventRecord is a structured (typed) event emitted by the smart contract.
+// Topics are indexed: events can be queried by any of its topics.
+// Usually topics are bytes of AgentID, Hname, Color and similar values
+type EventRecord struct {
+	Contract  coretypes.Hname
+	Timestamp int64
+	RequestID coretypes.RequestID
+	Name      string
+	Topics    [][]byte
+	Payload   dict.Dict
+}
+
+const (
+	// prefix of the log of structured events of the contract
+	prefixEvents = "ev"
+	// prefix of the index of structured events by topic
+	prefixTopicIndex = "et"
+)
+
+// eventsLogName is the name of the log of structured events of the contract
+func eventsLogName(contract coretypes.Hname) kv.Key {
+	return kv.Key(prefixEvents + string(contract.Bytes()))
+}
+
+// topicIndexName is the name of the index of the topic. Each record of the index points to the event in the log of the contract.
+// The topic is hashed to make keys of different topics non-overlapping
+func topicIndexName(topic []byte) kv.Key {
+	h := hashing.HashData(topic)
+	return kv.Key(prefixTopicIndex + string(h[:]))
+}
+
+// ValidateEvent checks if the structured event can be stored in the event log
+func ValidateEvent(name string, topics [][]byte) error {
+	if name == "" {
+		return fmt.Errorf("event name can't be empty")
+	}
+	// the name is a level of the MQTT topic of the event and a part of the published message
+	if strings.ContainsAny(name, "/+#") || strings.IndexFunc(name, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsControl(r)
+	}) >= 0 {
+		return fmt.Errorf("event name %q contains '/', '+', '#', whitespace or control characters", name)
+	}
+	if len(topics) > MaxEventTopics {
+		return fmt.Errorf("too many event topics: %d. Max is %d", len(topics), MaxEventTopics)
+	}
+	for _, topic := range topics {
+		if len(topic) == 0 || len(topic) > MaxEventTopicSize {
+			return fmt.Errorf("wrong size of the event topic: %d", len(topic))
+		}
+	}
+	return nil
+}
+
+// AppendEvent stores the structured event to the log of the contract and to the index of each topic
+func AppendEvent(state kv.KVStore, rec *EventRecord) {
+	theLog := collections.NewTimestampedLog(state, eventsLogName(rec.Contract))
+	idx := theLog.MustLen()
+	theLog.MustAppend(rec.Timestamp, EncodeEventRecord(rec))
+
+	ref := make([]byte, 0, coretypes.HnameLength+4)
+	ref = append(ref, rec.Contract.Bytes()...)
+	ref = append(ref, util.Uint32To4Bytes(idx)...)
+	indexed := make(map[kv.Key]bool)
+	for _, topic := range rec.Topics {
+		name := topicIndexName(topic)
+		if indexed[name] {
+			// same topic twice in the event
+			continue
+		}
+		indexed[name] = true
+		collections.NewTimestampedLog(state, name).MustAppend(rec.Timestamp, ref)
+	}
+}
+
+// eventFilter returns true if the event must be included into the result
+type eventFilter func(rec *EventRecord) bool
+
+// loadEvents takes up to maxLast last events from the log of the contract in the time interval, in time descending order.
+// At most MaxScannedEvents last events of the interval are checked by the filter
+func loadEvents(state kv.KVStoreReader, contract coretypes.Hname, fromTs, toTs int64, maxLast uint32, filter eventFilter) ([]*EventRecord, error) {
+	theLog := collections.NewTimestampedLogReadOnly(state, eventsLogName(contract))
+	tts, err := theLog.TakeTimeSlice(fromTs, toTs)
+	if err != nil || tts.IsEmpty() {
+		return nil, err
+	}
+	first, last := tts.FromToIndices()
+	ret := make([]*EventRecord, 0)
+	for i := int64(last); i >= int64(first) && i > int64(last)-MaxScannedEvents && uint32(len(ret)) < maxLast; i-- {
+		rec, err := loadEvent(theLog, uint32(i))
+		if err != nil {
+			return nil, err
+		}
+		if filter(rec) {
+			ret = append(ret, rec)
+		}
+	}
+	return ret, nil
+}
+
+// loadEventsByTopic takes up to maxLast last events with the topic in the time interval, in time descending order.
+// At most MaxScannedEvents last events of the interval are checked by the filter
+func loadEventsByTopic(state kv.KVStoreReader, topic []byte, fromTs, toTs int64, maxLast uint32, filter eventFilter) ([]*EventRecord, error) {
+	index := collections.NewTimestampedLogReadOnly(state, topicIndexName(topic))
+	tts, err := index.TakeTimeSlice(fromTs, toTs)
+	if err != nil || tts.IsEmpty() {
+		return nil, err
+	}
+	first, last := tts.FromToIndices()
+	ret := make([]*EventRecord, 0)
+	for i := int64(last); i >= int64(first) && i > int64(last)-MaxScannedEvents && uint32(len(ret)) < maxLast; i-- {
+		refs, err := index.LoadRecordsRaw(uint32(i), uint32(i), false)
+		if err != nil {
+			return nil, err
+		}
+		ref, err := collections.ParseRawLogRecord(refs[0])
+		if err != nil {
+			return nil, err
+		}
+		if len(ref.Data) != coretypes.HnameLength+4 {
+			return nil, fmt.Errorf("wrong topic index record")
+		}
+		contract, err := coretypes.NewHnameFromBytes(ref.Data[:coretypes.HnameLength])
+		if err != nil {
+			return nil, err
+		}
+		idx := util.MustUint32From4Bytes(ref.Data[coretypes.HnameLength:])
+		rec, err := loadEvent(collections.NewTimestampedLogReadOnly(state, eventsLogName(contract)), idx)
+		if err != nil {
+			return nil, err
+		}
+		if filter(rec) {
+			ret = append(ret, rec)
+		}
+	}
+	return ret, nil
+}
+
+func loadEvent(theLog *collections.ImmutableTimestampedLog, idx uint32) (*EventRecord, error) {
+	raw, err := theLog.LoadRecordsRaw(idx, idx, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) == 0 || raw[0] == nil {
+		return nil, fmt.Errorf("event #%d not found", idx)
+	}
+	r, err := collections.ParseRawLogRecord(raw[0])
+	if err != nil {
+		return nil, err
+	}
+	return DecodeEventRecord(r.Data)
+}
+
+// HasTopic returns true if the event is indexed by the topic
+func (e *EventRecord) HasTopic(topic []byte) bool {
+	for _, t := range e.Topics {
+		if bytes.Equal(t, topic) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *EventRecord) String() string {
+	return fmt.Sprintf("%s::%s (topics: %d, payload keys: %d)", e.Contract.String(), e.Name, len(e.Topics), len(e.Payload))
+}
+
+func (e *EventRecord) Write(w io.Writer) error {
+	if err := e.Contract.Write(w); err != nil {
+		return err
+	}
+	if err := util.WriteInt64(w, e.Timestamp); err != nil {
+		return err
+	}
+	if err := e.RequestID.Write(w); err != nil {
+		return err
+	}
+	if err := util.WriteString16(w, e.Name); err != nil {
+		return err
+	}
+	if err := util.WriteUint16(w, uint16(len(e.Topics))); err != nil {
+		return err
+	}
+	for _, topic := range e.Topics {
+		if err := util.WriteBytes16(w, topic); err != nil {
+			return err
+		}
+	}
+	payload := e.Payload
+	if payload == nil {
+		payload = dict.New()
+	}
+	return payload.Write(w)
+}
+
+func (e *EventRecord) Read(r io.Reader) error {
+	var err error
+	if err = e.Contract.Read(r); err != nil {
+		return err
+	}
+	if err = util.ReadInt64(r, &e.Timestamp); err != nil {
+		return err
+	}
+	if err = e.RequestID.Read(r); err != nil {
+		return err
+	}
+	if e.Name, err = util.ReadString16(r); err != nil {
+		return err
+	}
+	var num uint16
+	if err = util.ReadUint16(r, &num); err != nil {
+		return err
+	}
+	e.Topics = make([][]byte, num)
+	for i := range e.Topics {
+		if e.Topics[i], err = util.ReadBytes16(r); err != nil {
+			return err
+		}
+	}
+	e.Payload = dict.New()
+	return e.Payload.Read(r)
+}
+
+func EncodeEventRecord(e *EventRecord) []byte {
+	return util.MustBytes(e)
+}
+
+func DecodeEventRecord(data []byte) (*EventRecord, error) {
+	ret := new(EventRecord)
+	err := ret.Read(bytes.NewReader(data))
+	return ret, err
+}
+
+// DecodeEventRecords decodes the result of the getEvents and getEventsByTopic views
+func DecodeEventRecords(d dict.Dict) ([]*EventRecord, error) {
+	arr := collections.NewArrayReadOnly(d, ParamRecords)
+	n, err := arr.Len()
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]*EventRecord, n)
+	for i := uint16(0); i < n; i++ {
+		data, err := arr.GetAt(i)
+		if err != nil {
+			return nil, err
+		}
+		if ret[i], err = DecodeEventRecord(data); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
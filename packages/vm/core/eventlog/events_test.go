@@ -0,0 +1,45 @@
+package eventlog
+
+import (
+	"testing"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadEventsScanLimit(t *testing.T) {
+	state := dict.New()
+	contract := coretypes.Hn("emitter")
+	topic := []byte("topic")
+	AppendEvent(state, &EventRecord{Contract: contract, Timestamp: 1, Name: "rare", Topics: [][]byte{topic}})
+	for i := 0; i < MaxScannedEvents; i++ {
+		AppendEvent(state, &EventRecord{Contract: contract, Timestamp: int64(i + 2), Name: "common", Topics: [][]byte{topic}})
+	}
+	rare := func(rec *EventRecord) bool { return rec.Name == "rare" }
+
+	recs, err := loadEvents(state, contract, 0, MaxScannedEvents+1, DefaultMaxNumberOfRecords, rare)
+	require.NoError(t, err)
+	require.Len(t, recs, 0)
+	recs, err = loadEventsByTopic(state, topic, 0, MaxScannedEvents+1, DefaultMaxNumberOfRecords, rare)
+	require.NoError(t, err)
+	require.Len(t, recs, 0)
+
+	// the rare event is found when the interval doesn't contain too many events
+	recs, err = loadEvents(state, contract, 0, MaxScannedEvents, DefaultMaxNumberOfRecords, rare)
+	require.NoError(t, err)
+	require.Len(t, recs, 1)
+	recs, err = loadEventsByTopic(state, topic, 0, MaxScannedEvents, DefaultMaxNumberOfRecords, rare)
+	require.NoError(t, err)
+	require.Len(t, recs, 1)
+}
+
+func TestValidateEvent(t *testing.T) {
+	require.NoError(t, ValidateEvent("transfer", nil))
+	require.NoError(t, ValidateEvent("token.minted_v2", [][]byte{{1}}))
+	// the name must be a valid level of the MQTT topic
+	for _, name := range []string{"", "a/b", "a+", "#", "a b", "a\tb", "a\x00"} {
+		require.Error(t, ValidateEvent(name, nil), name)
+	}
+	require.Error(t, ValidateEvent("transfer", [][]byte{{}}))
+}
@@ -73,3 +73,66 @@ func getRecords(ctx coretypes.SandboxView) (dict.Dict, error) {
 	}
 	return ret, nil
 }
+
+// getEvents returns structured events of the contract between timestamp interval
+// In time descending order
+// Parameters:
+//	- ParamContractHname Hname of the contract to view the events
+//  - ParamEventName Filter param, name of the event. Defaults to all events
+//  - ParamFromTs From interval. Defaults to 0
+//  - ParamToTs To Interval. Defaults to now (if both are missing means all)
+//  - ParamMaxLastRecords Max amount of records that you want to return. Defaults to 50
+// Only the last MaxScannedEvents events of the interval are checked against the filter
+func getEvents(ctx coretypes.SandboxView) (dict.Dict, error) {
+	params := kvdecoder.New(ctx.Params())
+	contractHname, err := params.GetHname(ParamContractHname)
+	if err != nil {
+		return nil, err
+	}
+	q, err := getEventQuery(ctx)
+	if err != nil {
+		return nil, err
+	}
+	recs, err := loadEvents(ctx.State(), contractHname, q.fromTs, q.toTs, q.maxLast, q.filter)
+	if err != nil {
+		return nil, err
+	}
+	return encodeEvents(recs), nil
+}
+
+// getEventsByTopic returns structured events of all contracts with the topic between timestamp interval
+// In time descending order
+// Parameters:
+//	- ParamTopic The topic (bytes)
+//  - ParamContractHname Filter param, Hname of the contract. Defaults to all contracts
+//  - ParamEventName Filter param, name of the event. Defaults to all events
+//  - ParamFromTs From interval. Defaults to 0
+//  - ParamToTs To Interval. Defaults to now (if both are missing means all)
+//  - ParamMaxLastRecords Max amount of records that you want to return. Defaults to 50
+// Only the last MaxScannedEvents events with the topic in the interval are checked against the filters
+func getEventsByTopic(ctx coretypes.SandboxView) (dict.Dict, error) {
+	params := kvdecoder.New(ctx.Params())
+	topic, err := params.GetBytes(ParamTopic)
+	if err != nil {
+		return nil, err
+	}
+	q, err := getEventQuery(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if ctx.Params().MustHas(ParamContractHname) {
+		contractHname, err := params.GetHname(ParamContractHname)
+		if err != nil {
+			return nil, err
+		}
+		nameFilter := q.filter
+		q.filter = func(rec *EventRecord) bool {
+			return rec.Contract == contractHname && nameFilter(rec)
+		}
+	}
+	recs, err := loadEventsByTopic(ctx.State(), topic, q.fromTs, q.toTs, q.maxLast, q.filter)
+	if err != nil {
+		return nil, err
+	}
+	return encodeEvents(recs), nil
+}
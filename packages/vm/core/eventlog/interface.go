@@ -22,6 +22,8 @@ func init() {
 	Interface.WithFunctions(initialize, []coreutil.ContractFunctionInterface{
 		coreutil.ViewFunc(FuncGetRecords, getRecords),
 		coreutil.ViewFunc(FuncGetNumRecords, getNumRecords),
+		coreutil.ViewFunc(FuncGetEvents, getEvents),
+		coreutil.ViewFunc(FuncGetEventsByTopic, getEventsByTopic),
 	})
 }
 
@@ -33,10 +35,22 @@ const (
 	ParamMaxLastRecords = "maxLastRecords"
 	ParamNumRecords     = "numRecords"
 	ParamRecords        = "records"
+	ParamEventName      = "eventName"
+	ParamTopic          = "topic"
 
 	// function names
-	FuncGetRecords    = "getRecords"
-	FuncGetNumRecords = "getNumRecords"
+	FuncGetRecords       = "getRecords"
+	FuncGetNumRecords    = "getNumRecords"
+	FuncGetEvents        = "getEvents"
+	FuncGetEventsByTopic = "getEventsByTopic"
 
 	DefaultMaxNumberOfRecords = 50
+
+	// MaxEventTopics is the maximum number of indexed topics of the structured event
+	MaxEventTopics = 4
+	// MaxEventTopicSize is the maximum size of the topic in bytes
+	MaxEventTopicSize = 64
+	// MaxScannedEvents is the maximum number of events a single query loads from the state,
+	// including those skipped by the filter
+	MaxScannedEvents = 1000
 )
@@ -1,11 +1,124 @@
 package eventlog
 
 import (
+	"fmt"
+	"math"
+	"strings"
+
 	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/buffered"
 	"github.com/iotaledger/wasp/packages/kv/collections"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/kv/kvdecoder"
 )
 
 func AppendToLog(state kv.KVStore, ts int64, contract coretypes.Hname, data []byte) {
 	collections.NewTimestampedLog(state, kv.Key(contract.Bytes())).MustAppend(ts, data)
 }
+
+// Record is a record of the event log of the contract
+type Record struct {
+	Contract  coretypes.Hname
+	Timestamp int64
+	Data      []byte
+}
+
+// RecordsFromMutations returns records appended to event logs by the mutations, e.g. of a state update
+func RecordsFromMutations(muts buffered.MutationSequence) []*Record {
+	prefix := string(Interface.Hname().Bytes())
+	ret := make([]*Record, 0)
+	muts.Iterate(func(mut buffered.Mutation) bool {
+		key := string(mut.Key())
+		if mut.Value() == nil || !strings.HasPrefix(key, prefix) || len(key) < len(prefix)+coretypes.HnameLength {
+			return true
+		}
+		logKey := kv.Key(key[len(prefix):])
+		contract, err := coretypes.NewHnameFromBytes([]byte(logKey[:coretypes.HnameLength]))
+		if err != nil {
+			return true
+		}
+		if !collections.NewTimestampedLogReadOnly(nil, kv.Key(contract.Bytes())).IsElemKey(logKey) {
+			return true
+		}
+		rec, err := collections.ParseRawLogRecord(mut.Value())
+		if err != nil {
+			return true
+		}
+		ret = append(ret, &Record{
+			Contract:  contract,
+			Timestamp: rec.Timestamp,
+			Data:      rec.Data,
+		})
+		return true
+	})
+	return ret
+}
+
+const requestRecordPrefix = "[req] "
+
+// RequestRecord formats the event log record which is stored by the VM for each processed request
+func RequestRecord(reqID *coretypes.RequestID, err error, gasBurned uint64) []byte {
+	e := "Ok"
+	if err != nil {
+		e = err.Error()
+	}
+	return []byte(fmt.Sprintf("%s%s: %s (gas burned: %d)", requestRecordPrefix, reqID.String(), e, gasBurned))
+}
+
+// JobRunRecord formats the event log record which is stored by the VM for each run of the scheduled job
+func JobRunRecord(jobID uint32, err error, gasBurned uint64) []byte {
+	e := "Ok"
+	if err != nil {
+		e = err.Error()
+	}
+	return []byte(fmt.Sprintf("[job] #%d: %s (gas burned: %d)", jobID, e, gasBurned))
+}
+
+type eventQuery struct {
+	fromTs  int64
+	toTs    int64
+	maxLast uint32
+	filter  eventFilter
+}
+
+// getEventQuery decodes common parameters of the event views
+func getEventQuery(ctx coretypes.SandboxView) (*eventQuery, error) {
+	params := kvdecoder.New(ctx.Params())
+	maxLast, err := params.GetInt64(ParamMaxLastRecords, DefaultMaxNumberOfRecords)
+	if err != nil {
+		return nil, err
+	}
+	if maxLast <= 0 || maxLast > math.MaxUint16 {
+		return nil, fmt.Errorf("wrong max number of records: %d", maxLast)
+	}
+	fromTs, err := params.GetInt64(ParamFromTs, 0)
+	if err != nil {
+		return nil, err
+	}
+	toTs, err := params.GetInt64(ParamToTs, ctx.GetTimestamp())
+	if err != nil {
+		return nil, err
+	}
+	name, err := params.GetString(ParamEventName, "")
+	if err != nil {
+		return nil, err
+	}
+	return &eventQuery{
+		fromTs:  fromTs,
+		toTs:    toTs,
+		maxLast: uint32(maxLast),
+		filter: func(rec *EventRecord) bool {
+			return name == "" || rec.Name == name
+		},
+	}, nil
+}
+
+func encodeEvents(recs []*EventRecord) dict.Dict {
+	ret := dict.New()
+	a := collections.NewArray(ret, ParamRecords)
+	for _, rec := range recs {
+		a.MustPush(EncodeEventRecord(rec))
+	}
+	return ret
+}
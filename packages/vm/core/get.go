@@ -7,7 +7,11 @@ import (
 	"github.com/iotaledger/wasp/packages/vm/core/accounts"
 	"github.com/iotaledger/wasp/packages/vm/core/blob"
 	"github.com/iotaledger/wasp/packages/vm/core/eventlog"
+	"github.com/iotaledger/wasp/packages/vm/core/governance"
+	"github.com/iotaledger/wasp/packages/vm/core/nft"
 	"github.com/iotaledger/wasp/packages/vm/core/root"
+	"github.com/iotaledger/wasp/packages/vm/core/scheduler"
+	"github.com/iotaledger/wasp/packages/vm/core/tokens"
 )
 
 const (
@@ -27,6 +31,18 @@ func GetProcessor(programHash hashing.HashValue) (coretypes.Processor, error) {
 
 	case eventlog.Interface.ProgramHash:
 		return eventlog.Interface, nil
+
+	case scheduler.Interface.ProgramHash:
+		return scheduler.Interface, nil
+
+	case tokens.Interface.ProgramHash:
+		return tokens.Interface, nil
+
+	case nft.Interface.ProgramHash:
+		return nft.Interface, nil
+
+	case governance.Interface.ProgramHash:
+		return governance.Interface, nil
 	}
 	return nil, fmt.Errorf("can't find builtin processor with hash %s", programHash.String())
 }
@@ -0,0 +1,279 @@
+// 'governance' is a core contract on the chain. It lets a set of members with weights govern the chain
+// instead of the single chain owner. Members propose calls to entry points of contracts, for example
+// fee changes, deploy permission grants and contract upgrades of the 'root' contract or the transfer of the chain ownership.
+// The proposal is executed on behalf of the 'governance' contract when members with the total weight of at least
+// the quorum vote for it before it expires.
+// When enabled, the 'governance' contract becomes the chain owner, so the 'root' contract accepts
+// calls reserved for the chain owner only from proposals executed by the governance
+package governance
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/coretypes/assert"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/collections"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/kv/kvdecoder"
+)
+
+// the 'root' package can't be imported because of the import cycle
+const (
+	rootContractName            = "root"
+	rootFuncClaimChainOwnership = "claimChainOwnership"
+)
+
+// initialize is mandatory
+func initialize(ctx coretypes.Sandbox) (dict.Dict, error) {
+	ctx.Log().Debugf("governance.initialize.success hname = %s", Interface.Hname().String())
+	return nil, nil
+}
+
+// setMembers replaces members of the governance and the quorum. Can only be called by the chain owner:
+// before the governance is enabled by the owner, after that only by the executed proposal.
+// Votes already cast for open proposals are kept, but they are counted with the current weights of the members:
+// votes of removed members don't count anymore
+// Input:
+// - ParamMembers map agentID -> int64 weight of the member. Weights must be positive, the total weight must fit int64
+// - ParamQuorum int64 total weight of votes needed to execute the proposal
+func setMembers(ctx coretypes.Sandbox) (dict.Dict, error) {
+	a := assert.NewAssert(ctx.Log())
+	a.Require(ctx.Caller() == ctx.ChainOwnerID(), "governance.setMembers: not authorized")
+
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+	members, err := DecodeMembers(collections.NewMapReadOnly(ctx.Params(), ParamMembers))
+	a.RequireNoError(err)
+	a.Require(len(members) > 0 && len(members) <= MaxMembers, "governance.setMembers: number of members must be 1 to %d", MaxMembers)
+	var total int64
+	for _, weight := range members {
+		a.Require(weight > 0, "governance.setMembers: weight must be positive")
+		a.Require(weight <= math.MaxInt64-total, "governance.setMembers: total weight overflows int64")
+		total += weight
+	}
+	quorum := params.MustGetInt64(ParamQuorum)
+	a.Require(quorum > 0 && quorum <= total, "governance.setMembers: quorum must be 1 to %d", total)
+
+	old, err := GetMembers(ctx.State())
+	a.RequireNoError(err)
+	stateMembers := collections.NewMap(ctx.State(), VarMembers)
+	for _, agentID := range sortedAgentIDs(old) {
+		stateMembers.MustDelAt(agentID.Bytes())
+	}
+	for _, agentID := range sortedAgentIDs(members) {
+		stateMembers.MustSetAt(agentID.Bytes(), codec.EncodeInt64(members[agentID]))
+	}
+	ctx.State().Set(VarQuorum, codec.EncodeInt64(quorum))
+
+	ctx.Event(fmt.Sprintf("[governance] members: %d, total weight: %d, quorum: %d", len(members), total, quorum))
+	return nil, nil
+}
+
+// enable makes the 'governance' contract the chain owner. Can only be called by the chain owner, after
+// members are set and the chain ownership is delegated to the 'governance' contract with 'root.delegateChainOwnership'.
+// The governance is disabled by the executed proposal which delegates the chain ownership to another agentID
+func enable(ctx coretypes.Sandbox) (dict.Dict, error) {
+	a := assert.NewAssert(ctx.Log())
+	a.Require(ctx.Caller() == ctx.ChainOwnerID(), "governance.enable: not authorized")
+	a.Require(GetQuorum(ctx.State()) > 0, "governance.enable: members are not set")
+
+	_, err := ctx.Call(coretypes.Hn(rootContractName), coretypes.Hn(rootFuncClaimChainOwnership), nil, nil)
+	a.Require(err == nil, "governance.enable: %v", err)
+
+	ctx.Event(fmt.Sprintf("[governance] enabled, previous chain owner: %s", ctx.Caller()))
+	return nil, nil
+}
+
+// propose creates the proposal to call the entry point of the contract. Can only be called by the member.
+// The vote of the proposer is counted
+// Input:
+// - ParamContractHname Hname of the contract to call
+// - ParamEntryPoint Hname of the entry point to call
+// - ParamArgs bytes of the dict.Dict with arguments of the call. Optional
+// - ParamDescription string. Optional
+// - ParamExpiry int64 period in seconds after which the proposal expires, up to MaxExpiry. Optional. Defaults to DefaultExpiry
+// Output:
+// - ParamProposalID int64 ID of the proposal
+func propose(ctx coretypes.Sandbox) (dict.Dict, error) {
+	a := assert.NewAssert(ctx.Log())
+	weight := memberWeight(ctx, ctx.Caller())
+	a.Require(weight > 0, "governance.propose: caller is not a member")
+
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+	p := &ProposalRecord{
+		Proposer:    ctx.Caller(),
+		Contract:    params.MustGetHname(ParamContractHname),
+		EntryPoint:  params.MustGetHname(ParamEntryPoint),
+		Args:        dict.New(),
+		Description: params.MustGetString(ParamDescription, ""),
+		Votes:       weight,
+	}
+	if args := params.MustGetBytes(ParamArgs, nil); args != nil {
+		a.RequireNoError(p.Args.Read(bytes.NewReader(args)))
+	}
+	expiry := params.MustGetInt64(ParamExpiry, DefaultExpiry)
+	a.Require(expiry > 0 && expiry <= MaxExpiry, "governance.propose: expiry must be 1 to %d seconds", MaxExpiry)
+	p.Expiry = ctx.GetTimestamp() + expiry*int64(time.Second)
+
+	nextID, _, err := codec.DecodeInt64(ctx.State().MustGet(VarNextProposalID))
+	a.RequireNoError(err)
+	p.ID = uint32(nextID)
+	ctx.State().Set(VarNextProposalID, codec.EncodeInt64(nextID+1))
+	storeProposal(ctx.State(), p)
+	collections.NewMap(ctx.State(), votesName(p.ID)).MustSetAt(ctx.Caller().Bytes(), codec.EncodeInt64(weight))
+
+	ctx.Event(fmt.Sprintf("[governance] %s proposed by %s", p, ctx.Caller()))
+	ret := dict.New()
+	ret.Set(ParamProposalID, codec.EncodeInt64(int64(p.ID)))
+	return ret, nil
+}
+
+// vote adds the weight of the member to the votes for the proposal. Can only be called by the member, once per proposal
+// Input:
+// - ParamProposalID int64 ID of the proposal
+func vote(ctx coretypes.Sandbox) (dict.Dict, error) {
+	a := assert.NewAssert(ctx.Log())
+	weight := memberWeight(ctx, ctx.Caller())
+	a.Require(weight > 0, "governance.vote: caller is not a member")
+
+	p := mustGetOpenProposal(ctx, a)
+	votes := collections.NewMap(ctx.State(), votesName(p.ID))
+	a.Require(!votes.MustHasAt(ctx.Caller().Bytes()), "governance.vote: already voted")
+
+	votes.MustSetAt(ctx.Caller().Bytes(), codec.EncodeInt64(weight))
+	p.Votes = currentVotes(ctx, p.ID)
+	storeProposal(ctx.State(), p)
+
+	ctx.Event(fmt.Sprintf("[governance] %s voted for %s", ctx.Caller(), p))
+	return nil, nil
+}
+
+// execute calls the entry point of the proposal on behalf of the 'governance' contract, if the total weight
+// of votes reached the quorum and the proposal has not expired. Can only be called by the member.
+// The votes are counted with the current weights of the members, so the votes of removed members don't count
+// If the call fails, the proposal remains open and can be executed again before it expires
+// Input:
+// - ParamProposalID int64 ID of the proposal
+// Output:
+// - results of the call
+func execute(ctx coretypes.Sandbox) (dict.Dict, error) {
+	a := assert.NewAssert(ctx.Log())
+	a.Require(memberWeight(ctx, ctx.Caller()) > 0, "governance.execute: caller is not a member")
+
+	p := mustGetOpenProposal(ctx, a)
+	p.Votes = currentVotes(ctx, p.ID)
+	quorum := GetQuorum(ctx.State())
+	a.Require(p.Votes >= quorum, "governance.execute: not enough votes: %d, quorum is %d", p.Votes, quorum)
+
+	p.Executed = true
+	storeProposal(ctx.State(), p)
+	ret, err := ctx.Call(p.Contract, p.EntryPoint, p.Args, nil)
+	if err != nil {
+		return nil, fmt.Errorf("governance.execute: %s: %v", p, err)
+	}
+	ctx.Event(fmt.Sprintf("[governance] executed %s", p))
+	return ret, nil
+}
+
+// getMembers returns members of the governance and the quorum
+// Output:
+// - ParamMembers map agentID -> int64 weight of the member
+// - ParamQuorum int64
+func getMembers(ctx coretypes.SandboxView) (dict.Dict, error) {
+	ret := dict.New()
+	dst := collections.NewMap(ret, ParamMembers)
+	collections.NewMapReadOnly(ctx.State(), VarMembers).MustIterate(func(elemKey []byte, value []byte) bool {
+		dst.MustSetAt(elemKey, value)
+		return true
+	})
+	ret.Set(ParamQuorum, codec.EncodeInt64(GetQuorum(ctx.State())))
+	return ret, nil
+}
+
+// getProposal returns the proposal
+// Input:
+// - ParamProposalID int64 ID of the proposal
+// Output:
+// - ParamProposal bytes of the ProposalRecord
+func getProposal(ctx coretypes.SandboxView) (dict.Dict, error) {
+	a := assert.NewAssert(ctx.Log())
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+	id := uint32(params.MustGetInt64(ParamProposalID))
+
+	p, err := GetProposal(ctx.State(), id)
+	a.RequireNoError(err)
+	a.Require(p != nil, "governance.getProposal: proposal #%d not found", id)
+
+	ret := dict.New()
+	ret.Set(ParamProposal, EncodeProposalRecord(p))
+	return ret, nil
+}
+
+// getProposals returns all proposals
+// Output:
+// - ParamProposals array of ProposalRecord bytes, sorted by ID
+func getProposals(ctx coretypes.SandboxView) (dict.Dict, error) {
+	a := assert.NewAssert(ctx.Log())
+	proposals, err := GetProposals(ctx.State())
+	a.RequireNoError(err)
+
+	ret := dict.New()
+	arr := collections.NewArray(ret, ParamProposals)
+	for _, p := range proposals {
+		arr.MustPush(EncodeProposalRecord(p))
+	}
+	return ret, nil
+}
+
+// memberWeight returns the weight of the member or 0 if the agentID is not a member
+func memberWeight(ctx coretypes.Sandbox, agentID coretypes.AgentID) int64 {
+	ret, _, err := codec.DecodeInt64(collections.NewMapReadOnly(ctx.State(), VarMembers).MustGetAt(agentID.Bytes()))
+	if err != nil {
+		ctx.Log().Panicf("governance: %v", err)
+	}
+	return ret
+}
+
+// currentVotes returns the total current weight of the members who voted for the proposal.
+// It can't overflow because it is not greater than the total weight of members
+func currentVotes(ctx coretypes.Sandbox, id uint32) int64 {
+	members := collections.NewMapReadOnly(ctx.State(), VarMembers)
+	var ret int64
+	collections.NewMapReadOnly(ctx.State(), votesName(id)).MustIterateKeys(func(elemKey []byte) bool {
+		weight, _, err := codec.DecodeInt64(members.MustGetAt(elemKey))
+		if err != nil {
+			ctx.Log().Panicf("governance: %v", err)
+		}
+		ret += weight
+		return true
+	})
+	return ret
+}
+
+// mustGetOpenProposal returns the proposal from ParamProposalID which is neither executed nor expired
+func mustGetOpenProposal(ctx coretypes.Sandbox, a assert.Assert) *ProposalRecord {
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+	id := uint32(params.MustGetInt64(ParamProposalID))
+
+	p, err := GetProposal(ctx.State(), id)
+	a.RequireNoError(err)
+	a.Require(p != nil, "governance: proposal #%d not found", id)
+	a.Require(!p.Executed, "governance: proposal #%d already executed", id)
+	a.Require(ctx.GetTimestamp() <= p.Expiry, "governance: proposal #%d expired", id)
+	return p
+}
+
+func sortedAgentIDs(m map[coretypes.AgentID]int64) []coretypes.AgentID {
+	ret := make([]coretypes.AgentID, 0, len(m))
+	for agentID := range m {
+		ret = append(ret, agentID)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return bytes.Compare(ret[i][:], ret[j][:]) < 0
+	})
+	return ret
+}
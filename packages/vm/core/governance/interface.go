@@ -0,0 +1,70 @@
+package governance
+
+import (
+	"github.com/iotaledger/wasp/packages/coretypes/coreutil"
+	"github.com/iotaledger/wasp/packages/hashing"
+)
+
+const (
+	Name        = "governance"
+	description = "Chain Governance Contract"
+)
+
+var (
+	Interface = &coreutil.ContractInterface{
+		Name:        Name,
+		Description: description,
+		ProgramHash: hashing.HashStrings(Name),
+	}
+)
+
+func init() {
+	Interface.WithFunctions(initialize, []coreutil.ContractFunctionInterface{
+		coreutil.Func(FuncSetMembers, setMembers),
+		coreutil.Func(FuncEnable, enable),
+		coreutil.Func(FuncPropose, propose),
+		coreutil.Func(FuncVote, vote),
+		coreutil.Func(FuncExecute, execute),
+		coreutil.ViewFunc(FuncGetMembers, getMembers),
+		coreutil.ViewFunc(FuncGetProposal, getProposal),
+		coreutil.ViewFunc(FuncGetProposals, getProposals),
+	})
+}
+
+const (
+	// state variables
+	VarMembers        = "m"
+	VarQuorum         = "q"
+	VarProposals      = "p"
+	VarVotes          = "v"
+	VarNextProposalID = "n"
+
+	// request parameters
+	ParamMembers       = "members"
+	ParamQuorum        = "quorum"
+	ParamContractHname = "contractHname"
+	ParamEntryPoint    = "entryPoint"
+	ParamArgs          = "args"
+	ParamDescription   = "description"
+	ParamExpiry        = "expiry"
+	ParamProposalID    = "proposalID"
+	ParamProposal      = "proposal"
+	ParamProposals     = "proposals"
+
+	// function names
+	FuncSetMembers   = "setMembers"
+	FuncEnable       = "enable"
+	FuncPropose      = "propose"
+	FuncVote         = "vote"
+	FuncExecute      = "execute"
+	FuncGetMembers   = "getMembers"
+	FuncGetProposal  = "getProposal"
+	FuncGetProposals = "getProposals"
+
+	// DefaultExpiry is the default period in seconds after which the proposal expires
+	DefaultExpiry = 7 * 24 * 60 * 60
+	// MaxExpiry is the maximum period in seconds after which the proposal expires
+	MaxExpiry = 365 * 24 * 60 * 60
+	// MaxMembers is the maximum number of members of the governance
+	MaxMembers = 64
+)
@@ -0,0 +1,208 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package governance
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/collections"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/util"
+)
+
+// ProposalRecord is the proposal to call the entry point of the contract on behalf of the governance.
+// The proposal is executed when members with the total weight of at least the quorum vote for it before it expires
+type ProposalRecord struct {
+	ID          uint32
+	Proposer    coretypes.AgentID
+	Contract    coretypes.Hname
+	EntryPoint  coretypes.Hname
+	Args        dict.Dict
+	Description string
+	// Expiry is the timestamp after which the proposal can't be voted for or executed, in nanoseconds
+	Expiry int64
+	// Votes is the total weight of the members who voted for the proposal, as of the last vote or execution.
+	// The votes are recounted with the current weights of the members when the proposal is executed
+	Votes    int64
+	Executed bool
+}
+
+func (p *ProposalRecord) String() string {
+	return fmt.Sprintf("proposal #%d %s::%s '%s' (votes: %d, executed: %v)",
+		p.ID, p.Contract, p.EntryPoint, p.Description, p.Votes, p.Executed)
+}
+
+func (p *ProposalRecord) Write(w io.Writer) error {
+	if err := util.WriteUint32(w, p.ID); err != nil {
+		return err
+	}
+	if _, err := w.Write(p.Proposer[:]); err != nil {
+		return err
+	}
+	if err := p.Contract.Write(w); err != nil {
+		return err
+	}
+	if err := p.EntryPoint.Write(w); err != nil {
+		return err
+	}
+	args := p.Args
+	if args == nil {
+		args = dict.New()
+	}
+	if err := args.Write(w); err != nil {
+		return err
+	}
+	if err := util.WriteString16(w, p.Description); err != nil {
+		return err
+	}
+	if err := util.WriteInt64(w, p.Expiry); err != nil {
+		return err
+	}
+	if err := util.WriteInt64(w, p.Votes); err != nil {
+		return err
+	}
+	return util.WriteBoolByte(w, p.Executed)
+}
+
+func (p *ProposalRecord) Read(r io.Reader) error {
+	var err error
+	if err = util.ReadUint32(r, &p.ID); err != nil {
+		return err
+	}
+	if err = coretypes.ReadAgentID(r, &p.Proposer); err != nil {
+		return err
+	}
+	if err = p.Contract.Read(r); err != nil {
+		return err
+	}
+	if err = p.EntryPoint.Read(r); err != nil {
+		return err
+	}
+	p.Args = dict.New()
+	if err = p.Args.Read(r); err != nil {
+		return err
+	}
+	if p.Description, err = util.ReadString16(r); err != nil {
+		return err
+	}
+	if err = util.ReadInt64(r, &p.Expiry); err != nil {
+		return err
+	}
+	if err = util.ReadInt64(r, &p.Votes); err != nil {
+		return err
+	}
+	return util.ReadBoolByte(r, &p.Executed)
+}
+
+func EncodeProposalRecord(p *ProposalRecord) []byte {
+	return util.MustBytes(p)
+}
+
+func DecodeProposalRecord(data []byte) (*ProposalRecord, error) {
+	ret := new(ProposalRecord)
+	err := ret.Read(bytes.NewReader(data))
+	return ret, err
+}
+
+// DecodeProposalRecords decodes the result of the getProposals view
+func DecodeProposalRecords(d dict.Dict) ([]*ProposalRecord, error) {
+	arr := collections.NewArrayReadOnly(d, ParamProposals)
+	n, err := arr.Len()
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]*ProposalRecord, n)
+	for i := uint16(0); i < n; i++ {
+		data, err := arr.GetAt(i)
+		if err != nil {
+			return nil, err
+		}
+		if ret[i], err = DecodeProposalRecord(data); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+// GetProposal returns the proposal by ID or nil if it does not exist
+func GetProposal(state kv.KVStoreReader, id uint32) (*ProposalRecord, error) {
+	data := collections.NewMapReadOnly(state, VarProposals).MustGetAt(util.Uint32To4Bytes(id))
+	if data == nil {
+		return nil, nil
+	}
+	return DecodeProposalRecord(data)
+}
+
+// GetProposals returns all proposals, sorted by ID
+func GetProposals(state kv.KVStoreReader) ([]*ProposalRecord, error) {
+	ret := make([]*ProposalRecord, 0)
+	var err error
+	collections.NewMapReadOnly(state, VarProposals).MustIterate(func(_ []byte, value []byte) bool {
+		var p *ProposalRecord
+		if p, err = DecodeProposalRecord(value); err != nil {
+			return false
+		}
+		ret = append(ret, p)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].ID < ret[j].ID
+	})
+	return ret, nil
+}
+
+// storeProposal stores the proposal to the state
+func storeProposal(state kv.KVStore, p *ProposalRecord) {
+	collections.NewMap(state, VarProposals).MustSetAt(util.Uint32To4Bytes(p.ID), EncodeProposalRecord(p))
+}
+
+// votesName is the name of the map of members who voted for the proposal
+func votesName(id uint32) string {
+	return VarVotes + string(util.Uint32To4Bytes(id))
+}
+
+// GetMembers returns members of the governance with their weights
+func GetMembers(state kv.KVStoreReader) (map[coretypes.AgentID]int64, error) {
+	return DecodeMembers(collections.NewMapReadOnly(state, VarMembers))
+}
+
+// DecodeMembers decodes the map of members: agentID -> weight
+func DecodeMembers(members *collections.ImmutableMap) (map[coretypes.AgentID]int64, error) {
+	ret := make(map[coretypes.AgentID]int64)
+	var err error
+	members.MustIterate(func(elemKey []byte, value []byte) bool {
+		var agentID coretypes.AgentID
+		if agentID, err = coretypes.NewAgentIDFromBytes(elemKey); err != nil {
+			return false
+		}
+		var weight int64
+		if weight, _, err = codec.DecodeInt64(value); err != nil {
+			return false
+		}
+		ret[agentID] = weight
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// GetQuorum returns the total weight of votes needed to execute the proposal. 0 if members are not set
+func GetQuorum(state kv.KVStoreReader) int64 {
+	ret, _, err := codec.DecodeInt64(state.MustGet(VarQuorum))
+	if err != nil {
+		panic(err)
+	}
+	return ret
+}
@@ -0,0 +1,313 @@
+// 'nft' is a core contract on the chain. It keeps the registry of non-fungible tokens (NFTs).
+// Each NFT is the chain-native token with the unique color (the ID of the NFT) and the balance 1 in the
+// 'accounts' contract, so the owner of the NFT is the agent which holds it. The NFT has immutable metadata:
+// the hash of the blob in the 'blob' contract.
+// NFTs can't be withdrawn to L1 addresses, however they are carried to another chain by 'accounts.withdrawToChain'
+package nft
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/coretypes/assert"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/collections"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/kv/kvdecoder"
+	"github.com/iotaledger/wasp/packages/vm/core/accounts"
+	"github.com/iotaledger/wasp/packages/vm/core/blob"
+)
+
+// initialize is mandatory
+func initialize(ctx coretypes.Sandbox) (dict.Dict, error) {
+	ctx.Log().Debugf("nft.initialize.success hname = %s", Interface.Hname().String())
+	return nil, nil
+}
+
+// mint creates the new NFT. The caller becomes the creator of the NFT
+// Input:
+// - ParamMetadata hashing.HashValue hash of the blob with metadata. The blob must exist in the 'blob' contract
+// - ParamAgentID the account which receives the NFT. Optional. Defaults to the caller
+// Output:
+// - ParamID color, the ID of the NFT
+func mint(ctx coretypes.Sandbox) (dict.Dict, error) {
+	a := assert.NewAssert(ctx.Log())
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+
+	metadata := params.MustGetHashValue(ParamMetadata)
+	a.Require(blobExists(ctx, metadata), "nft.mint: blob %s not found", metadata)
+	target := params.MustGetAgentID(ParamAgentID, ctx.Caller())
+
+	chainID := ctx.ContractID().ChainID()
+	counter := nextNFTCounter(ctx.State())
+	n := &NFTRecord{
+		ID:       NFTID(chainID, counter),
+		Metadata: metadata,
+		Creator:  ctx.Caller(),
+		Origin:   chainID,
+		Counter:  counter,
+	}
+	storeNFT(ctx.State(), n)
+	_, err := ctx.Call(accounts.Interface.Hname(), coretypes.Hn(accounts.FuncMintNative), codec.MakeDict(map[string]interface{}{
+		accounts.ParamAgentID: target,
+		accounts.ParamColor:   n.ID,
+		accounts.ParamAmount:  1,
+	}), nil)
+	a.RequireNoError(err)
+	ctx.Event(fmt.Sprintf("[nft] minted %s to %s", n, target))
+
+	ret := dict.New()
+	ret.Set(ParamID, codec.EncodeColor(n.ID))
+	return ret, nil
+}
+
+// transfer moves the NFT to the target account on the chain. The caller must be the owner of the NFT
+// or be approved by the owner. Smart contracts can also move NFTs with the transfer of Sandbox.Call
+// Input:
+// - ParamID color, the ID of the NFT
+// - ParamTarget the target account
+// - ParamAgentID the owner of the NFT. Optional. Defaults to the caller
+func transfer(ctx coretypes.Sandbox) (dict.Dict, error) {
+	a := assert.NewAssert(ctx.Log())
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+
+	n := mustGetNFT(ctx, a, params.MustGetColor(ParamID))
+	target := params.MustGetAgentID(ParamTarget)
+	owner := params.MustGetAgentID(ParamAgentID, ctx.Caller())
+	if owner != ctx.Caller() {
+		approvedBy, spender, ok := GetApproval(ctx.State(), n.ID)
+		a.Require(ok && approvedBy == owner && spender == ctx.Caller(), "nft.transfer: not authorized")
+	}
+
+	_, err := ctx.Call(accounts.Interface.Hname(), coretypes.Hn(accounts.FuncMoveNative), codec.MakeDict(map[string]interface{}{
+		accounts.ParamAgentID: owner,
+		accounts.ParamTarget:  target,
+		accounts.ParamColor:   n.ID,
+		accounts.ParamAmount:  1,
+	}), nil)
+	a.RequireNoError(err)
+	collections.NewMap(ctx.State(), VarApprovals).MustDelAt(n.ID[:])
+
+	ctx.Event(fmt.Sprintf("[nft] transferred %s from %s to %s", n.ID, owner, target))
+	return nil, nil
+}
+
+// approve allows the spender to transfer the NFT of the caller. The new approval replaces the previous one.
+// The approval is revoked when the NFT is transferred
+// Input:
+// - ParamID color, the ID of the NFT
+// - ParamSpender the agent which is allowed to transfer the NFT. Optional. If not specified, the approval is revoked
+func approve(ctx coretypes.Sandbox) (dict.Dict, error) {
+	a := assert.NewAssert(ctx.Log())
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+
+	n := mustGetNFT(ctx, a, params.MustGetColor(ParamID))
+	a.Require(isOwner(ctx, a, n.ID, ctx.Caller()), "nft.approve: not authorized")
+
+	if !ctx.Params().MustHas(ParamSpender) {
+		collections.NewMap(ctx.State(), VarApprovals).MustDelAt(n.ID[:])
+		ctx.Event(fmt.Sprintf("[nft] %s revoked approval of %s", ctx.Caller(), n.ID))
+		return nil, nil
+	}
+	spender := params.MustGetAgentID(ParamSpender)
+	setApproval(ctx.State(), n.ID, ctx.Caller(), spender)
+	ctx.Event(fmt.Sprintf("[nft] %s approved %s to transfer %s", ctx.Caller(), spender, n.ID))
+	return nil, nil
+}
+
+// burn destroys the NFT in the account of the caller
+// Input:
+// - ParamID color, the ID of the NFT
+func burn(ctx coretypes.Sandbox) (dict.Dict, error) {
+	a := assert.NewAssert(ctx.Log())
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+
+	n := mustGetNFT(ctx, a, params.MustGetColor(ParamID))
+	_, err := ctx.Call(accounts.Interface.Hname(), coretypes.Hn(accounts.FuncBurnNative), codec.MakeDict(map[string]interface{}{
+		accounts.ParamAgentID: ctx.Caller(),
+		accounts.ParamColor:   n.ID,
+		accounts.ParamAmount:  1,
+	}), nil)
+	a.RequireNoError(err)
+	deleteNFT(ctx.State(), n.ID)
+
+	ctx.Event(fmt.Sprintf("[nft] burned %s by %s", n.ID, ctx.Caller()))
+	return nil, nil
+}
+
+// exportNFTs removes records of NFTs which are withdrawn to another chain.
+// Can only be called by the 'accounts' contract
+// Input:
+// - accounts.ParamChainID the chain NFTs are withdrawn to
+// - accounts.ParamNFTs array of IDs of NFTs
+// Output:
+// - accounts.ParamNFTs map of NFTRecord bytes by ID
+func exportNFTs(ctx coretypes.Sandbox) (dict.Dict, error) {
+	a := assert.NewAssert(ctx.Log())
+	requireCallerIsAccounts(ctx, a, "exportNFTs")
+
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+	target := params.MustGetChainID(accounts.ParamChainID)
+	ret := dict.New()
+	records := collections.NewMap(ret, accounts.ParamNFTs)
+	ids := collections.NewArrayReadOnly(ctx.Params(), accounts.ParamNFTs)
+	for i := uint16(0); i < ids.MustLen(); i++ {
+		id, _, err := codec.DecodeColor(ids.MustGetAt(i))
+		a.RequireNoError(err)
+		n := mustGetNFT(ctx, a, id)
+		records.MustSetAt(id[:], EncodeNFTRecord(n))
+		deleteNFT(ctx.State(), id)
+		setExported(ctx.State(), id, target)
+		ctx.Event(fmt.Sprintf("[nft] exported %s", id))
+	}
+	return ret, nil
+}
+
+// importNFTs stores records of NFTs which are withdrawn from another chain.
+// NFTs come from the chain which holds them, the chain of the caller of 'accounts.deposit'. The ID of each NFT
+// must be derived from its origin chain and its counter, so the record can't claim the NFT of another origin.
+// The NFT minted on this chain can only come back if it was exported from this chain,
+// possibly via other chains, because the origin doesn't learn where the NFT goes after the first hop.
+// Can only be called by the 'accounts' contract
+// Input:
+// - accounts.ParamChainID the chain NFTs come from
+// - accounts.ParamNFTs map of NFTRecord bytes by ID
+func importNFTs(ctx coretypes.Sandbox) (dict.Dict, error) {
+	a := assert.NewAssert(ctx.Log())
+	requireCallerIsAccounts(ctx, a, "importNFTs")
+
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+	source := params.MustGetChainID(accounts.ParamChainID)
+	collections.NewMapReadOnly(ctx.Params(), accounts.ParamNFTs).MustIterate(func(elemKey []byte, value []byte) bool {
+		n, err := DecodeNFTRecord(value)
+		a.RequireNoError(err)
+		a.Require(bytes.Equal(n.ID[:], elemKey), "nft.importNFTs: wrong record of %s", n.ID)
+		a.Require(n.Counter >= 0 && n.ID == NFTID(n.Origin, n.Counter),
+			"nft.importNFTs: %s is not the NFT #%d minted on %s", n.ID, n.Counter, n.Origin)
+		existing, err := GetNFT(ctx.State(), n.ID)
+		a.RequireNoError(err)
+		a.Require(existing == nil, "nft.importNFTs: %s already exists", n.ID)
+		if n.Origin == ctx.ContractID().ChainID() {
+			_, exported, err := getExported(ctx.State(), n.ID)
+			a.RequireNoError(err)
+			a.Require(exported && n.Counter < mintedNFTs(ctx.State()), "nft.importNFTs: %s was not exported from this chain", n.ID)
+			collections.NewMap(ctx.State(), VarExported).MustDelAt(n.ID[:])
+		}
+		storeNFT(ctx.State(), n)
+		ctx.Event(fmt.Sprintf("[nft] imported %s from %s", n, source))
+		return true
+	})
+	return nil, nil
+}
+
+// getNFT returns the NFT
+// Input:
+// - ParamID color, the ID of the NFT
+// Output:
+// - ParamNFT bytes of the NFTRecord
+func getNFT(ctx coretypes.SandboxView) (dict.Dict, error) {
+	a := assert.NewAssert(ctx.Log())
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+	id := params.MustGetColor(ParamID)
+
+	n, err := GetNFT(ctx.State(), id)
+	a.RequireNoError(err)
+	a.Require(n != nil, "nft.getNFT: NFT %s not found", id)
+
+	ret := dict.New()
+	ret.Set(ParamNFT, EncodeNFTRecord(n))
+	return ret, nil
+}
+
+// getNFTs returns all NFTs owned by the agent
+// Input:
+// - ParamAgentID the owner
+// Output:
+// - ParamNFTs array of NFTRecord bytes, sorted by the ID
+func getNFTs(ctx coretypes.SandboxView) (dict.Dict, error) {
+	a := assert.NewAssert(ctx.Log())
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+	agentID := params.MustGetAgentID(ParamAgentID)
+
+	res, err := ctx.Call(accounts.Interface.Hname(), coretypes.Hn(accounts.FuncBalance), codec.MakeDict(map[string]interface{}{
+		accounts.ParamAgentID: agentID,
+	}))
+	a.RequireNoError(err)
+	bals, err := accounts.DecodeBalances(res)
+	a.RequireNoError(err)
+
+	nfts := make([]*NFTRecord, 0)
+	for col, bal := range bals {
+		if bal != 1 {
+			continue
+		}
+		n, err := GetNFT(ctx.State(), col)
+		a.RequireNoError(err)
+		if n != nil {
+			nfts = append(nfts, n)
+		}
+	}
+	sort.Slice(nfts, func(i, j int) bool {
+		return bytes.Compare(nfts[i].ID[:], nfts[j].ID[:]) < 0
+	})
+
+	ret := dict.New()
+	arr := collections.NewArray(ret, ParamNFTs)
+	for _, n := range nfts {
+		arr.MustPush(EncodeNFTRecord(n))
+	}
+	return ret, nil
+}
+
+// getApproved returns the approval of the NFT
+// Input:
+// - ParamID color, the ID of the NFT
+// Output:
+// - ParamAgentID the owner which approved the spender. Empty if there is no approval
+// - ParamSpender the spender
+func getApproved(ctx coretypes.SandboxView) (dict.Dict, error) {
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+	id := params.MustGetColor(ParamID)
+
+	ret := dict.New()
+	owner, spender, ok := GetApproval(ctx.State(), id)
+	if ok {
+		ret.Set(ParamAgentID, codec.EncodeAgentID(owner))
+		ret.Set(ParamSpender, codec.EncodeAgentID(spender))
+	}
+	return ret, nil
+}
+
+func blobExists(ctx coretypes.Sandbox, hash hashing.HashValue) bool {
+	res, err := ctx.Call(blob.Interface.Hname(), coretypes.Hn(blob.FuncGetBlobInfo), codec.MakeDict(map[string]interface{}{
+		blob.ParamHash: hash,
+	}), nil)
+	return err == nil && len(res) > 0
+}
+
+func isOwner(ctx coretypes.Sandbox, a assert.Assert, id balance.Color, agentID coretypes.AgentID) bool {
+	res, err := ctx.Call(accounts.Interface.Hname(), coretypes.Hn(accounts.FuncBalance), codec.MakeDict(map[string]interface{}{
+		accounts.ParamAgentID: agentID,
+	}), nil)
+	a.RequireNoError(err)
+	bals, err := accounts.DecodeBalances(res)
+	a.RequireNoError(err)
+	return bals[id] == 1
+}
+
+func mustGetNFT(ctx coretypes.Sandbox, a assert.Assert, id balance.Color) *NFTRecord {
+	n, err := GetNFT(ctx.State(), id)
+	a.RequireNoError(err)
+	a.Require(n != nil, "nft: NFT %s not found", id)
+	return n
+}
+
+func requireCallerIsAccounts(ctx coretypes.Sandbox, a assert.Assert, fname string) {
+	caller := ctx.Caller()
+	a.Require(!caller.IsAddress() && caller.MustContractID() == accounts.Interface.ContractID(ctx.ContractID().ChainID()),
+		"nft.%s: not authorized", fname)
+}
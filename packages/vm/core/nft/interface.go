@@ -0,0 +1,62 @@
+package nft
+
+import (
+	"github.com/iotaledger/wasp/packages/coretypes/coreutil"
+	"github.com/iotaledger/wasp/packages/hashing"
+)
+
+const (
+	Name        = "nft"
+	description = "Non-Fungible Tokens Contract"
+)
+
+var (
+	Interface = &coreutil.ContractInterface{
+		Name:        Name,
+		Description: description,
+		ProgramHash: hashing.HashStrings(Name),
+	}
+)
+
+func init() {
+	Interface.WithFunctions(initialize, []coreutil.ContractFunctionInterface{
+		coreutil.Func(FuncMint, mint),
+		coreutil.Func(FuncTransfer, transfer),
+		coreutil.Func(FuncApprove, approve),
+		coreutil.Func(FuncBurn, burn),
+		coreutil.Func(FuncExportNFTs, exportNFTs),
+		coreutil.Func(FuncImportNFTs, importNFTs),
+		coreutil.ViewFunc(FuncGetNFT, getNFT),
+		coreutil.ViewFunc(FuncGetNFTs, getNFTs),
+		coreutil.ViewFunc(FuncGetApproved, getApproved),
+	})
+}
+
+const (
+	// state variables
+	VarNFTs      = "n"
+	VarApprovals = "a"
+	VarCounter   = "c"
+	// VarExported is the map of IDs of exported NFTs to the chain they were exported to
+	VarExported = "x"
+
+	// request parameters
+	ParamID       = "id"
+	ParamMetadata = "metadata"
+	ParamAgentID  = "agentID"
+	ParamTarget   = "target"
+	ParamSpender  = "spender"
+	ParamNFT      = "nft"
+	ParamNFTs     = "nfts"
+
+	// function names
+	FuncMint        = "mint"
+	FuncTransfer    = "transfer"
+	FuncApprove     = "approve"
+	FuncBurn        = "burn"
+	FuncExportNFTs  = "exportNFTs"
+	FuncImportNFTs  = "importNFTs"
+	FuncGetNFT      = "getNFT"
+	FuncGetNFTs     = "getNFTs"
+	FuncGetApproved = "getApproved"
+)
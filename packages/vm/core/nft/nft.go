@@ -0,0 +1,171 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package nft
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/collections"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/util"
+)
+
+// NFTRecord is the immutable metadata of the non-fungible token.
+// The NFT is kept in the 'accounts' contract as the chain-native token of the color ID with the balance 1.
+// The owner of the NFT is the agent which holds it in the account
+type NFTRecord struct {
+	ID balance.Color
+	// Metadata is the hash of the blob with metadata, stored in the 'blob' contract of the origin chain
+	Metadata hashing.HashValue
+	// Creator is the agent which minted the NFT
+	Creator coretypes.AgentID
+	// Origin is the chain where the NFT was minted
+	Origin coretypes.ChainID
+	// Counter is the number of NFTs minted on the origin chain before this one. The ID is derived from it
+	Counter int64
+}
+
+// NFTID is the ID of the n-th NFT minted on the chain
+func NFTID(chainID coretypes.ChainID, n int64) balance.Color {
+	h := hashing.HashData(chainID[:], Interface.Hname().Bytes(), util.Uint64To8Bytes(uint64(n)))
+	var ret balance.Color
+	copy(ret[:], h[:])
+	return ret
+}
+
+func (n *NFTRecord) String() string {
+	return fmt.Sprintf("NFT %s, metadata: %s, creator: %s, origin: %s", n.ID, n.Metadata, n.Creator, n.Origin)
+}
+
+func (n *NFTRecord) Write(w io.Writer) error {
+	if _, err := w.Write(n.ID[:]); err != nil {
+		return err
+	}
+	if _, err := w.Write(n.Metadata[:]); err != nil {
+		return err
+	}
+	if _, err := w.Write(n.Creator[:]); err != nil {
+		return err
+	}
+	if err := n.Origin.Write(w); err != nil {
+		return err
+	}
+	return util.WriteInt64(w, n.Counter)
+}
+
+func (n *NFTRecord) Read(r io.Reader) error {
+	if err := util.ReadColor(r, &n.ID); err != nil {
+		return err
+	}
+	if err := util.ReadHashValue(r, &n.Metadata); err != nil {
+		return err
+	}
+	if err := coretypes.ReadAgentID(r, &n.Creator); err != nil {
+		return err
+	}
+	if err := n.Origin.Read(r); err != nil {
+		return err
+	}
+	return util.ReadInt64(r, &n.Counter)
+}
+
+func EncodeNFTRecord(n *NFTRecord) []byte {
+	return util.MustBytes(n)
+}
+
+func DecodeNFTRecord(data []byte) (*NFTRecord, error) {
+	ret := new(NFTRecord)
+	err := ret.Read(bytes.NewReader(data))
+	return ret, err
+}
+
+// DecodeNFTRecords decodes the result of the getNFTs view
+func DecodeNFTRecords(d dict.Dict) ([]*NFTRecord, error) {
+	arr := collections.NewArrayReadOnly(d, ParamNFTs)
+	n, err := arr.Len()
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]*NFTRecord, n)
+	for i := uint16(0); i < n; i++ {
+		data, err := arr.GetAt(i)
+		if err != nil {
+			return nil, err
+		}
+		if ret[i], err = DecodeNFTRecord(data); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+// GetNFT returns the NFT by the ID, or nil if the NFT is not on the chain
+func GetNFT(state kv.KVStoreReader, id balance.Color) (*NFTRecord, error) {
+	data := collections.NewMapReadOnly(state, VarNFTs).MustGetAt(id[:])
+	if data == nil {
+		return nil, nil
+	}
+	return DecodeNFTRecord(data)
+}
+
+func storeNFT(state kv.KVStore, n *NFTRecord) {
+	collections.NewMap(state, VarNFTs).MustSetAt(n.ID[:], EncodeNFTRecord(n))
+}
+
+func deleteNFT(state kv.KVStore, id balance.Color) {
+	collections.NewMap(state, VarNFTs).MustDelAt(id[:])
+	collections.NewMap(state, VarApprovals).MustDelAt(id[:])
+}
+
+// setExported records the chain the NFT was exported to. Only that chain can bring the NFT back
+func setExported(state kv.KVStore, id balance.Color, target coretypes.ChainID) {
+	collections.NewMap(state, VarExported).MustSetAt(id[:], target[:])
+}
+
+// getExported returns the chain the NFT was exported to, if it was exported from this chain
+func getExported(state kv.KVStore, id balance.Color) (coretypes.ChainID, bool, error) {
+	m := collections.NewMap(state, VarExported)
+	data := m.MustGetAt(id[:])
+	if data == nil {
+		return coretypes.ChainID{}, false, nil
+	}
+	ret, err := coretypes.NewChainIDFromBytes(data)
+	return ret, err == nil, err
+}
+
+// nextNFTCounter increments the counter of minted NFTs and returns the counter of the new NFT
+func nextNFTCounter(state kv.KVStore) int64 {
+	n := mintedNFTs(state)
+	state.Set(VarCounter, codec.EncodeInt64(n+1))
+	return n
+}
+
+// mintedNFTs returns the number of NFTs minted on the chain
+func mintedNFTs(state kv.KVStoreReader) int64 {
+	n, _, _ := codec.DecodeInt64(state.MustGet(VarCounter))
+	return n
+}
+
+// GetApproval returns the owner which approved the spender to transfer the NFT, and the spender.
+// The approval is only valid while the NFT is in the account of the owner
+func GetApproval(state kv.KVStoreReader, id balance.Color) (owner, spender coretypes.AgentID, ok bool) {
+	data := collections.NewMapReadOnly(state, VarApprovals).MustGetAt(id[:])
+	if len(data) != 2*coretypes.AgentIDLength {
+		return
+	}
+	copy(owner[:], data[:coretypes.AgentIDLength])
+	copy(spender[:], data[coretypes.AgentIDLength:])
+	return owner, spender, true
+}
+
+func setApproval(state kv.KVStore, id balance.Color, owner, spender coretypes.AgentID) {
+	collections.NewMap(state, VarApprovals).MustSetAt(id[:], append(owner.Bytes(), spender.Bytes()...))
+}
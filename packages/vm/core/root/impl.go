@@ -1,13 +1,20 @@
 // 'root' a core contract on the chain. It is responsible for:
 // - initial setup of the chain during chain deployment
 // - maintaining of core parameters of the chain
-// - maintaining (setting, delegating) chain owner ID
+// - maintaining (setting, delegating) chain owner ID. The chain owner may be the 'governance' contract
 // - maintaining (granting, revoking) smart contract deployment rights
 // - deployment of smart contracts on the chain and maintenance of contract registry
+// - rotation of the chain to the new committee address
+// - upgrade of deployed smart contracts and maintenance of the upgrade history
+// - pausing, unpausing and removal of deployed smart contracts
+// - maintaining access control lists (ACLs) of requests to smart contracts and their entry points
 package root
 
 import (
+	"bytes"
 	"fmt"
+	"sort"
+
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
 	"github.com/iotaledger/wasp/packages/coretypes"
 	assert2 "github.com/iotaledger/wasp/packages/coretypes/assert"
@@ -18,6 +25,10 @@ import (
 	"github.com/iotaledger/wasp/packages/vm/core/accounts"
 	"github.com/iotaledger/wasp/packages/vm/core/blob"
 	"github.com/iotaledger/wasp/packages/vm/core/eventlog"
+	"github.com/iotaledger/wasp/packages/vm/core/governance"
+	"github.com/iotaledger/wasp/packages/vm/core/nft"
+	"github.com/iotaledger/wasp/packages/vm/core/scheduler"
+	"github.com/iotaledger/wasp/packages/vm/core/tokens"
 )
 
 // initialize handles constructor, the "init" request. This is the first call to the chain
@@ -25,7 +36,7 @@ import (
 // - stores chain ID and chain description in the state
 // - sets state ownership to the caller
 // - creates record in the registry for the 'root' itself
-// - deploys other core contracts: 'accounts', 'blob', 'eventlog' by creating records in the registry and calling constructors
+// - deploys other core contracts: 'accounts', 'blob', 'eventlog', 'scheduler', 'tokens', 'nft', 'governance' by creating records in the registry and calling constructors
 // Input:
 // - ParamChainID coretypes.ChainID. ID of the chain. Cannot be changed
 // - ParamChainColor balance.Color
@@ -72,6 +83,26 @@ func initialize(ctx coretypes.Sandbox) (dict.Dict, error) {
 	err = storeAndInitContract(ctx, &rec, nil)
 	a.Require(err == nil, "root.init.fail: %v", err)
 
+	// deploy scheduler
+	rec = NewContractRecord(scheduler.Interface, ctx.Caller())
+	err = storeAndInitContract(ctx, &rec, nil)
+	a.Require(err == nil, "root.init.fail: %v", err)
+
+	// deploy tokens
+	rec = NewContractRecord(tokens.Interface, ctx.Caller())
+	err = storeAndInitContract(ctx, &rec, nil)
+	a.Require(err == nil, "root.init.fail: %v", err)
+
+	// deploy nft
+	rec = NewContractRecord(nft.Interface, ctx.Caller())
+	err = storeAndInitContract(ctx, &rec, nil)
+	a.Require(err == nil, "root.init.fail: %v", err)
+
+	// deploy governance
+	rec = NewContractRecord(governance.Interface, ctx.Caller())
+	err = storeAndInitContract(ctx, &rec, nil)
+	a.Require(err == nil, "root.init.fail: %v", err)
+
 	state.Set(VarStateInitialized, []byte{0xFF})
 	state.Set(VarChainID, codec.EncodeChainID(chainID))
 	state.Set(VarChainColor, codec.EncodeColor(chainColor))
@@ -85,6 +116,10 @@ func initialize(ctx coretypes.Sandbox) (dict.Dict, error) {
 	ctx.Log().Debugf("root.initialize.deployed: '%s', hname = %s", blob.Interface.Name, blob.Interface.Hname().String())
 	ctx.Log().Debugf("root.initialize.deployed: '%s', hname = %s", accounts.Interface.Name, accounts.Interface.Hname().String())
 	ctx.Log().Debugf("root.initialize.deployed: '%s', hname = %s", eventlog.Interface.Name, eventlog.Interface.Hname().String())
+	ctx.Log().Debugf("root.initialize.deployed: '%s', hname = %s", scheduler.Interface.Name, scheduler.Interface.Hname().String())
+	ctx.Log().Debugf("root.initialize.deployed: '%s', hname = %s", tokens.Interface.Name, tokens.Interface.Hname().String())
+	ctx.Log().Debugf("root.initialize.deployed: '%s', hname = %s", nft.Interface.Name, nft.Interface.Hname().String())
+	ctx.Log().Debugf("root.initialize.deployed: '%s', hname = %s", governance.Interface.Name, governance.Interface.Hname().String())
 	ctx.Log().Debugf("root.initialize.success")
 	return nil, nil
 }
@@ -175,6 +210,7 @@ func getChainInfo(ctx coretypes.SandboxView) (dict.Dict, error) {
 	ret.Set(VarFeeColor, codec.EncodeColor(info.FeeColor))
 	ret.Set(VarDefaultOwnerFee, codec.EncodeInt64(info.DefaultOwnerFee))
 	ret.Set(VarDefaultValidatorFee, codec.EncodeInt64(info.DefaultValidatorFee))
+	ret.Set(VarGasPrice, codec.EncodeInt64(info.GasPrice))
 
 	src := collections.NewMapReadOnly(ctx.State(), VarContractRegistry)
 	dst := collections.NewMap(ret, VarContractRegistry)
@@ -228,6 +264,7 @@ func claimChainOwnership(ctx coretypes.Sandbox) (dict.Dict, error) {
 // Output:
 // - ParamFeeColor balance.Color color of tokens accepted for fees
 // - ParamValidatorFee int64 minimum fee for contract
+// - ParamGasPrice int64 fee tokens per coretypes.GasPriceUnit of gas
 // Note: return default chain values if contract doesn't exist
 func getFeeInfo(ctx coretypes.SandboxView) (dict.Dict, error) {
 	params := kvdecoder.New(ctx.Params())
@@ -240,6 +277,7 @@ func getFeeInfo(ctx coretypes.SandboxView) (dict.Dict, error) {
 	ret.Set(ParamFeeColor, codec.EncodeColor(feeColor))
 	ret.Set(ParamOwnerFee, codec.EncodeInt64(ownerFee))
 	ret.Set(ParamValidatorFee, codec.EncodeInt64(validatorFee))
+	ret.Set(ParamGasPrice, codec.EncodeInt64(GetGasPrice(ctx.State())))
 	return ret, nil
 }
 
@@ -247,6 +285,7 @@ func getFeeInfo(ctx coretypes.SandboxView) (dict.Dict, error) {
 // Input:
 // - ParamOwnerFee int64 non-negative value of the owner fee. May be skipped, then it is not set
 // - ParamValidatorFee int64 non-negative value of the contract fee. May be skipped, then it is not set
+// - ParamGasPrice int64 non-negative number of fee tokens per coretypes.GasPriceUnit of gas. May be skipped, then it is not set
 func setDefaultFee(ctx coretypes.Sandbox) (dict.Dict, error) {
 	a := assert2.NewAssert(ctx.Log())
 	a.Require(CheckAuthorizationByChainOwner(ctx.State(), ctx.Caller()), "root.setDefaultFee: not authorized")
@@ -257,8 +296,10 @@ func setDefaultFee(ctx coretypes.Sandbox) (dict.Dict, error) {
 	ownerFeeSet := ownerFee >= 0
 	validatorFee := params.MustGetInt64(ParamValidatorFee, -1)
 	validatorFeeSet := validatorFee >= 0
+	gasPrice := params.MustGetInt64(ParamGasPrice, -1)
+	gasPriceSet := gasPrice >= 0
 
-	a.Require(ownerFeeSet || validatorFeeSet, "root.setDefaultFee: wrong parameters")
+	a.Require(ownerFeeSet || validatorFeeSet || gasPriceSet, "root.setDefaultFee: wrong parameters")
 
 	if ownerFeeSet {
 		if ownerFee > 0 {
@@ -274,6 +315,13 @@ func setDefaultFee(ctx coretypes.Sandbox) (dict.Dict, error) {
 			ctx.State().Del(VarDefaultValidatorFee)
 		}
 	}
+	if gasPriceSet {
+		if gasPrice > 0 {
+			ctx.State().Set(VarGasPrice, codec.EncodeInt64(gasPrice))
+		} else {
+			ctx.State().Del(VarGasPrice)
+		}
+	}
 	return nil, nil
 }
 
@@ -339,3 +387,332 @@ func revokeDeployPermission(ctx coretypes.Sandbox) (dict.Dict, error) {
 	ctx.Event(fmt.Sprintf("[revoke deploy permission] from agentID: %s", deployer))
 	return nil, nil
 }
+
+// rotateCommittee nominates the new committee of the chain by its (DKG generated) address.
+// The chain token and all balances of the chain are moved to the new address
+// by the state transaction of the current block. The chain ID does not change
+// Input:
+//  - ParamChainAddress address.Address
+func rotateCommittee(ctx coretypes.Sandbox) (dict.Dict, error) {
+	a := assert2.NewAssert(ctx.Log())
+	a.Require(CheckAuthorizationByChainOwner(ctx.State(), ctx.Caller()), "root.rotateCommittee: not authorized")
+
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+	newAddress := params.MustGetAddress(ParamChainAddress)
+
+	stateDecoder := kvdecoder.New(ctx.State(), ctx.Log())
+	oldAddress := stateDecoder.MustGetAddress(VarChainAddress)
+	a.Require(newAddress != oldAddress, "root.rotateCommittee: the committee address is not changed")
+
+	ctx.State().Set(VarChainAddress, codec.EncodeAddress(newAddress))
+	ctx.Event(fmt.Sprintf("[rotate committee] %s --> %s", oldAddress, newAddress))
+	return nil, nil
+}
+
+// upgradeContract replaces the program of the deployed contract, keeping its hname, state and balances.
+// If the new program has the 'migrate' entry point, it is called with old and new versions of the contract.
+// If the call to 'migrate' fails, the upgrade is reverted
+// Only the creator of the contract or the chain owner can upgrade it. Core contracts can't be upgraded.
+// When the governance is enabled, only the chain owner, i.e. the executed proposal, can upgrade the contract
+// Input:
+//  - ParamHname coretypes.Hname of the contract
+//  - ParamProgramHash HashValue of the new program
+//  - all other parameters are passed to the 'migrate' entry point
+// Output:
+//  - ParamOldProgramHash HashValue
+//  - ParamOldVersion int64
+//  - ParamNewVersion int64
+func upgradeContract(ctx coretypes.Sandbox) (dict.Dict, error) {
+	ctx.Log().Debugf("root.upgradeContract.begin")
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+	a := assert2.NewAssert(ctx.Log())
+
+	hname := params.MustGetHname(ParamHname)
+	progHash := params.MustGetHashValue(ParamProgramHash)
+
+	a.Require(!isCoreContract(hname), "root.upgradeContract: core contract can't be upgraded")
+	rec, err := FindContract(ctx.State(), hname)
+	a.Require(err == nil, "root.upgradeContract: %v", err)
+	governed := ctx.ChainOwnerID() == coretypes.NewAgentIDFromContractID(governance.Interface.ContractID(ctx.ContractID().ChainID()))
+	a.Require(ctx.Caller() == ctx.ChainOwnerID() || (!governed && ctx.Caller() == rec.Creator),
+		"root.upgradeContract: not authorized: %s", ctx.Caller())
+	a.Require(progHash != rec.ProgramHash, "root.upgradeContract: program is not changed")
+
+	// calls to loads VM from binary to check if it loads successfully
+	err = ctx.DeployContract(progHash, "", "", nil)
+	a.Require(err == nil, "root.upgradeContract.fail: %v", err)
+
+	history := collections.NewArray(ctx.State(), upgradeHistoryName(hname))
+	oldVersion := int64(history.MustLen())
+	upgrade := &UpgradeRecord{
+		Version:        oldVersion + 1,
+		OldProgramHash: rec.ProgramHash,
+		NewProgramHash: progHash,
+		Caller:         ctx.Caller(),
+		Timestamp:      ctx.GetTimestamp(),
+	}
+	oldRecBin := EncodeContractRecord(rec)
+	rec.ProgramHash = progHash
+	contractRegistry := collections.NewMap(ctx.State(), VarContractRegistry)
+	contractRegistry.MustSetAt(hname.Bytes(), EncodeContractRecord(rec))
+
+	// pass to migrate function all params not consumed so far
+	migrateParams := dict.New()
+	for key, value := range ctx.Params() {
+		if key != ParamProgramHash && key != ParamHname {
+			migrateParams.Set(key, value)
+		}
+	}
+	migrateParams.Set(ParamOldProgramHash, codec.EncodeHashValue(&upgrade.OldProgramHash))
+	migrateParams.Set(ParamOldVersion, codec.EncodeInt64(oldVersion))
+	migrateParams.Set(ParamNewVersion, codec.EncodeInt64(upgrade.Version))
+	_, err = ctx.Call(hname, coretypes.EntryPointMigrate, migrateParams, nil)
+	if err != nil && err != coretypes.ErrEntryPointNotFound {
+		// call to 'migrate' failed: restore the record
+		contractRegistry.MustSetAt(hname.Bytes(), oldRecBin)
+		return nil, fmt.Errorf("root.upgradeContract.fail: contract '%s'/%s: calling 'migrate': %v", rec.Name, hname, err)
+	}
+	history.MustPush(EncodeUpgradeRecord(upgrade))
+
+	ctx.Event(fmt.Sprintf("[upgrade] name: %s hname: %s, version: %d, progHash: %s --> %s",
+		rec.Name, hname, upgrade.Version, upgrade.OldProgramHash.String(), progHash.String()))
+	ret := dict.New()
+	ret.Set(ParamOldProgramHash, codec.EncodeHashValue(&upgrade.OldProgramHash))
+	ret.Set(ParamOldVersion, codec.EncodeInt64(oldVersion))
+	ret.Set(ParamNewVersion, codec.EncodeInt64(upgrade.Version))
+	return ret, nil
+}
+
+// getUpgradeHistory view returns upgrade history of the contract
+// Input:
+//  - ParamHname coretypes.Hname
+// Output:
+//  - ParamData: array of encoded UpgradeRecord, oldest first
+func getUpgradeHistory(ctx coretypes.SandboxView) (dict.Dict, error) {
+	params := kvdecoder.New(ctx.Params())
+	hname, err := params.GetHname(ParamHname)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = FindContract(ctx.State(), hname); err != nil {
+		return nil, err
+	}
+	ret := dict.New()
+	history := collections.NewArrayReadOnly(ctx.State(), upgradeHistoryName(hname))
+	collections.NewArray(ret, ParamData).MustExtend(history)
+	return ret, nil
+}
+
+// pauseContract stops processing of requests to the contract. Requests to the paused contract are refunded
+// to the sender and no fees are charged. Only the chain owner can pause the contract
+// Input:
+//  - ParamHname coretypes.Hname of the contract
+func pauseContract(ctx coretypes.Sandbox) (dict.Dict, error) {
+	return setContractPaused(ctx, true)
+}
+
+// unpauseContract resumes processing of requests to the paused contract. Only the chain owner can unpause the contract
+// Input:
+//  - ParamHname coretypes.Hname of the contract
+func unpauseContract(ctx coretypes.Sandbox) (dict.Dict, error) {
+	return setContractPaused(ctx, false)
+}
+
+// removeContract deletes the contract from the registry. All funds on the account of the contract
+// are moved to the target account. The state of the contract is not accessible anymore.
+// The state and the upgrade history of the contract are kept, so the name of the removed contract
+// can't be reused for another contract.
+// Only the chain owner can remove the contract. Core contracts can't be removed
+// Input:
+//  - ParamHname coretypes.Hname of the contract
+//  - ParamSweepTo coretypes.AgentID target account for the funds of the contract. Defaults to the chain owner
+func removeContract(ctx coretypes.Sandbox) (dict.Dict, error) {
+	a := assert2.NewAssert(ctx.Log())
+	a.Require(CheckAuthorizationByChainOwner(ctx.State(), ctx.Caller()), "root.removeContract: not authorized")
+
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+	hname := params.MustGetHname(ParamHname)
+	sweepTo := params.MustGetAgentID(ParamSweepTo, ctx.ChainOwnerID())
+
+	a.Require(!isCoreContract(hname), "root.removeContract: core contract can't be removed")
+	rec, err := FindContract(ctx.State(), hname)
+	a.Require(err == nil, "root.removeContract: %v", err)
+
+	contractAgentID := coretypes.NewAgentIDFromContractID(coretypes.NewContractID(ctx.ContractID().ChainID(), hname))
+	_, err = ctx.Call(accounts.Interface.Hname(), coretypes.Hn(accounts.FuncSweep), codec.MakeDict(map[string]interface{}{
+		accounts.ParamAgentID: contractAgentID,
+		accounts.ParamTarget:  sweepTo,
+	}), nil)
+	a.Require(err == nil, "root.removeContract: %v", err)
+
+	collections.NewMap(ctx.State(), VarContractRegistry).MustDelAt(hname.Bytes())
+	collections.NewMap(ctx.State(), VarRemovedContracts).MustSetAt(hname.Bytes(), []byte{0xFF})
+	ctx.Event(fmt.Sprintf("[remove] name: %s hname: %s, funds swept to: %s", rec.Name, hname, sweepTo))
+	return nil, nil
+}
+
+// setACL creates the access control list of the target or changes its mode. Members of the ACL are kept.
+// The ACL is checked by the VM for each request to the target, before the entry point is called.
+// Requests of the chain owner and calls between contracts are not checked.
+// The target doesn't have to exist. Only the chain owner can set the ACL
+// Input:
+//  - ParamHname coretypes.Hname of the contract. Defaults to 0: all contracts of the chain
+//  - ParamEntryPoint coretypes.Hname of the entry point. Defaults to 0: all entry points of the contract
+//  - ParamACLMode string ACLModeAllow or ACLModeDeny
+func setACL(ctx coretypes.Sandbox) (dict.Dict, error) {
+	a := assert2.NewAssert(ctx.Log())
+	a.Require(CheckAuthorizationByChainOwner(ctx.State(), ctx.Caller()), "root.setACL: not authorized")
+
+	key := mustGetACLKeyFromParams(ctx.Params(), ctx.Log())
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+	mode := params.MustGetString(ParamACLMode)
+	a.Require(mode == ACLModeAllow || mode == ACLModeDeny, "root.setACL: wrong mode '%s'", mode)
+
+	collections.NewMap(ctx.State(), VarACLs).MustSetAt(key, codec.EncodeString(mode))
+	ctx.Event(fmt.Sprintf("[set acl] target: %s, mode: %s", aclTargetString(key), mode))
+	return nil, nil
+}
+
+// removeACL deletes the access control list of the target together with its members
+// Only the chain owner can remove the ACL
+// Input:
+//  - ParamHname coretypes.Hname of the contract. Defaults to 0: all contracts of the chain
+//  - ParamEntryPoint coretypes.Hname of the entry point. Defaults to 0: all entry points of the contract
+func removeACL(ctx coretypes.Sandbox) (dict.Dict, error) {
+	a := assert2.NewAssert(ctx.Log())
+	a.Require(CheckAuthorizationByChainOwner(ctx.State(), ctx.Caller()), "root.removeACL: not authorized")
+
+	key := mustGetACLKeyFromParams(ctx.Params(), ctx.Log())
+	acls := collections.NewMap(ctx.State(), VarACLs)
+	a.Require(acls.MustHasAt(key), "root.removeACL: ACL not found")
+
+	members := collections.NewMap(ctx.State(), aclMembersName(key))
+	keys := make([][]byte, 0)
+	members.MustIterateKeys(func(elemKey []byte) bool {
+		keys = append(keys, elemKey)
+		return true
+	})
+	for _, k := range keys {
+		members.MustDelAt(k)
+	}
+	acls.MustDelAt(key)
+	ctx.Event(fmt.Sprintf("[remove acl] target: %s", aclTargetString(key)))
+	return nil, nil
+}
+
+// addToACL adds the agentID or the role to the access control list of the target
+// Only the chain owner can change the ACL
+// Input:
+//  - ParamHname coretypes.Hname of the contract. Defaults to 0: all contracts of the chain
+//  - ParamEntryPoint coretypes.Hname of the entry point. Defaults to 0: all entry points of the contract
+//  - ParamRole string the role. If not specified, ParamAgentID is used
+//  - ParamAgentID coretypes.AgentID
+func addToACL(ctx coretypes.Sandbox) (dict.Dict, error) {
+	a := assert2.NewAssert(ctx.Log())
+	a.Require(CheckAuthorizationByChainOwner(ctx.State(), ctx.Caller()), "root.addToACL: not authorized")
+
+	key := mustGetACLKeyFromParams(ctx.Params(), ctx.Log())
+	a.Require(collections.NewMap(ctx.State(), VarACLs).MustHasAt(key), "root.addToACL: ACL not found")
+	member := mustGetACLMemberFromParams(ctx.Params(), ctx.Log())
+
+	collections.NewMap(ctx.State(), aclMembersName(key)).MustSetAt(member, []byte{0xFF})
+	ctx.Event(fmt.Sprintf("[add to acl] target: %s, member: %s", aclTargetString(key), aclMemberString(member)))
+	return nil, nil
+}
+
+// removeFromACL removes the agentID or the role from the access control list of the target
+// Only the chain owner can change the ACL
+// Input:
+//  - ParamHname coretypes.Hname of the contract. Defaults to 0: all contracts of the chain
+//  - ParamEntryPoint coretypes.Hname of the entry point. Defaults to 0: all entry points of the contract
+//  - ParamRole string the role. If not specified, ParamAgentID is used
+//  - ParamAgentID coretypes.AgentID
+func removeFromACL(ctx coretypes.Sandbox) (dict.Dict, error) {
+	a := assert2.NewAssert(ctx.Log())
+	a.Require(CheckAuthorizationByChainOwner(ctx.State(), ctx.Caller()), "root.removeFromACL: not authorized")
+
+	key := mustGetACLKeyFromParams(ctx.Params(), ctx.Log())
+	member := mustGetACLMemberFromParams(ctx.Params(), ctx.Log())
+	members := collections.NewMap(ctx.State(), aclMembersName(key))
+	a.Require(members.MustHasAt(member), "root.removeFromACL: not a member of the ACL")
+
+	members.MustDelAt(member)
+	ctx.Event(fmt.Sprintf("[remove from acl] target: %s, member: %s", aclTargetString(key), aclMemberString(member)))
+	return nil, nil
+}
+
+// grantRole grants the role to the agentID. Only the chain owner can grant roles
+// Input:
+//  - ParamRole string the role: up to 32 letters, digits, '_' or '-'
+//  - ParamAgentID coretypes.AgentID
+func grantRole(ctx coretypes.Sandbox) (dict.Dict, error) {
+	return setRole(ctx, true)
+}
+
+// revokeRole revokes the role from the agentID. Only the chain owner can revoke roles
+// Input:
+//  - ParamRole string the role
+//  - ParamAgentID coretypes.AgentID
+func revokeRole(ctx coretypes.Sandbox) (dict.Dict, error) {
+	return setRole(ctx, false)
+}
+
+// getACL view returns the access control list of the target
+// Input:
+//  - ParamHname coretypes.Hname of the contract. Defaults to 0: all contracts of the chain
+//  - ParamEntryPoint coretypes.Hname of the entry point. Defaults to 0: all entry points of the contract
+// Output:
+//  - ParamACLMode string. Not present if the target has no ACL
+//  - ParamAgentIDs array of agentIDs listed in the ACL
+//  - ParamRoles array of roles listed in the ACL
+func getACL(ctx coretypes.SandboxView) (dict.Dict, error) {
+	key := mustGetACLKeyFromParams(ctx.Params(), ctx.Log())
+	ret := dict.New()
+	mode := collections.NewMapReadOnly(ctx.State(), VarACLs).MustGetAt(key)
+	if mode == nil {
+		return ret, nil
+	}
+	ret.Set(ParamACLMode, mode)
+
+	agentIDs := make([][]byte, 0)
+	roles := make([][]byte, 0)
+	collections.NewMapReadOnly(ctx.State(), aclMembersName(key)).MustIterateKeys(func(elemKey []byte) bool {
+		switch elemKey[0] {
+		case aclMemberAgentID:
+			agentIDs = append(agentIDs, elemKey[1:])
+		case aclMemberRole:
+			roles = append(roles, elemKey[1:])
+		}
+		return true
+	})
+	for name, lst := range map[string][][]byte{ParamAgentIDs: agentIDs, ParamRoles: roles} {
+		sort.Slice(lst, func(i, j int) bool { return bytes.Compare(lst[i], lst[j]) < 0 })
+		arr := collections.NewArray(ret, name)
+		for _, data := range lst {
+			arr.MustPush(data)
+		}
+	}
+	return ret, nil
+}
+
+// checkAccess view checks if the agentID is allowed to post requests to the entry point of the contract
+// Input:
+//  - ParamHname coretypes.Hname of the contract
+//  - ParamEntryPoint coretypes.Hname of the entry point
+//  - ParamAgentID coretypes.AgentID
+// Output:
+//  - ParamAllowed int64: 1 if allowed, 0 otherwise
+func checkAccess(ctx coretypes.SandboxView) (dict.Dict, error) {
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+	hname := params.MustGetHname(ParamHname)
+	entryPoint := params.MustGetHname(ParamEntryPoint)
+	agentID := params.MustGetAgentID(ParamAgentID)
+
+	var allowed int64
+	if IsAccessAllowed(ctx.State(), hname, entryPoint, agentID) {
+		allowed = 1
+	}
+	ret := dict.New()
+	ret.Set(ParamAllowed, codec.EncodeInt64(allowed))
+	return ret, nil
+}
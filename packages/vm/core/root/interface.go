@@ -39,6 +39,20 @@ func init() {
 		coreutil.Func(FuncSetContractFee, setContractFee),
 		coreutil.Func(FuncGrantDeploy, grantDeployPermission),
 		coreutil.Func(FuncRevokeDeploy, revokeDeployPermission),
+		coreutil.Func(FuncRotateCommittee, rotateCommittee),
+		coreutil.Func(FuncUpgradeContract, upgradeContract),
+		coreutil.Func(FuncPauseContract, pauseContract),
+		coreutil.Func(FuncUnpauseContract, unpauseContract),
+		coreutil.Func(FuncRemoveContract, removeContract),
+		coreutil.ViewFunc(FuncGetUpgradeHistory, getUpgradeHistory),
+		coreutil.Func(FuncSetACL, setACL),
+		coreutil.Func(FuncRemoveACL, removeACL),
+		coreutil.Func(FuncAddToACL, addToACL),
+		coreutil.Func(FuncRemoveFromACL, removeFromACL),
+		coreutil.Func(FuncGrantRole, grantRole),
+		coreutil.Func(FuncRevokeRole, revokeRole),
+		coreutil.ViewFunc(FuncGetACL, getACL),
+		coreutil.ViewFunc(FuncCheckAccess, checkAccess),
 	})
 }
 
@@ -52,10 +66,16 @@ const (
 	VarFeeColor              = "f"
 	VarDefaultOwnerFee       = "do"
 	VarDefaultValidatorFee   = "dv"
+	VarGasPrice              = "gp"
 	VarChainOwnerIDDelegated = "n"
 	VarContractRegistry      = "r"
 	VarDescription           = "d"
 	VarDeployPermissions     = "dep"
+	VarUpgradeHistory        = "uh"
+	VarRemovedContracts      = "rm"
+	VarACLs                  = "acl"
+	VarACLMembers            = "aclm"
+	VarRoles                 = "rl"
 )
 
 // param variables
@@ -72,7 +92,20 @@ const (
 	ParamFeeColor     = "$$feecolor$$"
 	ParamOwnerFee     = "$$ownerfee$$"
 	ParamValidatorFee = "$$validatorfee$$"
+	ParamGasPrice     = "$$gasprice$$"
 	ParamDeployer     = "$$deployer$$"
+	ParamSweepTo      = "$$sweepto$$"
+	ParamEntryPoint   = "$$entrypoint$$"
+	ParamACLMode      = "$$aclmode$$"
+	ParamAgentID      = "$$agentid$$"
+	ParamRole         = "$$role$$"
+	ParamAgentIDs     = "$$agentids$$"
+	ParamRoles        = "$$roles$$"
+	ParamAllowed      = "$$allowed$$"
+	// parameters of the 'migrate' entry point of the upgraded contract
+	ParamOldProgramHash = "$$oldproghash$$"
+	ParamOldVersion     = "$$oldversion$$"
+	ParamNewVersion     = "$$newversion$$"
 )
 
 // function names
@@ -87,6 +120,28 @@ const (
 	FuncSetContractFee         = "setContractFee"
 	FuncGrantDeploy            = "grantDeployPermission"
 	FuncRevokeDeploy           = "revokeDeployPermission"
+	FuncRotateCommittee        = "rotateCommittee"
+	FuncUpgradeContract        = "upgradeContract"
+	FuncGetUpgradeHistory      = "getUpgradeHistory"
+	FuncPauseContract          = "pauseContract"
+	FuncUnpauseContract        = "unpauseContract"
+	FuncRemoveContract         = "removeContract"
+	FuncSetACL                 = "setACL"
+	FuncRemoveACL              = "removeACL"
+	FuncAddToACL               = "addToACL"
+	FuncRemoveFromACL          = "removeFromACL"
+	FuncGrantRole              = "grantRole"
+	FuncRevokeRole             = "revokeRole"
+	FuncGetACL                 = "getACL"
+	FuncCheckAccess            = "checkAccess"
+)
+
+// modes of the access control list
+const (
+	// ACLModeAllow only members of the ACL are allowed to post requests to the target
+	ACLModeAllow = "allow"
+	// ACLModeDeny members of the ACL are not allowed to post requests to the target
+	ACLModeDeny = "deny"
 )
 
 // ContractRecord is a structure which contains metadata of the deployed contract instance
@@ -110,6 +165,32 @@ type ContractRecord struct {
 	// The agentID of the entity which deployed the instance. It can be interpreted as
 	// an priviledged user of the instance, however it is up to the smart contract.
 	Creator coretypes.AgentID
+	// Paused contract does not process requests. Requests to the paused contract are refunded to the sender
+	Paused bool
+}
+
+// UpgradeRecord is an entry in the upgrade history of the contract
+type UpgradeRecord struct {
+	// Version of the contract after the upgrade. The version of the deployed contract is 0
+	Version int64
+	// ProgramHash of the contract before the upgrade
+	OldProgramHash hashing.HashValue
+	// ProgramHash of the contract after the upgrade
+	NewProgramHash hashing.HashValue
+	// The agentID which upgraded the contract
+	Caller coretypes.AgentID
+	// Timestamp of the upgrade
+	Timestamp int64
+}
+
+// ACL is an API structure which contains the access control list of the target (contract and entry point)
+type ACL struct {
+	// ACLModeAllow or ACLModeDeny. Empty if the target has no ACL
+	Mode string
+	// AgentIDs listed in the ACL
+	AgentIDs []coretypes.AgentID
+	// Roles listed in the ACL. The ACL applies to all agentIDs which have the role
+	Roles []string
 }
 
 // ChainInfo is an API structure which contains main properties of the chain in on place
@@ -122,6 +203,7 @@ type ChainInfo struct {
 	FeeColor            balance.Color
 	DefaultOwnerFee     int64
 	DefaultValidatorFee int64
+	GasPrice            int64
 }
 
 func (p *ContractRecord) Hname() coretypes.Hname {
@@ -148,6 +230,9 @@ func (p *ContractRecord) Write(w io.Writer) error {
 	if _, err := w.Write(p.Creator[:]); err != nil {
 		return err
 	}
+	if err := util.WriteBoolByte(w, p.Paused); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -171,6 +256,10 @@ func (p *ContractRecord) Read(r io.Reader) error {
 	if err := coretypes.ReadAgentID(r, &p.Creator); err != nil {
 		return err
 	}
+	// records stored before pausing was introduced don't have the flag
+	if err := util.ReadBoolByte(r, &p.Paused); err != nil && err != io.EOF {
+		return err
+	}
 	return nil
 }
 
@@ -197,3 +286,45 @@ func NewContractRecord(itf *coreutil.ContractInterface, creator coretypes.AgentI
 func (p *ContractRecord) HasCreator() bool {
 	return p.Creator != coretypes.AgentID{}
 }
+
+func (u *UpgradeRecord) Write(w io.Writer) error {
+	if err := util.WriteInt64(w, u.Version); err != nil {
+		return err
+	}
+	if _, err := w.Write(u.OldProgramHash[:]); err != nil {
+		return err
+	}
+	if _, err := w.Write(u.NewProgramHash[:]); err != nil {
+		return err
+	}
+	if _, err := w.Write(u.Caller[:]); err != nil {
+		return err
+	}
+	return util.WriteInt64(w, u.Timestamp)
+}
+
+func (u *UpgradeRecord) Read(r io.Reader) error {
+	if err := util.ReadInt64(r, &u.Version); err != nil {
+		return err
+	}
+	if err := util.ReadHashValue(r, &u.OldProgramHash); err != nil {
+		return err
+	}
+	if err := util.ReadHashValue(r, &u.NewProgramHash); err != nil {
+		return err
+	}
+	if err := coretypes.ReadAgentID(r, &u.Caller); err != nil {
+		return err
+	}
+	return util.ReadInt64(r, &u.Timestamp)
+}
+
+func EncodeUpgradeRecord(u *UpgradeRecord) []byte {
+	return util.MustBytes(u)
+}
+
+func DecodeUpgradeRecord(data []byte) (*UpgradeRecord, error) {
+	ret := new(UpgradeRecord)
+	err := ret.Read(bytes.NewReader(data))
+	return ret, err
+}
@@ -4,11 +4,19 @@ import (
 	"fmt"
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
 	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/coretypes/assert"
 	"github.com/iotaledger/wasp/packages/kv"
 	"github.com/iotaledger/wasp/packages/kv/codec"
 	"github.com/iotaledger/wasp/packages/kv/collections"
 	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/packages/kv/kvdecoder"
+	"github.com/iotaledger/wasp/packages/vm/core/accounts"
+	"github.com/iotaledger/wasp/packages/vm/core/blob"
+	"github.com/iotaledger/wasp/packages/vm/core/eventlog"
+	"github.com/iotaledger/wasp/packages/vm/core/governance"
+	"github.com/iotaledger/wasp/packages/vm/core/nft"
+	"github.com/iotaledger/wasp/packages/vm/core/scheduler"
+	"github.com/iotaledger/wasp/packages/vm/core/tokens"
 )
 
 // FindContract is an internal utility function which finds a contract in the KVStore
@@ -45,6 +53,7 @@ func MustGetChainInfo(state kv.KVStoreReader) ChainInfo {
 		FeeColor:            d.MustGetColor(VarFeeColor, balance.ColorIOTA),
 		DefaultOwnerFee:     d.MustGetInt64(VarDefaultOwnerFee, 0),
 		DefaultValidatorFee: d.MustGetInt64(VarDefaultValidatorFee, 0),
+		GasPrice:            d.MustGetInt64(VarGasPrice, 0),
 	}
 	return ret
 }
@@ -103,6 +112,15 @@ func GetDefaultFeeInfo(state kv.KVStoreReader) (balance.Color, int64, int64, err
 	return feeColor, defaultOwnerFee, defaultValidatorFee, nil
 }
 
+// GetGasPrice returns number of fee tokens charged per coretypes.GasPriceUnit of gas burned by the request
+func GetGasPrice(state kv.KVStoreReader) int64 {
+	ret, _, err := codec.DecodeInt64(state.MustGet(VarGasPrice))
+	if err != nil {
+		panic(err)
+	}
+	return ret
+}
+
 // DecodeContractRegistry encodes the whole contract registry from the map into a Go map.
 func DecodeContractRegistry(contractRegistry *collections.ImmutableMap) (map[coretypes.Hname]*ContractRecord, error) {
 	ret := make(map[coretypes.Hname]*ContractRecord)
@@ -141,6 +159,9 @@ func storeAndInitContract(ctx coretypes.Sandbox, rec *ContractRecord, initParams
 	if contractRegistry.MustHasAt(hname.Bytes()) {
 		return fmt.Errorf("contract '%s'/%s already exist", rec.Name, hname.String())
 	}
+	if collections.NewMap(ctx.State(), VarRemovedContracts).MustHasAt(hname.Bytes()) {
+		return fmt.Errorf("contract '%s'/%s was removed, the name can't be reused", rec.Name, hname.String())
+	}
 	contractRegistry.MustSetAt(hname.Bytes(), EncodeContractRecord(rec))
 	_, err := ctx.Call(coretypes.Hn(rec.Name), coretypes.EntryPointInit, initParams, nil)
 	if err != nil {
@@ -151,6 +172,64 @@ func storeAndInitContract(ctx coretypes.Sandbox, rec *ContractRecord, initParams
 	return err
 }
 
+// isCoreContract checks if the contract is one of the core contracts, deployed with the chain
+func isCoreContract(hname coretypes.Hname) bool {
+	switch hname {
+	case Interface.Hname(), accounts.Interface.Hname(), blob.Interface.Hname(), eventlog.Interface.Hname(), scheduler.Interface.Hname(),
+		tokens.Interface.Hname(), nft.Interface.Hname(), governance.Interface.Hname():
+		return true
+	}
+	return false
+}
+
+// setContractPaused internal utility function
+func setContractPaused(ctx coretypes.Sandbox, paused bool) (dict.Dict, error) {
+	fname := "root.pauseContract"
+	if !paused {
+		fname = "root.unpauseContract"
+	}
+	a := assert.NewAssert(ctx.Log())
+	a.Require(CheckAuthorizationByChainOwner(ctx.State(), ctx.Caller()), "%s: not authorized", fname)
+
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+	hname := params.MustGetHname(ParamHname)
+
+	a.Require(!isCoreContract(hname), "%s: core contract can't be paused", fname)
+	rec, err := FindContract(ctx.State(), hname)
+	a.Require(err == nil, "%s: %v", fname, err)
+	a.Require(rec.Paused != paused, "%s: paused = %v already", fname, paused)
+
+	rec.Paused = paused
+	collections.NewMap(ctx.State(), VarContractRegistry).MustSetAt(hname.Bytes(), EncodeContractRecord(rec))
+	ctx.Event(fmt.Sprintf("[pause] name: %s hname: %s, paused: %v", rec.Name, hname, paused))
+	return nil, nil
+}
+
+// upgradeHistoryName is the name of the array with the upgrade history of the contract
+func upgradeHistoryName(hname coretypes.Hname) string {
+	return VarUpgradeHistory + string(hname.Bytes())
+}
+
+// DecodeUpgradeHistory decodes the result of the 'getUpgradeHistory' view
+func DecodeUpgradeHistory(d dict.Dict) ([]*UpgradeRecord, error) {
+	history := collections.NewArrayReadOnly(d, ParamData)
+	n, err := history.Len()
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]*UpgradeRecord, n)
+	for i := range ret {
+		data, err := history.GetAt(uint16(i))
+		if err != nil {
+			return nil, err
+		}
+		if ret[i], err = DecodeUpgradeRecord(data); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
 // isAuthorizedToDeploy checks if caller is authorized to deploy smart contract
 func isAuthorizedToDeploy(ctx coretypes.Sandbox) bool {
 	if ctx.Caller() == ctx.ChainOwnerID() {
@@ -163,3 +242,179 @@ func isAuthorizedToDeploy(ctx coretypes.Sandbox) bool {
 	}
 	return collections.NewMap(ctx.State(), VarDeployPermissions).MustHasAt(ctx.Caller().Bytes())
 }
+
+// kinds of ACL members
+const (
+	aclMemberAgentID = byte('a')
+	aclMemberRole    = byte('r')
+)
+
+// aclKey is the key of the ACL of the target in the VarACLs map.
+// Zero entry point means all entry points of the contract, zero contract means all contracts of the chain
+func aclKey(hname, entryPoint coretypes.Hname) []byte {
+	return append(hname.Bytes(), entryPoint.Bytes()...)
+}
+
+// aclMembersName is the name of the map with members of the ACL
+func aclMembersName(key []byte) string {
+	return VarACLMembers + string(key)
+}
+
+// roleName is the name of the map with agentIDs which have the role
+func roleName(role string) string {
+	return VarRoles + role
+}
+
+func aclMemberKey(kind byte, data []byte) []byte {
+	return append([]byte{kind}, data...)
+}
+
+// isValidRoleName checks that the role name is not empty, not longer than 32 characters
+// and consists only of letters, digits, '_' and '-'
+func isValidRoleName(role string) bool {
+	if len(role) == 0 || len(role) > 32 {
+		return false
+	}
+	for _, c := range role {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '_', c == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// mustGetACLKeyFromParams decodes the target of the ACL from ParamHname and ParamEntryPoint
+func mustGetACLKeyFromParams(params kv.KVStoreReader, log coretypes.LogInterface) []byte {
+	d := kvdecoder.New(params, log)
+	hname := d.MustGetHname(ParamHname, 0)
+	entryPoint := d.MustGetHname(ParamEntryPoint, 0)
+	assert.NewAssert(log).Require(hname != 0 || entryPoint == 0, "root: entry point without contract")
+	return aclKey(hname, entryPoint)
+}
+
+// mustGetACLMemberFromParams decodes the member of the ACL: the role from ParamRole or the agentID from ParamAgentID
+func mustGetACLMemberFromParams(params kv.KVStoreReader, log coretypes.LogInterface) []byte {
+	d := kvdecoder.New(params, log)
+	role := d.MustGetString(ParamRole, "")
+	if role != "" {
+		assert.NewAssert(log).Require(isValidRoleName(role), "root: invalid role name '%s'", role)
+		return aclMemberKey(aclMemberRole, []byte(role))
+	}
+	agentID := d.MustGetAgentID(ParamAgentID)
+	return aclMemberKey(aclMemberAgentID, agentID.Bytes())
+}
+
+// aclTargetString is the human readable form of the target of the ACL for events
+func aclTargetString(key []byte) string {
+	hname, _ := coretypes.NewHnameFromBytes(key[:coretypes.HnameLength])
+	entryPoint, _ := coretypes.NewHnameFromBytes(key[coretypes.HnameLength:])
+	return fmt.Sprintf("%s::%s", hname, entryPoint)
+}
+
+// aclMemberString is the human readable form of the member of the ACL for events
+func aclMemberString(member []byte) string {
+	if member[0] == aclMemberRole {
+		return "role " + string(member[1:])
+	}
+	agentID, _ := coretypes.NewAgentIDFromBytes(member[1:])
+	return agentID.String()
+}
+
+// setRole internal utility function
+func setRole(ctx coretypes.Sandbox, granted bool) (dict.Dict, error) {
+	a := assert.NewAssert(ctx.Log())
+	a.Require(CheckAuthorizationByChainOwner(ctx.State(), ctx.Caller()), "root.grantRole: not authorized")
+
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+	role := params.MustGetString(ParamRole)
+	a.Require(isValidRoleName(role), "root.grantRole: invalid role name '%s'", role)
+	agentID := params.MustGetAgentID(ParamAgentID)
+
+	members := collections.NewMap(ctx.State(), roleName(role))
+	a.Require(members.MustHasAt(agentID.Bytes()) != granted, "root.grantRole: role '%s' of %s granted = %v already",
+		role, agentID, granted)
+	if granted {
+		members.MustSetAt(agentID.Bytes(), []byte{0xFF})
+	} else {
+		members.MustDelAt(agentID.Bytes())
+	}
+	ctx.Event(fmt.Sprintf("[role] role: %s agentID: %s, granted: %v", role, agentID, granted))
+	return nil, nil
+}
+
+// HasRole checks if the agentID has the role
+func HasRole(state kv.KVStoreReader, role string, agentID coretypes.AgentID) bool {
+	return collections.NewMapReadOnly(state, roleName(role)).MustHasAt(agentID.Bytes())
+}
+
+// isACLMember checks if the agentID is listed in the ACL directly or by one of its roles
+func isACLMember(state kv.KVStoreReader, key []byte, agentID coretypes.AgentID) bool {
+	members := collections.NewMapReadOnly(state, aclMembersName(key))
+	if members.MustHasAt(aclMemberKey(aclMemberAgentID, agentID.Bytes())) {
+		return true
+	}
+	ret := false
+	members.MustIterateKeys(func(elemKey []byte) bool {
+		if elemKey[0] == aclMemberRole && HasRole(state, string(elemKey[1:]), agentID) {
+			ret = true
+			return false
+		}
+		return true
+	})
+	return ret
+}
+
+// IsAccessAllowed is an internal utility function which checks if the agentID is allowed to post requests
+// to the entry point of the contract. The most specific ACL is in effect: the one of the entry point,
+// then the one of the contract, then the chain-wide one. If there is no ACL, access is allowed.
+// The chain owner is always allowed
+// It is called from within the 'root' contract as well as VMContext
+func IsAccessAllowed(state kv.KVStoreReader, hname, entryPoint coretypes.Hname, agentID coretypes.AgentID) bool {
+	owner, _, err := codec.DecodeAgentID(state.MustGet(VarChainOwnerID))
+	if err != nil {
+		panic(err)
+	}
+	if agentID == owner {
+		return true
+	}
+	acls := collections.NewMapReadOnly(state, VarACLs)
+	for _, key := range [][]byte{aclKey(hname, entryPoint), aclKey(hname, 0), aclKey(0, 0)} {
+		mode, _, err := codec.DecodeString(acls.MustGetAt(key))
+		if err != nil {
+			panic(err)
+		}
+		switch mode {
+		case "":
+			continue
+		case ACLModeAllow:
+			return isACLMember(state, key, agentID)
+		default:
+			return !isACLMember(state, key, agentID)
+		}
+	}
+	return true
+}
+
+// DecodeACL decodes the result of the 'getACL' view
+func DecodeACL(d dict.Dict) (*ACL, error) {
+	mode, _, err := codec.DecodeString(d.MustGet(ParamACLMode))
+	if err != nil {
+		return nil, err
+	}
+	ret := &ACL{Mode: mode}
+	agentIDs := collections.NewArrayReadOnly(d, ParamAgentIDs)
+	for i := uint16(0); i < agentIDs.MustLen(); i++ {
+		agentID, _, err := codec.DecodeAgentID(agentIDs.MustGetAt(i))
+		if err != nil {
+			return nil, err
+		}
+		ret.AgentIDs = append(ret.AgentIDs, agentID)
+	}
+	roles := collections.NewArrayReadOnly(d, ParamRoles)
+	for i := uint16(0); i < roles.MustLen(); i++ {
+		ret.Roles = append(ret.Roles, string(roles.MustGetAt(i)))
+	}
+	return ret, nil
+}
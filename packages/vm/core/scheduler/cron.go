@@ -0,0 +1,149 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CronSchedule is a parsed cron-like schedule with 5 fields: minute, hour, day of month, month and day of week.
+// Each field is '*', a number, a range 'a-b', a list 'a,b,c' or any of these with a step '/n', e.g. '*/15'.
+// Day of week is 0-6 (0 or 7 is Sunday). If both day of month and day of week are restricted,
+// the day matches if any of them matches. All times are in UTC
+type CronSchedule struct {
+	minute uint64
+	hour   uint64
+	dom    uint64
+	month  uint64
+	dow    uint64
+	// true if the field is '*'
+	domAny bool
+	dowAny bool
+}
+
+type cronField struct {
+	min, max int
+}
+
+var cronFields = []cronField{
+	{0, 59}, // minute
+	{0, 23}, // hour
+	{1, 31}, // day of month
+	{1, 12}, // month
+	{0, 7},  // day of week
+}
+
+// ParseCron parses the cron-like schedule
+func ParseCron(spec string) (*CronSchedule, error) {
+	fields := strings.Fields(spec)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("cron: expected %d fields, got %d", len(cronFields), len(fields))
+	}
+	masks := make([]uint64, len(fields))
+	for i, f := range fields {
+		var err error
+		if masks[i], err = parseCronField(f, cronFields[i]); err != nil {
+			return nil, fmt.Errorf("cron: field #%d '%s': %v", i, f, err)
+		}
+	}
+	ret := &CronSchedule{
+		minute: masks[0],
+		hour:   masks[1],
+		dom:    masks[2],
+		month:  masks[3],
+		dow:    masks[4],
+		domAny: fields[2] == "*",
+		dowAny: fields[4] == "*",
+	}
+	if ret.dow&(1<<7) != 0 {
+		// 7 is Sunday too
+		ret.dow |= 1
+	}
+	return ret, nil
+}
+
+func parseCronField(f string, bounds cronField) (uint64, error) {
+	var ret uint64
+	for _, part := range strings.Split(f, ",") {
+		step := 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step <= 0 {
+				return 0, fmt.Errorf("wrong step '%s'", part[i+1:])
+			}
+			part = part[:i]
+		}
+		from, to := bounds.min, bounds.max
+		switch {
+		case part == "*":
+		case strings.Contains(part, "-"):
+			i := strings.Index(part, "-")
+			var err error
+			if from, err = strconv.Atoi(part[:i]); err != nil {
+				return 0, fmt.Errorf("wrong range '%s'", part)
+			}
+			if to, err = strconv.Atoi(part[i+1:]); err != nil {
+				return 0, fmt.Errorf("wrong range '%s'", part)
+			}
+		default:
+			var err error
+			if from, err = strconv.Atoi(part); err != nil {
+				return 0, fmt.Errorf("wrong value '%s'", part)
+			}
+			if step == 1 {
+				// single value, 'a/n' means from a to max
+				to = from
+			}
+		}
+		if from < bounds.min || to > bounds.max || from > to {
+			return 0, fmt.Errorf("value out of range %d-%d", bounds.min, bounds.max)
+		}
+		for v := from; v <= to; v += step {
+			ret |= 1 << uint(v)
+		}
+	}
+	return ret, nil
+}
+
+// cronMaxYears limits the search of the next time, e.g. for schedules like '0 0 30 2 *' which never match
+const cronMaxYears = 5
+
+// Next returns the first time strictly after t which matches the schedule.
+// Returns false if there is no such time in the next few years
+func (s *CronSchedule) Next(t time.Time) (time.Time, bool) {
+	t = t.UTC().Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(cronMaxYears, 0, 0)
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = t.Truncate(time.Hour).Add(time.Hour)
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+func (s *CronSchedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domAny || s.dowAny {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
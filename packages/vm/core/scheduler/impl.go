@@ -0,0 +1,154 @@
+// 'scheduler' is a core contract on the chain. It keeps recurring jobs of smart contracts.
+// When the job is due, the VM runs it in the block as a synthetic request, without a request on the tangle.
+// The committee leader starts the block when jobs are due, even if there are no requests in the backlog
+package scheduler
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/coretypes/assert"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/collections"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/kv/kvdecoder"
+)
+
+// initialize is mandatory
+func initialize(ctx coretypes.Sandbox) (dict.Dict, error) {
+	ctx.Log().Debugf("scheduler.initialize.success hname = %s", Interface.Hname().String())
+	return nil, nil
+}
+
+// addJob schedules the recurring call to the entry point of the calling contract.
+// Only a smart contract on the same chain can add jobs, the job always calls the contract itself.
+// Fees of each run are paid from the account of the contract. If the account can't cover fees, the run is skipped
+// Input:
+// - ParamEntryPoint Hname of the entry point to call
+// - ParamArgs bytes of the dict.Dict with arguments of the call. Optional
+// - ParamInterval int64 interval between runs in seconds. Either ParamInterval or ParamCron must be specified
+// - ParamCron string cron-like schedule 'minute hour day-of-month month day-of-week', in UTC
+// - ParamStart int64 unix time (seconds) of the first run. Optional. Defaults to the first time by the schedule
+// - ParamMaxRuns int64 number of runs after which the job is removed. Optional. 0 means unlimited
+// - ParamGasBudget int64 gas budget of each run. Optional
+// Output:
+// - ParamJobID int64 ID of the job
+func addJob(ctx coretypes.Sandbox) (dict.Dict, error) {
+	a := assert.NewAssert(ctx.Log())
+	caller := ctx.Caller()
+	a.Require(!caller.IsAddress() && caller.MustContractID().ChainID() == ctx.ContractID().ChainID(),
+		"scheduler.addJob: caller must be a smart contract on the chain")
+
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+	job := &JobRecord{
+		Contract:   caller.MustContractID().Hname(),
+		EntryPoint: params.MustGetHname(ParamEntryPoint),
+		Args:       dict.New(),
+		Interval:   params.MustGetInt64(ParamInterval, 0),
+		Cron:       params.MustGetString(ParamCron, ""),
+		GasBudget:  uint64(params.MustGetInt64(ParamGasBudget, 0)),
+	}
+	maxRuns := params.MustGetInt64(ParamMaxRuns, 0)
+	a.Require(maxRuns >= 0 && maxRuns <= int64(^uint32(0)), "scheduler.addJob: wrong max runs")
+	job.MaxRuns = uint32(maxRuns)
+	if args := params.MustGetBytes(ParamArgs, nil); args != nil {
+		a.RequireNoError(job.Args.Read(bytes.NewReader(args)))
+	}
+	a.Require((job.Interval == 0) != (job.Cron == ""), "scheduler.addJob: either interval or cron schedule must be specified")
+
+	now := ctx.GetTimestamp()
+	var first int64
+	if job.Cron != "" {
+		sched, err := ParseCron(job.Cron)
+		a.RequireNoError(err)
+		next, ok := sched.Next(time.Unix(0, now))
+		a.Require(ok, "scheduler.addJob: cron schedule never matches")
+		first = next.UnixNano()
+	} else {
+		a.Require(job.Interval >= MinInterval, "scheduler.addJob: interval must be at least %d s", MinInterval)
+		first = now + job.Interval*int64(time.Second)
+	}
+	if start := params.MustGetInt64(ParamStart, 0); start != 0 {
+		first = start * int64(time.Second)
+		a.Require(first > now, "scheduler.addJob: start must be in the future")
+	}
+	job.NextRun = first
+
+	existing, err := GetJobs(ctx.State(), job.Contract)
+	a.RequireNoError(err)
+	a.Require(len(existing) < MaxJobsPerContract, "scheduler.addJob: too many jobs. Max is %d", MaxJobsPerContract)
+
+	nextID, _, err := codec.DecodeInt64(ctx.State().MustGet(VarNextJobID))
+	a.RequireNoError(err)
+	job.ID = uint32(nextID)
+	ctx.State().Set(VarNextJobID, codec.EncodeInt64(nextID+1))
+	StoreJob(ctx.State(), job)
+
+	ctx.Event(fmt.Sprintf("[scheduler] added %s", job))
+	ret := dict.New()
+	ret.Set(ParamJobID, codec.EncodeInt64(int64(job.ID)))
+	return ret, nil
+}
+
+// removeJob removes the job. Can be called by the contract which owns the job or by the chain owner
+// Input:
+// - ParamJobID int64 ID of the job
+func removeJob(ctx coretypes.Sandbox) (dict.Dict, error) {
+	a := assert.NewAssert(ctx.Log())
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+	id := uint32(params.MustGetInt64(ParamJobID))
+
+	job, err := GetJob(ctx.State(), id)
+	a.RequireNoError(err)
+	a.Require(job != nil, "scheduler.removeJob: job #%d not found", id)
+
+	caller := ctx.Caller()
+	owner := coretypes.NewAgentIDFromContractID(coretypes.NewContractID(ctx.ContractID().ChainID(), job.Contract))
+	a.Require(caller == owner || caller == ctx.ChainOwnerID(), "scheduler.removeJob: not authorized")
+
+	DeleteJob(ctx.State(), id)
+	ctx.Event(fmt.Sprintf("[scheduler] removed %s", job))
+	return nil, nil
+}
+
+// getJob returns the job
+// Input:
+// - ParamJobID int64 ID of the job
+// Output:
+// - ParamJob bytes of the JobRecord
+func getJob(ctx coretypes.SandboxView) (dict.Dict, error) {
+	a := assert.NewAssert(ctx.Log())
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+	id := uint32(params.MustGetInt64(ParamJobID))
+
+	job, err := GetJob(ctx.State(), id)
+	a.RequireNoError(err)
+	a.Require(job != nil, "scheduler.getJob: job #%d not found", id)
+
+	ret := dict.New()
+	ret.Set(ParamJob, EncodeJobRecord(job))
+	return ret, nil
+}
+
+// getJobs returns jobs of the contract or all jobs on the chain
+// Input:
+// - ParamContractHname Hname of the contract. Optional. If not specified, all jobs are returned
+// Output:
+// - ParamJobs array of JobRecord bytes, sorted by ID
+func getJobs(ctx coretypes.SandboxView) (dict.Dict, error) {
+	a := assert.NewAssert(ctx.Log())
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+	contract := params.MustGetHname(ParamContractHname, 0)
+
+	jobs, err := GetJobs(ctx.State(), contract)
+	a.RequireNoError(err)
+
+	ret := dict.New()
+	arr := collections.NewArray(ret, ParamJobs)
+	for _, job := range jobs {
+		arr.MustPush(EncodeJobRecord(job))
+	}
+	return ret, nil
+}
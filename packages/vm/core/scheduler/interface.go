@@ -0,0 +1,60 @@
+package scheduler
+
+import (
+	"github.com/iotaledger/wasp/packages/coretypes/coreutil"
+	"github.com/iotaledger/wasp/packages/hashing"
+)
+
+const (
+	Name        = "scheduler"
+	description = "Scheduler Contract"
+)
+
+var (
+	Interface = &coreutil.ContractInterface{
+		Name:        Name,
+		Description: description,
+		ProgramHash: hashing.HashStrings(Name),
+	}
+)
+
+func init() {
+	Interface.WithFunctions(initialize, []coreutil.ContractFunctionInterface{
+		coreutil.Func(FuncAddJob, addJob),
+		coreutil.Func(FuncRemoveJob, removeJob),
+		coreutil.ViewFunc(FuncGetJob, getJob),
+		coreutil.ViewFunc(FuncGetJobs, getJobs),
+	})
+}
+
+const (
+	// state variables
+	VarJobs      = "j"
+	VarNextJobID = "n"
+
+	// request parameters
+	ParamJobID         = "jobID"
+	ParamJob           = "job"
+	ParamEntryPoint    = "entryPoint"
+	ParamArgs          = "args"
+	ParamInterval      = "interval"
+	ParamCron          = "cron"
+	ParamStart         = "start"
+	ParamMaxRuns       = "maxRuns"
+	ParamGasBudget     = "gasBudget"
+	ParamContractHname = "contractHname"
+	ParamJobs          = "jobs"
+
+	// function names
+	FuncAddJob    = "addJob"
+	FuncRemoveJob = "removeJob"
+	FuncGetJob    = "getJob"
+	FuncGetJobs   = "getJobs"
+
+	// MinInterval is the minimum interval between runs of the job in seconds
+	MinInterval = 1
+	// MaxJobsPerContract is the maximum number of jobs one contract can have scheduled
+	MaxJobsPerContract = 16
+	// MaxJobsPerBlock is the maximum number of due jobs run in one block. The rest is run in the next block
+	MaxJobsPerBlock = 32
+)
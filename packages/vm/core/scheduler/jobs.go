@@ -0,0 +1,276 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/collections"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/kv/subrealm"
+	"github.com/iotaledger/wasp/packages/util"
+)
+
+// JobRecord is the recurring job of the smart contract. When the job is due, the VM calls
+// the entry point of the contract with the arguments. The caller is the 'scheduler' contract.
+// Fees of each run are paid from the on-chain account of the contract
+type JobRecord struct {
+	ID         uint32
+	Contract   coretypes.Hname
+	EntryPoint coretypes.Hname
+	Args       dict.Dict
+	// Interval between runs in seconds. 0 if the job is scheduled with Cron
+	Interval int64
+	// Cron-like schedule. Empty if the job is scheduled with Interval
+	Cron      string
+	GasBudget uint64
+	// MaxRuns is the number of runs after which the job is removed. 0 means unlimited
+	MaxRuns uint32
+	NumRuns uint32
+	// NextRun is the timestamp of the next run, in nanoseconds
+	NextRun int64
+}
+
+// NextRunAfter calculates the time of the next run after the timestamp.
+// Returns false if there are no more runs
+func (j *JobRecord) NextRunAfter(ts int64) (int64, bool) {
+	if j.MaxRuns != 0 && j.NumRuns >= j.MaxRuns {
+		return 0, false
+	}
+	if j.Cron != "" {
+		sched, err := ParseCron(j.Cron)
+		if err != nil {
+			return 0, false
+		}
+		next, ok := sched.Next(time.Unix(0, ts))
+		if !ok {
+			return 0, false
+		}
+		return next.UnixNano(), true
+	}
+	interval := j.Interval * int64(time.Second)
+	if interval <= 0 {
+		return 0, false
+	}
+	if j.NextRun > ts {
+		return j.NextRun, true
+	}
+	// skip missed runs
+	return j.NextRun + ((ts-j.NextRun)/interval+1)*interval, true
+}
+
+func (j *JobRecord) String() string {
+	sched := j.Cron
+	if sched == "" {
+		sched = fmt.Sprintf("every %ds", j.Interval)
+	}
+	return fmt.Sprintf("job #%d %s::%s (%s, runs: %d)", j.ID, j.Contract, j.EntryPoint, sched, j.NumRuns)
+}
+
+func (j *JobRecord) Write(w io.Writer) error {
+	if err := util.WriteUint32(w, j.ID); err != nil {
+		return err
+	}
+	if err := j.Contract.Write(w); err != nil {
+		return err
+	}
+	if err := j.EntryPoint.Write(w); err != nil {
+		return err
+	}
+	args := j.Args
+	if args == nil {
+		args = dict.New()
+	}
+	if err := args.Write(w); err != nil {
+		return err
+	}
+	if err := util.WriteInt64(w, j.Interval); err != nil {
+		return err
+	}
+	if err := util.WriteString16(w, j.Cron); err != nil {
+		return err
+	}
+	if err := util.WriteUint64(w, j.GasBudget); err != nil {
+		return err
+	}
+	if err := util.WriteUint32(w, j.MaxRuns); err != nil {
+		return err
+	}
+	if err := util.WriteUint32(w, j.NumRuns); err != nil {
+		return err
+	}
+	return util.WriteInt64(w, j.NextRun)
+}
+
+func (j *JobRecord) Read(r io.Reader) error {
+	var err error
+	if err = util.ReadUint32(r, &j.ID); err != nil {
+		return err
+	}
+	if err = j.Contract.Read(r); err != nil {
+		return err
+	}
+	if err = j.EntryPoint.Read(r); err != nil {
+		return err
+	}
+	j.Args = dict.New()
+	if err = j.Args.Read(r); err != nil {
+		return err
+	}
+	if err = util.ReadInt64(r, &j.Interval); err != nil {
+		return err
+	}
+	if j.Cron, err = util.ReadString16(r); err != nil {
+		return err
+	}
+	if err = util.ReadUint64(r, &j.GasBudget); err != nil {
+		return err
+	}
+	if err = util.ReadUint32(r, &j.MaxRuns); err != nil {
+		return err
+	}
+	if err = util.ReadUint32(r, &j.NumRuns); err != nil {
+		return err
+	}
+	return util.ReadInt64(r, &j.NextRun)
+}
+
+func EncodeJobRecord(j *JobRecord) []byte {
+	return util.MustBytes(j)
+}
+
+func DecodeJobRecord(data []byte) (*JobRecord, error) {
+	ret := new(JobRecord)
+	err := ret.Read(bytes.NewReader(data))
+	return ret, err
+}
+
+// DecodeJobRecords decodes the result of the getJobs view
+func DecodeJobRecords(d dict.Dict) ([]*JobRecord, error) {
+	arr := collections.NewArrayReadOnly(d, ParamJobs)
+	n, err := arr.Len()
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]*JobRecord, n)
+	for i := uint16(0); i < n; i++ {
+		data, err := arr.GetAt(i)
+		if err != nil {
+			return nil, err
+		}
+		if ret[i], err = DecodeJobRecord(data); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+// GetJobs returns all jobs of the contract, or all jobs on the chain if contract is 0, sorted by ID
+func GetJobs(state kv.KVStoreReader, contract coretypes.Hname) ([]*JobRecord, error) {
+	ret := make([]*JobRecord, 0)
+	var err error
+	collections.NewMapReadOnly(state, VarJobs).MustIterate(func(_ []byte, value []byte) bool {
+		var job *JobRecord
+		if job, err = DecodeJobRecord(value); err != nil {
+			return false
+		}
+		if contract == 0 || job.Contract == contract {
+			ret = append(ret, job)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].ID < ret[j].ID
+	})
+	return ret, nil
+}
+
+// GetDueJobs returns up to MaxJobsPerBlock jobs due at the timestamp, in deterministic order:
+// the earliest first, by ID if due at the same time
+func GetDueJobs(state kv.KVStoreReader, ts int64) ([]*JobRecord, error) {
+	jobs, err := GetJobs(state, 0)
+	if err != nil {
+		return nil, err
+	}
+	ret := jobs[:0]
+	for _, job := range jobs {
+		if job.NextRun <= ts {
+			ret = append(ret, job)
+		}
+	}
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i].NextRun < ret[j].NextRun
+	})
+	if len(ret) > MaxJobsPerBlock {
+		ret = ret[:MaxJobsPerBlock]
+	}
+	return ret, nil
+}
+
+// HasDueJobs returns true if there are jobs due at the timestamp in the state of the chain
+func HasDueJobs(chainState kv.KVStore, ts int64) bool {
+	jobs, err := GetDueJobs(Partition(chainState), ts)
+	return err == nil && len(jobs) > 0
+}
+
+// Partition returns the partition of the 'scheduler' contract in the state of the chain
+func Partition(chainState kv.KVStore) kv.KVStore {
+	return subrealm.New(chainState, kv.Key(Interface.Hname().Bytes()))
+}
+
+// GetJob returns the job by ID or nil if it does not exist
+func GetJob(state kv.KVStoreReader, id uint32) (*JobRecord, error) {
+	data := collections.NewMapReadOnly(state, VarJobs).MustGetAt(util.Uint32To4Bytes(id))
+	if data == nil {
+		return nil, nil
+	}
+	return DecodeJobRecord(data)
+}
+
+// StoreJob stores the job to the state
+func StoreJob(state kv.KVStore, job *JobRecord) {
+	collections.NewMap(state, VarJobs).MustSetAt(util.Uint32To4Bytes(job.ID), EncodeJobRecord(job))
+}
+
+// DeleteJob removes the job from the state
+func DeleteJob(state kv.KVStore, id uint32) {
+	collections.NewMap(state, VarJobs).MustDelAt(util.Uint32To4Bytes(id))
+}
+
+// UpdateAfterRun increments the number of runs of the job and calculates the next run.
+// The job is removed if there are no more runs. Returns false if the job was removed
+func UpdateAfterRun(state kv.KVStore, job *JobRecord, ts int64) bool {
+	job.NumRuns++
+	next, ok := job.NextRunAfter(ts)
+	if !ok {
+		DeleteJob(state, job.ID)
+		return false
+	}
+	job.NextRun = next
+	StoreJob(state, job)
+	return true
+}
+
+// JobRequestID is the deterministic ID of the synthetic request which runs the job at the timestamp.
+// It does not correspond to any transaction on the tangle
+func JobRequestID(chainID coretypes.ChainID, job *JobRecord, ts int64) coretypes.RequestID {
+	var buf bytes.Buffer
+	buf.Write(chainID[:])
+	_ = util.WriteUint32(&buf, job.ID)
+	_ = util.WriteInt64(&buf, ts)
+	h := hashing.HashData(buf.Bytes())
+	var ret coretypes.RequestID
+	copy(ret[:], h[:])
+	return ret
+}
@@ -0,0 +1,185 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package testcore
+
+import (
+	"testing"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address/signaturescheme"
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	"github.com/iotaledger/wasp/contracts/examples_core/inccounter"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/solo"
+	"github.com/iotaledger/wasp/packages/testutil"
+	"github.com/iotaledger/wasp/packages/vm/core/root"
+	"github.com/stretchr/testify/require"
+)
+
+func postToRoot(chain *solo.Chain, sigScheme signaturescheme.SignatureScheme, funName string, params ...interface{}) error {
+	_, err := chain.PostRequest(solo.NewCallParams(root.Interface.Name, funName, params...), sigScheme)
+	return err
+}
+
+func checkAccess(t *testing.T, chain *solo.Chain, funName string, agentID coretypes.AgentID, expected bool) {
+	ret, err := chain.CallView(root.Interface.Name, root.FuncCheckAccess,
+		root.ParamHname, coretypes.Hn(incName),
+		root.ParamEntryPoint, coretypes.Hn(funName),
+		root.ParamAgentID, agentID,
+	)
+	require.NoError(t, err)
+	allowed, _, err := codec.DecodeInt64(ret.MustGet(root.ParamAllowed))
+	require.NoError(t, err)
+	require.EqualValues(t, expected, allowed == 1)
+}
+
+func TestACLAllowList(t *testing.T) {
+	env := solo.New(t, false, false)
+	chain := env.NewChain(nil, "chain1")
+	defer chain.WaitForEmptyBacklog()
+
+	err := chain.DeployContract(nil, incName, inccounter.Interface.ProgramHash)
+	require.NoError(t, err)
+	user := env.NewSignatureSchemeWithFunds()
+	userAgentID := coretypes.NewAgentIDFromAddress(user.Address())
+
+	err = postToRoot(chain, nil, root.FuncSetACL, root.ParamHname, coretypes.Hn(incName), root.ParamACLMode, root.ACLModeAllow)
+	require.NoError(t, err)
+	checkAccess(t, chain, inccounter.FuncIncCounter, userAgentID, false)
+	checkAccess(t, chain, inccounter.FuncIncCounter, chain.OriginatorAgentID, true)
+
+	// the request of the user who is not in the list is refunded
+	req := solo.NewCallParams(incName, inccounter.FuncIncCounter).WithTransfer(balance.ColorIOTA, 42)
+	_, err = chain.PostRequest(req, user)
+	require.Error(t, err)
+	checkCounter(t, chain, 0)
+	env.AssertAddressBalance(user.Address(), balance.ColorIOTA, testutil.RequestFundsAmount-1)
+	chain.AssertAccountBalance(userAgentID, balance.ColorIOTA, 1)
+
+	// the chain owner is always allowed
+	_, err = chain.PostRequest(solo.NewCallParams(incName, inccounter.FuncIncCounter), nil)
+	require.NoError(t, err)
+	checkCounter(t, chain, 1)
+
+	err = postToRoot(chain, nil, root.FuncAddToACL, root.ParamHname, coretypes.Hn(incName), root.ParamAgentID, userAgentID)
+	require.NoError(t, err)
+	checkAccess(t, chain, inccounter.FuncIncCounter, userAgentID, true)
+	_, err = chain.PostRequest(solo.NewCallParams(incName, inccounter.FuncIncCounter), user)
+	require.NoError(t, err)
+	checkCounter(t, chain, 2)
+
+	ret, err := chain.CallView(root.Interface.Name, root.FuncGetACL, root.ParamHname, coretypes.Hn(incName))
+	require.NoError(t, err)
+	acl, err := root.DecodeACL(ret)
+	require.NoError(t, err)
+	require.EqualValues(t, root.ACLModeAllow, acl.Mode)
+	require.EqualValues(t, []coretypes.AgentID{userAgentID}, acl.AgentIDs)
+	require.EqualValues(t, 0, len(acl.Roles))
+
+	err = postToRoot(chain, nil, root.FuncRemoveFromACL, root.ParamHname, coretypes.Hn(incName), root.ParamAgentID, userAgentID)
+	require.NoError(t, err)
+	_, err = chain.PostRequest(solo.NewCallParams(incName, inccounter.FuncIncCounter), user)
+	require.Error(t, err)
+	checkCounter(t, chain, 2)
+
+	// without the ACL everybody is allowed again
+	err = postToRoot(chain, nil, root.FuncRemoveACL, root.ParamHname, coretypes.Hn(incName))
+	require.NoError(t, err)
+	_, err = chain.PostRequest(solo.NewCallParams(incName, inccounter.FuncIncCounter), user)
+	require.NoError(t, err)
+	checkCounter(t, chain, 3)
+
+	ret, err = chain.CallView(root.Interface.Name, root.FuncGetACL, root.ParamHname, coretypes.Hn(incName))
+	require.NoError(t, err)
+	acl, err = root.DecodeACL(ret)
+	require.NoError(t, err)
+	require.EqualValues(t, "", acl.Mode)
+	chain.CheckChain()
+}
+
+func TestACLDenyListRoles(t *testing.T) {
+	env := solo.New(t, false, false)
+	chain := env.NewChain(nil, "chain1")
+	defer chain.WaitForEmptyBacklog()
+
+	err := chain.DeployContract(nil, incName, inccounter.Interface.ProgramHash)
+	require.NoError(t, err)
+	user1 := env.NewSignatureSchemeWithFunds()
+	user1AgentID := coretypes.NewAgentIDFromAddress(user1.Address())
+	user2 := env.NewSignatureSchemeWithFunds()
+	user2AgentID := coretypes.NewAgentIDFromAddress(user2.Address())
+
+	// chain-wide deny list of the role
+	err = postToRoot(chain, nil, root.FuncSetACL, root.ParamACLMode, root.ACLModeDeny)
+	require.NoError(t, err)
+	err = postToRoot(chain, nil, root.FuncAddToACL, root.ParamRole, "blocked")
+	require.NoError(t, err)
+	err = postToRoot(chain, nil, root.FuncGrantRole, root.ParamRole, "blocked", root.ParamAgentID, user1AgentID)
+	require.NoError(t, err)
+	// granting twice fails
+	err = postToRoot(chain, nil, root.FuncGrantRole, root.ParamRole, "blocked", root.ParamAgentID, user1AgentID)
+	require.Error(t, err)
+
+	checkAccess(t, chain, inccounter.FuncIncCounter, user1AgentID, false)
+	checkAccess(t, chain, inccounter.FuncIncCounter, user2AgentID, true)
+	_, err = chain.PostRequest(solo.NewCallParams(incName, inccounter.FuncIncCounter), user1)
+	require.Error(t, err)
+	_, err = chain.PostRequest(solo.NewCallParams(incName, inccounter.FuncIncCounter), user2)
+	require.NoError(t, err)
+	checkCounter(t, chain, 1)
+
+	// the ACL of the entry point overrides the chain-wide one
+	err = postToRoot(chain, nil, root.FuncSetACL, root.ParamHname, coretypes.Hn(incName),
+		root.ParamEntryPoint, coretypes.Hn(inccounter.FuncIncCounter), root.ParamACLMode, root.ACLModeAllow)
+	require.NoError(t, err)
+	err = postToRoot(chain, nil, root.FuncAddToACL, root.ParamHname, coretypes.Hn(incName),
+		root.ParamEntryPoint, coretypes.Hn(inccounter.FuncIncCounter), root.ParamRole, "blocked")
+	require.NoError(t, err)
+	checkAccess(t, chain, inccounter.FuncIncCounter, user1AgentID, true)
+	checkAccess(t, chain, inccounter.FuncIncCounter, user2AgentID, false)
+	_, err = chain.PostRequest(solo.NewCallParams(incName, inccounter.FuncIncCounter), user1)
+	require.NoError(t, err)
+	checkCounter(t, chain, 2)
+
+	// the chain-wide ACL is still in effect for other entry points
+	checkAccess(t, chain, inccounter.FuncIncAndRepeatOnceAfter5s, user1AgentID, false)
+
+	err = postToRoot(chain, nil, root.FuncRevokeRole, root.ParamRole, "blocked", root.ParamAgentID, user1AgentID)
+	require.NoError(t, err)
+	checkAccess(t, chain, inccounter.FuncIncCounter, user1AgentID, false)
+	checkAccess(t, chain, inccounter.FuncIncAndRepeatOnceAfter5s, user1AgentID, true)
+	chain.CheckChain()
+}
+
+func TestACLFail(t *testing.T) {
+	env := solo.New(t, false, false)
+	chain := env.NewChain(nil, "chain1")
+	defer chain.WaitForEmptyBacklog()
+
+	user := env.NewSignatureSchemeWithFunds()
+	userAgentID := coretypes.NewAgentIDFromAddress(user.Address())
+
+	// not authorized
+	err := postToRoot(chain, user, root.FuncSetACL, root.ParamACLMode, root.ACLModeDeny)
+	require.Error(t, err)
+	err = postToRoot(chain, user, root.FuncGrantRole, root.ParamRole, "admin", root.ParamAgentID, userAgentID)
+	require.Error(t, err)
+
+	// wrong mode
+	err = postToRoot(chain, nil, root.FuncSetACL, root.ParamACLMode, "dummy")
+	require.Error(t, err)
+	// entry point without contract
+	err = postToRoot(chain, nil, root.FuncSetACL,
+		root.ParamEntryPoint, coretypes.Hn(inccounter.FuncIncCounter), root.ParamACLMode, root.ACLModeDeny)
+	require.Error(t, err)
+	// ACL does not exist
+	err = postToRoot(chain, nil, root.FuncAddToACL, root.ParamAgentID, userAgentID)
+	require.Error(t, err)
+	err = postToRoot(chain, nil, root.FuncRemoveACL)
+	require.Error(t, err)
+	// invalid role name
+	err = postToRoot(chain, nil, root.FuncGrantRole, root.ParamRole, "a b", root.ParamAgentID, userAgentID)
+	require.Error(t, err)
+	chain.CheckChain()
+}
@@ -0,0 +1,191 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package testcore
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	"github.com/iotaledger/wasp/contracts"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/coretypes/cbalances"
+	"github.com/iotaledger/wasp/packages/coretypes/coreutil"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/solo"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	cbName = "cbTest"
+
+	funcSend       = "send"
+	funcTarget     = "target"
+	funcCallback   = "callback"
+	funcGetResults = "getResults"
+
+	paramChainID     = "chainID"
+	paramNoCallback  = "noCallback"
+	paramFail        = "fail"
+	paramEcho        = "echo"
+	varCallbackError = "cbError"
+	varCallbackEcho  = "cbEcho"
+	varCallbackArg   = "cbArg"
+	varCallbackFunds = "cbFunds"
+)
+
+// callbackTester posts requests to the same contract on another chain and records results passed to its callback
+var callbackTester = &coreutil.ContractInterface{
+	Name:        "callbackTester",
+	Description: "Cross-chain callbacks and refunds",
+	ProgramHash: hashing.HashStrings("callbackTester"),
+}
+
+func init() {
+	callbackTester.WithFunctions(func(ctx coretypes.Sandbox) (dict.Dict, error) {
+		return nil, nil
+	}, []coreutil.ContractFunctionInterface{
+		coreutil.Func(funcSend, func(ctx coretypes.Sandbox) (dict.Dict, error) {
+			chainID, ok, err := codec.DecodeChainID(ctx.Params().MustGet(paramChainID))
+			if err != nil || !ok {
+				return nil, fmt.Errorf("wrong chain ID")
+			}
+			par := coretypes.PostRequestParams{
+				TargetContractID: coretypes.NewContractID(chainID, coretypes.Hn(cbName)),
+				EntryPoint:       coretypes.Hn(funcTarget),
+				Params:           dict.New(),
+				Transfer: cbalances.NewFromMap(map[balance.Color]int64{
+					balance.ColorIOTA: ctx.IncomingTransfer().Balance(balance.ColorIOTA) - 1,
+				}),
+				RefundOnFailure: true,
+			}
+			if !ctx.Params().MustHas(paramNoCallback) {
+				par.Callback = coretypes.Hn(funcCallback)
+			}
+			par.Params.Set(paramEcho, ctx.Params().MustGet(paramEcho))
+			if ctx.Params().MustHas(paramFail) {
+				par.Params.Set(paramFail, []byte{1})
+			}
+			if !ctx.PostRequest(par) {
+				return nil, fmt.Errorf("failed to post request")
+			}
+			return nil, nil
+		}),
+		coreutil.Func(funcTarget, func(ctx coretypes.Sandbox) (dict.Dict, error) {
+			if ctx.Params().MustHas(paramFail) {
+				return nil, fmt.Errorf("failed as requested")
+			}
+			ret := dict.New()
+			ret.Set(paramEcho, ctx.Params().MustGet(paramEcho))
+			return ret, nil
+		}),
+		coreutil.Func(funcCallback, func(ctx coretypes.Sandbox) (dict.Dict, error) {
+			result, err := coretypes.NewCallbackResultFromParams(ctx.Params())
+			if err != nil {
+				return nil, err
+			}
+			ctx.State().Set(varCallbackError, []byte(result.Error))
+			ctx.State().Set(varCallbackArg, result.Args.MustGet(paramEcho))
+			if !result.Failed() {
+				ctx.State().Set(varCallbackEcho, result.Results.MustGet(paramEcho))
+			}
+			ctx.State().Set(varCallbackFunds, codec.EncodeInt64(ctx.IncomingTransfer().Balance(balance.ColorIOTA)))
+			return nil, nil
+		}),
+		coreutil.ViewFunc(funcGetResults, func(ctx coretypes.SandboxView) (dict.Dict, error) {
+			ret := dict.New()
+			for _, key := range []kv.Key{varCallbackError, varCallbackArg, varCallbackEcho, varCallbackFunds} {
+				if v := ctx.State().MustGet(key); v != nil {
+					ret.Set(key, v)
+				}
+			}
+			return ret, nil
+		}),
+	})
+	contracts.AddExampleProcessor(callbackTester)
+}
+
+func setupCallbackChains(t *testing.T) (*solo.Solo, *solo.Chain, *solo.Chain) {
+	env := solo.New(t, false, false)
+	chain1 := env.NewChain(nil, "chain1")
+	chain2 := env.NewChain(nil, "chain2")
+	require.NoError(t, chain1.DeployContract(nil, cbName, callbackTester.ProgramHash))
+	require.NoError(t, chain2.DeployContract(nil, cbName, callbackTester.ProgramHash))
+	return env, chain1, chain2
+}
+
+func postCrossChain(t *testing.T, chain1, chain2 *solo.Chain, params ...interface{}) {
+	params = append(params, paramChainID, chain2.ChainID, paramEcho, "hello")
+	req := solo.NewCallParams(cbName, funcSend, params...).WithTransfer(balance.ColorIOTA, 43)
+	_, err := chain1.PostRequest(req, nil)
+	require.NoError(t, err)
+	chain2.WaitForEmptyBacklog()
+	chain1.WaitForEmptyBacklog()
+}
+
+func TestCallbackSuccess(t *testing.T) {
+	_, chain1, chain2 := setupCallbackChains(t)
+
+	postCrossChain(t, chain1, chain2)
+
+	ret, err := chain1.CallView(cbName, funcGetResults)
+	require.NoError(t, err)
+	require.EqualValues(t, "", string(ret.MustGet(varCallbackError)))
+	require.EqualValues(t, "hello", string(ret.MustGet(varCallbackArg)))
+	require.EqualValues(t, "hello", string(ret.MustGet(varCallbackEcho)))
+	funds, _, _ := codec.DecodeInt64(ret.MustGet(varCallbackFunds))
+	require.EqualValues(t, 0, funds)
+
+	// the transfer stays with the target
+	agentID1 := coretypes.NewAgentIDFromContractID(coretypes.NewContractID(chain1.ChainID, coretypes.Hn(cbName)))
+	agentID2 := coretypes.NewAgentIDFromContractID(coretypes.NewContractID(chain2.ChainID, coretypes.Hn(cbName)))
+	chain1.AssertAccountBalance(agentID1, balance.ColorIOTA, 0)
+	chain2.AssertAccountBalance(agentID2, balance.ColorIOTA, 42)
+	chain1.CheckChain()
+	chain2.CheckChain()
+}
+
+func TestCallbackRefund(t *testing.T) {
+	_, chain1, chain2 := setupCallbackChains(t)
+
+	postCrossChain(t, chain1, chain2, paramFail, 1)
+
+	ret, err := chain1.CallView(cbName, funcGetResults)
+	require.NoError(t, err)
+	require.Contains(t, string(ret.MustGet(varCallbackError)), "failed as requested")
+	require.EqualValues(t, "hello", string(ret.MustGet(varCallbackArg)))
+	require.Nil(t, ret.MustGet(varCallbackEcho))
+	funds, _, _ := codec.DecodeInt64(ret.MustGet(varCallbackFunds))
+	require.EqualValues(t, 42, funds)
+
+	// the transfer is returned to the sender with the callback
+	agentID1 := coretypes.NewAgentIDFromContractID(coretypes.NewContractID(chain1.ChainID, coretypes.Hn(cbName)))
+	agentID2 := coretypes.NewAgentIDFromContractID(coretypes.NewContractID(chain2.ChainID, coretypes.Hn(cbName)))
+	chain1.AssertAccountBalance(agentID1, balance.ColorIOTA, 42)
+	chain2.AssertAccountBalance(agentID2, balance.ColorIOTA, 0)
+	chain2.AssertAccountBalance(agentID1, balance.ColorIOTA, 0)
+	chain1.CheckChain()
+	chain2.CheckChain()
+}
+
+func TestRefundWithoutCallback(t *testing.T) {
+	_, chain1, chain2 := setupCallbackChains(t)
+
+	postCrossChain(t, chain1, chain2, paramFail, 1, paramNoCallback, 1)
+
+	// callback wasn't called
+	ret, err := chain1.CallView(cbName, funcGetResults)
+	require.NoError(t, err)
+	require.Nil(t, ret.MustGet(varCallbackArg))
+
+	// the transfer is deposited back to the account of the sender
+	agentID1 := coretypes.NewAgentIDFromContractID(coretypes.NewContractID(chain1.ChainID, coretypes.Hn(cbName)))
+	chain1.AssertAccountBalance(agentID1, balance.ColorIOTA, 42)
+	chain2.AssertAccountBalance(agentID1, balance.ColorIOTA, 0)
+	chain1.CheckChain()
+	chain2.CheckChain()
+}
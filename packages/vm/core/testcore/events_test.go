@@ -0,0 +1,148 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package testcore
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	"github.com/iotaledger/wasp/contracts"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/coretypes/coreutil"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/solo"
+	"github.com/iotaledger/wasp/packages/vm/core/eventlog"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	funcEmit       = "emit"
+	paramEventName = "eventName"
+	paramNumTopics = "numTopics"
+	paramAmount    = "amount"
+)
+
+// eventEmitter emits the structured event with the caller and the color as topics
+var eventEmitter = &coreutil.ContractInterface{
+	Name:        "eventEmitter",
+	Description: "Emits structured events",
+	ProgramHash: hashing.HashStrings("eventEmitter"),
+}
+
+func init() {
+	eventEmitter.WithFunctions(func(ctx coretypes.Sandbox) (dict.Dict, error) {
+		return nil, nil
+	}, []coreutil.ContractFunctionInterface{
+		coreutil.Func(funcEmit, func(ctx coretypes.Sandbox) (dict.Dict, error) {
+			name, _, _ := codec.DecodeString(ctx.Params().MustGet(paramEventName))
+			caller := ctx.Caller()
+			topics := [][]byte{caller.Bytes(), balance.ColorIOTA[:]}
+			if n, ok, _ := codec.DecodeInt64(ctx.Params().MustGet(paramNumTopics)); ok {
+				for i := int64(len(topics)); i < n; i++ {
+					topics = append(topics, codec.EncodeInt64(i))
+				}
+			}
+			payload := dict.New()
+			payload.Set(paramAmount, ctx.Params().MustGet(paramAmount))
+			ctx.TypedEvent(name, payload, topics...)
+			return nil, nil
+		}),
+	})
+	contracts.AddExampleProcessor(eventEmitter)
+}
+
+func emitEvent(chain *solo.Chain, contract string, name string, amount int64) error {
+	req := solo.NewCallParams(contract, funcEmit, paramEventName, name, paramAmount, amount)
+	_, err := chain.PostRequest(req, nil)
+	return err
+}
+
+func TestTypedEvents(t *testing.T) {
+	env := solo.New(t, false, false)
+	chain := env.NewChain(nil, "chain1")
+	defer chain.WaitForEmptyBacklog()
+
+	err := chain.DeployContract(nil, "emitter1", eventEmitter.ProgramHash)
+	require.NoError(t, err)
+	err = chain.DeployContract(nil, "emitter2", eventEmitter.ProgramHash)
+	require.NoError(t, err)
+
+	require.NoError(t, emitEvent(chain, "emitter1", "transfer", 1))
+	require.NoError(t, emitEvent(chain, "emitter1", "mint", 2))
+	require.NoError(t, emitEvent(chain, "emitter2", "transfer", 3))
+
+	recs, err := chain.GetEvents("emitter1")
+	require.NoError(t, err)
+	require.Len(t, recs, 2)
+	// time descending order
+	require.EqualValues(t, "mint", recs[0].Name)
+	require.EqualValues(t, "transfer", recs[1].Name)
+	require.EqualValues(t, coretypes.Hn("emitter1"), recs[1].Contract)
+	require.EqualValues(t, codec.EncodeInt64(1), recs[1].Payload.MustGet(paramAmount))
+	require.True(t, recs[1].HasTopic(chain.OriginatorAgentID.Bytes()))
+	require.True(t, recs[1].HasTopic(balance.ColorIOTA[:]))
+
+	recs, err = chain.GetEvents("emitter1", eventlog.ParamEventName, "transfer")
+	require.NoError(t, err)
+	require.Len(t, recs, 1)
+
+	// events of all contracts are indexed by the topic
+	recs, err = chain.GetEventsByTopic(chain.OriginatorAgentID.Bytes())
+	require.NoError(t, err)
+	require.Len(t, recs, 3)
+	require.EqualValues(t, coretypes.Hn("emitter2"), recs[0].Contract)
+	require.EqualValues(t, codec.EncodeInt64(3), recs[0].Payload.MustGet(paramAmount))
+
+	recs, err = chain.GetEventsByTopic(balance.ColorIOTA[:], eventlog.ParamContractHname, coretypes.Hn("emitter1"))
+	require.NoError(t, err)
+	require.Len(t, recs, 2)
+
+	recs, err = chain.GetEventsByTopic(balance.ColorIOTA[:], eventlog.ParamEventName, "transfer", eventlog.ParamMaxLastRecords, 1)
+	require.NoError(t, err)
+	require.Len(t, recs, 1)
+	require.EqualValues(t, coretypes.Hn("emitter2"), recs[0].Contract)
+
+	recs, err = chain.GetEventsByTopic([]byte("dummy"))
+	require.NoError(t, err)
+	require.Len(t, recs, 0)
+
+	// text event log of the contract contains only request records
+	logRecs, err := chain.GetEventLogRecords("emitter1")
+	require.NoError(t, err)
+	require.Len(t, logRecs, 2)
+	for _, rec := range logRecs {
+		require.True(t, strings.HasPrefix(string(rec.Data), "[req] "))
+	}
+	chain.CheckChain()
+}
+
+func TestTypedEventsFail(t *testing.T) {
+	env := solo.New(t, false, false)
+	chain := env.NewChain(nil, "chain1")
+	defer chain.WaitForEmptyBacklog()
+
+	err := chain.DeployContract(nil, "emitter1", eventEmitter.ProgramHash)
+	require.NoError(t, err)
+
+	// empty name
+	err = emitEvent(chain, "emitter1", "", 1)
+	require.Error(t, err)
+
+	// too many topics
+	req := solo.NewCallParams("emitter1", funcEmit, paramEventName, "transfer", paramAmount, 1, paramNumTopics, eventlog.MaxEventTopics+1)
+	_, err = chain.PostRequest(req, nil)
+	require.Error(t, err)
+
+	req = solo.NewCallParams("emitter1", funcEmit, paramEventName, "transfer", paramAmount, 1, paramNumTopics, eventlog.MaxEventTopics)
+	_, err = chain.PostRequest(req, nil)
+	require.NoError(t, err)
+
+	recs, err := chain.GetEvents("emitter1")
+	require.NoError(t, err)
+	require.Len(t, recs, 1)
+	require.Len(t, recs[0].Topics, eventlog.MaxEventTopics)
+}
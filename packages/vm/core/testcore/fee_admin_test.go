@@ -5,6 +5,8 @@ package testcore
 
 import (
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/kv/codec"
 	"github.com/iotaledger/wasp/packages/solo"
 	"github.com/iotaledger/wasp/packages/vm/core/accounts"
 	"github.com/iotaledger/wasp/packages/vm/core/blob"
@@ -250,3 +252,58 @@ func TestFeeOwnerDontNeed(t *testing.T) {
 	checkFees(chain, accounts.Interface.Name, 1000, 0)
 	checkFees(chain, blob.Interface.Name, 1000, 0)
 }
+
+func TestFeeHistory(t *testing.T) {
+	env := solo.New(t, false, false)
+	chain := env.NewChain(nil, "chain1")
+	blockIndex := chain.State.BlockIndex()
+
+	req := solo.NewCallParams(root.Interface.Name, root.FuncSetContractFee,
+		root.ParamHname, blob.Interface.Hname(),
+		root.ParamOwnerFee, 5,
+	)
+	_, err := chain.PostRequest(req, nil)
+	require.NoError(t, err)
+	checkFees(chain, blob.Interface.Name, 5, 0)
+
+	ret, err := chain.CallViewAtBlock(blockIndex, root.Interface.Name, root.FuncGetFeeInfo, root.ParamHname, blob.Interface.Hname())
+	require.NoError(t, err)
+	ownerFee, _, err := codec.DecodeInt64(ret.MustGet(root.ParamOwnerFee))
+	require.NoError(t, err)
+	require.EqualValues(t, 0, ownerFee)
+
+	ret, err = chain.CallViewAtBlock(blockIndex+1, root.Interface.Name, root.FuncGetFeeInfo, root.ParamHname, blob.Interface.Hname())
+	require.NoError(t, err)
+	ownerFee, _, err = codec.DecodeInt64(ret.MustGet(root.ParamOwnerFee))
+	require.NoError(t, err)
+	require.EqualValues(t, 5, ownerFee)
+
+	_, err = chain.CallViewAtBlock(blockIndex+2, root.Interface.Name, root.FuncGetFeeInfo, root.ParamHname, blob.Interface.Hname())
+	require.Error(t, err)
+}
+
+func TestValidatorFeeTarget(t *testing.T) {
+	env := solo.New(t, false, false)
+	feeTarget := coretypes.NewAgentIDFromAddress(env.NewSignatureSchemeWithFunds().Address())
+	chain := env.NewChain(nil, "chain1", feeTarget)
+
+	req := solo.NewCallParams(root.Interface.Name, root.FuncSetContractFee,
+		root.ParamHname, accounts.Interface.Hname(),
+		root.ParamValidatorFee, 10,
+	)
+	_, err := chain.PostRequest(req, nil)
+	require.NoError(t, err)
+	checkFees(chain, accounts.Interface.Name, 0, 10)
+	feeTargetBalance := chain.GetAccountBalance(feeTarget).Balance(balance.ColorIOTA)
+
+	user := env.NewSignatureSchemeWithFunds()
+	req = solo.NewCallParams(accounts.Interface.Name, accounts.FuncDeposit).
+		WithTransfer(balance.ColorIOTA, 100)
+	_, err = chain.PostRequest(req, user)
+	require.NoError(t, err)
+
+	// the validator fee goes to the fee target of the node, not to the empty agent ID
+	chain.AssertAccountBalance(feeTarget, balance.ColorIOTA, feeTargetBalance+10)
+	chain.AssertAccountBalance(coretypes.AgentID{}, balance.ColorIOTA, 0)
+	chain.AssertAccountBalance(coretypes.NewAgentIDFromAddress(user.Address()), balance.ColorIOTA, 100+1-10)
+}
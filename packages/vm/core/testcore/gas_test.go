@@ -0,0 +1,144 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package testcore
+
+import (
+	"testing"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	"github.com/iotaledger/wasp/contracts"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/coretypes/coreutil"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/solo"
+	"github.com/iotaledger/wasp/packages/testutil"
+	"github.com/iotaledger/wasp/packages/vm/core/accounts"
+	"github.com/iotaledger/wasp/packages/vm/core/blob"
+	"github.com/iotaledger/wasp/packages/vm/core/root"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	gasViewName     = "gasView"
+	funcCallView    = "callView"
+	funcBurningView = "burningView"
+	burningViewGas  = 1000
+)
+
+// gasViewContract calls its own view which burns gas, if it is called while the request is being processed
+var gasViewContract = &coreutil.ContractInterface{
+	Name:        gasViewName,
+	Description: "Calls the view burning gas",
+	ProgramHash: hashing.HashStrings(gasViewName),
+}
+
+func init() {
+	gasViewContract.WithFunctions(func(ctx coretypes.Sandbox) (dict.Dict, error) {
+		return nil, nil
+	}, []coreutil.ContractFunctionInterface{
+		coreutil.Func(funcCallView, func(ctx coretypes.Sandbox) (dict.Dict, error) {
+			return ctx.Call(ctx.ContractID().Hname(), coretypes.Hn(funcBurningView), nil, nil)
+		}),
+		coreutil.ViewFunc(funcBurningView, func(ctx coretypes.SandboxView) (dict.Dict, error) {
+			if gas, ok := ctx.(coretypes.GasMeter); ok {
+				gas.BurnGas(burningViewGas)
+			}
+			return nil, nil
+		}),
+	})
+	contracts.AddExampleProcessor(gasViewContract)
+}
+
+func checkGasPrice(chain *solo.Chain, expected int64) {
+	ret, err := chain.CallView(root.Interface.Name, root.FuncGetFeeInfo, root.ParamHname, blob.Interface.Hname())
+	require.NoError(chain.Env.T, err)
+	gasPrice, _, err := codec.DecodeInt64(ret.MustGet(root.ParamGasPrice))
+	require.NoError(chain.Env.T, err)
+	require.EqualValues(chain.Env.T, expected, gasPrice)
+}
+
+func TestGasPriceDefault(t *testing.T) {
+	env := solo.New(t, false, false)
+	chain := env.NewChain(nil, "chain1")
+	checkGasPrice(chain, 0)
+}
+
+func TestOutOfGas(t *testing.T) {
+	env := solo.New(t, false, false)
+	chain := env.NewChain(nil, "chain1")
+
+	req := solo.NewCallParams(blob.Interface.Name, blob.FuncStoreBlob,
+		blob.VarFieldVMType, "dummyType",
+		blob.VarFieldProgramBinary, "dummyBinary",
+	).WithGasBudget(10)
+	_, err := chain.PostRequest(req, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), coretypes.ErrOutOfGas.Error())
+
+	ret, err := chain.CallView(blob.Interface.Name, blob.FuncListBlobs)
+	require.NoError(t, err)
+	require.EqualValues(t, 0, len(ret))
+
+	_, err = chain.UploadBlob(nil,
+		blob.VarFieldVMType, "dummyType",
+		blob.VarFieldProgramBinary, "dummyBinary",
+	)
+	require.NoError(t, err)
+}
+
+func TestGasFee(t *testing.T) {
+	env := solo.New(t, false, false)
+	chain := env.NewChain(nil, "chain1")
+
+	// 1 fee token per 10 units of gas
+	req := solo.NewCallParams(root.Interface.Name, root.FuncSetDefaultFee, root.ParamGasPrice, 100)
+	_, err := chain.PostRequest(req, nil)
+	require.NoError(t, err)
+	checkGasPrice(chain, 100)
+	validatorBalance := chain.GetAccountBalance(chain.ValidatorFeeTarget).Balance(balance.ColorIOTA)
+
+	user := env.NewSignatureSchemeWithFunds()
+	userAgentID := coretypes.NewAgentIDFromAddress(user.Address())
+
+	// the transfer does not cover the gas budget: the request is not processed
+	req = solo.NewCallParams(accounts.Interface.Name, accounts.FuncDeposit).
+		WithTransfer(balance.ColorIOTA, 100).
+		WithGasBudget(2000)
+	_, err = chain.PostRequest(req, user)
+	require.Error(t, err)
+	chain.AssertAccountBalance(userAgentID, balance.ColorIOTA, 100+1)
+
+	// unused part of the gas budget is returned to the sender
+	req = solo.NewCallParams(accounts.Interface.Name, accounts.FuncDeposit).
+		WithTransfer(balance.ColorIOTA, 300).
+		WithGasBudget(2000)
+	_, err = chain.PostRequest(req, user)
+	require.NoError(t, err)
+	env.AssertAddressBalance(user.Address(), balance.ColorIOTA, testutil.RequestFundsAmount-400-2)
+
+	userBalance := chain.GetAccountBalance(userAgentID).Balance(balance.ColorIOTA)
+	gasFee := chain.GetAccountBalance(chain.ValidatorFeeTarget).Balance(balance.ColorIOTA) - validatorBalance
+	require.True(t, gasFee > 0)
+	require.EqualValues(t, 400+2, userBalance+gasFee)
+}
+
+func TestGasOfCalledView(t *testing.T) {
+	env := solo.New(t, false, false)
+	chain := env.NewChain(nil, "chain1")
+	err := chain.DeployContract(nil, gasViewName, gasViewContract.ProgramHash)
+	require.NoError(t, err)
+
+	// the gas of the view is charged to the request
+	_, err = chain.PostRequest(solo.NewCallParams(gasViewName, funcCallView).WithGasBudget(burningViewGas/2), nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), coretypes.ErrOutOfGas.Error())
+	_, err = chain.PostRequest(solo.NewCallParams(gasViewName, funcCallView).WithGasBudget(burningViewGas*2), nil)
+	require.NoError(t, err)
+
+	// the view called outside of the request is not charged
+	_, err = chain.CallView(gasViewName, funcBurningView)
+	require.NoError(t, err)
+}
@@ -0,0 +1,260 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package testcore
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address/signaturescheme"
+	"github.com/iotaledger/wasp/contracts/examples_core/inccounter"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/collections"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/solo"
+	"github.com/iotaledger/wasp/packages/util"
+	"github.com/iotaledger/wasp/packages/vm/core/governance"
+	"github.com/iotaledger/wasp/packages/vm/core/root"
+	"github.com/stretchr/testify/require"
+)
+
+func setGovernanceMembers(chain *solo.Chain, sigScheme signaturescheme.SignatureScheme, quorum int64, members map[coretypes.AgentID]int64) error {
+	par := dict.New()
+	m := collections.NewMap(par, governance.ParamMembers)
+	for agentID, weight := range members {
+		m.MustSetAt(agentID.Bytes(), codec.EncodeInt64(weight))
+	}
+	par.Set(governance.ParamQuorum, codec.EncodeInt64(quorum))
+	_, err := chain.PostRequest(solo.NewCallParamsFromDic(governance.Interface.Name, governance.FuncSetMembers, par), sigScheme)
+	return err
+}
+
+func propose(chain *solo.Chain, sigScheme signaturescheme.SignatureScheme, contract, funName string, params map[string]interface{}) (int64, error) {
+	args := codec.MakeDict(params)
+	ret, err := chain.PostRequest(solo.NewCallParams(governance.Interface.Name, governance.FuncPropose,
+		governance.ParamContractHname, coretypes.Hn(contract),
+		governance.ParamEntryPoint, coretypes.Hn(funName),
+		governance.ParamArgs, util.MustBytes(args),
+		governance.ParamExpiry, 60,
+	), sigScheme)
+	if err != nil {
+		return 0, err
+	}
+	id, _, err := codec.DecodeInt64(ret.MustGet(governance.ParamProposalID))
+	return id, err
+}
+
+func postProposal(chain *solo.Chain, sigScheme signaturescheme.SignatureScheme, funName string, id int64) error {
+	_, err := chain.PostRequest(solo.NewCallParams(governance.Interface.Name, funName, governance.ParamProposalID, id), sigScheme)
+	return err
+}
+
+func TestGovernance(t *testing.T) {
+	env := solo.New(t, false, false)
+	chain := env.NewChain(nil, "chain1")
+	govAgentID := coretypes.NewAgentIDFromContractID(governance.Interface.ContractID(chain.ChainID))
+	member1 := env.NewSignatureSchemeWithFunds()
+	member2 := env.NewSignatureSchemeWithFunds()
+	member3 := env.NewSignatureSchemeWithFunds()
+	user := env.NewSignatureSchemeWithFunds()
+	userAgentID := coretypes.NewAgentIDFromAddress(user.Address())
+
+	err := setGovernanceMembers(chain, nil, 3, map[coretypes.AgentID]int64{
+		coretypes.NewAgentIDFromAddress(member1.Address()): 1,
+		coretypes.NewAgentIDFromAddress(member2.Address()): 1,
+		coretypes.NewAgentIDFromAddress(member3.Address()): 2,
+	})
+	require.NoError(t, err)
+	ret, err := chain.CallView(governance.Interface.Name, governance.FuncGetMembers)
+	require.NoError(t, err)
+	members, err := governance.DecodeMembers(collections.NewMapReadOnly(ret, governance.ParamMembers))
+	require.NoError(t, err)
+	require.EqualValues(t, 3, len(members))
+
+	// the chain ownership must be delegated before enabling
+	_, err = chain.PostRequest(solo.NewCallParams(governance.Interface.Name, governance.FuncEnable), nil)
+	require.Error(t, err)
+	_, err = chain.PostRequest(solo.NewCallParams(root.Interface.Name, root.FuncDelegateChainOwnership,
+		root.ParamChainOwner, govAgentID), nil)
+	require.NoError(t, err)
+	_, err = chain.PostRequest(solo.NewCallParams(governance.Interface.Name, governance.FuncEnable), nil)
+	require.NoError(t, err)
+	info, _ := chain.GetInfo()
+	require.EqualValues(t, govAgentID, info.ChainOwnerID)
+
+	// the former chain owner is not authorized anymore
+	_, err = chain.PostRequest(solo.NewCallParams(root.Interface.Name, root.FuncGrantDeploy, root.ParamDeployer, userAgentID), nil)
+	require.Error(t, err)
+	require.Error(t, chain.DeployContract(user, incName, inccounter.Interface.ProgramHash))
+
+	// only members can propose
+	_, err = propose(chain, user, root.Interface.Name, root.FuncGrantDeploy,
+		map[string]interface{}{root.ParamDeployer: userAgentID})
+	require.Error(t, err)
+	id, err := propose(chain, member1, root.Interface.Name, root.FuncGrantDeploy,
+		map[string]interface{}{root.ParamDeployer: userAgentID})
+	require.NoError(t, err)
+
+	// not enough votes
+	require.Error(t, postProposal(chain, member1, governance.FuncExecute, id))
+	require.NoError(t, postProposal(chain, member2, governance.FuncVote, id))
+	require.Error(t, postProposal(chain, member2, governance.FuncVote, id))
+	require.Error(t, postProposal(chain, member2, governance.FuncExecute, id))
+	require.NoError(t, postProposal(chain, member3, governance.FuncVote, id))
+
+	require.Error(t, postProposal(chain, user, governance.FuncExecute, id))
+	require.NoError(t, postProposal(chain, member2, governance.FuncExecute, id))
+	require.Error(t, postProposal(chain, member2, governance.FuncExecute, id))
+	require.NoError(t, chain.DeployContract(user, incName, inccounter.Interface.ProgramHash))
+
+	ret, err = chain.CallView(governance.Interface.Name, governance.FuncGetProposal, governance.ParamProposalID, id)
+	require.NoError(t, err)
+	p, err := governance.DecodeProposalRecord(ret.MustGet(governance.ParamProposal))
+	require.NoError(t, err)
+	require.EqualValues(t, 4, p.Votes)
+	require.True(t, p.Executed)
+
+	// the ownership is transferred back by the proposal
+	id, err = propose(chain, member3, root.Interface.Name, root.FuncDelegateChainOwnership,
+		map[string]interface{}{root.ParamChainOwner: chain.OriginatorAgentID})
+	require.NoError(t, err)
+	require.NoError(t, postProposal(chain, member1, governance.FuncVote, id))
+	require.NoError(t, postProposal(chain, member1, governance.FuncExecute, id))
+	_, err = chain.PostRequest(solo.NewCallParams(root.Interface.Name, root.FuncClaimChainOwnership), nil)
+	require.NoError(t, err)
+	info, _ = chain.GetInfo()
+	require.EqualValues(t, chain.OriginatorAgentID, info.ChainOwnerID)
+
+	ret, err = chain.CallView(governance.Interface.Name, governance.FuncGetProposals)
+	require.NoError(t, err)
+	proposals, err := governance.DecodeProposalRecords(ret)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, len(proposals))
+	chain.CheckChain()
+}
+
+func TestGovernanceExpiry(t *testing.T) {
+	env := solo.New(t, false, false)
+	chain := env.NewChain(nil, "chain1")
+	member1 := env.NewSignatureSchemeWithFunds()
+	member2 := env.NewSignatureSchemeWithFunds()
+
+	// wrong quorum
+	err := setGovernanceMembers(chain, nil, 3, map[coretypes.AgentID]int64{
+		coretypes.NewAgentIDFromAddress(member1.Address()): 1,
+		coretypes.NewAgentIDFromAddress(member2.Address()): 1,
+	})
+	require.Error(t, err)
+	// not authorized
+	err = setGovernanceMembers(chain, member1, 2, map[coretypes.AgentID]int64{
+		coretypes.NewAgentIDFromAddress(member1.Address()): 1,
+		coretypes.NewAgentIDFromAddress(member2.Address()): 1,
+	})
+	require.Error(t, err)
+	err = setGovernanceMembers(chain, nil, 2, map[coretypes.AgentID]int64{
+		coretypes.NewAgentIDFromAddress(member1.Address()): 1,
+		coretypes.NewAgentIDFromAddress(member2.Address()): 1,
+	})
+	require.NoError(t, err)
+
+	id, err := propose(chain, member1, root.Interface.Name, root.FuncSetDefaultFee,
+		map[string]interface{}{root.ParamOwnerFee: 5})
+	require.NoError(t, err)
+	env.AdvanceClockBy(61 * time.Second)
+	require.Error(t, postProposal(chain, member2, governance.FuncVote, id))
+	chain.CheckChain()
+}
+
+func TestGovernanceMembersChange(t *testing.T) {
+	env := solo.New(t, false, false)
+	chain := env.NewChain(nil, "chain1")
+	member1 := env.NewSignatureSchemeWithFunds()
+	member2 := env.NewSignatureSchemeWithFunds()
+	member3 := env.NewSignatureSchemeWithFunds()
+
+	// the total weight overflows int64
+	err := setGovernanceMembers(chain, nil, 1, map[coretypes.AgentID]int64{
+		coretypes.NewAgentIDFromAddress(member1.Address()): math.MaxInt64,
+		coretypes.NewAgentIDFromAddress(member2.Address()): 1,
+	})
+	require.Error(t, err)
+	err = setGovernanceMembers(chain, nil, 2, map[coretypes.AgentID]int64{
+		coretypes.NewAgentIDFromAddress(member1.Address()): 1,
+		coretypes.NewAgentIDFromAddress(member2.Address()): 1,
+		coretypes.NewAgentIDFromAddress(member3.Address()): 1,
+	})
+	require.NoError(t, err)
+	require.NoError(t, chain.DeployContract(nil, incName, inccounter.Interface.ProgramHash))
+
+	id, err := propose(chain, member1, incName, inccounter.FuncIncCounter, nil)
+	require.NoError(t, err)
+
+	// the vote of the removed member doesn't count
+	err = setGovernanceMembers(chain, nil, 2, map[coretypes.AgentID]int64{
+		coretypes.NewAgentIDFromAddress(member2.Address()): 1,
+		coretypes.NewAgentIDFromAddress(member3.Address()): 1,
+	})
+	require.NoError(t, err)
+	require.NoError(t, postProposal(chain, member2, governance.FuncVote, id))
+	require.Error(t, postProposal(chain, member2, governance.FuncExecute, id))
+	require.NoError(t, postProposal(chain, member3, governance.FuncVote, id))
+	require.NoError(t, postProposal(chain, member3, governance.FuncExecute, id))
+
+	ret, err := chain.CallView(governance.Interface.Name, governance.FuncGetProposal, governance.ParamProposalID, id)
+	require.NoError(t, err)
+	p, err := governance.DecodeProposalRecord(ret.MustGet(governance.ParamProposal))
+	require.NoError(t, err)
+	require.EqualValues(t, 2, p.Votes)
+	require.True(t, p.Executed)
+	chain.CheckChain()
+}
+
+func TestGovernanceUpgrade(t *testing.T) {
+	env := solo.New(t, false, false)
+	chain := env.NewChain(nil, "chain1")
+	govAgentID := coretypes.NewAgentIDFromContractID(governance.Interface.ContractID(chain.ChainID))
+	member := env.NewSignatureSchemeWithFunds()
+	creator := env.NewSignatureSchemeWithFunds()
+
+	_, err := chain.PostRequest(solo.NewCallParams(root.Interface.Name, root.FuncGrantDeploy,
+		root.ParamDeployer, coretypes.NewAgentIDFromAddress(creator.Address())), nil)
+	require.NoError(t, err)
+	require.NoError(t, chain.DeployContract(creator, incName, inccounter.Interface.ProgramHash))
+	// the creator can upgrade the contract while the governance is not enabled
+	require.NoError(t, chain.UpgradeContract(creator, incName, incCounterV2.ProgramHash))
+
+	err = setGovernanceMembers(chain, nil, 1, map[coretypes.AgentID]int64{
+		coretypes.NewAgentIDFromAddress(member.Address()): 1,
+	})
+	require.NoError(t, err)
+	_, err = chain.PostRequest(solo.NewCallParams(root.Interface.Name, root.FuncDelegateChainOwnership,
+		root.ParamChainOwner, govAgentID), nil)
+	require.NoError(t, err)
+	_, err = chain.PostRequest(solo.NewCallParams(governance.Interface.Name, governance.FuncEnable), nil)
+	require.NoError(t, err)
+
+	// only the proposal can upgrade the contract
+	require.Error(t, chain.UpgradeContract(creator, incName, inccounter.Interface.ProgramHash))
+	require.Error(t, chain.UpgradeContract(nil, incName, inccounter.Interface.ProgramHash))
+	id, err := propose(chain, member, root.Interface.Name, root.FuncUpgradeContract, map[string]interface{}{
+		root.ParamHname:       coretypes.Hn(incName),
+		root.ParamProgramHash: inccounter.Interface.ProgramHash,
+	})
+	require.NoError(t, err)
+	require.NoError(t, postProposal(chain, member, governance.FuncExecute, id))
+	rec, err := chain.FindContract(incName)
+	require.NoError(t, err)
+	require.EqualValues(t, inccounter.Interface.ProgramHash, rec.ProgramHash)
+
+	// the expiry of the proposal is limited
+	_, err = chain.PostRequest(solo.NewCallParams(governance.Interface.Name, governance.FuncPropose,
+		governance.ParamContractHname, root.Interface.Hname(),
+		governance.ParamEntryPoint, coretypes.Hn(root.FuncSetDefaultFee),
+		governance.ParamExpiry, int64(math.MaxInt64/2),
+	), member)
+	require.Error(t, err)
+	chain.CheckChain()
+}
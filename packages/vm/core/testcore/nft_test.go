@@ -0,0 +1,273 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package testcore
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	"github.com/iotaledger/wasp/contracts"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/coretypes/cbalances"
+	"github.com/iotaledger/wasp/packages/coretypes/coreutil"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/kv/kvdecoder"
+	"github.com/iotaledger/wasp/packages/solo"
+	"github.com/iotaledger/wasp/packages/testutil"
+	"github.com/iotaledger/wasp/packages/vm/core/accounts"
+	"github.com/iotaledger/wasp/packages/vm/core/nft"
+	"github.com/iotaledger/wasp/packages/vm/core/testcore/sandbox_tests/test_sandbox_sc"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	holderName = "nftHolder"
+	funcPull   = "pull"
+	funcGive   = "give"
+)
+
+// nftHolder pulls its NFTs from another chain and gives NFTs to other agents
+var nftHolder = &coreutil.ContractInterface{
+	Name:        holderName,
+	Description: "Moves NFTs across chains",
+	ProgramHash: hashing.HashStrings(holderName),
+}
+
+func init() {
+	nftHolder.WithFunctions(func(ctx coretypes.Sandbox) (dict.Dict, error) {
+		return nil, nil
+	}, []coreutil.ContractFunctionInterface{
+		coreutil.Func(funcPull, func(ctx coretypes.Sandbox) (dict.Dict, error) {
+			params := kvdecoder.New(ctx.Params(), ctx.Log())
+			if !ctx.PostRequest(coretypes.PostRequestParams{
+				TargetContractID: accounts.Interface.ContractID(params.MustGetChainID(accounts.ParamChainID)),
+				EntryPoint:       coretypes.Hn(accounts.FuncWithdrawToChain),
+				Transfer: cbalances.NewFromMap(map[balance.Color]int64{
+					balance.ColorIOTA: 2,
+				}),
+			}) {
+				return nil, fmt.Errorf("failed to post request")
+			}
+			return nil, nil
+		}),
+		coreutil.Func(funcGive, func(ctx coretypes.Sandbox) (dict.Dict, error) {
+			return ctx.Call(nft.Interface.Hname(), coretypes.Hn(nft.FuncTransfer), ctx.Params(), nil)
+		}),
+	})
+	contracts.AddExampleProcessor(nftHolder)
+}
+
+func checkNFTs(t *testing.T, chain *solo.Chain, agentID coretypes.AgentID, ids ...balance.Color) {
+	nfts, err := chain.GetNFTs(agentID)
+	require.NoError(t, err)
+	require.EqualValues(t, len(ids), len(nfts))
+	for _, id := range ids {
+		found := false
+		for _, n := range nfts {
+			if n.ID == id {
+				found = true
+			}
+		}
+		require.True(t, found)
+	}
+}
+
+func TestMintNFT(t *testing.T) {
+	env := solo.New(t, false, false)
+	chain := env.NewChain(nil, "chain1")
+	creator := env.NewSignatureSchemeWithFunds()
+	creatorAgentID := coretypes.NewAgentIDFromAddress(creator.Address())
+
+	id1, err := chain.MintNFT(creator, "name", "Mona Lisa", "artist", "Leonardo")
+	require.NoError(t, err)
+	id2, err := chain.MintNFT(creator, "name", "The Last Supper", "artist", "Leonardo")
+	require.NoError(t, err)
+	require.NotEqual(t, id1, id2)
+	require.EqualValues(t, nft.NFTID(chain.ChainID, 0), id1)
+
+	n, err := chain.GetNFT(id1)
+	require.NoError(t, err)
+	require.EqualValues(t, id1, n.ID)
+	require.EqualValues(t, creatorAgentID, n.Creator)
+	require.EqualValues(t, chain.ChainID, n.Origin)
+	info, ok := chain.GetBlobInfo(n.Metadata)
+	require.True(t, ok)
+	require.EqualValues(t, 2, len(info))
+
+	checkNFTs(t, chain, creatorAgentID, id1, id2)
+	chain.AssertAccountBalance(creatorAgentID, id1, 1)
+
+	// the metadata blob must exist
+	req := solo.NewCallParams(nft.Interface.Name, nft.FuncMint, nft.ParamMetadata, hashing.RandomHash(nil))
+	_, err = chain.PostRequest(req, creator)
+	require.Error(t, err)
+	chain.CheckChain()
+}
+
+func TestTransferNFT(t *testing.T) {
+	env := solo.New(t, false, false)
+	chain := env.NewChain(nil, "chain1")
+	owner := env.NewSignatureSchemeWithFunds()
+	ownerAgentID := coretypes.NewAgentIDFromAddress(owner.Address())
+	user := env.NewSignatureSchemeWithFunds()
+	userAgentID := coretypes.NewAgentIDFromAddress(user.Address())
+
+	id, err := chain.MintNFT(owner, "name", "Mona Lisa")
+	require.NoError(t, err)
+
+	// only the owner can transfer
+	req := solo.NewCallParams(nft.Interface.Name, nft.FuncTransfer,
+		nft.ParamID, id, nft.ParamAgentID, ownerAgentID, nft.ParamTarget, userAgentID)
+	_, err = chain.PostRequest(req, user)
+	require.Error(t, err)
+
+	req = solo.NewCallParams(nft.Interface.Name, nft.FuncApprove, nft.ParamID, id, nft.ParamSpender, userAgentID)
+	_, err = chain.PostRequest(req, user)
+	require.Error(t, err)
+	_, err = chain.PostRequest(req, owner)
+	require.NoError(t, err)
+
+	ret, err := chain.CallView(nft.Interface.Name, nft.FuncGetApproved, nft.ParamID, id)
+	require.NoError(t, err)
+	spender, _, err := codec.DecodeAgentID(ret.MustGet(nft.ParamSpender))
+	require.NoError(t, err)
+	require.EqualValues(t, userAgentID, spender)
+
+	// the approved spender transfers the NFT to itself
+	req = solo.NewCallParams(nft.Interface.Name, nft.FuncTransfer,
+		nft.ParamID, id, nft.ParamAgentID, ownerAgentID, nft.ParamTarget, userAgentID)
+	_, err = chain.PostRequest(req, user)
+	require.NoError(t, err)
+	checkNFTs(t, chain, ownerAgentID)
+	checkNFTs(t, chain, userAgentID, id)
+
+	// the approval is revoked by the transfer
+	ret, err = chain.CallView(nft.Interface.Name, nft.FuncGetApproved, nft.ParamID, id)
+	require.NoError(t, err)
+	require.EqualValues(t, 0, len(ret))
+
+	// only the owner can burn
+	req = solo.NewCallParams(nft.Interface.Name, nft.FuncBurn, nft.ParamID, id)
+	_, err = chain.PostRequest(req, owner)
+	require.Error(t, err)
+	_, err = chain.PostRequest(req, user)
+	require.NoError(t, err)
+	checkNFTs(t, chain, userAgentID)
+	_, err = chain.GetNFT(id)
+	require.Error(t, err)
+	chain.CheckChain()
+}
+
+func TestNFTStaysOnChain(t *testing.T) {
+	env := solo.New(t, false, false)
+	chain := env.NewChain(nil, "chain1")
+	owner := env.NewSignatureSchemeWithFunds()
+	ownerAgentID := coretypes.NewAgentIDFromAddress(owner.Address())
+
+	id, err := chain.MintNFT(owner, "name", "Mona Lisa")
+	require.NoError(t, err)
+
+	_, err = chain.PostRequest(solo.NewCallParams(accounts.Interface.Name, accounts.FuncWithdrawToAddress), owner)
+	require.NoError(t, err)
+	checkNFTs(t, chain, ownerAgentID, id)
+	env.AssertAddressBalance(owner.Address(), balance.ColorIOTA, testutil.RequestFundsAmount)
+	env.AssertAddressBalance(owner.Address(), id, 0)
+	chain.CheckChain()
+}
+
+func TestNFTWithdrawToChain(t *testing.T) {
+	env := solo.New(t, false, false)
+	chain1 := env.NewChain(nil, "chain1")
+	chain2 := env.NewChain(nil, "chain2")
+	require.NoError(t, chain2.DeployContract(nil, test_sandbox_sc.Interface.Name, test_sandbox_sc.Interface.ProgramHash))
+	contractAgentID := coretypes.NewAgentIDFromContractID(coretypes.NewContractID(chain2.ChainID, test_sandbox_sc.Interface.Hname()))
+	user := env.NewSignatureSchemeWithFunds()
+
+	// the NFT is minted on chain1 to the account of the contract on chain2
+	metadata, err := chain1.UploadBlob(user, "name", "Mona Lisa")
+	require.NoError(t, err)
+	req := solo.NewCallParams(nft.Interface.Name, nft.FuncMint, nft.ParamMetadata, metadata, nft.ParamAgentID, contractAgentID)
+	ret, err := chain1.PostRequest(req, user)
+	require.NoError(t, err)
+	id, _, err := codec.DecodeColor(ret.MustGet(nft.ParamID))
+	require.NoError(t, err)
+	checkNFTs(t, chain1, contractAgentID, id)
+
+	req = solo.NewCallParams(test_sandbox_sc.Name, test_sandbox_sc.FuncWithdrawToChain,
+		test_sandbox_sc.ParamChainID, chain1.ChainID,
+	).WithTransfer(balance.ColorIOTA, 3)
+	_, err = chain2.PostRequest(req, user)
+	require.NoError(t, err)
+
+	chain1.WaitForEmptyBacklog()
+	chain2.WaitForEmptyBacklog()
+
+	// the NFT with the record is moved to chain2
+	checkNFTs(t, chain1, contractAgentID)
+	_, err = chain1.GetNFT(id)
+	require.Error(t, err)
+	checkNFTs(t, chain2, contractAgentID, id)
+	n, err := chain2.GetNFT(id)
+	require.NoError(t, err)
+	require.EqualValues(t, chain1.ChainID, n.Origin)
+	require.EqualValues(t, metadata, n.Metadata)
+
+	chain1.CheckChain()
+	chain2.CheckChain()
+}
+
+func TestNFTMultiHop(t *testing.T) {
+	env := solo.New(t, false, false)
+	chains := []*solo.Chain{env.NewChain(nil, "origin"), env.NewChain(nil, "chainC"), env.NewChain(nil, "chainD")}
+	holders := make([]coretypes.AgentID, len(chains))
+	for i, ch := range chains {
+		require.NoError(t, ch.DeployContract(nil, holderName, nftHolder.ProgramHash))
+		holders[i] = coretypes.NewAgentIDFromContractID(coretypes.NewContractID(ch.ChainID, nftHolder.Hname()))
+	}
+	origin := chains[0]
+	user := env.NewSignatureSchemeWithFunds()
+
+	metadata, err := origin.UploadBlob(user, "name", "Mona Lisa")
+	require.NoError(t, err)
+	req := solo.NewCallParams(nft.Interface.Name, nft.FuncMint, nft.ParamMetadata, metadata, nft.ParamAgentID, holders[1])
+	ret, err := origin.PostRequest(req, user)
+	require.NoError(t, err)
+	id, _, err := codec.DecodeColor(ret.MustGet(nft.ParamID))
+	require.NoError(t, err)
+
+	// the holder on the next chain pulls the NFT from the previous one and gives it to the holder on the next chain:
+	// origin -> C -> D -> origin
+	for hop := 1; hop <= len(chains); hop++ {
+		from, to := chains[hop-1], chains[hop%len(chains)]
+		req = solo.NewCallParams(holderName, funcPull, accounts.ParamChainID, from.ChainID).
+			WithTransfer(balance.ColorIOTA, 3)
+		_, err = to.PostRequest(req, user)
+		require.NoError(t, err)
+		from.WaitForEmptyBacklog()
+		to.WaitForEmptyBacklog()
+
+		holder := holders[hop%len(chains)]
+		checkNFTs(t, from, holder)
+		_, err = from.GetNFT(id)
+		require.Error(t, err)
+		checkNFTs(t, to, holder, id)
+		n, err := to.GetNFT(id)
+		require.NoError(t, err)
+		require.EqualValues(t, origin.ChainID, n.Origin)
+		require.EqualValues(t, 0, n.Counter)
+		require.EqualValues(t, metadata, n.Metadata)
+
+		if hop < len(chains) {
+			req = solo.NewCallParams(holderName, funcGive, nft.ParamID, id, nft.ParamTarget, holders[(hop+1)%len(chains)])
+			_, err = to.PostRequest(req, user)
+			require.NoError(t, err)
+		}
+	}
+	for _, ch := range chains {
+		ch.CheckChain()
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package testcore
+
+import (
+	"testing"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	"github.com/iotaledger/wasp/contracts/examples_core/inccounter"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/solo"
+	"github.com/iotaledger/wasp/packages/testutil"
+	"github.com/iotaledger/wasp/packages/vm/core/accounts"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPauseContract(t *testing.T) {
+	env := solo.New(t, false, false)
+	chain := env.NewChain(nil, "chain1")
+	defer chain.WaitForEmptyBacklog()
+
+	err := chain.DeployContract(nil, incName, inccounter.Interface.ProgramHash)
+	require.NoError(t, err)
+
+	err = chain.PauseContract(nil, incName)
+	require.NoError(t, err)
+	rec, err := chain.FindContract(incName)
+	require.NoError(t, err)
+	require.True(t, rec.Paused)
+
+	// request to the paused contract is refunded
+	user := env.NewSignatureSchemeWithFunds()
+	userAgentID := coretypes.NewAgentIDFromAddress(user.Address())
+	req := solo.NewCallParams(incName, inccounter.FuncIncCounter).WithTransfer(balance.ColorIOTA, 42)
+	_, err = chain.PostRequest(req, user)
+	require.Error(t, err)
+	checkCounter(t, chain, 0)
+	env.AssertAddressBalance(user.Address(), balance.ColorIOTA, testutil.RequestFundsAmount-1)
+	chain.AssertAccountBalance(userAgentID, balance.ColorIOTA, 1)
+
+	// pausing twice fails
+	err = chain.PauseContract(nil, incName)
+	require.Error(t, err)
+
+	err = chain.UnpauseContract(nil, incName)
+	require.NoError(t, err)
+	rec, err = chain.FindContract(incName)
+	require.NoError(t, err)
+	require.False(t, rec.Paused)
+
+	_, err = chain.PostRequest(solo.NewCallParams(incName, inccounter.FuncIncCounter), user)
+	require.NoError(t, err)
+	checkCounter(t, chain, 1)
+	chain.CheckChain()
+}
+
+func TestPauseContractFail(t *testing.T) {
+	env := solo.New(t, false, false)
+	chain := env.NewChain(nil, "chain1")
+	defer chain.WaitForEmptyBacklog()
+
+	err := chain.DeployContract(nil, incName, inccounter.Interface.ProgramHash)
+	require.NoError(t, err)
+
+	// not authorized
+	user := env.NewSignatureSchemeWithFunds()
+	err = chain.PauseContract(user, incName)
+	require.Error(t, err)
+
+	// core contract
+	err = chain.PauseContract(nil, accounts.Interface.Name)
+	require.Error(t, err)
+
+	// unknown contract
+	err = chain.PauseContract(nil, "dummy")
+	require.Error(t, err)
+
+	rec, err := chain.FindContract(incName)
+	require.NoError(t, err)
+	require.False(t, rec.Paused)
+}
+
+func TestRemoveContract(t *testing.T) {
+	env := solo.New(t, false, false)
+	chain := env.NewChain(nil, "chain1")
+	defer chain.WaitForEmptyBacklog()
+
+	err := chain.DeployContract(nil, incName, inccounter.Interface.ProgramHash)
+	require.NoError(t, err)
+
+	// transfer to the contract is accrued to its account
+	user := env.NewSignatureSchemeWithFunds()
+	req := solo.NewCallParams(incName, inccounter.FuncIncCounter).WithTransfer(balance.ColorIOTA, 42)
+	_, err = chain.PostRequest(req, user)
+	require.NoError(t, err)
+	contractAgentID := coretypes.NewAgentIDFromContractID(coretypes.NewContractID(chain.ChainID, coretypes.Hn(incName)))
+	chain.AssertAccountBalance(contractAgentID, balance.ColorIOTA, 42)
+
+	// not authorized
+	err = chain.RemoveContract(user, incName)
+	require.Error(t, err)
+
+	// core contract
+	err = chain.RemoveContract(nil, accounts.Interface.Name)
+	require.Error(t, err)
+
+	// funds of the contract are swept to the target
+	target := coretypes.NewAgentIDFromAddress(env.NewSignatureScheme().Address())
+	err = chain.RemoveContract(nil, incName, target)
+	require.NoError(t, err)
+	chain.AssertAccountBalance(contractAgentID, balance.ColorIOTA, 0)
+	chain.AssertAccountBalance(target, balance.ColorIOTA, 42)
+
+	_, err = chain.FindContract(incName)
+	require.Error(t, err)
+	_, contracts := chain.GetInfo()
+	require.EqualValues(t, 8, len(contracts))
+
+	_, err = chain.PostRequest(solo.NewCallParams(incName, inccounter.FuncIncCounter), user)
+	require.Error(t, err)
+
+	// the name of the removed contract can't be reused
+	err = chain.DeployContract(nil, incName, inccounter.Interface.ProgramHash)
+	require.Error(t, err)
+	chain.CheckChain()
+}
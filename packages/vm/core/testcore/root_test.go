@@ -4,11 +4,13 @@
 package testcore
 
 import (
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
 	"github.com/iotaledger/wasp/packages/vm/core/testcore/sandbox_tests/test_sandbox_sc"
 	"testing"
 
 	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/solo"
+	"github.com/iotaledger/wasp/packages/testutil"
 	"github.com/iotaledger/wasp/packages/vm/core/accounts"
 	"github.com/iotaledger/wasp/packages/vm/core/blob"
 	"github.com/iotaledger/wasp/packages/vm/core/root"
@@ -47,7 +49,7 @@ func TestGetInfo(t *testing.T) {
 	require.EqualValues(t, chain.ChainColor, info.ChainColor)
 	require.EqualValues(t, chain.ChainAddress, info.ChainAddress)
 	require.EqualValues(t, chain.OriginatorAgentID, info.ChainOwnerID)
-	require.EqualValues(t, 4, len(contracts))
+	require.EqualValues(t, 8, len(contracts))
 
 	_, ok := contracts[root.Interface.Hname()]
 	require.True(t, ok)
@@ -74,7 +76,7 @@ func TestDeployExample(t *testing.T) {
 
 	require.EqualValues(t, chain.ChainID, info.ChainID)
 	require.EqualValues(t, chain.OriginatorAgentID, info.ChainOwnerID)
-	require.EqualValues(t, 5, len(contracts))
+	require.EqualValues(t, 9, len(contracts))
 
 	_, ok := contracts[root.Interface.Hname()]
 	require.True(t, ok)
@@ -113,7 +115,7 @@ func TestDeployDouble(t *testing.T) {
 
 	require.EqualValues(t, chain.ChainID, info.ChainID)
 	require.EqualValues(t, chain.OriginatorAgentID, info.ChainOwnerID)
-	require.EqualValues(t, 5, len(contracts))
+	require.EqualValues(t, 9, len(contracts))
 
 	_, ok := contracts[root.Interface.Hname()]
 	require.True(t, ok)
@@ -168,3 +170,91 @@ func TestChangeOwnerUnauthorized(t *testing.T) {
 	info, _ := chain.GetInfo()
 	require.EqualValues(t, chain.OriginatorAgentID, info.ChainOwnerID)
 }
+
+func TestRotateCommittee(t *testing.T) {
+	env := solo.New(t, false, false)
+	chain := env.NewChain(nil, "chain1")
+	defer chain.WaitForEmptyBacklog()
+
+	oldAddress := chain.ChainAddress
+	env.AssertAddressBalance(oldAddress, chain.ChainColor, 1)
+	oldBalance := env.GetAddressBalance(oldAddress, balance.ColorIOTA)
+
+	newCommittee := env.NewSignatureScheme()
+	err := chain.RotateCommittee(nil, newCommittee)
+	require.NoError(t, err)
+	require.EqualValues(t, newCommittee.Address(), chain.ChainAddress)
+
+	info, _ := chain.GetInfo()
+	require.EqualValues(t, chain.ChainID, info.ChainID)
+	require.EqualValues(t, newCommittee.Address(), info.ChainAddress)
+
+	env.AssertAddressBalance(oldAddress, chain.ChainColor, 0)
+	env.AssertAddressBalance(oldAddress, balance.ColorIOTA, 0)
+	env.AssertAddressBalance(newCommittee.Address(), chain.ChainColor, 1)
+	// the request token of the rotation request is added to the balance of the chain
+	env.AssertAddressBalance(newCommittee.Address(), balance.ColorIOTA, oldBalance+1)
+
+	// the chain keeps working with the new committee
+	_, err = chain.UploadBlob(nil, "field", "value")
+	require.NoError(t, err)
+	chain.CheckChain()
+}
+
+func TestRotateCommitteeUnauthorized(t *testing.T) {
+	env := solo.New(t, false, false)
+	chain := env.NewChain(nil, "chain1")
+	defer chain.WaitForEmptyBacklog()
+
+	oldAddress := chain.ChainAddress
+	user := env.NewSignatureSchemeWithFunds()
+	err := chain.RotateCommittee(user, env.NewSignatureScheme())
+	require.Error(t, err)
+	require.EqualValues(t, oldAddress, chain.ChainAddress)
+
+	info, _ := chain.GetInfo()
+	require.EqualValues(t, oldAddress, info.ChainAddress)
+	env.AssertAddressBalance(oldAddress, chain.ChainColor, 1)
+}
+
+func TestStealthRequest(t *testing.T) {
+	env := solo.New(t, false, false)
+	chain := env.NewChain(nil, "chain1")
+	defer chain.WaitForEmptyBacklog()
+
+	newOwner := env.NewSignatureSchemeWithFunds()
+	newOwnerAgentID := coretypes.NewAgentIDFromAddress(newOwner.Address())
+	req := solo.NewCallParams(root.Interface.Name, root.FuncDelegateChainOwnership, root.ParamChainOwner, newOwnerAgentID).
+		WithStealth(chain.StealthKeys())
+	_, err := chain.PostRequest(req, nil)
+	require.NoError(t, err)
+
+	req = solo.NewCallParams(root.Interface.Name, root.FuncClaimChainOwnership)
+	_, err = chain.PostRequest(req, newOwner)
+	require.NoError(t, err)
+
+	info, _ := chain.GetInfo()
+	require.EqualValues(t, newOwnerAgentID, info.ChainOwnerID)
+}
+
+func TestStealthRequestWrongKeys(t *testing.T) {
+	env := solo.New(t, false, false)
+	chain := env.NewChain(nil, "chain1")
+	chain2 := env.NewChain(nil, "chain2")
+	defer chain.WaitForEmptyBacklog()
+
+	newOwner := env.NewSignatureSchemeWithFunds()
+	newOwnerAgentID := coretypes.NewAgentIDFromAddress(newOwner.Address())
+	// the committee can't decrypt the arguments: the request is processed as failed and the transfer is refunded
+	user := env.NewSignatureSchemeWithFunds()
+	req := solo.NewCallParams(root.Interface.Name, root.FuncDelegateChainOwnership, root.ParamChainOwner, newOwnerAgentID).
+		WithStealth(chain2.StealthKeys()).
+		WithTransfer(balance.ColorIOTA, 42)
+	_, err := chain.PostRequest(req, user)
+	require.Error(t, err)
+	env.AssertAddressBalance(user.Address(), balance.ColorIOTA, testutil.RequestFundsAmount-1)
+	chain.AssertAccountBalance(coretypes.NewAgentIDFromAddress(user.Address()), balance.ColorIOTA, 1)
+
+	info, _ := chain.GetInfo()
+	require.EqualValues(t, chain.OriginatorAgentID, info.ChainOwnerID)
+}
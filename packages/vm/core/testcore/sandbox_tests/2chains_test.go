@@ -1,6 +1,7 @@
 package sandbox_tests
 
 import (
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
 	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/solo"
@@ -82,3 +83,63 @@ func test2Chains(t *testing.T, w bool) {
 	chain2.AssertAccountBalance(accountsAgentID1, balance.ColorIOTA, 1) // !!!! TODO
 	chain2.AssertAccountBalance(accountsAgentID2, balance.ColorIOTA, 0)
 }
+
+func Test2ChainsRotated(t *testing.T) { run2(t, test2ChainsRotated) }
+func test2ChainsRotated(t *testing.T, w bool) {
+	env := solo.New(t, false, false)
+	chain1 := env.NewChain(nil, "ch1")
+	chain2 := env.NewChain(nil, "ch2")
+
+	contractID2, _ := setupTestSandboxSC(t, chain2, nil, w)
+	contractAgentID2 := coretypes.NewAgentIDFromContractID(contractID2)
+
+	userWallet := env.NewSignatureSchemeWithFunds()
+	userAddress := userWallet.Address()
+	userAgentID := coretypes.NewAgentIDFromAddress(userAddress)
+
+	req := solo.NewCallParams(accounts.Interface.Name, accounts.FuncDeposit,
+		accounts.ParamAgentID, contractAgentID2,
+	).WithTransfer(
+		balance.ColorIOTA, 42,
+	)
+	_, err := chain1.PostRequest(req, userWallet)
+	require.NoError(t, err)
+
+	// both chains move to new committees. Chains keep sending requests to each other to the chain ID address
+	err = chain1.RotateCommittee(nil, env.NewSignatureScheme())
+	require.NoError(t, err)
+	err = chain2.RotateCommittee(nil, env.NewSignatureScheme())
+	require.NoError(t, err)
+	require.NotEqualValues(t, address.Address(chain1.ChainID), chain1.ChainAddress)
+	require.NotEqualValues(t, address.Address(chain2.ChainID), chain2.ChainAddress)
+	chain1Balance := env.GetAddressBalance(chain1.ChainAddress, balance.ColorIOTA)
+	chain2Balance := env.GetAddressBalance(chain2.ChainAddress, balance.ColorIOTA)
+
+	req = solo.NewCallParams(test_sandbox_sc.Name, test_sandbox_sc.FuncWithdrawToChain,
+		test_sandbox_sc.ParamChainID, chain1.ChainID,
+	).WithTransfer(
+		balance.ColorIOTA, 3,
+	)
+	_, err = chain2.PostRequest(req, userWallet)
+	require.NoError(t, err)
+
+	chain1.WaitForEmptyBacklog()
+	chain2.WaitForEmptyBacklog()
+
+	env.AssertAddressBalance(userAddress, balance.ColorIOTA, solo.Supply-47)
+	chain1.AssertAccountBalance(userAgentID, balance.ColorIOTA, 1)
+	chain2.AssertAccountBalance(userAgentID, balance.ColorIOTA, 1)
+	chain1.AssertAccountBalance(contractAgentID2, balance.ColorIOTA, 0)
+	chain2.AssertAccountBalance(contractAgentID2, balance.ColorIOTA, 43)
+
+	// the requests sent to the chain ID addresses have been swept to the current committees
+	env.AssertAddressBalance(address.Address(chain1.ChainID), balance.ColorIOTA, 0)
+	env.AssertAddressBalance(address.Address(chain2.ChainID), balance.ColorIOTA, 0)
+	// chain2 sends 2 iotas with the request token to chain1, chain1 returns 43 iotas with the request token
+	env.AssertAddressBalance(chain1.ChainAddress, balance.ColorIOTA, chain1Balance+3-44)
+	env.AssertAddressBalance(chain2.ChainAddress, balance.ColorIOTA, chain2Balance+4-3+44)
+	chain1.CheckChain()
+	chain2.CheckChain()
+	chain1.CheckAccountLedger()
+	chain2.CheckAccountLedger()
+}
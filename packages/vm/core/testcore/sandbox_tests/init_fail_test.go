@@ -29,11 +29,11 @@ func TestFailRepeat(t *testing.T) {
 		test_sandbox_sc.ParamFail, 1)
 	require.Error(t, err)
 	_, rec := chain.GetInfo()
-	require.EqualValues(t, 4, len(rec))
+	require.EqualValues(t, 8, len(rec))
 
 	// repeat must succeed
 	err = chain.DeployContract(nil, test_sandbox_sc.Name, test_sandbox_sc.Interface.ProgramHash)
 	require.NoError(t, err)
 	_, rec = chain.GetInfo()
-	require.EqualValues(t, 5, len(rec))
+	require.EqualValues(t, 9, len(rec))
 }
@@ -0,0 +1,244 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package testcore
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iotaledger/wasp/contracts"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/coretypes/coreutil"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/solo"
+	"github.com/iotaledger/wasp/packages/vm/core/accounts"
+	"github.com/iotaledger/wasp/packages/vm/core/root"
+	"github.com/iotaledger/wasp/packages/vm/core/scheduler"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	schedName = "schedTest"
+
+	funcSchedule   = "schedule"
+	funcUnschedule = "unschedule"
+	funcTick       = "tick"
+	funcGetCounter = "getCounter"
+
+	varCounter = "counter"
+)
+
+// schedulerTester schedules its 'tick' entry point with the 'scheduler' and counts the runs
+var schedulerTester = &coreutil.ContractInterface{
+	Name:        "schedulerTester",
+	Description: "Scheduled jobs",
+	ProgramHash: hashing.HashStrings("schedulerTester"),
+}
+
+func init() {
+	schedulerTester.WithFunctions(func(ctx coretypes.Sandbox) (dict.Dict, error) {
+		return nil, nil
+	}, []coreutil.ContractFunctionInterface{
+		coreutil.Func(funcSchedule, func(ctx coretypes.Sandbox) (dict.Dict, error) {
+			par := ctx.Params().Clone()
+			par.Set(scheduler.ParamEntryPoint, codec.EncodeHname(coretypes.Hn(funcTick)))
+			return ctx.Call(scheduler.Interface.Hname(), coretypes.Hn(scheduler.FuncAddJob), par, nil)
+		}),
+		coreutil.Func(funcUnschedule, func(ctx coretypes.Sandbox) (dict.Dict, error) {
+			return ctx.Call(scheduler.Interface.Hname(), coretypes.Hn(scheduler.FuncRemoveJob), ctx.Params(), nil)
+		}),
+		coreutil.Func(funcTick, func(ctx coretypes.Sandbox) (dict.Dict, error) {
+			if ctx.Caller() != coretypes.NewAgentIDFromContractID(scheduler.Interface.ContractID(ctx.ContractID().ChainID())) {
+				return nil, fmt.Errorf("tick must be called by the scheduler")
+			}
+			counter, _, _ := codec.DecodeInt64(ctx.State().MustGet(varCounter))
+			ctx.State().Set(varCounter, codec.EncodeInt64(counter+1))
+			return nil, nil
+		}),
+		coreutil.ViewFunc(funcGetCounter, func(ctx coretypes.SandboxView) (dict.Dict, error) {
+			ret := dict.New()
+			counter, _, _ := codec.DecodeInt64(ctx.State().MustGet(varCounter))
+			ret.Set(varCounter, codec.EncodeInt64(counter))
+			return ret, nil
+		}),
+	})
+	contracts.AddExampleProcessor(schedulerTester)
+}
+
+func setupScheduler(t *testing.T) (*solo.Solo, *solo.Chain) {
+	env := solo.New(t, false, false)
+	chain := env.NewChain(nil, "chain1")
+	require.NoError(t, chain.DeployContract(nil, schedName, schedulerTester.ProgramHash))
+	return env, chain
+}
+
+func scheduleTick(chain *solo.Chain, params ...interface{}) (uint32, error) {
+	ret, err := chain.PostRequest(solo.NewCallParams(schedName, funcSchedule, params...), nil)
+	if err != nil {
+		return 0, err
+	}
+	id, _, err := codec.DecodeInt64(ret.MustGet(scheduler.ParamJobID))
+	return uint32(id), err
+}
+
+func checkTickCounter(t *testing.T, chain *solo.Chain, expected int64) {
+	ret, err := chain.CallView(schedName, funcGetCounter)
+	require.NoError(t, err)
+	counter, _, err := codec.DecodeInt64(ret.MustGet(varCounter))
+	require.NoError(t, err)
+	require.EqualValues(t, expected, counter)
+}
+
+func TestSchedulerInterval(t *testing.T) {
+	env, chain := setupScheduler(t)
+
+	id, err := scheduleTick(chain, scheduler.ParamInterval, 10, scheduler.ParamMaxRuns, 3)
+	require.NoError(t, err)
+	jobs, err := chain.GetScheduledJobs(schedName)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, len(jobs))
+	require.EqualValues(t, id, jobs[0].ID)
+	require.EqualValues(t, coretypes.Hn(funcTick), jobs[0].EntryPoint)
+
+	// not due yet
+	require.False(t, chain.RunScheduledJobs())
+	checkTickCounter(t, chain, 0)
+
+	env.AdvanceClockBy(11 * time.Second)
+	require.True(t, chain.RunScheduledJobs())
+	checkTickCounter(t, chain, 1)
+
+	// missed runs are skipped
+	env.AdvanceClockBy(35 * time.Second)
+	require.True(t, chain.RunScheduledJobs())
+	require.False(t, chain.RunScheduledJobs())
+	checkTickCounter(t, chain, 2)
+
+	// the job is removed after max runs
+	env.AdvanceClockBy(11 * time.Second)
+	require.True(t, chain.RunScheduledJobs())
+	checkTickCounter(t, chain, 3)
+	jobs, err = chain.GetScheduledJobs()
+	require.NoError(t, err)
+	require.EqualValues(t, 0, len(jobs))
+
+	recs, err := chain.GetEventLogRecordsString(scheduler.Interface.Name)
+	require.NoError(t, err)
+	require.EqualValues(t, 3, strings.Count(recs, fmt.Sprintf("[job] #%d", id)))
+	chain.CheckChain()
+}
+
+func TestSchedulerRunsWithRequests(t *testing.T) {
+	env, chain := setupScheduler(t)
+
+	_, err := scheduleTick(chain, scheduler.ParamInterval, 10)
+	require.NoError(t, err)
+
+	// due jobs are run at the beginning of the block with requests
+	env.AdvanceClockBy(11 * time.Second)
+	_, err = chain.PostRequest(solo.NewCallParams(accounts.Interface.Name, accounts.FuncDeposit), nil)
+	require.NoError(t, err)
+	checkTickCounter(t, chain, 1)
+	require.False(t, chain.RunScheduledJobs())
+}
+
+func TestSchedulerCron(t *testing.T) {
+	env, chain := setupScheduler(t)
+
+	_, err := scheduleTick(chain, scheduler.ParamCron, "61 * * * *")
+	require.Error(t, err)
+	_, err = scheduleTick(chain, scheduler.ParamCron, "0 0 30 2 *")
+	require.Error(t, err)
+	_, err = scheduleTick(chain, scheduler.ParamCron, "* * * * *", scheduler.ParamInterval, 10)
+	require.Error(t, err)
+
+	_, err = scheduleTick(chain, scheduler.ParamCron, "* * * * *")
+	require.NoError(t, err)
+
+	env.AdvanceClockBy(time.Minute)
+	require.True(t, chain.RunScheduledJobs())
+	env.AdvanceClockBy(time.Minute)
+	require.True(t, chain.RunScheduledJobs())
+	checkTickCounter(t, chain, 2)
+}
+
+func TestSchedulerRemove(t *testing.T) {
+	env, chain := setupScheduler(t)
+
+	id1, err := scheduleTick(chain, scheduler.ParamInterval, 10)
+	require.NoError(t, err)
+	id2, err := scheduleTick(chain, scheduler.ParamInterval, 10)
+	require.NoError(t, err)
+
+	// other users can't remove the job
+	user := env.NewSignatureSchemeWithFunds()
+	req := solo.NewCallParams(scheduler.Interface.Name, scheduler.FuncRemoveJob, scheduler.ParamJobID, id1)
+	_, err = chain.PostRequest(req, user)
+	require.Error(t, err)
+
+	// the contract which owns the job
+	_, err = chain.PostRequest(solo.NewCallParams(schedName, funcUnschedule, scheduler.ParamJobID, id1), nil)
+	require.NoError(t, err)
+	// the chain owner
+	req = solo.NewCallParams(scheduler.Interface.Name, scheduler.FuncRemoveJob, scheduler.ParamJobID, id2)
+	_, err = chain.PostRequest(req, nil)
+	require.NoError(t, err)
+
+	jobs, err := chain.GetScheduledJobs()
+	require.NoError(t, err)
+	require.EqualValues(t, 0, len(jobs))
+
+	env.AdvanceClockBy(11 * time.Second)
+	require.False(t, chain.RunScheduledJobs())
+	checkTickCounter(t, chain, 0)
+}
+
+func TestSchedulerAddJobFail(t *testing.T) {
+	_, chain := setupScheduler(t)
+
+	// only smart contracts can add jobs
+	req := solo.NewCallParams(scheduler.Interface.Name, scheduler.FuncAddJob,
+		scheduler.ParamEntryPoint, coretypes.Hn(funcTick),
+		scheduler.ParamInterval, 10,
+	)
+	_, err := chain.PostRequest(req, nil)
+	require.Error(t, err)
+
+	for i := 0; i < scheduler.MaxJobsPerContract; i++ {
+		_, err = scheduleTick(chain, scheduler.ParamInterval, 10)
+		require.NoError(t, err)
+	}
+	_, err = scheduleTick(chain, scheduler.ParamInterval, 10)
+	require.Error(t, err)
+}
+
+func TestSchedulerNoFunds(t *testing.T) {
+	env, chain := setupScheduler(t)
+
+	id, err := scheduleTick(chain, scheduler.ParamInterval, 10, scheduler.ParamGasBudget, 10_000)
+	require.NoError(t, err)
+
+	// the gas fee of the job is 1000 tokens, the account of the contract is empty
+	req := solo.NewCallParams(root.Interface.Name, root.FuncSetDefaultFee, root.ParamGasPrice, 100)
+	_, err = chain.PostRequest(req, nil)
+	require.NoError(t, err)
+
+	env.AdvanceClockBy(11 * time.Second)
+	require.True(t, chain.RunScheduledJobs())
+	checkTickCounter(t, chain, 0)
+
+	// the run is skipped, the job stays
+	jobs, err := chain.GetScheduledJobs(schedName)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, len(jobs))
+	require.EqualValues(t, 1, jobs[0].NumRuns)
+
+	recs, err := chain.GetEventLogRecordsString(scheduler.Interface.Name)
+	require.NoError(t, err)
+	require.Contains(t, recs, fmt.Sprintf("not enough funds to cover fees of job #%d", id))
+}
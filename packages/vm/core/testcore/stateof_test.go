@@ -0,0 +1,165 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package testcore
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/iotaledger/wasp/contracts"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/coretypes/coreutil"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/solo"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	oracleName = "oracle"
+	readerName = "reader"
+
+	funcSetPrice    = "setPrice"
+	funcReadKey     = "readKey"
+	funcCopyPrice   = "copyPrice"
+	funcGetNumKeys  = "getNumKeys"
+	funcGetCopy     = "getCopy"
+	paramPrice      = "price"
+	paramContract   = "contract"
+	paramKey        = "key"
+	varPublicPrice  = "pub.price"
+	varPublicTime   = "pub.time"
+	varPrivatePrice = "priv.price"
+	varCopy         = "copy"
+)
+
+// publicOracle exposes its state with the prefix 'pub.' to other contracts
+var publicOracle = &coreutil.ContractInterface{
+	Name:              "publicOracle",
+	Description:       "Oracle with public state",
+	ProgramHash:       hashing.HashStrings("publicOracle"),
+	PublicStatePrefix: "pub.",
+}
+
+// stateReader reads the state of other contracts with StateOf
+var stateReader = &coreutil.ContractInterface{
+	Name:        "stateReader",
+	Description: "Reads public state of other contracts",
+	ProgramHash: hashing.HashStrings("stateReader"),
+}
+
+func init() {
+	publicOracle.WithFunctions(func(ctx coretypes.Sandbox) (dict.Dict, error) {
+		return nil, nil
+	}, []coreutil.ContractFunctionInterface{
+		coreutil.Func(funcSetPrice, func(ctx coretypes.Sandbox) (dict.Dict, error) {
+			price := ctx.Params().MustGet(paramPrice)
+			ctx.State().Set(varPublicPrice, price)
+			ctx.State().Set(varPublicTime, codec.EncodeInt64(ctx.GetTimestamp()))
+			ctx.State().Set(varPrivatePrice, price)
+			return nil, nil
+		}),
+	})
+	contracts.AddExampleProcessor(publicOracle)
+
+	stateReader.WithFunctions(func(ctx coretypes.Sandbox) (dict.Dict, error) {
+		return nil, nil
+	}, []coreutil.ContractFunctionInterface{
+		coreutil.Func(funcCopyPrice, func(ctx coretypes.Sandbox) (dict.Dict, error) {
+			state := ctx.StateOf(coretypes.Hn(oracleName))
+			if state == nil {
+				return nil, fmt.Errorf("state of the oracle is not public")
+			}
+			ctx.State().Set(varCopy, state.MustGet(varPublicPrice))
+			return nil, nil
+		}),
+		coreutil.ViewFunc(funcGetCopy, func(ctx coretypes.SandboxView) (dict.Dict, error) {
+			ret := dict.New()
+			ret.Set(varCopy, ctx.State().MustGet(varCopy))
+			return ret, nil
+		}),
+		coreutil.ViewFunc(funcReadKey, func(ctx coretypes.SandboxView) (dict.Dict, error) {
+			state := ctx.StateOf(coretypes.Hn(string(ctx.Params().MustGet(paramContract))))
+			if state == nil {
+				return nil, fmt.Errorf("state is not public")
+			}
+			ret := dict.New()
+			ret.Set(paramKey, state.MustGet(kv.Key(ctx.Params().MustGet(paramKey))))
+			return ret, nil
+		}),
+		coreutil.ViewFunc(funcGetNumKeys, func(ctx coretypes.SandboxView) (dict.Dict, error) {
+			state := ctx.StateOf(coretypes.Hn(oracleName))
+			if state == nil {
+				return nil, fmt.Errorf("state of the oracle is not public")
+			}
+			n := 0
+			state.MustIterateKeys("", func(key kv.Key) bool {
+				n++
+				return true
+			})
+			ret := dict.New()
+			ret.Set(paramKey, codec.EncodeInt64(int64(n)))
+			return ret, nil
+		}),
+	})
+	contracts.AddExampleProcessor(stateReader)
+}
+
+func setupStateOf(t *testing.T) *solo.Chain {
+	env := solo.New(t, false, false)
+	chain := env.NewChain(nil, "chain1")
+	require.NoError(t, chain.DeployContract(nil, oracleName, publicOracle.ProgramHash))
+	require.NoError(t, chain.DeployContract(nil, readerName, stateReader.ProgramHash))
+	_, err := chain.PostRequest(solo.NewCallParams(oracleName, funcSetPrice, paramPrice, 42), nil)
+	require.NoError(t, err)
+	return chain
+}
+
+func TestStateOfView(t *testing.T) {
+	chain := setupStateOf(t)
+
+	ret, err := chain.CallView(readerName, funcReadKey, paramContract, oracleName, paramKey, varPublicPrice)
+	require.NoError(t, err)
+	price, _, err := codec.DecodeInt64(ret.MustGet(paramKey))
+	require.NoError(t, err)
+	require.EqualValues(t, 42, price)
+
+	// only public keys are visible when iterating
+	ret, err = chain.CallView(readerName, funcGetNumKeys)
+	require.NoError(t, err)
+	n, _, err := codec.DecodeInt64(ret.MustGet(paramKey))
+	require.NoError(t, err)
+	require.EqualValues(t, 2, n)
+}
+
+func TestStateOfFull(t *testing.T) {
+	chain := setupStateOf(t)
+
+	_, err := chain.PostRequest(solo.NewCallParams(readerName, funcCopyPrice), nil)
+	require.NoError(t, err)
+
+	ret, err := chain.CallView(readerName, funcGetCopy)
+	require.NoError(t, err)
+	price, _, err := codec.DecodeInt64(ret.MustGet(varCopy))
+	require.NoError(t, err)
+	require.EqualValues(t, 42, price)
+}
+
+func TestStateOfPrivate(t *testing.T) {
+	chain := setupStateOf(t)
+
+	// keys outside of the public prefix
+	_, err := chain.CallView(readerName, funcReadKey, paramContract, oracleName, paramKey, varPrivatePrice)
+	require.Error(t, err)
+
+	// the contract does not declare the public state
+	_, err = chain.CallView(readerName, funcReadKey, paramContract, readerName, paramKey, varCopy)
+	require.Error(t, err)
+
+	// the contract does not exist
+	_, err = chain.CallView(readerName, funcReadKey, paramContract, "dummy", paramKey, varPublicPrice)
+	require.Error(t, err)
+}
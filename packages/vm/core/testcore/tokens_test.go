@@ -0,0 +1,288 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package testcore
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address/signaturescheme"
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	"github.com/iotaledger/wasp/contracts"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/coretypes/cbalances"
+	"github.com/iotaledger/wasp/packages/coretypes/coreutil"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/solo"
+	"github.com/iotaledger/wasp/packages/testutil"
+	"github.com/iotaledger/wasp/packages/vm/core/accounts"
+	"github.com/iotaledger/wasp/packages/vm/core/tokens"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	payerName    = "payer"
+	receiverName = "receiver"
+
+	funcPay          = "pay"
+	funcReceive      = "receive"
+	funcSendOut      = "sendOut"
+	funcGetReceived  = "getReceived"
+	paramTokenColor  = "color"
+	paramTokenAmount = "amount"
+	varReceived      = "received"
+)
+
+// tokenPayer moves chain-native tokens with the transfer of Sandbox.Call, the receiver records the incoming transfer
+var tokenPayer = &coreutil.ContractInterface{
+	Name:        "tokenPayer",
+	Description: "Pays with chain-native tokens",
+	ProgramHash: hashing.HashStrings("tokenPayer"),
+}
+
+func init() {
+	tokenPayer.WithFunctions(func(ctx coretypes.Sandbox) (dict.Dict, error) {
+		return nil, nil
+	}, []coreutil.ContractFunctionInterface{
+		coreutil.Func(funcPay, func(ctx coretypes.Sandbox) (dict.Dict, error) {
+			color, _, _ := codec.DecodeColor(ctx.Params().MustGet(paramTokenColor))
+			amount, _, _ := codec.DecodeInt64(ctx.Params().MustGet(paramTokenAmount))
+			transfer := cbalances.NewFromMap(map[balance.Color]int64{color: amount})
+			return ctx.Call(coretypes.Hn(receiverName), coretypes.Hn(funcReceive), nil, transfer)
+		}),
+		coreutil.Func(funcReceive, func(ctx coretypes.Sandbox) (dict.Dict, error) {
+			ctx.IncomingTransfer().Iterate(func(col balance.Color, bal int64) bool {
+				ctx.State().Set(varReceived, codec.EncodeInt64(bal))
+				return true
+			})
+			return nil, nil
+		}),
+		coreutil.Func(funcSendOut, func(ctx coretypes.Sandbox) (dict.Dict, error) {
+			color, _, _ := codec.DecodeColor(ctx.Params().MustGet(paramTokenColor))
+			transfer := cbalances.NewFromMap(map[balance.Color]int64{color: 1})
+			if ctx.TransferToAddress(ctx.Caller().MustAddress(), transfer) {
+				return nil, fmt.Errorf("chain-native tokens were sent to the address")
+			}
+			if ctx.PostRequest(coretypes.PostRequestParams{
+				TargetContractID: ctx.ContractID(),
+				EntryPoint:       coretypes.Hn(funcReceive),
+				Transfer:         transfer,
+			}) {
+				return nil, fmt.Errorf("chain-native tokens were sent with the request")
+			}
+			return nil, nil
+		}),
+		coreutil.ViewFunc(funcGetReceived, func(ctx coretypes.SandboxView) (dict.Dict, error) {
+			ret := dict.New()
+			ret.Set(varReceived, ctx.State().MustGet(varReceived))
+			return ret, nil
+		}),
+	})
+	contracts.AddExampleProcessor(tokenPayer)
+}
+
+func createToken(t *testing.T, chain *solo.Chain, owner signaturescheme.SignatureScheme, symbol string, supply int64) balance.Color {
+	req := solo.NewCallParams(tokens.Interface.Name, tokens.FuncCreateToken,
+		tokens.ParamSymbol, symbol,
+		tokens.ParamName, "Token "+symbol,
+		tokens.ParamDecimals, 2,
+		tokens.ParamSupply, supply,
+	)
+	ret, err := chain.PostRequest(req, owner)
+	require.NoError(t, err)
+	color, _, err := codec.DecodeColor(ret.MustGet(tokens.ParamColor))
+	require.NoError(t, err)
+	require.EqualValues(t, tokens.TokenColor(chain.ChainID, symbol), color)
+	return color
+}
+
+func checkHolding(t *testing.T, chain *solo.Chain, agentID coretypes.AgentID, color balance.Color, expected int64) {
+	holdings, err := chain.GetTokenHoldings(agentID)
+	require.NoError(t, err)
+	for _, h := range holdings {
+		if h.Token.Color == color {
+			require.EqualValues(t, expected, h.Balance)
+			return
+		}
+	}
+	require.EqualValues(t, expected, 0)
+}
+
+func TestCreateToken(t *testing.T) {
+	env := solo.New(t, false, false)
+	chain := env.NewChain(nil, "chain1")
+	owner := env.NewSignatureSchemeWithFunds()
+	ownerAgentID := coretypes.NewAgentIDFromAddress(owner.Address())
+
+	color := createToken(t, chain, owner, "ABC", 1000)
+
+	all, err := chain.GetNativeTokens()
+	require.NoError(t, err)
+	require.EqualValues(t, 1, len(all))
+	require.EqualValues(t, "ABC", all[0].Symbol)
+	require.EqualValues(t, "Token ABC", all[0].Name)
+	require.EqualValues(t, 2, all[0].Decimals)
+	require.EqualValues(t, 1000, all[0].Supply)
+	require.EqualValues(t, ownerAgentID, all[0].Owner)
+	require.EqualValues(t, "10.00 ABC", all[0].FormatAmount(all[0].Supply))
+
+	checkHolding(t, chain, ownerAgentID, color, 1000)
+	chain.AssertAccountBalance(ownerAgentID, color, 1000)
+
+	// the symbol is unique
+	req := solo.NewCallParams(tokens.Interface.Name, tokens.FuncCreateToken, tokens.ParamSymbol, "ABC")
+	_, err = chain.PostRequest(req, nil)
+	require.Error(t, err)
+	// wrong symbols
+	for _, symbol := range []string{"", "abc", "A-B", "ABCDEFGHIJKLMNOPQ"} {
+		req = solo.NewCallParams(tokens.Interface.Name, tokens.FuncCreateToken, tokens.ParamSymbol, symbol)
+		_, err = chain.PostRequest(req, nil)
+		require.Error(t, err)
+	}
+	chain.CheckChain()
+}
+
+func TestMintBurnToken(t *testing.T) {
+	env := solo.New(t, false, false)
+	chain := env.NewChain(nil, "chain1")
+	owner := env.NewSignatureSchemeWithFunds()
+	user := env.NewSignatureSchemeWithFunds()
+	userAgentID := coretypes.NewAgentIDFromAddress(user.Address())
+
+	color := createToken(t, chain, owner, "ABC", 0)
+
+	// only the owner can mint
+	req := solo.NewCallParams(tokens.Interface.Name, tokens.FuncMint,
+		tokens.ParamColor, color, tokens.ParamAmount, 500, tokens.ParamAgentID, userAgentID)
+	_, err := chain.PostRequest(req, user)
+	require.Error(t, err)
+	_, err = chain.PostRequest(req, owner)
+	require.NoError(t, err)
+	checkHolding(t, chain, userAgentID, color, 500)
+
+	req = solo.NewCallParams(tokens.Interface.Name, tokens.FuncBurn, tokens.ParamColor, color, tokens.ParamAmount, 600)
+	_, err = chain.PostRequest(req, user)
+	require.Error(t, err)
+	req = solo.NewCallParams(tokens.Interface.Name, tokens.FuncBurn, tokens.ParamColor, color, tokens.ParamAmount, 200)
+	_, err = chain.PostRequest(req, user)
+	require.NoError(t, err)
+	checkHolding(t, chain, userAgentID, color, 300)
+
+	all, err := chain.GetNativeTokens()
+	require.NoError(t, err)
+	require.EqualValues(t, 300, all[0].Supply)
+	require.EqualValues(t, 300, chain.GetTotalAssets().Balance(color))
+	chain.CheckChain()
+}
+
+func TestTransferToken(t *testing.T) {
+	env := solo.New(t, false, false)
+	chain := env.NewChain(nil, "chain1")
+	owner := env.NewSignatureSchemeWithFunds()
+	ownerAgentID := coretypes.NewAgentIDFromAddress(owner.Address())
+	user := env.NewSignatureSchemeWithFunds()
+	userAgentID := coretypes.NewAgentIDFromAddress(user.Address())
+
+	color := createToken(t, chain, owner, "ABC", 1000)
+
+	req := solo.NewCallParams(tokens.Interface.Name, tokens.FuncTransfer,
+		tokens.ParamColor, color, tokens.ParamAmount, 100, tokens.ParamTarget, userAgentID)
+	_, err := chain.PostRequest(req, owner)
+	require.NoError(t, err)
+	checkHolding(t, chain, ownerAgentID, color, 900)
+	checkHolding(t, chain, userAgentID, color, 100)
+
+	// not enough tokens
+	req = solo.NewCallParams(tokens.Interface.Name, tokens.FuncTransfer,
+		tokens.ParamColor, color, tokens.ParamAmount, 101, tokens.ParamTarget, ownerAgentID)
+	_, err = chain.PostRequest(req, user)
+	require.Error(t, err)
+
+	// the user spends tokens of the owner
+	req = solo.NewCallParams(tokens.Interface.Name, tokens.FuncApprove,
+		tokens.ParamColor, color, tokens.ParamSpender, userAgentID, tokens.ParamAmount, 50)
+	_, err = chain.PostRequest(req, owner)
+	require.NoError(t, err)
+
+	ret, err := chain.CallView(tokens.Interface.Name, tokens.FuncGetAllowance,
+		tokens.ParamColor, color, tokens.ParamAgentID, ownerAgentID, tokens.ParamSpender, userAgentID)
+	require.NoError(t, err)
+	allowance, _, err := codec.DecodeInt64(ret.MustGet(tokens.ParamAmount))
+	require.NoError(t, err)
+	require.EqualValues(t, 50, allowance)
+
+	req = solo.NewCallParams(tokens.Interface.Name, tokens.FuncTransferFrom,
+		tokens.ParamColor, color, tokens.ParamAgentID, ownerAgentID, tokens.ParamTarget, userAgentID, tokens.ParamAmount, 30)
+	_, err = chain.PostRequest(req, user)
+	require.NoError(t, err)
+	// exceeds the allowance
+	_, err = chain.PostRequest(req, user)
+	require.Error(t, err)
+
+	checkHolding(t, chain, ownerAgentID, color, 870)
+	checkHolding(t, chain, userAgentID, color, 130)
+	chain.CheckChain()
+}
+
+func TestNativeTokensCallTransfer(t *testing.T) {
+	env := solo.New(t, false, false)
+	chain := env.NewChain(nil, "chain1")
+	require.NoError(t, chain.DeployContract(nil, payerName, tokenPayer.ProgramHash))
+	require.NoError(t, chain.DeployContract(nil, receiverName, tokenPayer.ProgramHash))
+	owner := env.NewSignatureSchemeWithFunds()
+
+	color := createToken(t, chain, owner, "ABC", 1000)
+	payerAgentID := coretypes.NewAgentIDFromContractID(coretypes.NewContractID(chain.ChainID, coretypes.Hn(payerName)))
+	receiverAgentID := coretypes.NewAgentIDFromContractID(coretypes.NewContractID(chain.ChainID, coretypes.Hn(receiverName)))
+
+	req := solo.NewCallParams(tokens.Interface.Name, tokens.FuncTransfer,
+		tokens.ParamColor, color, tokens.ParamAmount, 100, tokens.ParamTarget, payerAgentID)
+	_, err := chain.PostRequest(req, owner)
+	require.NoError(t, err)
+
+	req = solo.NewCallParams(payerName, funcPay, paramTokenColor, color, paramTokenAmount, 40)
+	_, err = chain.PostRequest(req, nil)
+	require.NoError(t, err)
+
+	ret, err := chain.CallView(receiverName, funcGetReceived)
+	require.NoError(t, err)
+	received, _, err := codec.DecodeInt64(ret.MustGet(varReceived))
+	require.NoError(t, err)
+	require.EqualValues(t, 40, received)
+	checkHolding(t, chain, payerAgentID, color, 60)
+	checkHolding(t, chain, receiverAgentID, color, 40)
+	chain.CheckChain()
+}
+
+func TestNativeTokensStayOnChain(t *testing.T) {
+	env := solo.New(t, false, false)
+	chain := env.NewChain(nil, "chain1")
+	require.NoError(t, chain.DeployContract(nil, payerName, tokenPayer.ProgramHash))
+	owner := env.NewSignatureSchemeWithFunds()
+	ownerAgentID := coretypes.NewAgentIDFromAddress(owner.Address())
+
+	color := createToken(t, chain, owner, "ABC", 1000)
+	payerAgentID := coretypes.NewAgentIDFromContractID(coretypes.NewContractID(chain.ChainID, coretypes.Hn(payerName)))
+	req := solo.NewCallParams(tokens.Interface.Name, tokens.FuncTransfer,
+		tokens.ParamColor, color, tokens.ParamAmount, 100, tokens.ParamTarget, payerAgentID)
+	_, err := chain.PostRequest(req, owner)
+	require.NoError(t, err)
+
+	// chain-native tokens can't be sent out of the chain
+	req = solo.NewCallParams(payerName, funcSendOut, paramTokenColor, color)
+	_, err = chain.PostRequest(req, owner)
+	require.NoError(t, err)
+	checkHolding(t, chain, payerAgentID, color, 100)
+
+	// only iotas are withdrawn, chain-native tokens stay in the account
+	_, err = chain.PostRequest(solo.NewCallParams(accounts.Interface.Name, accounts.FuncWithdrawToAddress), owner)
+	require.NoError(t, err)
+	chain.AssertAccountBalance(ownerAgentID, balance.ColorIOTA, 0)
+	checkHolding(t, chain, ownerAgentID, color, 900)
+	env.AssertAddressBalance(owner.Address(), balance.ColorIOTA, testutil.RequestFundsAmount)
+	env.AssertAddressBalance(owner.Address(), color, 0)
+	chain.CheckChain()
+}
@@ -0,0 +1,188 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package testcore
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/iotaledger/wasp/contracts"
+	"github.com/iotaledger/wasp/contracts/examples_core/inccounter"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/coretypes/coreutil"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/solo"
+	"github.com/iotaledger/wasp/packages/vm/core/accounts"
+	"github.com/iotaledger/wasp/packages/vm/core/root"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	incName          = "incTest"
+	varMigratedFrom  = "migratedFrom"
+	varMigratedTo    = "migratedTo"
+	paramFailMigrate = "failMigrate"
+)
+
+// incCounterV2 is the new version of the inccounter: it increments by 2 and has a 'migrate' entry point
+var incCounterV2 = &coreutil.ContractInterface{
+	Name:        "inccounterV2",
+	Description: "Increment counter, version 2",
+	ProgramHash: hashing.HashStrings("inccounterV2"),
+}
+
+func init() {
+	incCounterV2.WithFunctions(nil, []coreutil.ContractFunctionInterface{
+		coreutil.Func(coretypes.FuncMigrate, func(ctx coretypes.Sandbox) (dict.Dict, error) {
+			if ctx.Params().MustHas(paramFailMigrate) {
+				return nil, fmt.Errorf("migration failed")
+			}
+			ctx.State().Set(varMigratedFrom, ctx.Params().MustGet(root.ParamOldVersion))
+			ctx.State().Set(varMigratedTo, ctx.Params().MustGet(root.ParamNewVersion))
+			return nil, nil
+		}),
+		coreutil.Func(inccounter.FuncIncCounter, func(ctx coretypes.Sandbox) (dict.Dict, error) {
+			val, _, _ := codec.DecodeInt64(ctx.State().MustGet(inccounter.VarCounter))
+			ctx.State().Set(inccounter.VarCounter, codec.EncodeInt64(val+2))
+			return nil, nil
+		}),
+		coreutil.ViewFunc(inccounter.FuncGetCounter, func(ctx coretypes.SandboxView) (dict.Dict, error) {
+			ret := dict.New()
+			ret.Set(inccounter.VarCounter, ctx.State().MustGet(inccounter.VarCounter))
+			ret.Set(varMigratedFrom, ctx.State().MustGet(varMigratedFrom))
+			ret.Set(varMigratedTo, ctx.State().MustGet(varMigratedTo))
+			return ret, nil
+		}),
+	})
+	contracts.AddExampleProcessor(incCounterV2)
+}
+
+func checkCounter(t *testing.T, chain *solo.Chain, expected int64) dict.Dict {
+	ret, err := chain.CallView(incName, inccounter.FuncGetCounter)
+	require.NoError(t, err)
+	c, ok, err := codec.DecodeInt64(ret.MustGet(inccounter.VarCounter))
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.EqualValues(t, expected, c)
+	return ret
+}
+
+func TestUpgradeContract(t *testing.T) {
+	env := solo.New(t, false, false)
+	chain := env.NewChain(nil, "chain1")
+	defer chain.WaitForEmptyBacklog()
+
+	err := chain.DeployContract(nil, incName, inccounter.Interface.ProgramHash, inccounter.VarCounter, 17)
+	require.NoError(t, err)
+	_, err = chain.PostRequest(solo.NewCallParams(incName, inccounter.FuncIncCounter), nil)
+	require.NoError(t, err)
+	checkCounter(t, chain, 18)
+
+	history, err := chain.GetUpgradeHistory(incName)
+	require.NoError(t, err)
+	require.Len(t, history, 0)
+
+	err = chain.UpgradeContract(nil, incName, incCounterV2.ProgramHash)
+	require.NoError(t, err)
+
+	rec, err := chain.FindContract(incName)
+	require.NoError(t, err)
+	require.EqualValues(t, incCounterV2.ProgramHash, rec.ProgramHash)
+	require.EqualValues(t, incName, rec.Name)
+	require.EqualValues(t, chain.OriginatorAgentID, rec.Creator)
+
+	// state is kept and 'migrate' was called with versions
+	ret := checkCounter(t, chain, 18)
+	from, _, _ := codec.DecodeInt64(ret.MustGet(varMigratedFrom))
+	to, _, _ := codec.DecodeInt64(ret.MustGet(varMigratedTo))
+	require.EqualValues(t, 0, from)
+	require.EqualValues(t, 1, to)
+
+	// the new program is in effect
+	_, err = chain.PostRequest(solo.NewCallParams(incName, inccounter.FuncIncCounter), nil)
+	require.NoError(t, err)
+	checkCounter(t, chain, 20)
+
+	history, err = chain.GetUpgradeHistory(incName)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	require.EqualValues(t, 1, history[0].Version)
+	require.EqualValues(t, inccounter.Interface.ProgramHash, history[0].OldProgramHash)
+	require.EqualValues(t, incCounterV2.ProgramHash, history[0].NewProgramHash)
+	require.EqualValues(t, chain.OriginatorAgentID, history[0].Caller)
+
+	// downgrade without 'migrate' entry point
+	err = chain.UpgradeContract(nil, incName, inccounter.Interface.ProgramHash)
+	require.NoError(t, err)
+	checkCounter(t, chain, 20)
+
+	history, err = chain.GetUpgradeHistory(incName)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	require.EqualValues(t, 2, history[1].Version)
+	chain.CheckChain()
+}
+
+func TestUpgradeContractFail(t *testing.T) {
+	env := solo.New(t, false, false)
+	chain := env.NewChain(nil, "chain1")
+	defer chain.WaitForEmptyBacklog()
+
+	err := chain.DeployContract(nil, incName, inccounter.Interface.ProgramHash)
+	require.NoError(t, err)
+
+	// not authorized
+	user := env.NewSignatureSchemeWithFunds()
+	err = chain.UpgradeContract(user, incName, incCounterV2.ProgramHash)
+	require.Error(t, err)
+
+	// same program
+	err = chain.UpgradeContract(nil, incName, inccounter.Interface.ProgramHash)
+	require.Error(t, err)
+
+	// unknown program
+	err = chain.UpgradeContract(nil, incName, hashing.HashStrings("dummy"))
+	require.Error(t, err)
+
+	// core contract
+	err = chain.UpgradeContract(nil, accounts.Interface.Name, incCounterV2.ProgramHash)
+	require.Error(t, err)
+
+	// failed migration reverts the upgrade
+	err = chain.UpgradeContract(nil, incName, incCounterV2.ProgramHash, paramFailMigrate, 1)
+	require.Error(t, err)
+
+	// 'migrate' can only be called by root
+	err = chain.UpgradeContract(nil, incName, incCounterV2.ProgramHash)
+	require.NoError(t, err)
+	_, err = chain.PostRequest(solo.NewCallParams(incName, coretypes.FuncMigrate), nil)
+	require.Error(t, err)
+
+	history, err := chain.GetUpgradeHistory(incName)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	chain.CheckChain()
+}
+
+func TestUpgradeContractByCreator(t *testing.T) {
+	env := solo.New(t, false, false)
+	chain := env.NewChain(nil, "chain1")
+	defer chain.WaitForEmptyBacklog()
+
+	creator := env.NewSignatureSchemeWithFunds()
+	err := chain.GrantDeployPermission(nil, coretypes.NewAgentIDFromAddress(creator.Address()))
+	require.NoError(t, err)
+	err = chain.DeployContract(creator, incName, inccounter.Interface.ProgramHash)
+	require.NoError(t, err)
+
+	err = chain.UpgradeContract(creator, incName, incCounterV2.ProgramHash)
+	require.NoError(t, err)
+
+	history, err := chain.GetUpgradeHistory(incName)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	require.EqualValues(t, coretypes.NewAgentIDFromAddress(creator.Address()), history[0].Caller)
+}
@@ -0,0 +1,305 @@
+// 'tokens' is a core contract on the chain. It keeps the registry of chain-native fungible tokens.
+// Chain-native tokens exist only on the chain: balances are kept in the 'accounts' contract under the color
+// of the token, so tokens can be moved with Sandbox.Call transfers and are seen in IncomingTransfer.
+// They can't be withdrawn to L1 addresses or sent to other chains
+package tokens
+
+import (
+	"fmt"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/coretypes/assert"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/collections"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/kv/kvdecoder"
+	"github.com/iotaledger/wasp/packages/vm/core/accounts"
+)
+
+// initialize is mandatory
+func initialize(ctx coretypes.Sandbox) (dict.Dict, error) {
+	ctx.Log().Debugf("tokens.initialize.success hname = %s", Interface.Hname().String())
+	return nil, nil
+}
+
+// createToken creates the new chain-native token. The caller becomes the owner of the token
+// and receives the initial supply to its account
+// Input:
+// - ParamSymbol string symbol of the token, up to MaxSymbolLength upper case letters and digits. Unique on the chain
+// - ParamName string name of the token. Optional. Defaults to the symbol
+// - ParamDecimals int64 number of decimals. Optional. Default is 0
+// - ParamSupply int64 initial supply. Optional. Default is 0
+// Output:
+// - ParamColor color of the token
+func createToken(ctx coretypes.Sandbox) (dict.Dict, error) {
+	a := assert.NewAssert(ctx.Log())
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+
+	symbol := params.MustGetString(ParamSymbol)
+	a.Require(validSymbol(symbol), "tokens.createToken: wrong symbol '%s'", symbol)
+	name := params.MustGetString(ParamName, symbol)
+	a.Require(len(name) <= MaxNameLength, "tokens.createToken: name is too long")
+	decimals := params.MustGetInt64(ParamDecimals, 0)
+	a.Require(decimals >= 0 && decimals <= MaxDecimals, "tokens.createToken: wrong decimals")
+	supply := params.MustGetInt64(ParamSupply, 0)
+	a.Require(supply >= 0, "tokens.createToken: wrong supply")
+
+	token := &TokenRecord{
+		Color:    TokenColor(ctx.ContractID().ChainID(), symbol),
+		Symbol:   symbol,
+		Name:     name,
+		Decimals: uint8(decimals),
+		Owner:    ctx.Caller(),
+	}
+	existing, err := GetToken(ctx.State(), token.Color)
+	a.RequireNoError(err)
+	a.Require(existing == nil, "tokens.createToken: token '%s' already exists", symbol)
+
+	storeToken(ctx.State(), token)
+	if supply > 0 {
+		mustMint(ctx, token, token.Owner, supply)
+	}
+	ctx.Event(fmt.Sprintf("[tokens] created %s", token))
+
+	ret := dict.New()
+	ret.Set(ParamColor, codec.EncodeColor(token.Color))
+	return ret, nil
+}
+
+// mint creates new tokens. Can only be called by the owner of the token
+// Input:
+// - ParamColor color of the token
+// - ParamAmount int64 number of tokens
+// - ParamAgentID the account which receives new tokens. Optional. Defaults to the caller
+func mint(ctx coretypes.Sandbox) (dict.Dict, error) {
+	a := assert.NewAssert(ctx.Log())
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+
+	token := mustGetToken(ctx, a, params.MustGetColor(ParamColor))
+	a.Require(ctx.Caller() == token.Owner, "tokens.mint: not authorized")
+	amount := params.MustGetInt64(ParamAmount)
+	a.Require(amount > 0, "tokens.mint: amount must be positive")
+	a.Require(token.Supply+amount > token.Supply, "tokens.mint: supply overflow")
+	target := params.MustGetAgentID(ParamAgentID, ctx.Caller())
+
+	mustMint(ctx, token, target, amount)
+	ctx.Event(fmt.Sprintf("[tokens] minted %s to %s", token.FormatAmount(amount), target))
+	return nil, nil
+}
+
+// burn destroys tokens in the account of the caller
+// Input:
+// - ParamColor color of the token
+// - ParamAmount int64 number of tokens
+func burn(ctx coretypes.Sandbox) (dict.Dict, error) {
+	a := assert.NewAssert(ctx.Log())
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+
+	token := mustGetToken(ctx, a, params.MustGetColor(ParamColor))
+	amount := params.MustGetInt64(ParamAmount)
+	a.Require(amount > 0, "tokens.burn: amount must be positive")
+
+	_, err := ctx.Call(accounts.Interface.Hname(), coretypes.Hn(accounts.FuncBurnNative), codec.MakeDict(map[string]interface{}{
+		accounts.ParamAgentID: ctx.Caller(),
+		accounts.ParamColor:   token.Color,
+		accounts.ParamAmount:  amount,
+	}), nil)
+	a.RequireNoError(err)
+	token.Supply -= amount
+	storeToken(ctx.State(), token)
+
+	ctx.Event(fmt.Sprintf("[tokens] burned %s by %s", token.FormatAmount(amount), ctx.Caller()))
+	return nil, nil
+}
+
+// transfer moves tokens from the account of the caller to the target account on the chain.
+// Smart contracts can also move tokens with the transfer of Sandbox.Call
+// Input:
+// - ParamColor color of the token
+// - ParamAmount int64 number of tokens
+// - ParamTarget the target account
+func transfer(ctx coretypes.Sandbox) (dict.Dict, error) {
+	a := assert.NewAssert(ctx.Log())
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+
+	token := mustGetToken(ctx, a, params.MustGetColor(ParamColor))
+	amount := params.MustGetInt64(ParamAmount)
+	target := params.MustGetAgentID(ParamTarget)
+
+	mustMove(ctx, a, token, ctx.Caller(), target, amount)
+	return nil, nil
+}
+
+// approve allows the spender to transfer tokens from the account of the caller with transferFrom.
+// The new allowance replaces the previous one
+// Input:
+// - ParamColor color of the token
+// - ParamSpender the agent which is allowed to transfer tokens
+// - ParamAmount int64 the allowance. 0 revokes the allowance
+func approve(ctx coretypes.Sandbox) (dict.Dict, error) {
+	a := assert.NewAssert(ctx.Log())
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+
+	token := mustGetToken(ctx, a, params.MustGetColor(ParamColor))
+	spender := params.MustGetAgentID(ParamSpender)
+	amount := params.MustGetInt64(ParamAmount)
+	a.Require(amount >= 0, "tokens.approve: wrong amount")
+
+	setAllowance(ctx.State(), token.Color, ctx.Caller(), spender, amount)
+	ctx.Event(fmt.Sprintf("[tokens] %s approved %s to spend %s", ctx.Caller(), spender, token.FormatAmount(amount)))
+	return nil, nil
+}
+
+// transferFrom moves tokens from the account of the owner to the target account.
+// The caller must be approved by the owner to spend the amount
+// Input:
+// - ParamColor color of the token
+// - ParamAgentID the owner of tokens
+// - ParamTarget the target account
+// - ParamAmount int64 number of tokens
+func transferFrom(ctx coretypes.Sandbox) (dict.Dict, error) {
+	a := assert.NewAssert(ctx.Log())
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+
+	token := mustGetToken(ctx, a, params.MustGetColor(ParamColor))
+	owner := params.MustGetAgentID(ParamAgentID)
+	target := params.MustGetAgentID(ParamTarget)
+	amount := params.MustGetInt64(ParamAmount)
+
+	allowance := GetAllowance(ctx.State(), token.Color, owner, ctx.Caller())
+	a.Require(amount <= allowance, "tokens.transferFrom: amount exceeds the allowance")
+	setAllowance(ctx.State(), token.Color, owner, ctx.Caller(), allowance-amount)
+
+	mustMove(ctx, a, token, owner, target, amount)
+	return nil, nil
+}
+
+// getToken returns the token
+// Input:
+// - ParamColor color of the token
+// Output:
+// - ParamToken bytes of the TokenRecord
+func getToken(ctx coretypes.SandboxView) (dict.Dict, error) {
+	a := assert.NewAssert(ctx.Log())
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+	color := params.MustGetColor(ParamColor)
+
+	token, err := GetToken(ctx.State(), color)
+	a.RequireNoError(err)
+	a.Require(token != nil, "tokens.getToken: token %s not found", color)
+
+	ret := dict.New()
+	ret.Set(ParamToken, EncodeTokenRecord(token))
+	return ret, nil
+}
+
+// getTokens returns all tokens on the chain
+// Output:
+// - ParamTokens array of TokenRecord bytes, sorted by the symbol
+func getTokens(ctx coretypes.SandboxView) (dict.Dict, error) {
+	a := assert.NewAssert(ctx.Log())
+	tokens, err := GetTokens(ctx.State())
+	a.RequireNoError(err)
+
+	ret := dict.New()
+	arr := collections.NewArray(ret, ParamTokens)
+	for _, token := range tokens {
+		arr.MustPush(EncodeTokenRecord(token))
+	}
+	return ret, nil
+}
+
+// getAllowance returns the number of tokens the spender is allowed to transfer from the account of the owner
+// Input:
+// - ParamColor color of the token
+// - ParamAgentID the owner of tokens
+// - ParamSpender the spender
+// Output:
+// - ParamAmount int64 the allowance
+func getAllowance(ctx coretypes.SandboxView) (dict.Dict, error) {
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+	color := params.MustGetColor(ParamColor)
+	owner := params.MustGetAgentID(ParamAgentID)
+	spender := params.MustGetAgentID(ParamSpender)
+
+	ret := dict.New()
+	ret.Set(ParamAmount, codec.EncodeInt64(GetAllowance(ctx.State(), color, owner, spender)))
+	return ret, nil
+}
+
+// getHoldings returns balances of chain-native tokens in the account
+// Input:
+// - ParamAgentID the account
+// Output:
+// - ParamTokens array of TokenRecord bytes of tokens in the account, sorted by the symbol
+// - color: int64 balance of the token, for each token
+func getHoldings(ctx coretypes.SandboxView) (dict.Dict, error) {
+	a := assert.NewAssert(ctx.Log())
+	params := kvdecoder.New(ctx.Params(), ctx.Log())
+	agentID := params.MustGetAgentID(ParamAgentID)
+
+	res, err := ctx.Call(accounts.Interface.Hname(), coretypes.Hn(accounts.FuncBalance), codec.MakeDict(map[string]interface{}{
+		accounts.ParamAgentID: agentID,
+	}))
+	a.RequireNoError(err)
+	bals, err := accounts.DecodeBalances(res)
+	a.RequireNoError(err)
+
+	tokens, err := GetTokens(ctx.State())
+	a.RequireNoError(err)
+
+	ret := dict.New()
+	arr := collections.NewArray(ret, ParamTokens)
+	for _, token := range tokens {
+		if bals[token.Color] == 0 {
+			continue
+		}
+		arr.MustPush(EncodeTokenRecord(token))
+		ret.Set(kv.Key(token.Color[:]), codec.EncodeInt64(bals[token.Color]))
+	}
+	return ret, nil
+}
+
+func validSymbol(symbol string) bool {
+	if len(symbol) == 0 || len(symbol) > MaxSymbolLength {
+		return false
+	}
+	for _, c := range symbol {
+		if !(c >= 'A' && c <= 'Z') && !(c >= '0' && c <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+func mustGetToken(ctx coretypes.Sandbox, a assert.Assert, color balance.Color) *TokenRecord {
+	token, err := GetToken(ctx.State(), color)
+	a.RequireNoError(err)
+	a.Require(token != nil, "tokens: token %s not found", color)
+	return token
+}
+
+func mustMint(ctx coretypes.Sandbox, token *TokenRecord, target coretypes.AgentID, amount int64) {
+	_, err := ctx.Call(accounts.Interface.Hname(), coretypes.Hn(accounts.FuncMintNative), codec.MakeDict(map[string]interface{}{
+		accounts.ParamAgentID: target,
+		accounts.ParamColor:   token.Color,
+		accounts.ParamAmount:  amount,
+	}), nil)
+	assert.NewAssert(ctx.Log()).RequireNoError(err)
+	token.Supply += amount
+	storeToken(ctx.State(), token)
+}
+
+func mustMove(ctx coretypes.Sandbox, a assert.Assert, token *TokenRecord, from, to coretypes.AgentID, amount int64) {
+	a.Require(amount > 0, "tokens: amount must be positive")
+	_, err := ctx.Call(accounts.Interface.Hname(), coretypes.Hn(accounts.FuncMoveNative), codec.MakeDict(map[string]interface{}{
+		accounts.ParamAgentID: from,
+		accounts.ParamTarget:  to,
+		accounts.ParamColor:   token.Color,
+		accounts.ParamAmount:  amount,
+	}), nil)
+	a.RequireNoError(err)
+	ctx.Event(fmt.Sprintf("[tokens] transferred %s from %s to %s", token.FormatAmount(amount), from, to))
+}
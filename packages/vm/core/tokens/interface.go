@@ -0,0 +1,72 @@
+package tokens
+
+import (
+	"github.com/iotaledger/wasp/packages/coretypes/coreutil"
+	"github.com/iotaledger/wasp/packages/hashing"
+)
+
+const (
+	Name        = "tokens"
+	description = "Native Tokens Contract"
+)
+
+var (
+	Interface = &coreutil.ContractInterface{
+		Name:        Name,
+		Description: description,
+		ProgramHash: hashing.HashStrings(Name),
+	}
+)
+
+func init() {
+	Interface.WithFunctions(initialize, []coreutil.ContractFunctionInterface{
+		coreutil.Func(FuncCreateToken, createToken),
+		coreutil.Func(FuncMint, mint),
+		coreutil.Func(FuncBurn, burn),
+		coreutil.Func(FuncTransfer, transfer),
+		coreutil.Func(FuncApprove, approve),
+		coreutil.Func(FuncTransferFrom, transferFrom),
+		coreutil.ViewFunc(FuncGetToken, getToken),
+		coreutil.ViewFunc(FuncGetTokens, getTokens),
+		coreutil.ViewFunc(FuncGetAllowance, getAllowance),
+		coreutil.ViewFunc(FuncGetHoldings, getHoldings),
+	})
+}
+
+const (
+	// state variables
+	VarTokens     = "t"
+	VarAllowances = "a"
+
+	// request parameters
+	ParamColor    = "color"
+	ParamSymbol   = "symbol"
+	ParamName     = "name"
+	ParamDecimals = "decimals"
+	ParamSupply   = "supply"
+	ParamAmount   = "amount"
+	ParamAgentID  = "agentID"
+	ParamTarget   = "target"
+	ParamSpender  = "spender"
+	ParamToken    = "token"
+	ParamTokens   = "tokens"
+
+	// function names
+	FuncCreateToken  = "createToken"
+	FuncMint         = "mint"
+	FuncBurn         = "burn"
+	FuncTransfer     = "transfer"
+	FuncApprove      = "approve"
+	FuncTransferFrom = "transferFrom"
+	FuncGetToken     = "getToken"
+	FuncGetTokens    = "getTokens"
+	FuncGetAllowance = "getAllowance"
+	FuncGetHoldings  = "getHoldings"
+
+	// MaxSymbolLength is the maximum length of the token symbol
+	MaxSymbolLength = 16
+	// MaxNameLength is the maximum length of the token name
+	MaxNameLength = 64
+	// MaxDecimals is the maximum number of decimals of the token
+	MaxDecimals = 18
+)
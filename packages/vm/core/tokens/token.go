@@ -0,0 +1,205 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package tokens
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/collections"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/util"
+)
+
+// TokenRecord is the metadata of the chain-native token.
+// Balances of the token are kept in the 'accounts' contract under the color of the token
+type TokenRecord struct {
+	Color    balance.Color
+	Symbol   string
+	Name     string
+	Decimals uint8
+	// Supply is the number of tokens in circulation
+	Supply int64
+	// Owner is the agent which created the token. Only the owner can mint new tokens
+	Owner coretypes.AgentID
+}
+
+// TokenColor is the color of the chain-native token with the symbol.
+// The color is deterministic and it does not exist on the tangle
+func TokenColor(chainID coretypes.ChainID, symbol string) balance.Color {
+	h := hashing.HashData(chainID[:], Interface.Hname().Bytes(), []byte(symbol))
+	var ret balance.Color
+	copy(ret[:], h[:])
+	return ret
+}
+
+// FormatAmount formats the amount of tokens with the decimals of the token, e.g. '1.50 ABC'
+func (t *TokenRecord) FormatAmount(amount int64) string {
+	if t.Decimals == 0 {
+		return fmt.Sprintf("%d %s", amount, t.Symbol)
+	}
+	s := fmt.Sprintf("%0*d", int(t.Decimals)+1, amount)
+	return fmt.Sprintf("%s.%s %s", s[:len(s)-int(t.Decimals)], s[len(s)-int(t.Decimals):], t.Symbol)
+}
+
+func (t *TokenRecord) String() string {
+	return fmt.Sprintf("%s '%s' (%s), supply: %s, owner: %s", t.Symbol, t.Name, t.Color, t.FormatAmount(t.Supply), t.Owner)
+}
+
+func (t *TokenRecord) Write(w io.Writer) error {
+	if _, err := w.Write(t.Color[:]); err != nil {
+		return err
+	}
+	if err := util.WriteString16(w, t.Symbol); err != nil {
+		return err
+	}
+	if err := util.WriteString16(w, t.Name); err != nil {
+		return err
+	}
+	if err := util.WriteByte(w, t.Decimals); err != nil {
+		return err
+	}
+	if err := util.WriteInt64(w, t.Supply); err != nil {
+		return err
+	}
+	_, err := w.Write(t.Owner[:])
+	return err
+}
+
+func (t *TokenRecord) Read(r io.Reader) error {
+	var err error
+	if err = util.ReadColor(r, &t.Color); err != nil {
+		return err
+	}
+	if t.Symbol, err = util.ReadString16(r); err != nil {
+		return err
+	}
+	if t.Name, err = util.ReadString16(r); err != nil {
+		return err
+	}
+	if t.Decimals, err = util.ReadByte(r); err != nil {
+		return err
+	}
+	if err = util.ReadInt64(r, &t.Supply); err != nil {
+		return err
+	}
+	return coretypes.ReadAgentID(r, &t.Owner)
+}
+
+func EncodeTokenRecord(t *TokenRecord) []byte {
+	return util.MustBytes(t)
+}
+
+func DecodeTokenRecord(data []byte) (*TokenRecord, error) {
+	ret := new(TokenRecord)
+	err := ret.Read(bytes.NewReader(data))
+	return ret, err
+}
+
+// DecodeTokenRecords decodes the result of the getTokens view
+func DecodeTokenRecords(d dict.Dict) ([]*TokenRecord, error) {
+	arr := collections.NewArrayReadOnly(d, ParamTokens)
+	n, err := arr.Len()
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]*TokenRecord, n)
+	for i := uint16(0); i < n; i++ {
+		data, err := arr.GetAt(i)
+		if err != nil {
+			return nil, err
+		}
+		if ret[i], err = DecodeTokenRecord(data); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+// Holding is the balance of the chain-native token in the account
+type Holding struct {
+	Token   *TokenRecord
+	Balance int64
+}
+
+// DecodeHoldings decodes the result of the getHoldings view
+func DecodeHoldings(d dict.Dict) ([]*Holding, error) {
+	tokens, err := DecodeTokenRecords(d)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]*Holding, len(tokens))
+	for i, token := range tokens {
+		bal, _, err := codec.DecodeInt64(d.MustGet(kv.Key(token.Color[:])))
+		if err != nil {
+			return nil, err
+		}
+		ret[i] = &Holding{Token: token, Balance: bal}
+	}
+	return ret, nil
+}
+
+// GetToken returns the token by the color, or nil if the token does not exist
+func GetToken(state kv.KVStoreReader, color balance.Color) (*TokenRecord, error) {
+	data := collections.NewMapReadOnly(state, VarTokens).MustGetAt(color[:])
+	if data == nil {
+		return nil, nil
+	}
+	return DecodeTokenRecord(data)
+}
+
+// GetTokens returns all tokens of the chain, sorted by the symbol
+func GetTokens(state kv.KVStoreReader) ([]*TokenRecord, error) {
+	ret := make([]*TokenRecord, 0)
+	var err error
+	collections.NewMapReadOnly(state, VarTokens).MustIterate(func(_ []byte, value []byte) bool {
+		var token *TokenRecord
+		if token, err = DecodeTokenRecord(value); err != nil {
+			return false
+		}
+		ret = append(ret, token)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Symbol < ret[j].Symbol
+	})
+	return ret, nil
+}
+
+func storeToken(state kv.KVStore, token *TokenRecord) {
+	collections.NewMap(state, VarTokens).MustSetAt(token.Color[:], EncodeTokenRecord(token))
+}
+
+func allowanceKey(color balance.Color, owner, spender coretypes.AgentID) []byte {
+	var buf bytes.Buffer
+	buf.Write(color[:])
+	buf.Write(owner[:])
+	buf.Write(spender[:])
+	return buf.Bytes()
+}
+
+// GetAllowance returns the number of tokens the spender is allowed to transfer from the account of the owner
+func GetAllowance(state kv.KVStoreReader, color balance.Color, owner, spender coretypes.AgentID) int64 {
+	ret, _, _ := codec.DecodeInt64(collections.NewMapReadOnly(state, VarAllowances).MustGetAt(allowanceKey(color, owner, spender)))
+	return ret
+}
+
+func setAllowance(state kv.KVStore, color balance.Color, owner, spender coretypes.AgentID, amount int64) {
+	allowances := collections.NewMap(state, VarAllowances)
+	if amount == 0 {
+		allowances.MustDelAt(allowanceKey(color, owner, spender))
+		return
+	}
+	allowances.MustSetAt(allowanceKey(color, owner, spender), codec.EncodeInt64(amount))
+}
@@ -1,11 +1,15 @@
 package vm
 
 import (
+	"encoding/hex"
 	"fmt"
+	"strings"
 
 	"github.com/iotaledger/hive.go/logger"
 	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/packages/publisher"
+	"github.com/iotaledger/wasp/packages/util"
 )
 
 type ContractEventPublisher struct {
@@ -29,3 +33,16 @@ func (c ContractEventPublisher) Publishf(format string, args ...interface{}) {
 	c.log.Infof(c.contractID.String()+"/event "+format, args...)
 	publisher.Publish("vmmsg", c.contractID.ChainID().String(), c.contractID.Hname().String(), fmt.Sprintf(format, args...))
 }
+
+// PublishTyped publishes the structured event. The payload and the topics are hex encoded
+func (c ContractEventPublisher) PublishTyped(name string, payload dict.Dict, topics [][]byte) {
+	if payload == nil {
+		payload = dict.New()
+	}
+	parts := []string{c.contractID.ChainID().String(), c.contractID.Hname().String(), name, hex.EncodeToString(util.MustBytes(payload))}
+	for _, topic := range topics {
+		parts = append(parts, hex.EncodeToString(topic))
+	}
+	c.log.Info(c.contractID.String() + "/typed event " + strings.Join(parts[2:], " "))
+	publisher.Publish("vmevent", parts...)
+}
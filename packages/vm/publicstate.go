@@ -0,0 +1,78 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package vm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/kv"
+)
+
+// publicState is the read-only view of the contract state restricted to the keys with the public prefix
+type publicState struct {
+	contractHname coretypes.Hname
+	state         kv.KVStoreReader
+	prefix        kv.Key
+}
+
+// NewPublicState returns read-only access to the keys of the contract state which start with the public prefix.
+// Keys are the same as seen by the contract itself. Reading any other key panics
+func NewPublicState(contractHname coretypes.Hname, contractState kv.KVStoreReader, prefix kv.Key) kv.KVStoreReader {
+	return &publicState{
+		contractHname: contractHname,
+		state:         contractState,
+		prefix:        prefix,
+	}
+}
+
+func (s *publicState) mustBePublic(key kv.Key) {
+	if !strings.HasPrefix(string(key), string(s.prefix)) {
+		panic(fmt.Sprintf("key '%s' is not in the public state of the contract %s", key, s.contractHname))
+	}
+}
+
+// iterationPrefix narrows the iteration prefix to the public part of the state
+func (s *publicState) iterationPrefix(prefix kv.Key) kv.Key {
+	if strings.HasPrefix(string(s.prefix), string(prefix)) {
+		return s.prefix
+	}
+	s.mustBePublic(prefix)
+	return prefix
+}
+
+func (s *publicState) Get(key kv.Key) ([]byte, error) {
+	s.mustBePublic(key)
+	return s.state.Get(key)
+}
+
+func (s *publicState) Has(key kv.Key) (bool, error) {
+	s.mustBePublic(key)
+	return s.state.Has(key)
+}
+
+func (s *publicState) Iterate(prefix kv.Key, f func(key kv.Key, value []byte) bool) error {
+	return s.state.Iterate(s.iterationPrefix(prefix), f)
+}
+
+func (s *publicState) IterateKeys(prefix kv.Key, f func(key kv.Key) bool) error {
+	return s.state.IterateKeys(s.iterationPrefix(prefix), f)
+}
+
+func (s *publicState) MustGet(key kv.Key) []byte {
+	return kv.MustGet(s, key)
+}
+
+func (s *publicState) MustHas(key kv.Key) bool {
+	return kv.MustHas(s, key)
+}
+
+func (s *publicState) MustIterate(prefix kv.Key, f func(key kv.Key, value []byte) bool) {
+	kv.MustIterate(s, prefix, f)
+}
+
+func (s *publicState) MustIterateKeys(prefix kv.Key, f func(key kv.Key) bool) {
+	kv.MustIterateKeys(s, prefix, f)
+}
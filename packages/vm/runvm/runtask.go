@@ -2,8 +2,9 @@ package runvm
 
 import (
 	"fmt"
-	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
 	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/metrics"
+	"github.com/iotaledger/wasp/packages/vm/core/scheduler"
 	"github.com/iotaledger/wasp/packages/vm/statetxbuilder"
 	"github.com/iotaledger/wasp/packages/vm/vmcontext"
 	"time"
@@ -16,15 +17,21 @@ import (
 // RunComputationsAsync runs computations for the batch of requests in the background
 // This is the main entry point to the VM
 func RunComputationsAsync(ctx *vm.VMTask) error {
-	if len(ctx.Requests) == 0 {
-		return fmt.Errorf("RunComputationsAsync: must be at least 1 request")
+	if len(ctx.Requests) == 0 && !scheduler.HasDueJobs(ctx.VirtualState.Variables(), ctx.Timestamp) {
+		return fmt.Errorf("RunComputationsAsync: must be at least 1 request or due scheduled job")
 	}
 
-	txb, err := statetxbuilder.New(address.Address(ctx.ChainID), ctx.Color, ctx.Balances)
+	txb, err := statetxbuilder.New(ctx.ChainID, ctx.ChainAddress, ctx.Color, ctx.Balances)
 	if err != nil {
 		ctx.Log.Debugf("statetxbuilder.New: %v", err)
 		return err
 	}
+	if len(ctx.ChainIDBalances) > 0 {
+		if err = txb.SweepChainIDAddress(ctx.ChainIDBalances); err != nil {
+			ctx.Log.Debugf("statetxbuilder.SweepChainIDAddress: %v", err)
+			return err
+		}
+	}
 
 	// TODO 1 graceful shutdown of the running VM task (with daemon)
 	// TODO 2 timeout for VM. Gas limit
@@ -41,6 +48,7 @@ func runTask(task *vm.VMTask, txb *statetxbuilder.Builder) {
 		"state index", task.VirtualState.BlockIndex(),
 		"num req", len(task.Requests),
 	)
+	start := time.Now()
 	vmctx, err := vmcontext.NewVMContext(task, txb)
 	if err != nil {
 		task.OnFinish(nil, nil, fmt.Errorf("runTask.createVMContext: %v", err))
@@ -55,8 +63,19 @@ func runTask(task *vm.VMTask, txb *statetxbuilder.Builder) {
 	// loop over the batch of requests and run each request on the VM.
 	// the result accumulates in the VMContext and in the list of stateUpdates
 	timestamp := task.Timestamp
+
+	// scheduled jobs which are due are run first, each as a synthetic request
+	for _, job := range vmctx.DueJobs(timestamp) {
+		vmctx.RunScheduledJob(job, timestamp)
+		lastStateUpdate, lastResult, lastErr = vmctx.GetResult()
+
+		stateUpdates = append(stateUpdates, lastStateUpdate)
+		if timestamp != 0 {
+			timestamp += 1
+		}
+	}
 	for _, reqRef := range task.Requests {
-		if reqRef.RequestSection().SolidArgs() == nil {
+		if reqRef.RequestSection().SolidArgs() == nil && !reqRef.ArgsTimeout {
 			task.Log.Panicf("inconsistency: request args have not been solidified")
 		}
 		vmctx.RunTheRequest(reqRef, timestamp)
@@ -102,5 +121,6 @@ func runTask(task *vm.VMTask, txb *statetxbuilder.Builder) {
 		"tx essence hash", hashing.HashData(task.ResultTransaction.EssenceBytes()).String(),
 		"tx finalTimestamp", time.Unix(0, task.ResultTransaction.MustState().Timestamp()),
 	)
+	metrics.ObserveVMRun(task.ChainID.String(), len(task.Requests), time.Since(start))
 	task.OnFinish(lastResult, lastErr, nil)
 }
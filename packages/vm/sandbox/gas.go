@@ -0,0 +1,86 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package sandbox
+
+import (
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/kv"
+)
+
+// gasMeteredState burns gas for each write to the state of the contract
+type gasMeteredState struct {
+	kv.KVStore
+	gas coretypes.GasMeter
+}
+
+func newGasMeteredState(state kv.KVStore, gas coretypes.GasMeter) kv.KVStore {
+	return gasMeteredState{
+		KVStore: state,
+		gas:     gas,
+	}
+}
+
+func (s gasMeteredState) Set(key kv.Key, value []byte) {
+	s.gas.BurnGas(coretypes.GasStateSet + uint64(len(key)+len(value))*coretypes.GasPerByte)
+	s.KVStore.Set(key, value)
+}
+
+func (s gasMeteredState) Del(key kv.Key) {
+	s.gas.BurnGas(coretypes.GasStateDel)
+	s.KVStore.Del(key)
+}
+
+// gasMeteredStateReader burns gas for each read from the state of another contract
+type gasMeteredStateReader struct {
+	state kv.KVStoreReader
+	gas   coretypes.GasMeter
+}
+
+func newGasMeteredStateReader(state kv.KVStoreReader, gas coretypes.GasMeter) kv.KVStoreReader {
+	return gasMeteredStateReader{
+		state: state,
+		gas:   gas,
+	}
+}
+
+func (s gasMeteredStateReader) Get(key kv.Key) ([]byte, error) {
+	ret, err := s.state.Get(key)
+	s.gas.BurnGas(coretypes.GasStateGet + uint64(len(key)+len(ret))*coretypes.GasPerByte)
+	return ret, err
+}
+
+func (s gasMeteredStateReader) Has(key kv.Key) (bool, error) {
+	s.gas.BurnGas(coretypes.GasStateGet + uint64(len(key))*coretypes.GasPerByte)
+	return s.state.Has(key)
+}
+
+func (s gasMeteredStateReader) Iterate(prefix kv.Key, f func(key kv.Key, value []byte) bool) error {
+	return s.state.Iterate(prefix, func(key kv.Key, value []byte) bool {
+		s.gas.BurnGas(coretypes.GasStateGet + uint64(len(key)+len(value))*coretypes.GasPerByte)
+		return f(key, value)
+	})
+}
+
+func (s gasMeteredStateReader) IterateKeys(prefix kv.Key, f func(key kv.Key) bool) error {
+	return s.state.IterateKeys(prefix, func(key kv.Key) bool {
+		s.gas.BurnGas(coretypes.GasStateGet + uint64(len(key))*coretypes.GasPerByte)
+		return f(key)
+	})
+}
+
+func (s gasMeteredStateReader) MustGet(key kv.Key) []byte {
+	return kv.MustGet(s, key)
+}
+
+func (s gasMeteredStateReader) MustHas(key kv.Key) bool {
+	return kv.MustHas(s, key)
+}
+
+func (s gasMeteredStateReader) MustIterate(prefix kv.Key, f func(key kv.Key, value []byte) bool) {
+	kv.MustIterate(s, prefix, f)
+}
+
+func (s gasMeteredStateReader) MustIterateKeys(prefix kv.Key, f func(key kv.Key) bool) {
+	kv.MustIterateKeys(s, prefix, f)
+}
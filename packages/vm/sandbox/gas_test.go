@@ -0,0 +1,42 @@
+package sandbox
+
+import (
+	"testing"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/stretchr/testify/require"
+)
+
+type testGasMeter struct {
+	burned uint64
+}
+
+func (g *testGasMeter) GasBudget() uint64  { return coretypes.MaxGasBudget }
+func (g *testGasMeter) GasBurned() uint64  { return g.burned }
+func (g *testGasMeter) BurnGas(gas uint64) { g.burned += gas }
+
+func TestGasMeteredStateReader(t *testing.T) {
+	state := dict.New()
+	state.Set("a", []byte{1, 2, 3})
+	state.Set("b", []byte{4})
+	gas := &testGasMeter{}
+	r := newGasMeteredStateReader(state, gas)
+
+	require.EqualValues(t, []byte{1, 2, 3}, r.MustGet("a"))
+	require.EqualValues(t, coretypes.GasStateGet+4*coretypes.GasPerByte, gas.burned)
+
+	gas.burned = 0
+	require.True(t, r.MustHas("b"))
+	require.EqualValues(t, coretypes.GasStateGet+coretypes.GasPerByte, gas.burned)
+
+	gas.burned = 0
+	n := 0
+	r.MustIterate("", func(key kv.Key, value []byte) bool {
+		n++
+		return true
+	})
+	require.EqualValues(t, 2, n)
+	require.EqualValues(t, 2*coretypes.GasStateGet+6*coretypes.GasPerByte, gas.burned)
+}
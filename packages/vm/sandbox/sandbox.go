@@ -10,6 +10,7 @@ import (
 	"github.com/iotaledger/wasp/packages/hashing"
 	"github.com/iotaledger/wasp/packages/kv"
 	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/vm/core/eventlog"
 	"github.com/iotaledger/wasp/packages/vm/vmcontext"
 )
 
@@ -52,7 +53,15 @@ func (s *sandbox) Params() dict.Dict {
 }
 
 func (s *sandbox) State() kv.KVStore {
-	return s.vmctx.State()
+	return newGasMeteredState(s.vmctx.State(), s.vmctx)
+}
+
+func (s *sandbox) StateOf(contractHname coretypes.Hname) kv.KVStoreReader {
+	state := s.vmctx.StateOf(contractHname)
+	if state == nil {
+		return nil
+	}
+	return newGasMeteredStateReader(state, s.vmctx)
 }
 
 func (s *sandbox) Caller() coretypes.AgentID {
@@ -62,11 +71,13 @@ func (s *sandbox) Caller() coretypes.AgentID {
 // DeployContract deploys contract by the binary hash
 // and calls "init" endpoint (constructor) with provided parameters
 func (s *sandbox) DeployContract(programHash hashing.HashValue, name string, description string, initParams dict.Dict) error {
+	s.vmctx.BurnGas(coretypes.GasDeployContract)
 	return s.vmctx.DeployContract(programHash, name, description, initParams)
 }
 
 // Call calls an entry point of contact, passes parameters and funds
 func (s *sandbox) Call(contractHname coretypes.Hname, entryPoint coretypes.Hname, params dict.Dict, transfer coretypes.ColoredBalances) (dict.Dict, error) {
+	s.vmctx.BurnGas(coretypes.GasCall)
 	return s.vmctx.Call(contractHname, entryPoint, params, transfer)
 }
 
@@ -79,10 +90,12 @@ func (s *sandbox) GetEntropy() hashing.HashValue {
 }
 
 func (s *sandbox) TransferToAddress(targetAddr address.Address, transfer coretypes.ColoredBalances) bool {
+	s.vmctx.BurnGas(coretypes.GasTransferToAddress)
 	return s.vmctx.TransferToAddress(targetAddr, transfer)
 }
 
 func (s *sandbox) PostRequest(par coretypes.PostRequestParams) bool {
+	s.vmctx.BurnGas(coretypes.GasPostRequest)
 	return s.vmctx.PostRequest(par)
 }
 
@@ -91,11 +104,34 @@ func (s *sandbox) Log() coretypes.LogInterface {
 }
 
 func (s *sandbox) Event(msg string) {
+	s.vmctx.BurnGas(coretypes.GasEvent + uint64(len(msg))*coretypes.GasPerByte)
 	s.Log().Infof("eventlog::%s -> '%s'", s.vmctx.CurrentContractHname(), msg)
 	s.vmctx.StoreToEventLog(s.vmctx.CurrentContractHname(), []byte(msg))
 	s.vmctx.EventPublisher().Publish(msg)
 }
 
+func (s *sandbox) TypedEvent(name string, payload dict.Dict, topics ...[]byte) {
+	if err := eventlog.ValidateEvent(name, topics); err != nil {
+		s.Log().Panicf("TypedEvent: %v", err)
+	}
+	if payload == nil {
+		payload = dict.New()
+	}
+	rec := &eventlog.EventRecord{
+		Contract:  s.vmctx.CurrentContractHname(),
+		Timestamp: s.vmctx.Timestamp(),
+		RequestID: s.vmctx.RequestID(),
+		Name:      name,
+		Topics:    topics,
+		Payload:   payload.Clone(),
+	}
+	data := eventlog.EncodeEventRecord(rec)
+	s.vmctx.BurnGas(coretypes.GasEvent + uint64(len(data))*coretypes.GasPerByte)
+	s.Log().Infof("eventlog::%s -> %s", s.vmctx.CurrentContractHname(), rec.String())
+	s.vmctx.StoreTypedEvent(rec)
+	s.vmctx.EventPublisher().PublishTyped(name, payload, topics)
+}
+
 func (s *sandbox) IncomingTransfer() coretypes.ColoredBalances {
 	return s.vmctx.GetIncoming()
 }
@@ -107,3 +143,15 @@ func (s *sandbox) Balance(col balance.Color) int64 {
 func (s *sandbox) Balances() coretypes.ColoredBalances {
 	return s.vmctx.GetMyBalances()
 }
+
+func (s *sandbox) GasBudget() uint64 {
+	return s.vmctx.GasBudget()
+}
+
+func (s *sandbox) GasBurned() uint64 {
+	return s.vmctx.GasBurned()
+}
+
+func (s *sandbox) BurnGas(gas uint64) {
+	s.vmctx.BurnGas(gas)
+}
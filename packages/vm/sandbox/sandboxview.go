@@ -14,6 +14,8 @@ func init() {
 	vmcontext.NewSandboxView = newView
 }
 
+// sandboxView is passed to the views called while the request is being processed.
+// It implements coretypes.GasMeter: the views burn the gas of the request
 type sandboxView struct {
 	vmctx *vmcontext.VMContext
 }
@@ -47,7 +49,15 @@ func (s sandboxView) Params() dict.Dict {
 }
 
 func (s sandboxView) State() kv.KVStoreReader {
-	return s.vmctx.State()
+	return newGasMeteredStateReader(s.vmctx.State(), s.vmctx)
+}
+
+func (s sandboxView) StateOf(contractHname coretypes.Hname) kv.KVStoreReader {
+	state := s.vmctx.StateOf(contractHname)
+	if state == nil {
+		return nil
+	}
+	return newGasMeteredStateReader(state, s.vmctx)
 }
 
 func (s sandboxView) WriteableState() kv.KVStore {
@@ -65,3 +75,15 @@ func (s sandboxView) Balances() coretypes.ColoredBalances {
 func (s sandboxView) Log() coretypes.LogInterface {
 	return s.vmctx
 }
+
+func (s sandboxView) GasBudget() uint64 {
+	return s.vmctx.GasBudget()
+}
+
+func (s sandboxView) GasBurned() uint64 {
+	return s.vmctx.GasBurned()
+}
+
+func (s sandboxView) BurnGas(gas uint64) {
+	s.vmctx.BurnGas(gas)
+}
@@ -14,12 +14,15 @@ import (
 
 type Builder struct {
 	vtxb            *vtxBuilder
+	chainID         coretypes.ChainID
 	chainAddress    address.Address
 	stateSection    *sctransaction.StateSection
 	requestSections []*sctransaction.RequestSection
 }
 
-func New(chainAddress address.Address, chainColor balance.Color, addressBalances map[valuetransaction.ID][]*balance.Balance) (*Builder, error) {
+// New creates builder of the state transaction. The chainAddress is the address of the current
+// committee of the chain. It differs from the chain ID after the committee has been rotated
+func New(chainID coretypes.ChainID, chainAddress address.Address, chainColor balance.Color, addressBalances map[valuetransaction.ID][]*balance.Balance) (*Builder, error) {
 	if chainColor == balance.ColorNew || chainColor == balance.ColorIOTA {
 		return nil, errors.New("statetxbuilder.New: wrong chain color")
 	}
@@ -29,10 +32,12 @@ func New(chainAddress address.Address, chainColor balance.Color, addressBalances
 	}
 	ret := &Builder{
 		vtxb:            vtxb,
+		chainID:         chainID,
 		chainAddress:    chainAddress,
 		stateSection:    sctransaction.NewStateSection(sctransaction.NewStateSectionParams{Color: chainColor}),
 		requestSections: make([]*sctransaction.RequestSection, 0),
 	}
+	ret.setStateChainID()
 	err = vtxb.MoveTokens(ret.chainAddress, chainColor, 1)
 	return ret, err
 }
@@ -40,6 +45,7 @@ func New(chainAddress address.Address, chainColor balance.Color, addressBalances
 func (txb *Builder) Clone() *Builder {
 	ret := &Builder{
 		vtxb:            txb.vtxb.clone(),
+		chainID:         txb.chainID,
 		chainAddress:    txb.chainAddress,
 		stateSection:    txb.stateSection.Clone(),
 		requestSections: make([]*sctransaction.RequestSection, len(txb.requestSections)),
@@ -55,10 +61,50 @@ func (txb *Builder) SetStateParams(stateIndex uint32, stateHash hashing.HashValu
 	return nil
 }
 
+// ChainAddress is the address which holds the chain token and balances in the resulting transaction
+func (txb *Builder) ChainAddress() address.Address {
+	return txb.chainAddress
+}
+
+// RotateTo moves the chain token and all balances of the chain to the address of the new committee.
+// Outputs already created to the current chain address are redirected to the new address
+func (txb *Builder) RotateTo(newAddress address.Address) {
+	if newAddress == txb.chainAddress {
+		return
+	}
+	txb.vtxb.redirectOutputs(txb.chainAddress, newAddress)
+	txb.vtxb.reminderAddr = newAddress
+	txb.chainAddress = newAddress
+	txb.setStateChainID()
+}
+
+// SweepChainIDAddress adds the outputs of the chain ID address to the inputs of the transaction.
+// After the committee has been rotated, requests may still be sent to the chain ID address.
+// Their request tokens are consumed from there and the rest moves to the address of the current committee
+func (txb *Builder) SweepChainIDAddress(addressBalances map[valuetransaction.ID][]*balance.Balance) error {
+	if txb.chainAddress == address.Address(txb.chainID) {
+		return errors.New("SweepChainIDAddress: the committee of the chain has not been rotated")
+	}
+	return txb.vtxb.addInputs(address.Address(txb.chainID), addressBalances)
+}
+
+// setStateChainID puts the chain ID into the state section when it can't be derived from the chain address
+func (txb *Builder) setStateChainID() {
+	if txb.chainAddress == address.Address(txb.chainID) {
+		txb.stateSection.WithChainID(nil)
+		return
+	}
+	txb.stateSection.WithChainID(&txb.chainID)
+}
+
 // AddRequestSectionWithTransfer adds request block with the request
 // token and adds respective outputs for the colored transfers
 func (txb *Builder) AddRequestSection(req *sctransaction.RequestSection) error {
 	targetAddr := address.Address(req.Target().ChainID())
+	if req.Target().ChainID() == txb.chainID {
+		// request to itself goes to the current committee
+		targetAddr = txb.chainAddress
+	}
 	var err error
 	if err = txb.vtxb.MintColor(targetAddr, balance.ColorIOTA, 1); err != nil {
 		return err
@@ -1,11 +1,15 @@
 package statetxbuilder
 
 import (
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address/signaturescheme"
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
 	"github.com/iotaledger/hive.go/crypto/ed25519"
+	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/sctransaction"
+	"github.com/iotaledger/wasp/packages/txutil"
 	"github.com/stretchr/testify/require"
 	"testing"
 )
@@ -29,7 +33,7 @@ func TestBasic(t *testing.T) {
 			balance.New(balance.ColorIOTA, 5),
 		},
 	}
-	b, err := New(chAddr, col1, inps)
+	b, err := New(coretypes.ChainID(chAddr), chAddr, col1, inps)
 	require.NoError(t, err)
 
 	b.MustValidate()
@@ -61,7 +65,7 @@ func TestClone(t *testing.T) {
 			balance.New(balance.ColorIOTA, 5),
 		},
 	}
-	b, err := New(chAddr, col1, inps)
+	b, err := New(coretypes.ChainID(chAddr), chAddr, col1, inps)
 	require.NoError(t, err)
 
 	b.MustValidate()
@@ -78,3 +82,103 @@ func TestClone(t *testing.T) {
 
 	require.EqualValues(t, tx.ID(), tx1.ID())
 }
+
+func TestRotate(t *testing.T) {
+	chSig := signaturescheme.ED25519(ed25519.GenerateKeyPair())
+	chAddr := chSig.Address()
+	newAddr := signaturescheme.ED25519(ed25519.GenerateKeyPair()).Address()
+	chainID := coretypes.ChainID(chAddr)
+	col1, _, err := balance.ColorFromBytes(hashing.RandomHash(nil).Bytes())
+	require.NoError(t, err)
+	txid1, _, err := transaction.IDFromBytes(hashing.RandomHash(nil).Bytes())
+	require.NoError(t, err)
+
+	inps := map[transaction.ID][]*balance.Balance{
+		txid1: {
+			balance.New(col1, 1),
+			balance.New(balance.ColorIOTA, 3),
+		},
+	}
+	b, err := New(chainID, chAddr, col1, inps)
+	require.NoError(t, err)
+	b.RotateTo(newAddr)
+	req := sctransaction.NewRequestSection(0, coretypes.NewContractID(chainID, coretypes.Hn("test")), coretypes.Hn("test"))
+	require.NoError(t, b.AddRequestSection(req))
+
+	tx, err := b.Build()
+	require.NoError(t, err)
+	tx.Sign(chSig)
+
+	vtx, _, err := transaction.FromBytes(tx.Transaction.Bytes())
+	require.NoError(t, err)
+	parsed, err := sctransaction.ParseValueTransaction(vtx)
+	require.NoError(t, err)
+	prop := parsed.MustProperties()
+	require.EqualValues(t, chainID, *prop.MustChainID())
+	require.EqualValues(t, newAddr, prop.ChainAddress())
+	require.EqualValues(t, col1, *prop.MustStateColor())
+	require.EqualValues(t, 1, len(parsed.Requests()))
+	// the request token and all balances of the chain go to the new address
+	parsed.Outputs().ForEach(func(addr address.Address, _ []*balance.Balance) bool {
+		require.EqualValues(t, newAddr, addr)
+		return true
+	})
+}
+
+func TestSweepChainIDAddress(t *testing.T) {
+	chainIDSig := signaturescheme.ED25519(ed25519.GenerateKeyPair())
+	committeeSig := signaturescheme.ED25519(ed25519.GenerateKeyPair())
+	chainID := coretypes.ChainID(chainIDSig.Address())
+	committeeAddr := committeeSig.Address()
+	col1, _, err := balance.ColorFromBytes(hashing.RandomHash(nil).Bytes())
+	require.NoError(t, err)
+	txid1, _, err := transaction.IDFromBytes(hashing.RandomHash(nil).Bytes())
+	require.NoError(t, err)
+	txid2, _, err := transaction.IDFromBytes(hashing.RandomHash(nil).Bytes())
+	require.NoError(t, err)
+
+	inps := map[transaction.ID][]*balance.Balance{
+		txid1: {
+			balance.New(col1, 1),
+			balance.New(balance.ColorIOTA, 3),
+		},
+	}
+	// request token and transfer sent to the chain ID address after the rotation
+	reqColor := balance.Color(txid2)
+	chainIDInps := map[transaction.ID][]*balance.Balance{
+		txid2: {
+			balance.New(reqColor, 1),
+			balance.New(balance.ColorIOTA, 5),
+		},
+	}
+	b, err := New(chainID, committeeAddr, col1, inps)
+	require.NoError(t, err)
+	require.NoError(t, b.SweepChainIDAddress(chainIDInps))
+	require.EqualValues(t, 1, b.Balance(reqColor))
+	require.True(t, b.Erase1TokenToChain(reqColor))
+
+	tx, err := b.Build()
+	require.NoError(t, err)
+	tx.Sign(committeeSig)
+	tx.Sign(chainIDSig)
+
+	vtx, _, err := transaction.FromBytes(tx.Transaction.Bytes())
+	require.NoError(t, err)
+	parsed, err := sctransaction.ParseValueTransaction(vtx)
+	require.NoError(t, err)
+	prop := parsed.MustProperties()
+	require.EqualValues(t, chainID, *prop.MustChainID())
+	require.EqualValues(t, committeeAddr, prop.ChainAddress())
+	require.EqualValues(t, committeeAddr, *prop.SenderAddress())
+	// everything goes to the address of the committee
+	parsed.Outputs().ForEach(func(addr address.Address, _ []*balance.Balance) bool {
+		require.EqualValues(t, committeeAddr, addr)
+		return true
+	})
+	bals, _ := parsed.OutputBalancesByAddress(committeeAddr)
+	require.EqualValues(t, 3+5+1, txutil.BalanceOfColor(bals, balance.ColorIOTA))
+
+	b, err = New(chainID, chainIDSig.Address(), col1, inps)
+	require.NoError(t, err)
+	require.Error(t, b.SweepChainIDAddress(chainIDInps))
+}
@@ -40,10 +40,18 @@ func newValueTxBuilder(addr address.Address, addressBalances map[valuetransactio
 		outputBalances:        make(map[address.Address]map[balance.Color]int64),
 		originalBalances:      make(map[balance.Color]int64),
 	}
+	if err := ret.addInputs(addr, addressBalances); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// addInputs adds outputs of the address to the inputs. Remaining balances go to the reminder address
+func (vtxb *vtxBuilder) addInputs(addr address.Address, addressBalances map[valuetransaction.ID][]*balance.Balance) error {
 	var err error
 	for txid, bals := range addressBalances {
 		if balance.Color(txid) == balance.ColorNew || balance.Color(txid) == balance.ColorIOTA {
-			return nil, errorWrongInputs
+			return errorWrongInputs
 		}
 		inb := inputBalances{
 			outputId: valuetransaction.NewOutputID(addr, txid),
@@ -52,17 +60,17 @@ func newValueTxBuilder(addr address.Address, addressBalances map[valuetransactio
 		}
 		inb.remain, err = copyCompressAndSortBalances(inb.remain)
 		if err != nil {
-			return nil, err
+			return err
 		}
-		ret.inputBalancesByOutput = append(ret.inputBalancesByOutput, inb)
+		vtxb.inputBalancesByOutput = append(vtxb.inputBalancesByOutput, inb)
 
 		for _, bal := range bals {
-			b, _ := ret.originalBalances[bal.Color]
-			ret.originalBalances[bal.Color] = b + bal.Value
+			b, _ := vtxb.originalBalances[bal.Color]
+			vtxb.originalBalances[bal.Color] = b + bal.Value
 		}
 	}
-	ret.sortInputBalancesById() // for determinism
-	return ret, nil
+	vtxb.sortInputBalancesById() // for determinism
+	return nil
 }
 
 func (vtxb *vtxBuilder) clone() *vtxBuilder {
@@ -212,6 +220,18 @@ func (vtxb *vtxBuilder) addToOutputs(targetAddr address.Address, col balance.Col
 	cmap[col] = b + amount
 }
 
+// redirectOutputs moves all outputs created to the address 'from' to the address 'to'
+func (vtxb *vtxBuilder) redirectOutputs(from, to address.Address) {
+	cmap, ok := vtxb.outputBalances[from]
+	if !ok {
+		return
+	}
+	delete(vtxb.outputBalances, from)
+	for col, amount := range cmap {
+		vtxb.addToOutputs(to, col, amount)
+	}
+}
+
 // MoveTokens move token without changing color
 func (vtxb *vtxBuilder) MoveTokens(targetAddr address.Address, col balance.Color, amount int64) error {
 	if vtxb.GetInputBalance(col) < amount {
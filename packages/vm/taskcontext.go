@@ -2,6 +2,7 @@ package vm
 
 import (
 	"bytes"
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
 	valuetransaction "github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
 	"github.com/iotaledger/hive.go/logger"
@@ -17,6 +18,9 @@ import (
 type RequestRefWithFreeTokens struct {
 	sctransaction.RequestRef
 	FreeTokens coretypes.ColoredBalances
+	// ArgsTimeout is true if the committee agreed that arguments of the request were not solidified in time.
+	// The request is processed as failed and the transfer is returned to the sender
+	ArgsTimeout bool
 }
 
 // task context (for batch of requests)
@@ -24,10 +28,16 @@ type VMTask struct {
 	Processors *processors.ProcessorCache
 	// inputs (immutable)
 	ChainID coretypes.ChainID
-	Color   balance.Color
+	// address of the current committee, which holds the chain token and balances.
+	// It is equal to the ChainID until the committee is rotated
+	ChainAddress address.Address
+	Color        balance.Color
 	// deterministic source of entropy
-	Entropy            hashing.HashValue
-	Balances           map[valuetransaction.ID][]*balance.Balance
+	Entropy  hashing.HashValue
+	Balances map[valuetransaction.ID][]*balance.Balance
+	// outputs of the chain ID address after the committee has been rotated.
+	// They are swept to the current committee address by the state transaction
+	ChainIDBalances    map[valuetransaction.ID][]*balance.Balance
 	ValidatorFeeTarget coretypes.AgentID
 	Requests           []RequestRefWithFreeTokens
 	Timestamp          int64
@@ -41,10 +51,11 @@ type VMTask struct {
 }
 
 // BatchHash is used to uniquely identify the VM task
-func BatchHash(reqids []coretypes.RequestID, ts int64, leaderIndex uint16) hashing.HashValue {
+func BatchHash(reqids []coretypes.RequestID, argsTimeout []bool, ts int64, leaderIndex uint16) hashing.HashValue {
 	var buf bytes.Buffer
 	for i := range reqids {
 		buf.Write(reqids[i].Bytes())
+		_ = util.WriteBoolByte(&buf, argsTimeout[i])
 	}
 	_ = util.WriteInt64(&buf, ts)
 	_ = util.WriteUint16(&buf, leaderIndex)
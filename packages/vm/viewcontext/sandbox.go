@@ -49,6 +49,10 @@ func (s *sandboxview) State() kv.KVStoreReader {
 	return s.state
 }
 
+func (s *sandboxview) StateOf(contractHname coretypes.Hname) kv.KVStoreReader {
+	return s.vctx.stateOf(contractHname)
+}
+
 func (s *sandboxview) WriteableState() kv.KVStore {
 	return s.state
 }
@@ -1,6 +1,7 @@
 package viewcontext
 
 import (
+	"errors"
 	"fmt"
 	"github.com/iotaledger/hive.go/logger"
 	"github.com/iotaledger/wasp/packages/kv/buffered"
@@ -11,12 +12,16 @@ import (
 	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/packages/kv/subrealm"
 	"github.com/iotaledger/wasp/packages/state"
+	"github.com/iotaledger/wasp/packages/vm"
 	"github.com/iotaledger/wasp/packages/vm/core/blob"
 	"github.com/iotaledger/wasp/packages/vm/core/root"
 	"github.com/iotaledger/wasp/packages/vm/hardcoded"
 	"github.com/iotaledger/wasp/packages/vm/processors"
 )
 
+// ErrStateNotFound is returned when the past state is ahead of the solid state or can't be reconstructed
+var ErrStateNotFound = errors.New("state not found")
+
 type viewcontext struct {
 	processors *processors.ProcessorCache
 	state      kv.KVStore //buffered.BufferedKVStore
@@ -37,6 +42,18 @@ func NewFromDB(chainID coretypes.ChainID, proc *processors.ProcessorCache) (*vie
 	return New(chainID, state_.Variables(), state_.Timestamp(), proc, nil), nil
 }
 
+// NewFromDBAtBlock creates the view context for the past state of the chain, as it was after the block with the index
+func NewFromDBAtBlock(chainID coretypes.ChainID, blockIndex uint32, proc *processors.ProcessorCache) (*viewcontext, error) {
+	state_, _, ok, err := state.LoadStateAt(&chainID, blockIndex)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: #%d of chain %s", ErrStateNotFound, blockIndex, chainID.String())
+	}
+	return New(chainID, state_.Variables(), state_.Timestamp(), proc, nil), nil
+}
+
 func New(chainID coretypes.ChainID, state kv.KVStore, ts int64, proc *processors.ProcessorCache, logSet *logger.Logger) *viewcontext {
 	if logSet == nil {
 		logSet = logDefault
@@ -72,18 +89,21 @@ func (v *viewcontext) CallView(contractHname coretypes.Hname, epCode coretypes.H
 	return ret, err
 }
 
-func (v *viewcontext) mustCallView(contractHname coretypes.Hname, epCode coretypes.Hname, params dict.Dict) (dict.Dict, error) {
-	var err error
+func (v *viewcontext) getProcessor(contractHname coretypes.Hname) (coretypes.Processor, error) {
 	contractRecord, err := root.FindContract(contractStateSubpartition(v.state, root.Interface.Hname()), contractHname)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find contract %s: %v", contractHname, err)
 	}
-	proc, err := v.processors.GetOrCreateProcessor(contractRecord, func(programHash hashing.HashValue) (string, []byte, error) {
+	return v.processors.GetOrCreateProcessor(contractRecord, func(programHash hashing.HashValue) (string, []byte, error) {
 		if vmtype, ok := hardcoded.LocateHardcodedProgram(programHash); ok {
 			return vmtype, nil, nil
 		}
 		return blob.LocateProgram(contractStateSubpartition(v.state, blob.Interface.Hname()), programHash)
 	})
+}
+
+func (v *viewcontext) mustCallView(contractHname coretypes.Hname, epCode coretypes.Hname, params dict.Dict) (dict.Dict, error) {
+	proc, err := v.getProcessor(contractHname)
 	if err != nil {
 		return nil, err
 	}
@@ -99,6 +119,20 @@ func (v *viewcontext) mustCallView(contractHname coretypes.Hname, epCode coretyp
 	return ep.CallView(newSandboxView(v, contractHname, params))
 }
 
+// stateOf returns read-only access to the public part of the state of the contract,
+// or nil if the contract does not exist or it does not declare the public state prefix
+func (v *viewcontext) stateOf(contractHname coretypes.Hname) kv.KVStoreReader {
+	proc, err := v.getProcessor(contractHname)
+	if err != nil {
+		return nil
+	}
+	pub, ok := proc.(coretypes.PublicStateProcessor)
+	if !ok || pub.GetPublicStatePrefix() == "" {
+		return nil
+	}
+	return vm.NewPublicState(contractHname, contractStateSubpartition(v.state, contractHname), pub.GetPublicStatePrefix())
+}
+
 func contractStateSubpartition(state kv.KVStore, contractHname coretypes.Hname) kv.KVStore {
 	return subrealm.New(state, kv.Key(contractHname.Bytes()))
 }
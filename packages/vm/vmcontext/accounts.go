@@ -25,6 +25,10 @@ func (vmctx *VMContext) GetMyBalances() coretypes.ColoredBalances {
 func (vmctx *VMContext) TransferToAddress(targetAddr address.Address, transfer coretypes.ColoredBalances) bool {
 	privileged := vmctx.CurrentContractHname() == accounts.Interface.Hname()
 	fmt.Printf("TransferToAddress: %s privileged = %v\n", targetAddr.String(), privileged)
+	if vmctx.hasNativeTokens(transfer) {
+		vmctx.log.Debugf("TransferToAddress: chain-native tokens can't be sent to the address")
+		return false
+	}
 	if !privileged {
 		// if caller is accounts, it must debit from account by itself
 		agentID := vmctx.MyAgentID()
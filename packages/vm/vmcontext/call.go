@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv/codec"
 	"github.com/iotaledger/wasp/packages/vm/core/root"
 
 	"github.com/iotaledger/wasp/packages/coretypes"
@@ -12,10 +13,11 @@ import (
 
 var (
 	ErrContractNotFound   = errors.New("contract not found")
-	ErrEntryPointNotFound = errors.New("entry point not found")
+	ErrEntryPointNotFound = coretypes.ErrEntryPointNotFound
 	ErrProcessorNotFound  = errors.New("VM not found. Internal error")
 	ErrNotEnoughFees      = errors.New("not enough fees")
 	ErrWrongRequestToken  = errors.New("wrong request token")
+	ErrContractPaused     = errors.New("contract is paused")
 )
 
 // Call
@@ -25,6 +27,10 @@ func (vmctx *VMContext) Call(targetContract coretypes.Hname, epCode coretypes.Hn
 	if !ok {
 		return nil, ErrContractNotFound
 	}
+	// only 'root' can call the paused contract
+	if rec.Paused && vmctx.CurrentContractHname() != root.Interface.Hname() {
+		return nil, ErrContractPaused
+	}
 	return vmctx.callByProgramHash(targetContract, epCode, params, transfer, rec.ProgramHash)
 }
 
@@ -61,7 +67,15 @@ func (vmctx *VMContext) callByProgramHash(targetContract coretypes.Hname, epCode
 			return nil, fmt.Errorf("attempt to callByProgramHash init not from the root contract")
 		}
 	}
-	return ep.Call(NewSandbox(vmctx))
+	// prevent calling 'migrate' not from root contract
+	if epCode == coretypes.EntryPointMigrate && !vmctx.callerIsRoot() {
+		return nil, fmt.Errorf("attempt to callByProgramHash migrate not from the root contract")
+	}
+	ret, err := ep.Call(NewSandbox(vmctx))
+	if err == nil {
+		vmctx.evictUpgradedProcessor(targetContract, epCode, ret)
+	}
+	return ret, err
 }
 
 func (vmctx *VMContext) callNonViewByProgramHash(targetContract coretypes.Hname, epCode coretypes.Hname, params dict.Dict, transfer coretypes.ColoredBalances, progHash hashing.HashValue) (dict.Dict, error) {
@@ -89,7 +103,28 @@ func (vmctx *VMContext) callNonViewByProgramHash(targetContract coretypes.Hname,
 			return nil, fmt.Errorf("attempt to callByProgramHash init not from the root contract")
 		}
 	}
-	return ep.Call(NewSandbox(vmctx))
+	// prevent calling 'migrate' not from root contract
+	if epCode == coretypes.EntryPointMigrate && !vmctx.callerIsRoot() {
+		return nil, fmt.Errorf("attempt to callByProgramHash migrate not from the root contract")
+	}
+	ret, err := ep.Call(NewSandbox(vmctx))
+	if err == nil {
+		vmctx.evictUpgradedProcessor(targetContract, epCode, ret)
+	}
+	return ret, err
+}
+
+// evictUpgradedProcessor removes the processor of the replaced program from the cache after the upgrade of the contract.
+// If the program is still used by other contracts, the processor will be loaded again upon the next call
+func (vmctx *VMContext) evictUpgradedProcessor(targetContract coretypes.Hname, epCode coretypes.Hname, result dict.Dict) {
+	if targetContract != root.Interface.Hname() || epCode != coretypes.Hn(root.FuncUpgradeContract) {
+		return
+	}
+	oldProgHash, ok, err := codec.DecodeHashValue(result.MustGet(root.ParamOldProgramHash))
+	if err != nil || !ok {
+		return
+	}
+	vmctx.processors.RemoveProcessor(oldProgHash)
 }
 
 func (vmctx *VMContext) callerIsRoot() bool {
@@ -101,7 +136,7 @@ func (vmctx *VMContext) callerIsRoot() bool {
 }
 
 func (vmctx *VMContext) requesterIsChainOwner() bool {
-	return vmctx.chainOwnerID == vmctx.reqRef.SenderAgentID()
+	return vmctx.chainOwnerID == vmctx.requestSender()
 }
 
 func (vmctx *VMContext) Params() dict.Dict {
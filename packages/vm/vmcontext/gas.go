@@ -0,0 +1,81 @@
+package vmcontext
+
+import (
+	"fmt"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/coretypes/cbalances"
+)
+
+// GasBudget returns the gas budget of the current request
+func (vmctx *VMContext) GasBudget() uint64 {
+	return vmctx.gasBudget
+}
+
+// GasBurned returns the gas consumed by the current request so far
+func (vmctx *VMContext) GasBurned() uint64 {
+	return vmctx.gasBurned
+}
+
+// BurnGas consumes gas from the budget of the current request.
+// Gas is burned only while the request is being processed by the contracts.
+// When the budget is exhausted, it panics with coretypes.ErrOutOfGas. The panic is recovered
+// in RunTheRequest, so the request fails deterministically on all nodes
+func (vmctx *VMContext) BurnGas(gas uint64) {
+	if !vmctx.gasMetered {
+		return
+	}
+	if vmctx.gasBurned+gas > vmctx.gasBudget {
+		vmctx.gasBurned = vmctx.gasBudget
+		panic(coretypes.ErrOutOfGas)
+	}
+	vmctx.gasBurned += gas
+}
+
+func (vmctx *VMContext) errOutOfGas() error {
+	return fmt.Errorf("%w: gas budget %d exhausted", coretypes.ErrOutOfGas, vmctx.gasBudget)
+}
+
+// mustReserveGasFee takes the maximum possible gas fee for the budget of the request from the remaining
+// transfer. Returns false if the remaining transfer is not enough to cover it
+func (vmctx *VMContext) mustReserveGasFee() bool {
+	maxGasFee := coretypes.GasFee(vmctx.gasBudget, vmctx.gasPrice)
+	if maxGasFee == 0 {
+		return true
+	}
+	if vmctx.remainingAfterFees.Balance(vmctx.feeColor) < maxGasFee {
+		return false
+	}
+	remaining := map[balance.Color]int64{
+		vmctx.feeColor: -maxGasFee,
+	}
+	vmctx.remainingAfterFees.AddToMap(remaining)
+	vmctx.remainingAfterFees = cbalances.NewFromMap(remaining)
+	vmctx.gasFeeReserved = maxGasFee
+	return true
+}
+
+// mustSettleGasFee charges the fee for the gas actually burned by the request. The fee is accrued
+// to the validator, the rest of the reserved amount is returned to the on-chain account of the payer
+func (vmctx *VMContext) mustSettleGasFee() {
+	if vmctx.gasFeeReserved == 0 {
+		return
+	}
+	gasFee := coretypes.GasFee(vmctx.gasBurned, vmctx.gasPrice)
+	if gasFee > vmctx.gasFeeReserved {
+		gasFee = vmctx.gasFeeReserved
+	}
+	if gasFee > 0 {
+		vmctx.creditToAccount(vmctx.validatorFeeTarget, cbalances.NewFromMap(map[balance.Color]int64{
+			vmctx.feeColor: gasFee,
+		}))
+	}
+	if refund := vmctx.gasFeeReserved - gasFee; refund > 0 {
+		vmctx.creditToAccount(vmctx.feePayer(), cbalances.NewFromMap(map[balance.Color]int64{
+			vmctx.feeColor: refund,
+		}))
+	}
+	vmctx.log.Debugf("mustSettleGasFee: gas burned: %d, gas fee: %d", vmctx.gasBurned, gasFee)
+	vmctx.gasFeeReserved = 0
+}
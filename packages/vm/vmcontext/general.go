@@ -64,6 +64,10 @@ func (vmctx *VMContext) PostRequest(par coretypes.PostRequestParams) bool {
 		"ep", par.EntryPoint.String(),
 		"transfer", cbalances.Str(par.Transfer),
 	)
+	if vmctx.hasNativeTokens(par.Transfer) {
+		vmctx.log.Debugf("-- PostRequest: chain-native tokens can't be sent to another chain")
+		return false
+	}
 	myAgentID := vmctx.MyAgentID()
 	if !vmctx.debitFromAccount(myAgentID, cbalances.NewFromMap(map[balance.Color]int64{
 		balance.ColorIOTA: 1,
@@ -79,7 +83,10 @@ func (vmctx *VMContext) PostRequest(par coretypes.PostRequestParams) bool {
 	reqParams.AddEncodeSimpleMany(par.Params)
 	reqSection := sctransaction.NewRequestSection(vmctx.CurrentContractHname(), par.TargetContractID, par.EntryPoint).
 		WithTimelock(par.TimeLock).
+		WithGasBudget(par.GasBudget).
 		WithTransfer(par.Transfer).
+		WithCallback(par.Callback).
+		WithRefundOnFailure(par.RefundOnFailure).
 		WithArgs(reqParams)
 	return vmctx.txBuilder.AddRequestSection(reqSection) == nil
 }
@@ -108,5 +115,8 @@ func (vmctx *VMContext) EventPublisher() vm.ContractEventPublisher {
 }
 
 func (vmctx *VMContext) RequestID() coretypes.RequestID {
+	if vmctx.job != nil {
+		return vmctx.jobRequestID
+	}
 	return *vmctx.reqRef.RequestID()
 }
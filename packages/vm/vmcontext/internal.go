@@ -1,10 +1,12 @@
 package vmcontext
 
 import (
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
 	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/coretypes/cbalances"
 	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv/codec"
 	"github.com/iotaledger/wasp/packages/vm/core/accounts"
 	"github.com/iotaledger/wasp/packages/vm/core/blob"
 	"github.com/iotaledger/wasp/packages/vm/core/eventlog"
@@ -45,6 +47,14 @@ func (vmctx *VMContext) moveBetweenAccounts(fromAgentID, toAgentID coretypes.Age
 	return accounts.MoveBetweenAccounts(vmctx.State(), fromAgentID, toAgentID, transfer)
 }
 
+// hasNativeTokens returns true if the transfer contains chain-native tokens. They can't leave the chain
+func (vmctx *VMContext) hasNativeTokens(transfer coretypes.ColoredBalances) bool {
+	vmctx.pushCallContext(accounts.Interface.Hname(), nil, nil)
+	defer vmctx.popCallContext()
+
+	return accounts.HasNativeTokens(vmctx.State(), transfer)
+}
+
 func (vmctx *VMContext) findContractByHname(contractHname coretypes.Hname) (*root.ContractRecord, bool) {
 	vmctx.pushCallContext(root.Interface.Hname(), nil, nil)
 	defer vmctx.popCallContext()
@@ -56,6 +66,15 @@ func (vmctx *VMContext) findContractByHname(contractHname coretypes.Hname) (*roo
 	return ret, true
 }
 
+// isAccessAllowed checks the request against the access control lists of the chain
+func (vmctx *VMContext) isAccessAllowed() bool {
+	vmctx.pushCallContext(root.Interface.Hname(), nil, nil)
+	defer vmctx.popCallContext()
+
+	return root.IsAccessAllowed(vmctx.State(), vmctx.reqHname, vmctx.reqRef.RequestSection().EntryPointCode(),
+		vmctx.reqRef.SenderAgentID())
+}
+
 func (vmctx *VMContext) mustGetChainInfo() root.ChainInfo {
 	vmctx.pushCallContext(root.Interface.Hname(), nil, nil)
 	defer vmctx.popCallContext()
@@ -63,6 +82,18 @@ func (vmctx *VMContext) mustGetChainInfo() root.ChainInfo {
 	return root.MustGetChainInfo(vmctx.State())
 }
 
+// getChainAddress returns the committee address stored in the 'root' contract, if the chain is initialized
+func (vmctx *VMContext) getChainAddress() (address.Address, bool) {
+	vmctx.pushCallContext(root.Interface.Hname(), nil, nil)
+	defer vmctx.popCallContext()
+
+	ret, ok, err := codec.DecodeAddress(vmctx.State().MustGet(root.VarChainAddress))
+	if err != nil || !ok {
+		return address.Address{}, false
+	}
+	return ret, true
+}
+
 func (vmctx *VMContext) getFeeInfo() (balance.Color, int64, int64) {
 	vmctx.pushCallContext(root.Interface.Hname(), nil, nil)
 	defer vmctx.popCallContext()
@@ -117,3 +148,11 @@ func (vmctx *VMContext) StoreToEventLog(contract coretypes.Hname, data []byte) {
 	vmctx.log.Debugf("StoreToEventLog/%s: data: '%s'", contract.String(), string(data))
 	eventlog.AppendToLog(vmctx.State(), vmctx.timestamp, contract, data)
 }
+
+func (vmctx *VMContext) StoreTypedEvent(rec *eventlog.EventRecord) {
+	vmctx.pushCallContext(eventlog.Interface.Hname(), nil, nil)
+	defer vmctx.popCallContext()
+
+	vmctx.log.Debugf("StoreTypedEvent/%s: event: '%s'", rec.Contract.String(), rec.Name)
+	eventlog.AppendEvent(vmctx.State(), rec)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/iotaledger/wasp/packages/state"
 	"github.com/iotaledger/wasp/packages/vm"
 	"github.com/iotaledger/wasp/packages/vm/core/root"
+	"github.com/iotaledger/wasp/packages/vm/core/scheduler"
 	"github.com/iotaledger/wasp/packages/vm/processors"
 	"github.com/iotaledger/wasp/packages/vm/statetxbuilder"
 )
@@ -32,6 +33,7 @@ type VMContext struct {
 	feeColor           balance.Color
 	ownerFee           int64
 	validatorFee       int64
+	gasPrice           int64
 	// request context
 	remainingAfterFees coretypes.ColoredBalances
 	entropy            hashing.HashValue // mutates with each request
@@ -40,6 +42,13 @@ type VMContext struct {
 	contractRecord     *root.ContractRecord
 	timestamp          int64
 	stateUpdate        state.StateUpdate
+	gasBudget          uint64
+	gasBurned          uint64
+	gasMetered         bool
+	gasFeeReserved     int64
+	refund             coretypes.ColoredBalances
+	job                *scheduler.JobRecord // not nil if the scheduled job is run instead of the request
+	jobRequestID       coretypes.RequestID
 	lastError          error     // mutated
 	lastResult         dict.Dict // mutated. Used only by 'solo'
 	callStack          []*callContext
@@ -56,14 +65,15 @@ type callContext struct {
 // NewVMContext a constructor
 func NewVMContext(task *vm.VMTask, txb *statetxbuilder.Builder) (*VMContext, error) {
 	ret := &VMContext{
-		processors:   task.Processors,
-		chainID:      task.ChainID,
-		balances:     task.Balances,
-		txBuilder:    txb,
-		virtualState: task.VirtualState.Clone(),
-		log:          task.Log,
-		entropy:      task.Entropy,
-		callStack:    make([]*callContext, 0),
+		processors:         task.Processors,
+		chainID:            task.ChainID,
+		balances:           task.Balances,
+		txBuilder:          txb,
+		virtualState:       task.VirtualState.Clone(),
+		validatorFeeTarget: task.ValidatorFeeTarget,
+		log:                task.Log,
+		entropy:            task.Entropy,
+		callStack:          make([]*callContext, 0),
 	}
 	return ret, nil
 }
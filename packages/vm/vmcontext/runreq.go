@@ -5,11 +5,16 @@ import (
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
 	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/coretypes/cbalances"
+	"github.com/iotaledger/wasp/packages/coretypes/requestargs"
 	"github.com/iotaledger/wasp/packages/hashing"
 	"github.com/iotaledger/wasp/packages/kv/buffered"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/packages/sctransaction"
 	"github.com/iotaledger/wasp/packages/state"
 	"github.com/iotaledger/wasp/packages/vm"
+	"github.com/iotaledger/wasp/packages/vm/core/accounts"
+	"github.com/iotaledger/wasp/packages/vm/core/eventlog"
 	"github.com/iotaledger/wasp/packages/vm/core/root"
 )
 
@@ -22,6 +27,23 @@ func (vmctx *VMContext) RunTheRequest(reqRef vm.RequestRefWithFreeTokens, timest
 
 	if !vmctx.isInitChainRequest() {
 		vmctx.mustGetBaseValues()
+		if vmctx.reqRef.ArgsTimeout {
+			vmctx.mustRejectRequest(fmt.Errorf("arguments of the request were not solidified in time"))
+			return
+		}
+		if err := vmctx.reqRef.RequestSection().ArgsError(); err != nil {
+			vmctx.mustRejectRequest(fmt.Errorf("wrong request arguments: %v", err))
+			return
+		}
+		if vmctx.contractRecord != nil && vmctx.contractRecord.Paused {
+			vmctx.mustRejectRequest(fmt.Errorf("smart contract '%s' is paused", vmctx.reqHname))
+			return
+		}
+		if vmctx.contractRecord != nil && !vmctx.isAccessAllowed() {
+			vmctx.mustRejectRequest(fmt.Errorf("access to '%s'::%s denied for %s",
+				vmctx.reqHname, vmctx.reqRef.RequestSection().EntryPointCode(), vmctx.reqRef.SenderAgentID()))
+			return
+		}
 		vmctx.mustHandleFees()
 	}
 	vmctx.mustHandleFreeTokens()
@@ -31,28 +53,24 @@ func (vmctx *VMContext) RunTheRequest(reqRef vm.RequestRefWithFreeTokens, timest
 		// sc does not exist, stop here
 		vmctx.lastResult = nil
 		vmctx.lastError = fmt.Errorf("smart contract '%s' does not exist", vmctx.reqHname)
+		vmctx.mustHandleFallback()
+		return
+	}
+	if !vmctx.isInitChainRequest() && !vmctx.requesterIsChainOwner() && !vmctx.mustReserveGasFee() {
+		// not enough tokens to cover the gas budget. Accrue everything to the sender
+		sender := vmctx.reqRef.SenderAgentID()
+		vmctx.creditToAccount(sender, vmctx.remainingAfterFees)
+		vmctx.remainingAfterFees = cbalances.NewFromMap(nil)
+		vmctx.lastResult = nil
+		vmctx.lastError = fmt.Errorf("not enough fees to cover gas budget %d of request %s. Transfer accrued to %s",
+			vmctx.gasBudget, vmctx.reqRef.RequestID().Short(), sender.String())
 		return
 	}
 	// snapshot state baseline for rollback in case of panic
 	snapshotTxBuilder := vmctx.txBuilder.Clone()
 	snapshotStateUpdate := vmctx.stateUpdate.Clone()
 
-	vmctx.lastError = nil
-	func() {
-		// panic catcher for the whole call from request to the VM
-		defer func() {
-			if r := recover(); r != nil {
-				vmctx.lastResult = nil
-				vmctx.lastError = fmt.Errorf("recovered from panic in VM: %v", r)
-				if dberr, ok := r.(buffered.DBError); ok {
-					// There was an error accessing the DB
-					// The world stops
-					vmctx.Panicf("DB error: %v", dberr)
-				}
-			}
-		}()
-		vmctx.mustCallFromRequest()
-	}()
+	vmctx.callMetered(vmctx.mustCallFromRequest)
 
 	if vmctx.lastError != nil {
 		// treating panic and error returned from request the same way
@@ -61,6 +79,31 @@ func (vmctx *VMContext) RunTheRequest(reqRef vm.RequestRefWithFreeTokens, timest
 
 		vmctx.mustHandleFallback()
 	}
+	// gas fee is charged for successful and failed requests alike
+	vmctx.mustSettleGasFee()
+}
+
+// callMetered runs the call with gas metering. Panic in the call is recovered and treated as an error
+func (vmctx *VMContext) callMetered(call func()) {
+	vmctx.lastError = nil
+	vmctx.gasMetered = true
+	// panic catcher for the whole call from request to the VM
+	defer func() {
+		vmctx.gasMetered = false
+		if r := recover(); r != nil {
+			vmctx.lastResult = nil
+			vmctx.lastError = fmt.Errorf("recovered from panic in VM: %v", r)
+			if r == coretypes.ErrOutOfGas {
+				vmctx.lastError = vmctx.errOutOfGas()
+			}
+			if dberr, ok := r.(buffered.DBError); ok {
+				// There was an error accessing the DB
+				// The world stops
+				vmctx.Panicf("DB error: %v", dberr)
+			}
+		}
+	}()
+	call()
 }
 
 // mustHandleRequestToken handles the request token
@@ -132,21 +175,110 @@ func (vmctx *VMContext) mustHandleFreeTokens() {
 	vmctx.creditToAccount(vmctx.ChainOwnerID(), vmctx.reqRef.FreeTokens)
 }
 
+// mustRejectRequest the request to the paused contract, the request denied by the ACL or the request with
+// arguments which can't be decrypted or weren't solidified in time is not processed.
+// No fees are charged, all tokens are returned to the sender
+func (vmctx *VMContext) mustRejectRequest(err error) {
+	vmctx.mustHandleFreeTokens()
+	defer vmctx.finalizeRequestCall()
+
+	vmctx.lastResult = nil
+	vmctx.lastError = err
+	vmctx.mustHandleFallback()
+}
+
 // mustHandleFallback all remaining tokens are:
 // -- if sender is address, sent to that address
+// -- if sender is a contract and the request was posted with 'refund on failure', returned to the sender
+// by the return request
 // -- otherwise accrue to the sender on-chain
 func (vmctx *VMContext) mustHandleFallback() {
 	sender := vmctx.reqRef.SenderAgentID()
-	if sender.IsAddress() {
+	switch {
+	case sender.IsAddress():
 		err := vmctx.txBuilder.TransferToAddress(sender.MustAddress(), vmctx.remainingAfterFees)
 		if err != nil {
 			vmctx.log.Panicf("mustHandleFallback: transferring tokens to address %s", sender.MustAddress().String())
 		}
-	} else {
+	case vmctx.reqRef.RequestSection().RefundOnFailure():
+		vmctx.refund = vmctx.remainingAfterFees
+	default:
 		vmctx.creditToAccount(sender, vmctx.remainingAfterFees)
 	}
 }
 
+// mustPostReturnRequest posts the return request to the contract which posted the request, if the request
+// has a callback or the transfer must be refunded. The return request calls the callback entry point with
+// the result of the request, or deposits the refund to the account of the contract if there is no callback.
+// The request token of the request, accrued to the sender, is used as the request token of the return request
+func (vmctx *VMContext) mustPostReturnRequest() {
+	req := vmctx.reqRef.RequestSection()
+	refund := vmctx.refund
+	vmctx.refund = nil
+	if req.Callback() == 0 && refund == nil {
+		return
+	}
+	senderContract, err := vmctx.reqRef.SenderContractID()
+	if err != nil {
+		// the request wasn't posted by a smart contract
+		return
+	}
+	sender := coretypes.NewAgentIDFromContractID(senderContract)
+	if refund == nil {
+		refund = cbalances.NewFromMap(nil)
+	}
+	if !vmctx.debitFromAccount(sender, cbalances.NewFromMap(map[balance.Color]int64{
+		balance.ColorIOTA: 1,
+	})) {
+		vmctx.log.Errorf("mustPostReturnRequest: not enough funds for request token. Refund accrued to %s", sender)
+		vmctx.creditToAccount(sender, refund)
+		return
+	}
+	target := accounts.Interface.ContractID(senderContract.ChainID())
+	entryPoint := coretypes.Hn(accounts.FuncDeposit)
+	params := codec.MakeDict(map[string]interface{}{
+		accounts.ParamAgentID: sender,
+	})
+	switch {
+	case req.Callback() != 0:
+		target = senderContract
+		entryPoint = req.Callback()
+		params = vmctx.callbackResult().Params()
+	case senderContract.Hname() == accounts.Interface.Hname():
+		// the refund of the 'deposit' request posted by 'accounts.withdrawToChain' is credited back
+		// to the withdrawing contract, which is known from the arguments of the failed request
+		entryPoint = coretypes.Hn(accounts.FuncWithdrawToChainCallback)
+		params = vmctx.callbackResult().Params()
+	}
+	args := requestargs.New(nil)
+	args.AddEncodeSimpleMany(params)
+	section := sctransaction.NewRequestSection(vmctx.reqHname, target, entryPoint).
+		WithTransfer(refund).
+		WithArgs(args)
+	if err := vmctx.txBuilder.AddRequestSection(section); err != nil {
+		vmctx.log.Panicf("mustPostReturnRequest: %v", err)
+	}
+	vmctx.log.Debugf("mustPostReturnRequest: return request to %s::%s, refund: %s",
+		target.String(), entryPoint.String(), cbalances.Str(refund))
+}
+
+// callbackResult is the result of the current request, passed to the callback by the return request
+func (vmctx *VMContext) callbackResult() *coretypes.CallbackResult {
+	ret := &coretypes.CallbackResult{
+		RequestID: *vmctx.reqRef.RequestID(),
+		Args:      vmctx.reqRef.RequestSection().SolidArgs(),
+	}
+	if vmctx.lastError != nil {
+		ret.Error = vmctx.lastError.Error()
+		return ret
+	}
+	ret.Results = vmctx.lastResult
+	if ret.Results == nil {
+		ret.Results = dict.New()
+	}
+	return ret
+}
+
 // mustCallFromRequest is the call itself. Assumes sc exists
 func (vmctx *VMContext) mustCallFromRequest() {
 	req := vmctx.reqRef.RequestSection()
@@ -158,7 +290,9 @@ func (vmctx *VMContext) mustCallFromRequest() {
 }
 
 func (vmctx *VMContext) finalizeRequestCall() {
+	vmctx.mustPostReturnRequest()
 	vmctx.mustRequestToEventLog(vmctx.lastError)
+	vmctx.stateUpdate.WithResult(vmctx.requestResult())
 	vmctx.virtualState.ApplyStateUpdate(vmctx.stateUpdate)
 
 	vmctx.log.Debugw("runTheRequest OUT",
@@ -167,17 +301,31 @@ func (vmctx *VMContext) finalizeRequestCall() {
 	)
 }
 
+// requestResult is the outcome of the call, recorded in the state update
+func (vmctx *VMContext) requestResult() *state.RequestResult {
+	ret := &state.RequestResult{
+		Contract:  vmctx.reqHname,
+		Result:    vmctx.lastResult,
+		GasBurned: vmctx.gasBurned,
+	}
+	if vmctx.job != nil {
+		ret.Job = true
+		ret.JobID = vmctx.job.ID
+	}
+	if vmctx.lastError != nil {
+		ret.Result = nil
+		ret.Error = vmctx.lastError.Error()
+	}
+	return ret
+}
+
 func (vmctx *VMContext) mustRequestToEventLog(err error) {
 	if err != nil {
 		vmctx.log.Error(err)
 	}
-	e := "Ok"
-	if err != nil {
-		e = err.Error()
-	}
-	msg := fmt.Sprintf("[req] %s: %s", vmctx.reqRef.RequestID().String(), e)
-	vmctx.log.Infof("eventlog -> '%s'", msg)
-	vmctx.StoreToEventLog(vmctx.reqHname, []byte(msg))
+	msg := eventlog.RequestRecord(vmctx.reqRef.RequestID(), err, vmctx.gasBurned)
+	vmctx.log.Infof("eventlog -> '%s'", string(msg))
+	vmctx.StoreToEventLog(vmctx.reqHname, msg)
 }
 
 // mustGetBaseValues only makes sense if chain is already deployed
@@ -188,6 +336,7 @@ func (vmctx *VMContext) mustGetBaseValues() {
 	}
 	vmctx.chainOwnerID = info.ChainOwnerID
 	vmctx.feeColor, vmctx.ownerFee, vmctx.validatorFee = vmctx.getFeeInfo()
+	vmctx.gasPrice = info.GasPrice
 }
 
 // initRequestContext initializes VMContext for request and returns  if contract exists
@@ -201,6 +350,12 @@ func (vmctx *VMContext) initRequestContext(reqRef vm.RequestRefWithFreeTokens, t
 	vmctx.callStack = vmctx.callStack[:0]
 	vmctx.entropy = hashing.HashData(vmctx.entropy[:])
 	vmctx.remainingAfterFees = cbalances.NewFromMap(nil)
+	vmctx.gasBudget = coretypes.EffectiveGasBudget(reqRef.RequestSection().GasBudget())
+	vmctx.gasBurned = 0
+	vmctx.gasMetered = false
+	vmctx.gasFeeReserved = 0
+	vmctx.refund = nil
+	vmctx.job = nil
 
 	vmctx.contractRecord, _ = vmctx.findContractByHname(vmctx.reqHname)
 }
@@ -211,6 +366,11 @@ func (vmctx *VMContext) isInitChainRequest() bool {
 }
 
 func (vmctx *VMContext) FinalizeTransactionEssence(blockIndex uint32, stateHash hashing.HashValue, timestamp int64) (*sctransaction.Transaction, error) {
+	// move the chain to the new committee if the address was changed by 'root.rotateCommittee'
+	if chainAddress, ok := vmctx.getChainAddress(); ok && chainAddress != vmctx.txBuilder.ChainAddress() {
+		vmctx.log.Infof("rotating committee: %s --> %s", vmctx.txBuilder.ChainAddress(), chainAddress)
+		vmctx.txBuilder.RotateTo(chainAddress)
+	}
 	// add state block
 	err := vmctx.txBuilder.SetStateParams(blockIndex, stateHash, timestamp)
 	if err != nil {
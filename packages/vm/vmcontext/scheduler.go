@@ -0,0 +1,159 @@
+package vmcontext
+
+import (
+	"fmt"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/coretypes/cbalances"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/state"
+	"github.com/iotaledger/wasp/packages/vm/core/eventlog"
+	"github.com/iotaledger/wasp/packages/vm/core/scheduler"
+)
+
+// requestSender is the sender of the current request. The sender of the scheduled job is the 'scheduler' contract
+func (vmctx *VMContext) requestSender() coretypes.AgentID {
+	if vmctx.job != nil {
+		return coretypes.NewAgentIDFromContractID(scheduler.Interface.ContractID(vmctx.chainID))
+	}
+	return vmctx.reqRef.SenderAgentID()
+}
+
+// feePayer is the agent which pays fees of the current request. Fees of the scheduled job are paid by the contract
+func (vmctx *VMContext) feePayer() coretypes.AgentID {
+	if vmctx.job != nil {
+		return coretypes.NewAgentIDFromContractID(coretypes.NewContractID(vmctx.chainID, vmctx.job.Contract))
+	}
+	return vmctx.reqRef.SenderAgentID()
+}
+
+// DueJobs returns jobs of the 'scheduler' which are due at the timestamp, in the order they must be run
+func (vmctx *VMContext) DueJobs(timestamp int64) []*scheduler.JobRecord {
+	ret, err := scheduler.GetDueJobs(scheduler.Partition(vmctx.virtualState.Variables()), timestamp)
+	if err != nil {
+		vmctx.log.Errorf("DueJobs: %v", err)
+		return nil
+	}
+	return ret
+}
+
+// RunScheduledJob runs the due job as a synthetic request: the entry point of the contract is called by
+// the 'scheduler' contract. Fees are paid from the account of the contract. If the account can't cover fees,
+// or the contract is paused, the run is skipped. The result is recorded in the event log, as for the request
+func (vmctx *VMContext) RunScheduledJob(job *scheduler.JobRecord, timestamp int64) {
+	vmctx.initJobContext(job, timestamp)
+	vmctx.mustGetBaseValues()
+	defer vmctx.finalizeJobCall()
+
+	switch {
+	case vmctx.contractRecord == nil:
+		vmctx.lastError = fmt.Errorf("smart contract '%s' does not exist. Job #%d removed", job.Contract, job.ID)
+		return
+	case vmctx.contractRecord.Paused:
+		vmctx.lastError = fmt.Errorf("smart contract '%s' is paused. Run of job #%d skipped", job.Contract, job.ID)
+		return
+	case !vmctx.mustChargeJobFees():
+		vmctx.lastError = fmt.Errorf("not enough funds to cover fees of job #%d. Run skipped", job.ID)
+		return
+	}
+	// snapshot state baseline for rollback in case of panic
+	snapshotTxBuilder := vmctx.txBuilder.Clone()
+	snapshotStateUpdate := vmctx.stateUpdate.Clone()
+
+	vmctx.callMetered(func() {
+		vmctx.lastResult, vmctx.lastError = vmctx.callNonViewByProgramHash(
+			job.Contract, job.EntryPoint, job.Args, nil, vmctx.contractRecord.ProgramHash)
+	})
+	if vmctx.lastError != nil {
+		vmctx.txBuilder = snapshotTxBuilder
+		vmctx.stateUpdate = snapshotStateUpdate
+	}
+	vmctx.mustSettleGasFee()
+}
+
+func (vmctx *VMContext) initJobContext(job *scheduler.JobRecord, timestamp int64) {
+	vmctx.job = job
+	vmctx.jobRequestID = scheduler.JobRequestID(vmctx.chainID, job, timestamp)
+	vmctx.reqHname = job.Contract
+
+	vmctx.timestamp = timestamp
+	vmctx.stateUpdate = state.NewStateUpdate(&vmctx.jobRequestID).WithTimestamp(timestamp)
+	vmctx.callStack = vmctx.callStack[:0]
+	vmctx.entropy = hashing.HashData(vmctx.entropy[:])
+	vmctx.remainingAfterFees = cbalances.NewFromMap(nil)
+	vmctx.gasBudget = coretypes.EffectiveGasBudget(job.GasBudget)
+	vmctx.gasBurned = 0
+	vmctx.gasMetered = false
+	vmctx.gasFeeReserved = 0
+	vmctx.refund = nil
+	vmctx.lastResult = nil
+	vmctx.lastError = nil
+
+	vmctx.contractRecord, _ = vmctx.findContractByHname(job.Contract)
+}
+
+// mustChargeJobFees takes node fees and the maximum gas fee of the run from the account of the contract.
+// Returns false if the account can't cover them
+func (vmctx *VMContext) mustChargeJobFees() bool {
+	maxGasFee := coretypes.GasFee(vmctx.gasBudget, vmctx.gasPrice)
+	total := vmctx.ownerFee + vmctx.validatorFee + maxGasFee
+	if total == 0 {
+		return true
+	}
+	if !vmctx.debitFromAccount(vmctx.feePayer(), cbalances.NewFromMap(map[balance.Color]int64{
+		vmctx.feeColor: total,
+	})) {
+		return false
+	}
+	if vmctx.ownerFee > 0 {
+		vmctx.creditToAccount(vmctx.ChainOwnerID(), cbalances.NewFromMap(map[balance.Color]int64{
+			vmctx.feeColor: vmctx.ownerFee,
+		}))
+	}
+	if vmctx.validatorFee > 0 {
+		vmctx.creditToAccount(vmctx.validatorFeeTarget, cbalances.NewFromMap(map[balance.Color]int64{
+			vmctx.feeColor: vmctx.validatorFee,
+		}))
+	}
+	vmctx.gasFeeReserved = maxGasFee
+	return true
+}
+
+func (vmctx *VMContext) finalizeJobCall() {
+	vmctx.mustUpdateJob()
+	vmctx.mustJobToEventLog(vmctx.lastError)
+	vmctx.stateUpdate.WithResult(vmctx.requestResult())
+	vmctx.virtualState.ApplyStateUpdate(vmctx.stateUpdate)
+
+	vmctx.log.Debugw("runScheduledJob OUT",
+		"job", vmctx.job.String(),
+		"reqId", vmctx.jobRequestID.Short(),
+	)
+}
+
+// mustUpdateJob schedules the next run of the job, or removes it if the contract does not exist anymore
+func (vmctx *VMContext) mustUpdateJob() {
+	vmctx.pushCallContext(scheduler.Interface.Hname(), nil, nil)
+	defer vmctx.popCallContext()
+
+	if vmctx.contractRecord == nil {
+		scheduler.DeleteJob(vmctx.State(), vmctx.job.ID)
+		return
+	}
+	if !scheduler.UpdateAfterRun(vmctx.State(), vmctx.job, vmctx.timestamp) {
+		vmctx.log.Infof("job #%d removed after %d runs", vmctx.job.ID, vmctx.job.NumRuns)
+	}
+}
+
+func (vmctx *VMContext) mustJobToEventLog(err error) {
+	if err != nil {
+		vmctx.log.Error(err)
+	}
+	msg := eventlog.JobRunRecord(vmctx.job.ID, err, vmctx.gasBurned)
+	vmctx.log.Infof("eventlog -> '%s'", string(msg))
+	vmctx.StoreToEventLog(scheduler.Interface.Hname(), msg)
+	if vmctx.contractRecord != nil {
+		vmctx.StoreToEventLog(vmctx.job.Contract, msg)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/iotaledger/wasp/packages/kv"
 	"github.com/iotaledger/wasp/packages/kv/buffered"
 	"github.com/iotaledger/wasp/packages/state"
+	"github.com/iotaledger/wasp/packages/vm"
 )
 
 type stateWrapper struct {
@@ -103,6 +104,25 @@ func (vmctx *VMContext) State() kv.KVStore {
 	return w
 }
 
+// StateOf returns read-only access to the public part of the state of the contract on the chain.
+// Returns nil if the contract does not exist or it does not declare the public state prefix
+func (vmctx *VMContext) StateOf(contractHname coretypes.Hname) kv.KVStoreReader {
+	rec, ok := vmctx.findContractByHname(contractHname)
+	if !ok {
+		return nil
+	}
+	proc, err := vmctx.processors.GetOrCreateProcessorByProgramHash(rec.ProgramHash, vmctx.getBinary)
+	if err != nil {
+		return nil
+	}
+	pub, ok := proc.(coretypes.PublicStateProcessor)
+	if !ok || pub.GetPublicStatePrefix() == "" {
+		return nil
+	}
+	state := newStateWrapper(contractHname, vmctx.virtualState, vmctx.stateUpdate)
+	return vm.NewPublicState(contractHname, state, pub.GetPublicStatePrefix())
+}
+
 func (s stateWrapper) MustGet(key kv.Key) []byte {
 	return kv.MustGet(s, key)
 }
@@ -0,0 +1,429 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package wasmhost
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// GasGlobalName is the name of the exported global injected by InjectGasMetering.
+// It holds the number of Wasm instructions the code is still allowed to execute
+const GasGlobalName = "__wasp_gas"
+
+const (
+	wasmSectionCustom    = 0
+	wasmSectionImport    = 2
+	wasmSectionGlobal    = 6
+	wasmSectionExport    = 7
+	wasmSectionCode      = 10
+	wasmSectionDataCount = 12
+
+	wasmImportGlobal = 3
+	wasmExportGlobal = 3
+	wasmTypeI64      = 0x7e
+)
+
+var wasmMagic = []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+
+type wasmSection struct {
+	id   byte
+	data []byte
+}
+
+// InjectGasMetering instruments the Wasm module with deterministic metering of executed instructions.
+// The code of each function is split into straight-line segments, which end with a control instruction
+// (block, loop, if, else, end, branch, return or call). At the start of each segment the number of its
+// instructions is subtracted from the injected global GasGlobalName and the code traps when the global
+// becomes negative. Each iteration of a loop and each call are charged, so an infinite loop or recursion
+// always terminates. The host sets the global before it calls the Wasm code and reads it after the call
+func InjectGasMetering(wasmData []byte) ([]byte, error) {
+	if len(wasmData) < len(wasmMagic) || !bytes.Equal(wasmData[:len(wasmMagic)], wasmMagic) {
+		return nil, errors.New("InjectGasMetering: not a Wasm module")
+	}
+	sections, err := wasmSections(wasmData[len(wasmMagic):])
+	if err != nil {
+		return nil, err
+	}
+	importedGlobals := uint32(0)
+	definedGlobals := uint32(0)
+	for _, s := range sections {
+		switch s.id {
+		case wasmSectionImport:
+			if importedGlobals, err = wasmCountImportedGlobals(s.data); err != nil {
+				return nil, err
+			}
+		case wasmSectionGlobal:
+			if definedGlobals, _, err = wasmReadUint(s.data, 0); err != nil {
+				return nil, err
+			}
+		}
+	}
+	gasGlobal := importedGlobals + definedGlobals
+
+	// i64 mutable global, initialized with 0
+	globalEntry := []byte{wasmTypeI64, 0x01, 0x42, 0x00, 0x0b}
+	if sections, err = wasmAppendEntry(sections, wasmSectionGlobal, globalEntry); err != nil {
+		return nil, err
+	}
+	exportEntry := wasmAppendUint(nil, uint32(len(GasGlobalName)))
+	exportEntry = append(exportEntry, GasGlobalName...)
+	exportEntry = append(exportEntry, wasmExportGlobal)
+	exportEntry = wasmAppendUint(exportEntry, gasGlobal)
+	if sections, err = wasmAppendEntry(sections, wasmSectionExport, exportEntry); err != nil {
+		return nil, err
+	}
+	for i := range sections {
+		if sections[i].id == wasmSectionCode {
+			if sections[i].data, err = wasmInstrumentCode(sections[i].data, gasGlobal); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	ret := append([]byte{}, wasmMagic...)
+	for _, s := range sections {
+		ret = append(ret, s.id)
+		ret = wasmAppendUint(ret, uint32(len(s.data)))
+		ret = append(ret, s.data...)
+	}
+	return ret, nil
+}
+
+func wasmSections(data []byte) ([]wasmSection, error) {
+	ret := make([]wasmSection, 0)
+	for pos := 0; pos < len(data); {
+		id := data[pos]
+		size, p, err := wasmReadUint(data, pos+1)
+		if err != nil {
+			return nil, err
+		}
+		if p+int(size) > len(data) {
+			return nil, errors.New("InjectGasMetering: section out of bounds")
+		}
+		ret = append(ret, wasmSection{id: id, data: data[p : p+int(size)]})
+		pos = p + int(size)
+	}
+	return ret, nil
+}
+
+// wasmSectionOrder is the position of the known section in the module. Custom sections are not ordered
+func wasmSectionOrder(id byte) int {
+	switch {
+	case id == wasmSectionDataCount:
+		return 10
+	case id >= wasmSectionCode:
+		return int(id) + 1
+	default:
+		return int(id)
+	}
+}
+
+// wasmAppendEntry appends the entry to the vector of entries of the section. The section is created if missing
+func wasmAppendEntry(sections []wasmSection, id byte, entry []byte) ([]wasmSection, error) {
+	for i := range sections {
+		if sections[i].id != id {
+			continue
+		}
+		count, p, err := wasmReadUint(sections[i].data, 0)
+		if err != nil {
+			return nil, err
+		}
+		data := wasmAppendUint(nil, count+1)
+		data = append(data, sections[i].data[p:]...)
+		sections[i].data = append(data, entry...)
+		return sections, nil
+	}
+	pos := len(sections)
+	for i := range sections {
+		if sections[i].id != wasmSectionCustom && wasmSectionOrder(sections[i].id) > wasmSectionOrder(id) {
+			pos = i
+			break
+		}
+	}
+	s := wasmSection{id: id, data: append(wasmAppendUint(nil, 1), entry...)}
+	ret := append([]wasmSection{}, sections[:pos]...)
+	ret = append(ret, s)
+	return append(ret, sections[pos:]...), nil
+}
+
+func wasmCountImportedGlobals(data []byte) (uint32, error) {
+	count, pos, err := wasmReadUint(data, 0)
+	if err != nil {
+		return 0, err
+	}
+	ret := uint32(0)
+	for i := uint32(0); i < count; i++ {
+		// module and field names
+		for j := 0; j < 2; j++ {
+			size, p, err := wasmReadUint(data, pos)
+			if err != nil {
+				return 0, err
+			}
+			pos = p + int(size)
+		}
+		if pos >= len(data) {
+			return 0, errors.New("InjectGasMetering: import out of bounds")
+		}
+		kind := data[pos]
+		pos++
+		switch kind {
+		case 0:
+			// function type index
+			if _, pos, err = wasmReadUint(data, pos); err != nil {
+				return 0, err
+			}
+		case 1:
+			// table: reference type and limits
+			if pos, err = wasmSkipLimits(data, pos+1); err != nil {
+				return 0, err
+			}
+		case 2:
+			// memory limits
+			if pos, err = wasmSkipLimits(data, pos); err != nil {
+				return 0, err
+			}
+		case wasmImportGlobal:
+			// value type and mutability
+			pos += 2
+			ret++
+		default:
+			return 0, fmt.Errorf("InjectGasMetering: unknown import kind %d", kind)
+		}
+	}
+	return ret, nil
+}
+
+func wasmSkipLimits(data []byte, pos int) (int, error) {
+	if pos >= len(data) {
+		return 0, errors.New("InjectGasMetering: limits out of bounds")
+	}
+	flags := data[pos]
+	_, pos, err := wasmReadUint(data, pos+1)
+	if err != nil {
+		return 0, err
+	}
+	if flags&1 != 0 {
+		_, pos, err = wasmReadUint(data, pos)
+	}
+	return pos, err
+}
+
+func wasmInstrumentCode(data []byte, gasGlobal uint32) ([]byte, error) {
+	count, pos, err := wasmReadUint(data, 0)
+	if err != nil {
+		return nil, err
+	}
+	ret := wasmAppendUint(nil, count)
+	for i := uint32(0); i < count; i++ {
+		size, p, err := wasmReadUint(data, pos)
+		if err != nil {
+			return nil, err
+		}
+		if p+int(size) > len(data) {
+			return nil, errors.New("InjectGasMetering: function body out of bounds")
+		}
+		body, err := wasmInstrumentBody(data[p:p+int(size)], gasGlobal)
+		if err != nil {
+			return nil, fmt.Errorf("InjectGasMetering: function #%d: %v", i, err)
+		}
+		ret = wasmAppendUint(ret, uint32(len(body)))
+		ret = append(ret, body...)
+		pos = p + int(size)
+	}
+	return ret, nil
+}
+
+// wasmCharge is the code which subtracts the cost from the gas global and traps if it becomes negative
+func wasmCharge(out []byte, gasGlobal uint32, cost int) []byte {
+	out = append(out, 0x23) // global.get
+	out = wasmAppendUint(out, gasGlobal)
+	out = append(out, 0x42) // i64.const
+	out = wasmAppendInt(out, int64(cost))
+	out = append(out, 0x7d, 0x24) // i64.sub, global.set
+	out = wasmAppendUint(out, gasGlobal)
+	out = append(out, 0x23) // global.get
+	out = wasmAppendUint(out, gasGlobal)
+	// i64.const 0, i64.lt_s, if, unreachable, end
+	return append(out, 0x42, 0x00, 0x53, 0x04, 0x40, 0x00, 0x0b)
+}
+
+func wasmInstrumentBody(body []byte, gasGlobal uint32) ([]byte, error) {
+	// local declarations
+	count, pos, err := wasmReadUint(body, 0)
+	if err != nil {
+		return nil, err
+	}
+	for i := uint32(0); i < count; i++ {
+		if _, pos, err = wasmReadUint(body, pos); err != nil {
+			return nil, err
+		}
+		pos++
+	}
+	if pos > len(body) {
+		return nil, errors.New("locals out of bounds")
+	}
+	ret := append([]byte{}, body[:pos]...)
+	segStart := pos
+	segCost := 0
+	for pos < len(body) {
+		opcode := body[pos]
+		next, err := wasmSkipInstruction(body, pos)
+		if err != nil {
+			return nil, err
+		}
+		if next > len(body) {
+			return nil, errors.New("instruction out of bounds")
+		}
+		segCost++
+		pos = next
+		if wasmEndsSegment(opcode) {
+			ret = wasmCharge(ret, gasGlobal, segCost)
+			ret = append(ret, body[segStart:pos]...)
+			segStart = pos
+			segCost = 0
+		}
+	}
+	if segCost != 0 {
+		return nil, errors.New("function body does not end with 'end'")
+	}
+	return ret, nil
+}
+
+func wasmEndsSegment(opcode byte) bool {
+	switch opcode {
+	case 0x00, 0x02, 0x03, 0x04, 0x05, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10, 0x11, 0x12, 0x13:
+		// unreachable, block, loop, if, else, end, br, br_if, br_table, return,
+		// call, call_indirect, return_call, return_call_indirect
+		return true
+	}
+	return false
+}
+
+// wasmSkipInstruction returns the position of the instruction following the one at the position
+func wasmSkipInstruction(code []byte, pos int) (int, error) {
+	opcode := code[pos]
+	pos++
+	var err error
+	switch {
+	case opcode == 0x02 || opcode == 0x03 || opcode == 0x04:
+		// block type: empty, value type or type index, all encoded as signed LEB128
+		_, pos, err = wasmReadInt(code, pos)
+		return pos, err
+	case opcode == 0x0c || opcode == 0x0d || opcode == 0x10 || opcode == 0x12 ||
+		(opcode >= 0x20 && opcode <= 0x26) || opcode == 0x3f || opcode == 0x40 || opcode == 0xd2:
+		// label, function, local, global or table index, memory index of memory.size and memory.grow
+		_, pos, err = wasmReadUint(code, pos)
+		return pos, err
+	case opcode == 0x0e:
+		// br_table
+		var n uint32
+		if n, pos, err = wasmReadUint(code, pos); err != nil {
+			return 0, err
+		}
+		return wasmSkipUints(code, pos, int(n)+1)
+	case opcode == 0x11 || opcode == 0x13 || (opcode >= 0x28 && opcode <= 0x3e):
+		// type index and table index of indirect calls, alignment and offset of memory access
+		return wasmSkipUints(code, pos, 2)
+	case opcode == 0x1c:
+		// select with value types
+		var n uint32
+		if n, pos, err = wasmReadUint(code, pos); err != nil {
+			return 0, err
+		}
+		return pos + int(n), nil
+	case opcode == 0x41 || opcode == 0x42:
+		_, pos, err = wasmReadInt(code, pos)
+		return pos, err
+	case opcode == 0x43:
+		return pos + 4, nil
+	case opcode == 0x44:
+		return pos + 8, nil
+	case opcode == 0xd0:
+		// reference type
+		return pos + 1, nil
+	case opcode == 0xfc:
+		return wasmSkipPrefixed(code, pos)
+	case opcode <= 0x01 || opcode == 0x05 || opcode == 0x0b || opcode == 0x0f || opcode == 0x1a || opcode == 0x1b ||
+		(opcode >= 0x45 && opcode <= 0xc4) || opcode == 0xd1:
+		// no immediates
+		return pos, nil
+	}
+	return 0, fmt.Errorf("unsupported instruction 0x%02x", opcode)
+}
+
+// wasmSkipPrefixed skips the saturating truncation and bulk memory instructions
+func wasmSkipPrefixed(code []byte, pos int) (int, error) {
+	op, pos, err := wasmReadUint(code, pos)
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case op <= 7:
+		return pos, nil
+	case op == 9 || op == 11 || op == 13 || (op >= 15 && op <= 17):
+		return wasmSkipUints(code, pos, 1)
+	case op == 8 || op == 10 || op == 12 || op == 14:
+		return wasmSkipUints(code, pos, 2)
+	}
+	return 0, fmt.Errorf("unsupported instruction 0xfc %d", op)
+}
+
+func wasmSkipUints(code []byte, pos int, n int) (int, error) {
+	var err error
+	for i := 0; i < n; i++ {
+		if _, pos, err = wasmReadUint(code, pos); err != nil {
+			return 0, err
+		}
+	}
+	return pos, nil
+}
+
+// wasmReadUint reads unsigned LEB128 encoded value
+func wasmReadUint(data []byte, pos int) (uint32, int, error) {
+	if pos >= len(data) {
+		return 0, 0, errors.New("InjectGasMetering: unexpected end of data")
+	}
+	v, n := binary.Uvarint(data[pos:])
+	if n <= 0 || v > 0xffffffff {
+		return 0, 0, errors.New("InjectGasMetering: invalid unsigned LEB128 value")
+	}
+	return uint32(v), pos + n, nil
+}
+
+// wasmReadInt reads signed LEB128 encoded value
+func wasmReadInt(data []byte, pos int) (int64, int, error) {
+	var ret int64
+	shift := uint(0)
+	for ; pos < len(data) && shift < 70; shift += 7 {
+		b := data[pos]
+		pos++
+		ret |= int64(b&0x7f) << shift
+		if b&0x80 == 0 {
+			if shift+7 < 64 && b&0x40 != 0 {
+				ret |= -1 << (shift + 7)
+			}
+			return ret, pos, nil
+		}
+	}
+	return 0, 0, errors.New("InjectGasMetering: invalid signed LEB128 value")
+}
+
+func wasmAppendUint(out []byte, v uint32) []byte {
+	var buf [binary.MaxVarintLen32]byte
+	return append(out, buf[:binary.PutUvarint(buf[:], uint64(v))]...)
+}
+
+func wasmAppendInt(out []byte, v int64) []byte {
+	for {
+		b := byte(v & 0x7f)
+		v >>= 7
+		if (v == 0 && b&0x40 == 0) || (v == -1 && b&0x40 != 0) {
+			return append(out, b)
+		}
+		out = append(out, b|0x80)
+	}
+}
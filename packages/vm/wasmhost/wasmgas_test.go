@@ -0,0 +1,104 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package wasmhost
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testSection(id byte, content ...byte) []byte {
+	return append(append([]byte{id}, wasmAppendUint(nil, uint32(len(content)))...), content...)
+}
+
+func testName(s string) []byte {
+	return append(wasmAppendUint(nil, uint32(len(s))), s...)
+}
+
+func testBody(code ...byte) []byte {
+	return append(wasmAppendUint(nil, uint32(len(code))), code...)
+}
+
+// testModule imports global 'm.g' and exports function 'spin' with an infinite loop
+// and function 'add' with if/else
+func testModule() []byte {
+	ret := append([]byte{}, wasmMagic...)
+	ret = append(ret, testSection(1, 2, 0x60, 0, 0, 0x60, 1, 0x7f, 1, 0x7f)...)
+	ret = append(ret, testSection(2, append(append(append([]byte{1}, testName("m")...), testName("g")...), 3, 0x7f, 0)...)...)
+	ret = append(ret, testSection(3, 2, 0, 1)...)
+	exports := []byte{2}
+	exports = append(append(exports, testName("spin")...), 0, 0)
+	exports = append(append(exports, testName("add")...), 0, 1)
+	ret = append(ret, testSection(7, exports...)...)
+	code := []byte{2}
+	// loop, br 0, end, end
+	code = append(code, testBody(0, 0x03, 0x40, 0x0c, 0, 0x0b, 0x0b)...)
+	// local.get 0, global.get 0, i32.add, if (result i32), i32.const 1, else, i32.const 2, end, end
+	code = append(code, testBody(0, 0x20, 0, 0x23, 0, 0x6a, 0x04, 0x7f, 0x41, 1, 0x05, 0x41, 2, 0x0b, 0x0b)...)
+	return append(ret, testSection(10, code...)...)
+}
+
+func TestInjectGasMetering(t *testing.T) {
+	out, err := InjectGasMetering(testModule())
+	require.NoError(t, err)
+
+	sections, err := wasmSections(out[len(wasmMagic):])
+	require.NoError(t, err)
+	ids := make([]byte, len(sections))
+	for i, s := range sections {
+		ids[i] = s.id
+	}
+	// the global section is created in its place
+	assert.EqualValues(t, []byte{1, 2, 3, wasmSectionGlobal, wasmSectionExport, wasmSectionCode}, ids)
+	// one global is imported, so the gas global has index 1
+	assert.EqualValues(t, []byte{1, wasmTypeI64, 1, 0x42, 0, 0x0b}, sections[3].data)
+	assert.True(t, bytes.HasSuffix(sections[4].data, append(testName(GasGlobalName), wasmExportGlobal, 1)))
+
+	code := sections[5].data
+	// the loop is charged on each iteration: the charge follows the 'loop' instruction
+	spin := append([]byte{0}, wasmCharge(nil, 1, 1)...)
+	spin = append(spin, 0x03, 0x40)
+	spin = append(spin, wasmCharge(nil, 1, 1)...)
+	spin = append(spin, 0x0c, 0)
+	spin = append(spin, wasmCharge(nil, 1, 1)...)
+	spin = append(spin, 0x0b)
+	spin = append(spin, wasmCharge(nil, 1, 1)...)
+	spin = append(spin, 0x0b)
+	assert.True(t, bytes.HasPrefix(code[1:], testBody(spin...)))
+}
+
+func TestInjectGasMeteringInvalid(t *testing.T) {
+	_, err := InjectGasMetering([]byte("go:example"))
+	require.Error(t, err)
+
+	module := testModule()
+	_, err = InjectGasMetering(module[:len(module)-3])
+	require.Error(t, err)
+}
+
+func TestInjectGasMeteringContract(t *testing.T) {
+	wasm, err := ioutil.ReadFile("../core/testcore/sandbox_tests/test_sandbox_sc/testcore_bg.wasm")
+	require.NoError(t, err)
+	out, err := InjectGasMetering(wasm)
+	require.NoError(t, err)
+	assert.Greater(t, len(out), len(wasm))
+}
+
+func TestWasmLEB128(t *testing.T) {
+	for _, v := range []int64{0, 1, -1, 63, 64, -64, -65, 1 << 40, -(1 << 40), 1<<63 - 1, -1 << 63} {
+		back, pos, err := wasmReadInt(wasmAppendInt(nil, v), 0)
+		require.NoError(t, err)
+		assert.EqualValues(t, v, back)
+		assert.EqualValues(t, len(wasmAppendInt(nil, v)), pos)
+	}
+	for _, v := range []uint32{0, 127, 128, 1 << 31} {
+		back, _, err := wasmReadUint(wasmAppendUint(nil, v), 0)
+		require.NoError(t, err)
+		assert.EqualValues(t, v, back)
+	}
+}
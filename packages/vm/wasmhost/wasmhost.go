@@ -15,6 +15,9 @@ type WasmHost struct {
 	codeToFunc  map[uint32]string
 	funcToCode  map[string]uint32
 	funcToIndex map[string]int32
+	gasMeter    coretypes.GasMeter
+	// Wasm instructions executed, but not yet burned as gas
+	instructions uint64
 }
 
 func (host *WasmHost) InitVM(vm WasmVM, useBase58Keys bool) error {
@@ -29,6 +32,73 @@ func (host *WasmHost) Init(null HostObject, root HostObject, log *logger.Logger)
 	host.funcToIndex = make(map[string]int32)
 }
 
+// SetGasMeter sets the gas meter of the current call.
+// Calls from the Wasm code to the host and Wasm instructions are charged to it
+func (host *WasmHost) SetGasMeter(gasMeter coretypes.GasMeter) {
+	host.gasMeter = gasMeter
+	host.instructions = 0
+}
+
+func (host *WasmHost) GasMeter() coretypes.GasMeter {
+	return host.gasMeter
+}
+
+// instructionsLeft returns the number of Wasm instructions the remaining gas pays for.
+// Without the gas meter (e.g. when the module is loaded) the Wasm code is limited by coretypes.MaxGasBudget
+func (host *WasmHost) instructionsLeft() int64 {
+	if host.gasMeter == nil {
+		return int64(coretypes.MaxGasBudget * coretypes.WasmInstructionsPerGas)
+	}
+	budget, burned := host.gasMeter.GasBudget(), host.gasMeter.GasBurned()
+	if burned >= budget {
+		return 0
+	}
+	return int64((budget-burned)*coretypes.WasmInstructionsPerGas - host.instructions)
+}
+
+// burnInstructions burns gas for the executed Wasm instructions.
+// The remainder of instructions which doesn't make a whole unit of gas is burned with the next instructions
+func (host *WasmHost) burnInstructions(n int64) {
+	if n <= 0 || host.gasMeter == nil {
+		return
+	}
+	host.instructions += uint64(n)
+	gas := host.instructions / coretypes.WasmInstructionsPerGas
+	host.instructions %= coretypes.WasmInstructionsPerGas
+	if gas > 0 {
+		host.gasMeter.BurnGas(gas)
+	}
+}
+
+// ViewGasMeter limits the gas of view calls. The views don't pay for gas, but the gas limit makes sure
+// an infinite loop in the view terminates. It never panics: the Wasm code traps when the gas runs out
+type ViewGasMeter struct {
+	burned uint64
+}
+
+func (m *ViewGasMeter) GasBudget() uint64 {
+	return coretypes.MaxGasBudget
+}
+
+func (m *ViewGasMeter) GasBurned() uint64 {
+	return m.burned
+}
+
+func (m *ViewGasMeter) BurnGas(gas uint64) {
+	m.burned += gas
+}
+
+func (host *WasmHost) burnGas(size int32) {
+	if host.gasMeter == nil {
+		return
+	}
+	gas := coretypes.GasWasmHostCall
+	if size > 0 {
+		gas += uint64(size) * coretypes.GasPerByte
+	}
+	host.gasMeter.BurnGas(gas)
+}
+
 func (host *WasmHost) FunctionFromCode(code uint32) string {
 	return host.codeToFunc[code]
 }
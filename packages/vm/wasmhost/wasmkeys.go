@@ -42,43 +42,52 @@ const (
 	// to the keys give this one a different value and make sure
 	// the client side in wasplib is updated accordingly
 	KeyZzzzzzz = int32(-37)
+
+	// Keys added after the version key. They don't change existing key
+	// values, so contracts compiled with an older wasmlib keep working
+	KeyTypedEvent        = int32(-38)
+	KeyStateOf           = int32(-39)
+	KeyPublicStatePrefix = int32(-40)
 )
 
 var keyMap = map[string]int32{
-	"address":         KeyAddress,
-	"aggregateBls":    KeyAggregateBls,
-	"balances":        KeyBalances,
-	"base58Bytes":     KeyBase58Bytes,
-	"base58String":    KeyBase58String,
-	"call":            KeyCall,
-	"caller":          KeyCaller,
-	"chainOwnerId":    KeyChainOwnerId,
-	"color":           KeyColor,
-	"contractCreator": KeyContractCreator,
-	"contractId":      KeyContractId,
-	"deploy":          KeyDeploy,
-	"event":           KeyEvent,
-	"exports":         KeyExports,
-	"hashBlake2b":     KeyHashBlake2b,
-	"hashSha3":        KeyHashSha3,
-	"hname":           KeyHname,
-	"incoming":        KeyIncoming,
-	"length":          KeyLength,
-	"log":             KeyLog,
-	"maps":            KeyMaps,
-	"name":            KeyName,
-	"panic":           KeyPanic,
-	"params":          KeyParams,
-	"post":            KeyPost,
-	"random":          KeyRandom,
-	"results":         KeyResults,
-	"return":          KeyReturn,
-	"state":           KeyState,
-	"timestamp":       KeyTimestamp,
-	"trace":           KeyTrace,
-	"transfers":       KeyTransfers,
-	"utility":         KeyUtility,
-	"valid":           KeyValid,
-	"validBls":        KeyValidBls,
-	"validEd25519":    KeyValidEd25519,
+	"address":           KeyAddress,
+	"aggregateBls":      KeyAggregateBls,
+	"balances":          KeyBalances,
+	"base58Bytes":       KeyBase58Bytes,
+	"base58String":      KeyBase58String,
+	"call":              KeyCall,
+	"caller":            KeyCaller,
+	"chainOwnerId":      KeyChainOwnerId,
+	"color":             KeyColor,
+	"contractCreator":   KeyContractCreator,
+	"contractId":        KeyContractId,
+	"deploy":            KeyDeploy,
+	"event":             KeyEvent,
+	"exports":           KeyExports,
+	"hashBlake2b":       KeyHashBlake2b,
+	"hashSha3":          KeyHashSha3,
+	"hname":             KeyHname,
+	"incoming":          KeyIncoming,
+	"length":            KeyLength,
+	"log":               KeyLog,
+	"maps":              KeyMaps,
+	"name":              KeyName,
+	"panic":             KeyPanic,
+	"params":            KeyParams,
+	"post":              KeyPost,
+	"publicStatePrefix": KeyPublicStatePrefix,
+	"random":            KeyRandom,
+	"results":           KeyResults,
+	"return":            KeyReturn,
+	"state":             KeyState,
+	"stateOf":           KeyStateOf,
+	"timestamp":         KeyTimestamp,
+	"trace":             KeyTrace,
+	"typedEvent":        KeyTypedEvent,
+	"transfers":         KeyTransfers,
+	"utility":           KeyUtility,
+	"valid":             KeyValid,
+	"validBls":          KeyValidBls,
+	"validEd25519":      KeyValidEd25519,
 }
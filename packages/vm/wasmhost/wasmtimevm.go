@@ -5,9 +5,15 @@ package wasmhost
 
 import (
 	"errors"
+	"fmt"
+
 	"github.com/bytecodealliance/wasmtime-go"
+	"github.com/iotaledger/wasp/packages/coretypes"
 )
 
+// WasmTimeVM runs Wasm code with wasmtime.
+// Gas is metered for each call from the Wasm code to the host and for the executed Wasm instructions
+// (see InjectGasMetering and WasmHost.SetGasMeter)
 type WasmTimeVM struct {
 	WasmVmBase
 	instance *wasmtime.Instance
@@ -15,6 +21,9 @@ type WasmTimeVM struct {
 	memory   *wasmtime.Memory
 	module   *wasmtime.Module
 	store    *wasmtime.Store
+	gas      *wasmtime.Global
+	// value of the gas global when it was set last time
+	gasLeft int64
 }
 
 func NewWasmTimeVM() *WasmTimeVM {
@@ -28,6 +37,8 @@ func (vm *WasmTimeVM) LinkHost(impl WasmVM, host *WasmHost) error {
 	vm.WasmVmBase.LinkHost(impl, host)
 	err := vm.linker.DefineFunc("wasplib", "hostGetBytes",
 		func(objId int32, keyId int32, typeId int32, stringRef int32, size int32) int32 {
+			vm.burnGasUsed()
+			defer vm.setGasLeft()
 			return vm.HostGetBytes(objId, keyId, typeId, stringRef, size)
 		})
 	if err != nil {
@@ -35,6 +46,8 @@ func (vm *WasmTimeVM) LinkHost(impl WasmVM, host *WasmHost) error {
 	}
 	err = vm.linker.DefineFunc("wasplib", "hostGetKeyId",
 		func(keyRef int32, size int32) int32 {
+			vm.burnGasUsed()
+			defer vm.setGasLeft()
 			return vm.HostGetKeyId(keyRef, size)
 		})
 	if err != nil {
@@ -42,6 +55,8 @@ func (vm *WasmTimeVM) LinkHost(impl WasmVM, host *WasmHost) error {
 	}
 	err = vm.linker.DefineFunc("wasplib", "hostGetObjectId",
 		func(objId int32, keyId int32, typeId int32) int32 {
+			vm.burnGasUsed()
+			defer vm.setGasLeft()
 			return vm.HostGetObjectId(objId, keyId, typeId)
 		})
 	if err != nil {
@@ -49,6 +64,8 @@ func (vm *WasmTimeVM) LinkHost(impl WasmVM, host *WasmHost) error {
 	}
 	err = vm.linker.DefineFunc("wasplib", "hostSetBytes",
 		func(objId int32, keyId int32, typeId int32, stringRef int32, size int32) {
+			vm.burnGasUsed()
+			defer vm.setGasLeft()
 			vm.HostSetBytes(objId, keyId, typeId, stringRef, size)
 		})
 	if err != nil {
@@ -57,6 +74,8 @@ func (vm *WasmTimeVM) LinkHost(impl WasmVM, host *WasmHost) error {
 	// go implementation uses this one to write panic message
 	err = vm.linker.DefineFunc("wasi_unstable", "fd_write",
 		func(fd int32, iovs int32, size int32, written int32) int32 {
+			vm.burnGasUsed()
+			defer vm.setGasLeft()
 			return vm.HostFdWrite(fd, iovs, size, written)
 		})
 	if err != nil {
@@ -66,7 +85,10 @@ func (vm *WasmTimeVM) LinkHost(impl WasmVM, host *WasmHost) error {
 }
 
 func (vm *WasmTimeVM) LoadWasm(wasmData []byte) error {
-	var err error
+	wasmData, err := InjectGasMetering(wasmData)
+	if err != nil {
+		return err
+	}
 	vm.module, err = wasmtime.NewModule(vm.store.Engine, wasmData)
 	if err != nil {
 		return err
@@ -83,6 +105,7 @@ func (vm *WasmTimeVM) LoadWasm(wasmData []byte) error {
 	if vm.memory == nil {
 		return errors.New("not a memory type")
 	}
+	vm.gas = vm.instance.GetExport(GasGlobalName).Global()
 	return nil
 }
 
@@ -91,7 +114,11 @@ func (vm *WasmTimeVM) RunFunction(functionName string) error {
 	if export == nil {
 		return errors.New("unknown export function: '" + functionName + "'")
 	}
+	vm.setGasLeft()
 	_, err := export.Func().Call()
+	if !vm.burnGasUsed() {
+		return fmt.Errorf("%w: function '%s'", coretypes.ErrOutOfGas, functionName)
+	}
 	return err
 }
 
@@ -101,11 +128,32 @@ func (vm *WasmTimeVM) RunScFunction(index int32) error {
 		return errors.New("unknown export function: 'on_call_entrypoint'")
 	}
 	frame := vm.PreCall()
+	vm.setGasLeft()
 	_, err := export.Func().Call(index)
 	vm.PostCall(frame)
+	if !vm.burnGasUsed() {
+		return coretypes.ErrOutOfGas
+	}
 	return err
 }
 
+// setGasLeft passes the number of instructions the remaining gas pays for to the metering code in the Wasm module
+func (vm *WasmTimeVM) setGasLeft() {
+	vm.gasLeft = vm.host.instructionsLeft()
+	if err := vm.gas.Set(wasmtime.ValI64(vm.gasLeft)); err != nil {
+		panic(err)
+	}
+}
+
+// burnGasUsed burns gas for the Wasm instructions executed since the gas global was set.
+// Returns false if the Wasm code ran out of gas
+func (vm *WasmTimeVM) burnGasUsed() bool {
+	left := vm.gas.Get().I64()
+	vm.host.burnInstructions(vm.gasLeft - left)
+	vm.gasLeft = left
+	return left >= 0
+}
+
 func (vm *WasmTimeVM) UnsafeMemory() []byte {
 	return vm.memory.UnsafeData()
 }
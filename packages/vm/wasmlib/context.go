@@ -210,6 +210,13 @@ func (ctx ScBaseContext) Results() ScMutableMap {
 	return Root.GetMap(KeyResults)
 }
 
+// read-only access to the public state of another smart contract on the chain
+// panics if the contract does not declare the public state prefix
+func (ctx ScBaseContext) StateOf(contract ScHname) ScImmutableMap {
+	Root.GetBytes(KeyStateOf).SetValue(contract.Bytes())
+	return Root.GetMap(KeyReturn).Immutable()
+}
+
 // deterministic time stamp fixed at the moment of calling the smart contract
 func (ctx ScBaseContext) Timestamp() int64 {
 	return Root.GetInt(KeyTimestamp).Value()
@@ -316,6 +323,23 @@ func (ctx ScFuncContext) State() ScMutableMap {
 	return Root.GetMap(KeyState)
 }
 
+// signals a structured event with an optional payload
+// the event is stored in the event log and can be queried by any of its topics
+func (ctx ScFuncContext) TypedEvent(name string, payload *ScMutableMap, topics ...[]byte) {
+	encode := NewBytesEncoder()
+	encode.String(name)
+	if payload != nil {
+		encode.Int(int64(payload.objId))
+	} else {
+		encode.Int(0)
+	}
+	encode.Int(int64(len(topics)))
+	for _, topic := range topics {
+		encode.Bytes(topic)
+	}
+	Root.GetBytes(KeyTypedEvent).SetValue(encode.Data())
+}
+
 // transfer colored token amounts to the specified Tangle ledger address
 func (ctx ScFuncContext) TransferToAddress(address *ScAddress, transfer balances) {
 	transfers := Root.GetMapArray(KeyTransfers)
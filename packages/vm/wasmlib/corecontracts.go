@@ -23,13 +23,17 @@ const CoreBlobParamField = Key("field")
 const CoreBlobParamHash = Key("hash")
 
 const CoreEventlog = ScHname(0x661aa7d8)
+const CoreEventlogViewGetEvents = ScHname(0xa0603cc7)
+const CoreEventlogViewGetEventsByTopic = ScHname(0xaa098cf2)
 const CoreEventlogViewGetNumRecords = ScHname(0x2f4b4a8c)
 const CoreEventlogViewGetRecords = ScHname(0xd01a8085)
 
 const CoreEventlogParamContractHname = Key("contractHname")
+const CoreEventlogParamEventName = Key("eventName")
 const CoreEventlogParamFromTs = Key("fromTs")
 const CoreEventlogParamMaxLastRecords = Key("maxLastRecords")
 const CoreEventlogParamToTs = Key("toTs")
+const CoreEventlogParamTopic = Key("topic")
 
 const CoreRoot = ScHname(0xcebf5908)
 const CoreRootFuncClaimChainOwnership = ScHname(0x03ff0fc0)
@@ -51,3 +55,83 @@ const CoreRootParamName = Key("$$name$$")
 const CoreRootParamOwnerFee = Key("$$ownerfee$$")
 const CoreRootParamProgramHash = Key("$$proghash$$")
 const CoreRootParamValidatorFee = Key("$$validatorfee$$")
+
+const CoreScheduler = ScHname(0x9c305966)
+const CoreSchedulerFuncAddJob = ScHname(0x39a94021)
+const CoreSchedulerFuncRemoveJob = ScHname(0x373b3647)
+const CoreSchedulerViewGetJob = ScHname(0x6a9ecbe5)
+const CoreSchedulerViewGetJobs = ScHname(0xde70686f)
+
+const CoreSchedulerParamArgs = Key("args")
+const CoreSchedulerParamContractHname = Key("contractHname")
+const CoreSchedulerParamCron = Key("cron")
+const CoreSchedulerParamEntryPoint = Key("entryPoint")
+const CoreSchedulerParamGasBudget = Key("gasBudget")
+const CoreSchedulerParamInterval = Key("interval")
+const CoreSchedulerParamJob = Key("job")
+const CoreSchedulerParamJobID = Key("jobID")
+const CoreSchedulerParamJobs = Key("jobs")
+const CoreSchedulerParamMaxRuns = Key("maxRuns")
+const CoreSchedulerParamStart = Key("start")
+
+const CoreTokens = ScHname(0xbab8b5a8)
+const CoreTokensFuncApprove = ScHname(0xa0661268)
+const CoreTokensFuncBurn = ScHname(0x7bc1efb1)
+const CoreTokensFuncCreateToken = ScHname(0xacfcb639)
+const CoreTokensFuncMint = ScHname(0xa29addcf)
+const CoreTokensFuncTransfer = ScHname(0xa15da184)
+const CoreTokensFuncTransferFrom = ScHname(0xd5e0a602)
+const CoreTokensViewGetAllowance = ScHname(0x329aa88f)
+const CoreTokensViewGetHoldings = ScHname(0x8d5df2f2)
+const CoreTokensViewGetToken = ScHname(0x855b94ba)
+const CoreTokensViewGetTokens = ScHname(0x414150d3)
+
+const CoreTokensParamAgentID = Key("agentID")
+const CoreTokensParamAmount = Key("amount")
+const CoreTokensParamColor = Key("color")
+const CoreTokensParamDecimals = Key("decimals")
+const CoreTokensParamName = Key("name")
+const CoreTokensParamSpender = Key("spender")
+const CoreTokensParamSupply = Key("supply")
+const CoreTokensParamSymbol = Key("symbol")
+const CoreTokensParamTarget = Key("target")
+const CoreTokensParamToken = Key("token")
+const CoreTokensParamTokens = Key("tokens")
+
+const CoreNft = ScHname(0xdfc7488e)
+const CoreNftFuncApprove = ScHname(0xa0661268)
+const CoreNftFuncBurn = ScHname(0x7bc1efb1)
+const CoreNftFuncMint = ScHname(0xa29addcf)
+const CoreNftFuncTransfer = ScHname(0xa15da184)
+const CoreNftViewGetApproved = ScHname(0xbe34b6ba)
+const CoreNftViewGetNFT = ScHname(0x50f1f2c8)
+const CoreNftViewGetNFTs = ScHname(0x4684c634)
+
+const CoreNftParamAgentID = Key("agentID")
+const CoreNftParamID = Key("id")
+const CoreNftParamMetadata = Key("metadata")
+const CoreNftParamNFT = Key("nft")
+const CoreNftParamNFTs = Key("nfts")
+const CoreNftParamSpender = Key("spender")
+const CoreNftParamTarget = Key("target")
+
+const CoreGovernance = ScHname(0x17cf909f)
+const CoreGovernanceFuncEnable = ScHname(0xbb145b40)
+const CoreGovernanceFuncExecute = ScHname(0x94c80ed0)
+const CoreGovernanceFuncPropose = ScHname(0xb5b514cb)
+const CoreGovernanceFuncSetMembers = ScHname(0xc40b3ae6)
+const CoreGovernanceFuncVote = ScHname(0x60e23b08)
+const CoreGovernanceViewGetMembers = ScHname(0x5d2c65f8)
+const CoreGovernanceViewGetProposal = ScHname(0xd828d59f)
+const CoreGovernanceViewGetProposals = ScHname(0xe3869158)
+
+const CoreGovernanceParamArgs = Key("args")
+const CoreGovernanceParamContractHname = Key("contractHname")
+const CoreGovernanceParamDescription = Key("description")
+const CoreGovernanceParamEntryPoint = Key("entryPoint")
+const CoreGovernanceParamExpiry = Key("expiry")
+const CoreGovernanceParamMembers = Key("members")
+const CoreGovernanceParamProposal = Key("proposal")
+const CoreGovernanceParamProposalID = Key("proposalID")
+const CoreGovernanceParamProposals = Key("proposals")
+const CoreGovernanceParamQuorum = Key("quorum")
@@ -37,6 +37,11 @@ func (ctx ScExports) AddFunc(name string, f func(ctx *ScFuncContext)) {
 	ctx.exports.GetString(index).SetValue(name)
 }
 
+// exposes the state keys which start with the prefix to other smart contracts on the chain
+func (ctx ScExports) SetPublicStatePrefix(prefix string) {
+	Root.GetString(KeyPublicStatePrefix).SetValue(prefix)
+}
+
 func (ctx ScExports) AddView(name string, f func(ctx *ScViewContext)) {
 	index := int32(len(views))
 	views = append(views, f)
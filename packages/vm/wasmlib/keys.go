@@ -59,4 +59,9 @@ const (
 	KeyValidBls        = Key32(-35)
 	KeyValidEd25519    = Key32(-36)
 	KeyZzzzzzz         = Key32(-37)
+
+	// keys added after the version key
+	KeyTypedEvent        = Key32(-38)
+	KeyStateOf           = Key32(-39)
+	KeyPublicStatePrefix = Key32(-40)
 )
@@ -11,32 +11,36 @@ import (
 	"github.com/iotaledger/wasp/packages/kv"
 	"github.com/iotaledger/wasp/packages/kv/codec"
 	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/vm/core/eventlog"
 	"github.com/iotaledger/wasp/packages/vm/wasmhost"
 )
 
 var typeIds = map[int32]int32{
-	wasmhost.KeyBalances:        wasmhost.OBJTYPE_MAP,
-	wasmhost.KeyCall:            wasmhost.OBJTYPE_BYTES,
-	wasmhost.KeyCaller:          wasmhost.OBJTYPE_AGENT_ID,
-	wasmhost.KeyChainOwnerId:    wasmhost.OBJTYPE_AGENT_ID,
-	wasmhost.KeyContractCreator: wasmhost.OBJTYPE_AGENT_ID,
-	wasmhost.KeyDeploy:          wasmhost.OBJTYPE_BYTES,
-	wasmhost.KeyEvent:           wasmhost.OBJTYPE_STRING,
-	wasmhost.KeyExports:         wasmhost.OBJTYPE_STRING | wasmhost.OBJTYPE_ARRAY,
-	wasmhost.KeyContractId:      wasmhost.OBJTYPE_CONTRACT_ID,
-	wasmhost.KeyIncoming:        wasmhost.OBJTYPE_MAP,
-	wasmhost.KeyLog:             wasmhost.OBJTYPE_STRING,
-	wasmhost.KeyMaps:            wasmhost.OBJTYPE_MAP | wasmhost.OBJTYPE_ARRAY,
-	wasmhost.KeyPanic:           wasmhost.OBJTYPE_STRING,
-	wasmhost.KeyParams:          wasmhost.OBJTYPE_MAP,
-	wasmhost.KeyPost:            wasmhost.OBJTYPE_BYTES,
-	wasmhost.KeyResults:         wasmhost.OBJTYPE_MAP,
-	wasmhost.KeyReturn:          wasmhost.OBJTYPE_MAP,
-	wasmhost.KeyState:           wasmhost.OBJTYPE_MAP,
-	wasmhost.KeyTimestamp:       wasmhost.OBJTYPE_INT,
-	wasmhost.KeyTrace:           wasmhost.OBJTYPE_STRING,
-	wasmhost.KeyTransfers:       wasmhost.OBJTYPE_MAP | wasmhost.OBJTYPE_ARRAY,
-	wasmhost.KeyUtility:         wasmhost.OBJTYPE_MAP,
+	wasmhost.KeyBalances:          wasmhost.OBJTYPE_MAP,
+	wasmhost.KeyCall:              wasmhost.OBJTYPE_BYTES,
+	wasmhost.KeyCaller:            wasmhost.OBJTYPE_AGENT_ID,
+	wasmhost.KeyChainOwnerId:      wasmhost.OBJTYPE_AGENT_ID,
+	wasmhost.KeyContractCreator:   wasmhost.OBJTYPE_AGENT_ID,
+	wasmhost.KeyDeploy:            wasmhost.OBJTYPE_BYTES,
+	wasmhost.KeyEvent:             wasmhost.OBJTYPE_STRING,
+	wasmhost.KeyExports:           wasmhost.OBJTYPE_STRING | wasmhost.OBJTYPE_ARRAY,
+	wasmhost.KeyContractId:        wasmhost.OBJTYPE_CONTRACT_ID,
+	wasmhost.KeyIncoming:          wasmhost.OBJTYPE_MAP,
+	wasmhost.KeyLog:               wasmhost.OBJTYPE_STRING,
+	wasmhost.KeyMaps:              wasmhost.OBJTYPE_MAP | wasmhost.OBJTYPE_ARRAY,
+	wasmhost.KeyPanic:             wasmhost.OBJTYPE_STRING,
+	wasmhost.KeyParams:            wasmhost.OBJTYPE_MAP,
+	wasmhost.KeyPost:              wasmhost.OBJTYPE_BYTES,
+	wasmhost.KeyPublicStatePrefix: wasmhost.OBJTYPE_STRING,
+	wasmhost.KeyResults:           wasmhost.OBJTYPE_MAP,
+	wasmhost.KeyReturn:            wasmhost.OBJTYPE_MAP,
+	wasmhost.KeyState:             wasmhost.OBJTYPE_MAP,
+	wasmhost.KeyStateOf:           wasmhost.OBJTYPE_BYTES,
+	wasmhost.KeyTimestamp:         wasmhost.OBJTYPE_INT,
+	wasmhost.KeyTrace:             wasmhost.OBJTYPE_STRING,
+	wasmhost.KeyTransfers:         wasmhost.OBJTYPE_MAP | wasmhost.OBJTYPE_ARRAY,
+	wasmhost.KeyTypedEvent:        wasmhost.OBJTYPE_BYTES,
+	wasmhost.KeyUtility:           wasmhost.OBJTYPE_MAP,
 }
 
 type ScContext struct {
@@ -115,10 +119,16 @@ func (o *ScContext) SetBytes(keyId int32, typeId int32, bytes []byte) {
 		o.vm.log().Infof(string(bytes))
 	case wasmhost.KeyTrace:
 		o.vm.log().Debugf(string(bytes))
+	case wasmhost.KeyTypedEvent:
+		o.processTypedEvent(bytes)
 	case wasmhost.KeyPanic:
 		o.vm.log().Panicf(string(bytes))
 	case wasmhost.KeyPost:
 		o.processPost(bytes)
+	case wasmhost.KeyPublicStatePrefix:
+		o.processPublicStatePrefix(bytes)
+	case wasmhost.KeyStateOf:
+		o.processStateOf(bytes)
 	default:
 		o.invalidKey(keyId)
 	}
@@ -193,6 +203,50 @@ func (o *ScContext) processPost(bytes []byte) {
 	})
 }
 
+// processPublicStatePrefix declares the public prefix of the contract state. Only allowed in on_load
+func (o *ScContext) processPublicStatePrefix(bytes []byte) {
+	if o.vm.ctx != nil || o.vm.ctxView != nil {
+		o.Panic("public state prefix can only be declared in on_load")
+	}
+	o.vm.publicStatePrefix = kv.Key(bytes)
+}
+
+// processStateOf makes the public state of the contract available as the read-only 'return' map
+func (o *ScContext) processStateOf(bytes []byte) {
+	contract, err := coretypes.NewHnameFromBytes(bytes)
+	if err != nil {
+		o.Panic(err.Error())
+	}
+	o.Trace("STATEOF c'%s'", contract.String())
+	var state kv.KVStoreReader
+	if o.vm.ctx != nil {
+		state = o.vm.ctx.StateOf(contract)
+	} else {
+		state = o.vm.ctxView.StateOf(contract)
+	}
+	if state == nil {
+		o.Panic("state of the contract '%s' is not public", contract.String())
+	}
+	resultsId := o.GetObjectId(wasmhost.KeyReturn, wasmhost.OBJTYPE_MAP)
+	o.host.FindObject(resultsId).(*ScDict).kvStore = NewScReadOnlyState(o.vm.log(), state)
+}
+
+func (o *ScContext) processTypedEvent(bytes []byte) {
+	decode := NewBytesDecoder(bytes)
+	name := string(decode.Bytes())
+	payload := o.getParams(int32(decode.Int()))
+	numTopics := decode.Int()
+	if numTopics < 0 || numTopics > eventlog.MaxEventTopics {
+		o.Panic("invalid number of event topics: %d", numTopics)
+	}
+	topics := make([][]byte, numTopics)
+	for i := range topics {
+		topics[i] = decode.Bytes()
+	}
+	o.Trace("EVENT '%s'", name)
+	o.vm.ctx.TypedEvent(name, payload, topics...)
+}
+
 func (o *ScContext) getParams(paramsId int32) dict.Dict {
 	if paramsId == 0 {
 		return dict.New()
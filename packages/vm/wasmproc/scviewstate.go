@@ -11,20 +11,25 @@ import (
 // so these panics should never trigger and we can avoid
 // a much more drastic refactoring for now
 type ScViewState struct {
-	ctxView   coretypes.SandboxView
+	log       coretypes.LogInterface
 	viewState kv.KVStoreReader
 }
 
 func NewScViewState(ctxView coretypes.SandboxView) kv.KVStore {
-	return &ScViewState{ctxView: ctxView, viewState: ctxView.State()}
+	return NewScReadOnlyState(ctxView.Log(), ctxView.State())
+}
+
+// NewScReadOnlyState wraps any read-only state, e.g. the public state of another contract
+func NewScReadOnlyState(log coretypes.LogInterface, state kv.KVStoreReader) kv.KVStore {
+	return &ScViewState{log: log, viewState: state}
 }
 
 func (s ScViewState) Set(key kv.Key, value []byte) {
-	s.ctxView.Log().Panicf("ScViewState.Set")
+	s.log.Panicf("ScViewState.Set")
 }
 
 func (s ScViewState) Del(key kv.Key) {
-	s.ctxView.Log().Panicf("ScViewState.Del")
+	s.log.Panicf("ScViewState.Del")
 }
 
 func (s ScViewState) Get(key kv.Key) ([]byte, error) {
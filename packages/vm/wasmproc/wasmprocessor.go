@@ -18,6 +18,8 @@ type wasmProcessor struct {
 	function  string
 	nesting   int
 	scContext *ScContext
+	// publicStatePrefix is declared by the contract in on_load
+	publicStatePrefix kv.Key
 }
 
 const ViewCopyAllState = "copy_all_state"
@@ -63,6 +65,16 @@ func (host *wasmProcessor) call(ctx coretypes.Sandbox, ctxView coretypes.Sandbox
 	host.ctx = ctx
 	host.ctxView = ctxView
 	host.nesting++
+	saveGasMeter := host.GasMeter()
+	if ctx != nil {
+		host.SetGasMeter(ctx)
+	} else if gasMeter, ok := ctxView.(coretypes.GasMeter); ok {
+		// the view called while the request is being processed, its gas is charged to the request
+		host.SetGasMeter(gasMeter)
+	} else if saveGasMeter == nil {
+		host.SetGasMeter(&wasmhost.ViewGasMeter{})
+	}
+	// otherwise it is the view called by the view, it shares the gas limit of the calling view
 
 	defer func() {
 		host.nesting--
@@ -73,6 +85,7 @@ func (host *wasmProcessor) call(ctx coretypes.Sandbox, ctxView coretypes.Sandbox
 		}
 		host.ctx = saveCtx
 		host.ctxView = saveCtxView
+		host.SetGasMeter(saveGasMeter)
 	}()
 
 	testMode, _ := host.params().Has("testMode")
@@ -103,6 +116,11 @@ func (host *wasmProcessor) CallView(ctx coretypes.SandboxView) (dict.Dict, error
 	return host.call(nil, ctx)
 }
 
+// GetPublicStatePrefix implements coretypes.PublicStateProcessor
+func (host *wasmProcessor) GetPublicStatePrefix() kv.Key {
+	return host.publicStatePrefix
+}
+
 func (host *wasmProcessor) GetDescription() string {
 	return "Wasm VM smart contract processor"
 }
@@ -23,7 +23,7 @@ func addChainRecordEndpoints(adm echoswagger.ApiGroup) {
 	}
 
 	adm.POST(routes.PutChainRecord(), handlePutChainRecord).
-		SetSummary("Create a new chain record").
+		SetSummary("Create a new chain record or replace the existing one with the new committee address").
 		AddParamBody(example, "ChainRecord", "Chain record", true)
 
 	adm.GET(routes.GetChainRecord(":chainID"), handleGetChainRecord).
@@ -49,7 +49,8 @@ func handlePutChainRecord(c echo.Context) error {
 	if err != nil {
 		return err
 	}
-	if bd2 != nil {
+	// the existing record can only be replaced by the record of the rotated committee
+	if bd2 != nil && bd2.Address() == bd.Address() {
 		return httperrors.Conflict(fmt.Sprintf("ChainRecord already exists: %s", bd.ChainID.String()))
 	}
 	if err = registry.SaveChainRecord(bd); err != nil {
@@ -23,7 +23,9 @@ func AddEndpoints(adm echoswagger.ApiGroup, adminWhitelist []net.IP) {
 	addShutdownEndpoint(adm)
 	addChainRecordEndpoints(adm)
 	addChainEndpoints(adm)
+	addSnapshotEndpoints(adm)
 	addDKSharesEndpoints(adm)
+	addPeeringEndpoints(adm)
 }
 
 // allow only if the remote address is private or in whitelist
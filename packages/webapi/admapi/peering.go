@@ -0,0 +1,108 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package admapi
+
+// Endpoints for maintaining the list of trusted peers.
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/iotaledger/wasp/packages/peering"
+	"github.com/iotaledger/wasp/packages/webapi/httperrors"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/packages/webapi/routes"
+	"github.com/iotaledger/wasp/plugins/dkg"
+	"github.com/iotaledger/wasp/plugins/registry"
+	"github.com/labstack/echo/v4"
+	"github.com/pangpanglabs/echoswagger/v2"
+	"go.dedis.ch/kyber/v3"
+)
+
+func addPeeringEndpoints(adm echoswagger.ApiGroup) {
+	example := model.PeeringTrustedNode{
+		PubKey: base64.StdEncoding.EncodeToString([]byte("key")),
+		NetID:  "wasp1:4000",
+	}
+
+	adm.GET(routes.PeeringTrustedList(), handlePeeringTrustedList).
+		AddResponse(http.StatusOK, "Trusted peers", []model.PeeringTrustedNode{example}, nil).
+		SetSummary("Get the list of trusted peers")
+
+	adm.POST(routes.PeeringTrustedList(), handlePeeringTrustedPost).
+		AddParamBody(example, "PeeringTrustedNode", "Peer to trust", true).
+		AddResponse(http.StatusOK, "Trusted peer", example, nil).
+		SetSummary("Trust the peer").
+		SetDescription("The node only peers with trusted nodes. Trusting the already trusted peer updates its NetID")
+
+	adm.DELETE(routes.PeeringTrustedDelete(":pubKey"), handlePeeringTrustedDelete).
+		AddParamPath("", "pubKey", "Public key of the peer (base64, URL escaped)").
+		AddResponse(http.StatusOK, "Distrusted peer", example, nil).
+		SetSummary("Distrust the peer").
+		SetDescription("The established connection with the peer is not affected until the peers handshake again")
+}
+
+func handlePeeringTrustedList(c echo.Context) error {
+	trusted, err := registry.DefaultRegistry().TrustedPeers()
+	if err != nil {
+		return err
+	}
+	ret := make([]model.PeeringTrustedNode, len(trusted))
+	for i, tp := range trusted {
+		ret[i] = makePeeringTrustedNode(tp)
+	}
+	return c.JSON(http.StatusOK, ret)
+}
+
+func handlePeeringTrustedPost(c echo.Context) error {
+	var req model.PeeringTrustedNode
+	if err := c.Bind(&req); err != nil {
+		return httperrors.BadRequest("Invalid request body.")
+	}
+	pubKey, err := pubKeyFromString(req.PubKey)
+	if err != nil {
+		return err
+	}
+	if req.NetID == "" {
+		return httperrors.BadRequest("NetID is required.")
+	}
+	tp, err := registry.DefaultRegistry().TrustPeer(pubKey, req.NetID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, makePeeringTrustedNode(tp))
+}
+
+func handlePeeringTrustedDelete(c echo.Context) error {
+	pubKeyStr, err := url.PathUnescape(c.Param("pubKey"))
+	if err != nil {
+		return httperrors.BadRequest(fmt.Sprintf("Invalid pubKey: %s", c.Param("pubKey")))
+	}
+	pubKey, err := pubKeyFromString(pubKeyStr)
+	if err != nil {
+		return err
+	}
+	tp, err := registry.DefaultRegistry().DistrustPeer(pubKey)
+	if err != nil {
+		return httperrors.NotFound(err.Error())
+	}
+	return c.JSON(http.StatusOK, makePeeringTrustedNode(tp))
+}
+
+func pubKeyFromString(s string) (kyber.Point, error) {
+	pubKey, err := peering.PubKeyFromString(s, dkg.DefaultNode().GroupSuite())
+	if err != nil {
+		return nil, httperrors.BadRequest(fmt.Sprintf("Invalid pubKey: %s", s))
+	}
+	return pubKey, nil
+}
+
+func makePeeringTrustedNode(tp *peering.TrustedPeer) model.PeeringTrustedNode {
+	return model.PeeringTrustedNode{
+		PubKey: tp.PubKeyString(),
+		NetID:  tp.NetID,
+	}
+}
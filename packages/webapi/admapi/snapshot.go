@@ -0,0 +1,117 @@
+package admapi
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/registry"
+	"github.com/iotaledger/wasp/packages/sctransaction"
+	"github.com/iotaledger/wasp/packages/state"
+	"github.com/iotaledger/wasp/packages/webapi/httperrors"
+	"github.com/iotaledger/wasp/packages/webapi/routes"
+	"github.com/iotaledger/wasp/plugins/chains"
+	"github.com/iotaledger/wasp/plugins/nodeconn"
+	"github.com/labstack/echo/v4"
+	"github.com/pangpanglabs/echoswagger/v2"
+)
+
+// time to wait for the anchoring state transaction of the imported snapshot from the node
+const snapshotAnchorTimeout = 10 * time.Second
+
+func addSnapshotEndpoints(adm echoswagger.ApiGroup) {
+	adm.GET(routes.ChainSnapshot(":chainID"), handleExportSnapshot).
+		AddParamPath("", "chainID", "ChainID (base58)").
+		SetResponseContentType(echo.MIMEOctetStream).
+		AddResponse(http.StatusOK, "Binary snapshot", nil, nil).
+		SetSummary("Export the snapshot of the solid state of the chain").
+		SetDescription("The snapshot contains all state variables, it is streamed as it is read from the db. " +
+			"If the solid state changes during the export, the snapshot is incomplete and can't be imported")
+
+	adm.POST(routes.ChainSnapshot(":chainID"), handleImportSnapshot).
+		AddParamPath("", "chainID", "ChainID (base58)").
+		SetRequestContentType(echo.MIMEOctetStream).
+		AddParamBody("", "Snapshot", "Binary snapshot of the solid state of the chain", true).
+		SetSummary("Import the snapshot of the solid state of the chain").
+		SetDescription("The chain must not be active. The snapshot is verified against the confirmed anchoring state " +
+			"transaction before it replaces the state of the chain in the node. After the chain is activated, " +
+			"the node syncs only the blocks after the snapshot")
+}
+
+func chainIDFromParam(c echo.Context) (coretypes.ChainID, error) {
+	addr, err := address.FromBase58(c.Param("chainID"))
+	if err != nil {
+		return coretypes.ChainID{}, httperrors.BadRequest(fmt.Sprintf("Invalid chain id: %s", c.Param("chainID")))
+	}
+	return (coretypes.ChainID)(addr), nil
+}
+
+func handleExportSnapshot(c echo.Context) error {
+	chainID, err := chainIDFromParam(c)
+	if err != nil {
+		return err
+	}
+	_, _, ok, err := state.LoadSolidState(&chainID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return httperrors.NotFound(fmt.Sprintf("State not found for chain %s", chainID.String()))
+	}
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, echo.MIMEOctetStream)
+	res.WriteHeader(http.StatusOK)
+	if _, err := state.ExportSnapshot(&chainID, res); err != nil {
+		// the response is already sent, the incomplete snapshot is rejected by the reader
+		log.Errorf("failed to export snapshot of chain %s: %v", chainID.String(), err)
+		return nil
+	}
+	log.Infof("exported snapshot of chain %s", chainID.String())
+	return nil
+}
+
+func handleImportSnapshot(c echo.Context) error {
+	chainID, err := chainIDFromParam(c)
+	if err != nil {
+		return err
+	}
+	snapshot := new(state.Snapshot)
+	if err := snapshot.Read(c.Request().Body); err != nil {
+		return httperrors.BadRequest(fmt.Sprintf("Invalid snapshot: %v", err))
+	}
+	if snapshot.ChainID != chainID {
+		return httperrors.BadRequest(fmt.Sprintf("Snapshot belongs to chain %s", snapshot.ChainID.String()))
+	}
+	if chains.GetChain(chainID) != nil {
+		return httperrors.Conflict(fmt.Sprintf("Chain %s is active", chainID.String()))
+	}
+	if err := snapshot.Verify(); err != nil {
+		return httperrors.BadRequest(fmt.Sprintf("Invalid snapshot: %v", err))
+	}
+	chainRecord, err := registry.GetChainRecord(&chainID)
+	if err != nil {
+		return err
+	}
+	if chainRecord == nil {
+		return httperrors.NotFound(fmt.Sprintf("Chain record not found: %s", chainID.String()))
+	}
+	txid := snapshot.Block.StateTransactionID()
+	vtx, err := nodeconn.GetConfirmedTransaction(&txid, snapshotAnchorTimeout)
+	if err != nil {
+		return httperrors.Timeout(fmt.Sprintf("Can't get the anchoring transaction: %v", err))
+	}
+	tx, err := sctransaction.ParseValueTransaction(vtx)
+	if err != nil {
+		return httperrors.BadRequest(fmt.Sprintf("Invalid anchoring transaction: %v", err))
+	}
+	if err := snapshot.VerifyAnchor(tx, &chainRecord.Color); err != nil {
+		return httperrors.BadRequest(fmt.Sprintf("Snapshot is not anchored: %v", err))
+	}
+	if err := state.ImportSnapshot(snapshot); err != nil {
+		return httperrors.BadRequest(fmt.Sprintf("Can't import snapshot: %v", err))
+	}
+	log.Infof("imported %s", snapshot.String())
+	return c.NoContent(http.StatusOK)
+}
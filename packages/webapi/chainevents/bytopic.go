@@ -0,0 +1,85 @@
+package chainevents
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/vm/core/eventlog"
+	"github.com/iotaledger/wasp/packages/vm/viewcontext"
+	"github.com/iotaledger/wasp/packages/webapi/httperrors"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/packages/webapi/routes"
+	"github.com/labstack/echo/v4"
+	"github.com/pangpanglabs/echoswagger/v2"
+)
+
+func addEventsByTopicEndpoint(server echoswagger.ApiRouter) {
+	server.GET(routes.EventsByTopic(":chainID", ":topic"), handleEventsByTopic).
+		SetSummary("Get the last structured events of the chain with the topic, in time descending order").
+		AddParamPath("", "chainID", "ChainID (base58)").
+		AddParamPath("", "topic", "Topic of the event (hex)").
+		AddParamQuery("", "contract", "Only events of the contract (hname)", false).
+		AddParamQuery("", "name", "Only events with the name", false).
+		AddParamQuery("", "fromTs", "Only events not earlier than the timestamp", false).
+		AddParamQuery("", "toTs", "Only events not later than the timestamp", false).
+		AddParamQuery("", "max", "Max number of events. Default is 50", false).
+		AddResponse(http.StatusOK, "Structured events", []*model.TypedEvent{}, nil)
+}
+
+func handleEventsByTopic(c echo.Context) error {
+	ch, contract, err := parseParams(c)
+	if err != nil {
+		return err
+	}
+	topic, err := hex.DecodeString(c.Param("topic"))
+	if err != nil || len(topic) == 0 {
+		return httperrors.BadRequest(fmt.Sprintf("Invalid topic: %+v", c.Param("topic")))
+	}
+
+	params := dict.New()
+	params.Set(eventlog.ParamTopic, topic)
+	if contract != nil {
+		params.Set(eventlog.ParamContractHname, codec.EncodeHname(*contract))
+	}
+	if name := c.QueryParam("name"); name != "" {
+		params.Set(eventlog.ParamEventName, codec.EncodeString(name))
+	}
+	for qparam, key := range map[string]kv.Key{
+		"fromTs": eventlog.ParamFromTs,
+		"toTs":   eventlog.ParamToTs,
+		"max":    eventlog.ParamMaxLastRecords,
+	} {
+		if c.QueryParam(qparam) == "" {
+			continue
+		}
+		n, err := strconv.ParseInt(c.QueryParam(qparam), 10, 64)
+		if err != nil {
+			return httperrors.BadRequest(fmt.Sprintf("Invalid %s: %+v", qparam, c.QueryParam(qparam)))
+		}
+		params.Set(key, codec.EncodeInt64(n))
+	}
+
+	vctx, err := viewcontext.NewFromDB(*ch.ID(), ch.Processors())
+	if err != nil {
+		return fmt.Errorf("Failed to create context: %v", err)
+	}
+	ret, err := vctx.CallView(eventlog.Interface.Hname(), coretypes.Hn(eventlog.FuncGetEventsByTopic), params)
+	if err != nil {
+		return httperrors.BadRequest(fmt.Sprintf("View call failed: %v", err))
+	}
+	recs, err := eventlog.DecodeEventRecords(ret)
+	if err != nil {
+		return err
+	}
+	res := make([]*model.TypedEvent, len(recs))
+	for i, rec := range recs {
+		res[i] = model.NewTypedEvent(rec)
+	}
+	return c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,211 @@
+package chainevents
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/wasp/packages/chain"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/state"
+	"github.com/iotaledger/wasp/packages/vm/core/eventlog"
+	"github.com/iotaledger/wasp/packages/webapi/httperrors"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/packages/webapi/routes"
+	"github.com/iotaledger/wasp/plugins/chains"
+	"github.com/labstack/echo/v4"
+	"github.com/pangpanglabs/echoswagger/v2"
+	"golang.org/x/net/websocket"
+)
+
+// size of the buffer of committed blocks for each connected client. If the client does not keep up, the stream is closed
+const clientBufferSize = 100
+
+func AddEndpoints(server echoswagger.ApiRouter) {
+	server.GET(routes.ChainEventsWebSocket(":chainID"), handleWebSocket).
+		SetSummary("Stream committed blocks, processed requests and event log records of the chain via WebSocket").
+		AddParamPath("", "chainID", "ChainID (base58)").
+		AddParamQuery("", "contract", "Only stream requests and event log records of the contract (hname)", false).
+		AddResponse(http.StatusSwitchingProtocols, "Stream of JSON encoded chain events", model.ChainEvent{}, nil)
+
+	server.GET(routes.ChainEventsSSE(":chainID"), handleSSE).
+		SetSummary("Stream committed blocks, processed requests and event log records of the chain as Server-Sent Events").
+		AddParamPath("", "chainID", "ChainID (base58)").
+		AddParamQuery("", "contract", "Only stream requests and event log records of the contract (hname)", false).
+		AddResponse(http.StatusOK, "Stream of JSON encoded chain events", model.ChainEvent{}, nil)
+
+	addEventsByTopicEndpoint(server)
+}
+
+func handleWebSocket(c echo.Context) error {
+	ch, contract, err := parseParams(c)
+	if err != nil {
+		return err
+	}
+	websocket.Handler(func(ws *websocket.Conn) {
+		defer ws.Close()
+
+		closed := make(chan struct{})
+		go func() {
+			// the client is not expected to send anything; reading detects the closed connection
+			defer close(closed)
+			var buf [1]byte
+			for {
+				if _, err := ws.Read(buf[:]); err != nil {
+					return
+				}
+			}
+		}()
+
+		stream(ch, contract, closed, func(ev *model.ChainEvent) error {
+			return websocket.JSON.Send(ws, ev)
+		})
+	}).ServeHTTP(c.Response(), c.Request())
+	return nil
+}
+
+func handleSSE(c echo.Context) error {
+	ch, contract, err := parseParams(c)
+	if err != nil {
+		return err
+	}
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set("Cache-Control", "no-cache")
+	res.Header().Set("Connection", "keep-alive")
+	res.WriteHeader(http.StatusOK)
+	res.Flush()
+
+	stream(ch, contract, c.Request().Context().Done(), func(ev *model.ChainEvent) error {
+		data, err := json.Marshal(ev)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(res, "event: %s\ndata: %s\n\n", ev.Type, data); err != nil {
+			return err
+		}
+		res.Flush()
+		return nil
+	})
+	return nil
+}
+
+type committedBlock struct {
+	stateHash hashing.HashValue
+	block     state.Block
+}
+
+// stream sends events of committed blocks until closed or until sending fails.
+// The handler of the chain event only queues the block, events are created and sent by the stream
+func stream(ch chain.Chain, contract *coretypes.Hname, closed <-chan struct{}, send func(*model.ChainEvent) error) {
+	blockCh := make(chan *committedBlock, clientBufferSize)
+	overflow := make(chan struct{})
+	overflowed := false
+
+	onBlock := events.NewClosure(func(stateHash hashing.HashValue, block state.Block) {
+		if overflowed {
+			return
+		}
+		select {
+		case blockCh <- &committedBlock{stateHash: stateHash, block: block}:
+		default:
+			overflowed = true
+			close(overflow)
+		}
+	})
+	ch.EventBlockCommitted().Attach(onBlock)
+	defer ch.EventBlockCommitted().Detach(onBlock)
+
+	for {
+		select {
+		case b := <-blockCh:
+			for _, ev := range BlockEvents(b.stateHash, b.block, contract) {
+				if err := send(ev); err != nil {
+					return
+				}
+			}
+		case <-overflow:
+			return
+		case <-closed:
+			return
+		}
+	}
+}
+
+// BlockEvents returns events of the committed block: the block itself, event log records and results of
+// processed requests, as recorded by the VM. If contract is not nil, only records and requests of the contract are returned
+func BlockEvents(stateHash hashing.HashValue, block state.Block, contract *coretypes.Hname) []*model.ChainEvent {
+	txid := block.StateTransactionID()
+	stateTxID := model.NewValueTxID(&txid)
+	hash := model.NewHashValue(stateHash)
+	ret := []*model.ChainEvent{{
+		Type:        model.ChainEventBlock,
+		BlockIndex:  block.StateIndex(),
+		Timestamp:   block.Timestamp(),
+		StateTxID:   &stateTxID,
+		StateHash:   &hash,
+		NumRequests: block.Size(),
+	}}
+	block.ForEach(func(_ uint16, su state.StateUpdate) bool {
+		for _, rec := range eventlog.RecordsFromMutations(su.Mutations()) {
+			if contract != nil && rec.Contract != *contract {
+				continue
+			}
+			ret = append(ret, &model.ChainEvent{
+				Type:       model.ChainEventEventLog,
+				BlockIndex: block.StateIndex(),
+				Timestamp:  rec.Timestamp,
+				Contract:   rec.Contract.String(),
+				Data:       string(rec.Data),
+			})
+		}
+		res := su.Result()
+		if res == nil || (contract != nil && res.Contract != *contract) {
+			return true
+		}
+		ev := &model.ChainEvent{
+			Type:       model.ChainEventRequest,
+			BlockIndex: block.StateIndex(),
+			Timestamp:  su.Timestamp(),
+			Contract:   res.Contract.String(),
+			Error:      res.Error,
+			GasBurned:  res.GasBurned,
+		}
+		if res.Job {
+			// the request ID of the job run is synthetic
+			jobID := res.JobID
+			ev.Type = model.ChainEventJob
+			ev.JobID = &jobID
+		} else {
+			ev.RequestID = su.RequestID().Base58()
+		}
+		if res.Result != nil {
+			result := res.Result.JSONDict()
+			ev.Result = &result
+		}
+		ret = append(ret, ev)
+		return true
+	})
+	return ret
+}
+
+func parseParams(c echo.Context) (chain.Chain, *coretypes.Hname, error) {
+	chainID, err := coretypes.NewChainIDFromBase58(c.Param("chainID"))
+	if err != nil {
+		return nil, nil, httperrors.BadRequest(fmt.Sprintf("Invalid chain ID %+v: %s", c.Param("chainID"), err.Error()))
+	}
+	ch := chains.GetChain(chainID)
+	if ch == nil {
+		return nil, nil, httperrors.NotFound(fmt.Sprintf("Chain not found: %+v", chainID.String()))
+	}
+	if c.QueryParam("contract") == "" {
+		return ch, nil, nil
+	}
+	contract, err := coretypes.HnameFromString(c.QueryParam("contract"))
+	if err != nil {
+		return nil, nil, httperrors.BadRequest(fmt.Sprintf("Invalid contract hname %+v: %s", c.QueryParam("contract"), err.Error()))
+	}
+	return ch, &contract, nil
+}
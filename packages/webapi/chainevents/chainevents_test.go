@@ -0,0 +1,102 @@
+package chainevents
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/kv/buffered"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/kv/subrealm"
+	"github.com/iotaledger/wasp/packages/state"
+	"github.com/iotaledger/wasp/packages/vm/core/eventlog"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newStateUpdate(reqID *coretypes.RequestID, contract coretypes.Hname, reqErr error, records ...string) state.StateUpdate {
+	store := buffered.NewBufferedKVStore(mapdb.NewMapDB())
+	eventlogState := subrealm.New(store, kv.Key(eventlog.Interface.Hname().Bytes()))
+	for _, rec := range records {
+		eventlog.AppendToLog(eventlogState, 1000, contract, []byte(rec))
+	}
+	// contract state is not an event log record
+	store.Set(kv.Key(contract.Bytes())+"x", []byte("y"))
+
+	su := state.NewStateUpdate(reqID).WithTimestamp(2000)
+	store.Mutations().Iterate(func(mut buffered.Mutation) bool {
+		su.Mutations().Add(mut)
+		return true
+	})
+	res := &state.RequestResult{
+		Contract:  contract,
+		GasBurned: 42,
+	}
+	if reqErr != nil {
+		res.Error = reqErr.Error()
+	} else {
+		res.Result = dict.Dict{"ret": []byte{1}}
+	}
+	return su.WithResult(res)
+}
+
+func TestBlockEvents(t *testing.T) {
+	chainID := coretypes.ChainID{1, 3, 3, 7}
+	vs := state.NewVirtualState(mapdb.NewMapDB(), &chainID)
+	stateHash := hashing.HashStrings("state")
+
+	txid := (transaction.ID)(hashing.HashStrings("test"))
+	reqID1 := coretypes.NewRequestID(txid, 0)
+	reqID2 := coretypes.NewRequestID(txid, 1)
+	contract1 := coretypes.Hn("contract1")
+	contract2 := coretypes.Hn("contract2")
+
+	block, err := state.NewBlock([]state.StateUpdate{
+		newStateUpdate(&reqID1, contract1, nil, "event 1", "event 2"),
+		newStateUpdate(&reqID2, contract2, errors.New("failed")),
+	})
+	require.NoError(t, err)
+	stateTxID := (transaction.ID)(hashing.HashStrings("state tx"))
+	block.WithStateTransaction(stateTxID)
+	require.NoError(t, vs.ApplyBlock(block))
+
+	evs := BlockEvents(stateHash, block, nil)
+	require.Len(t, evs, 5)
+
+	assert.Equal(t, model.ChainEventBlock, evs[0].Type)
+	assert.EqualValues(t, 0, evs[0].BlockIndex)
+	assert.EqualValues(t, 2, evs[0].NumRequests)
+	assert.Equal(t, stateTxID, evs[0].StateTxID.ID())
+	assert.Equal(t, stateHash, evs[0].StateHash.HashValue())
+
+	assert.Equal(t, model.ChainEventEventLog, evs[1].Type)
+	assert.Equal(t, contract1.String(), evs[1].Contract)
+	assert.Equal(t, "event 1", evs[1].Data)
+	assert.EqualValues(t, 1000, evs[1].Timestamp)
+	assert.Equal(t, "event 2", evs[2].Data)
+
+	assert.Equal(t, model.ChainEventRequest, evs[3].Type)
+	assert.Equal(t, reqID1.Base58(), evs[3].RequestID)
+	assert.Equal(t, contract1.String(), evs[3].Contract)
+	assert.EqualValues(t, 2000, evs[3].Timestamp)
+	assert.Empty(t, evs[3].Error)
+	assert.EqualValues(t, 42, evs[3].GasBurned)
+	require.NotNil(t, evs[3].Result)
+	assert.EqualValues(t, dict.Dict{"ret": []byte{1}}.JSONDict(), *evs[3].Result)
+
+	assert.Equal(t, model.ChainEventRequest, evs[4].Type)
+	assert.Equal(t, reqID2.Base58(), evs[4].RequestID)
+	assert.Equal(t, contract2.String(), evs[4].Contract)
+	assert.Equal(t, "failed", evs[4].Error)
+	assert.Nil(t, evs[4].Result)
+
+	evs = BlockEvents(stateHash, block, &contract2)
+	require.Len(t, evs, 2)
+	assert.Equal(t, model.ChainEventBlock, evs[0].Type)
+	assert.Equal(t, reqID2.Base58(), evs[1].RequestID)
+}
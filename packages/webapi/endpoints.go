@@ -6,15 +6,17 @@ import (
 	"github.com/iotaledger/hive.go/logger"
 	"github.com/iotaledger/wasp/packages/webapi/admapi"
 	"github.com/iotaledger/wasp/packages/webapi/blob"
+	"github.com/iotaledger/wasp/packages/webapi/chainevents"
 	"github.com/iotaledger/wasp/packages/webapi/info"
 	"github.com/iotaledger/wasp/packages/webapi/request"
 	"github.com/iotaledger/wasp/packages/webapi/state"
+	"github.com/iotaledger/wasp/packages/webapi/tokens"
 	"github.com/pangpanglabs/echoswagger/v2"
 )
 
 var log *logger.Logger
 
-func Init(server echoswagger.ApiRoot, adminWhitelist []net.IP) {
+func Init(server echoswagger.ApiRoot, adminWhitelist []net.IP, maxPastViewCalls int) {
 	log = logger.NewLogger("WebAPI")
 
 	server.SetRequestContentType("application/json")
@@ -22,9 +24,11 @@ func Init(server echoswagger.ApiRoot, adminWhitelist []net.IP) {
 
 	pub := server.Group("public", "").SetDescription("Public endpoints")
 	blob.AddEndpoints(pub)
+	chainevents.AddEndpoints(pub)
 	info.AddEndpoints(pub)
 	request.AddEndpoints(pub)
-	state.AddEndpoints(pub)
+	state.AddEndpoints(pub, maxPastViewCalls)
+	tokens.AddEndpoints(pub)
 
 	adm := server.Group("admin", "").SetDescription("Admin endpoints")
 	admapi.AddEndpoints(adm, adminWhitelist)
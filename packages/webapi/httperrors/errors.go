@@ -23,6 +23,10 @@ func Conflict(message string) *HTTPError {
 	return &HTTPError{Code: http.StatusConflict, Message: message}
 }
 
+func TooManyRequests(message string) *HTTPError {
+	return &HTTPError{Code: http.StatusTooManyRequests, Message: message}
+}
+
 func Timeout(message string) *HTTPError {
 	return &HTTPError{Code: http.StatusRequestTimeout, Message: message}
 }
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/iotaledger/wasp/packages/parameters"
+	peering_pkg "github.com/iotaledger/wasp/packages/peering"
 	"github.com/iotaledger/wasp/packages/webapi/model"
 	"github.com/iotaledger/wasp/packages/webapi/routes"
 	"github.com/iotaledger/wasp/plugins/banner"
@@ -22,6 +23,7 @@ func handleInfo(c echo.Context) error {
 	return c.JSON(http.StatusOK, model.InfoResponse{
 		Version:       banner.AppVersion,
 		NetworkId:     peering.DefaultNetworkProvider().Self().NetID(),
+		PubKey:        peering_pkg.PubKeyToString(peering.DefaultNetworkProvider().Self().PubKey()),
 		PublisherPort: parameters.GetInt(parameters.NanomsgPublisherPort),
 	})
 }
@@ -0,0 +1,33 @@
+package model
+
+import "github.com/iotaledger/wasp/packages/kv/dict"
+
+const (
+	ChainEventBlock    = "block"
+	ChainEventRequest  = "request"
+	ChainEventJob      = "job"
+	ChainEventEventLog = "eventlog"
+)
+
+// ChainEvent is an event of the chain, streamed by the web API when a block is committed
+type ChainEvent struct {
+	Type       string `swagger:"desc(Type of the event: block, request, job or eventlog)"`
+	BlockIndex uint32 `swagger:"desc(Index of the committed block)"`
+	Timestamp  int64  `swagger:"desc(Timestamp of the block or of the event log record)"`
+
+	// block events
+	StateTxID   *ValueTxID `json:"StateTxID,omitempty" swagger:"desc(ID of the anchor transaction of the block)"`
+	StateHash   *HashValue `json:"StateHash,omitempty" swagger:"desc(Hash of the state after the block)"`
+	NumRequests uint16     `json:"NumRequests,omitempty" swagger:"desc(Number of requests in the block)"`
+
+	// request and job events
+	RequestID string         `json:"RequestID,omitempty" swagger:"desc(ID of the processed request (base58). Omitted for jobs)"`
+	JobID     *uint32        `json:"JobID,omitempty" swagger:"desc(ID of the scheduled job which was run)"`
+	Error     string         `json:"Error,omitempty" swagger:"desc(Error message, if the request failed)"`
+	GasBurned uint64         `json:"GasBurned,omitempty" swagger:"desc(Gas burned by the request)"`
+	Result    *dict.JSONDict `json:"Result,omitempty" swagger:"desc(Values returned by the request, if the request succeeded)"`
+
+	// request and event log events
+	Contract string `json:"Contract,omitempty" swagger:"desc(Hname of the contract)"`
+	Data     string `json:"Data,omitempty" swagger:"desc(Content of the event log record)"`
+}
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/address"
 	"github.com/iotaledger/wasp/packages/registry"
 )
 
@@ -9,22 +10,36 @@ type ChainRecord struct {
 	Color          Color    `swagger:"desc(Chain color (base58-encoded))"`
 	CommitteeNodes []string `swagger:"desc(List of committee nodes (network IDs))"`
 	Active         bool     `swagger:"desc(Whether or not the chain is active)"`
+	// CommitteeAddress is empty unless the committee of the chain has been rotated
+	CommitteeAddress Address `json:",omitempty" swagger:"desc(Address of the current committee (base58-encoded). Defaults to the chain ID)"`
+	// AccessNodes follow the chain without participating in the consensus
+	AccessNodes []string `json:",omitempty" swagger:"desc(List of access nodes (network IDs))"`
 }
 
 func NewChainRecord(bd *registry.ChainRecord) *ChainRecord {
-	return &ChainRecord{
+	ret := &ChainRecord{
 		ChainID:        NewChainID(&bd.ChainID),
 		Color:          NewColor(&bd.Color),
 		CommitteeNodes: bd.CommitteeNodes[:],
 		Active:         bd.Active,
+		AccessNodes:    bd.AccessNodes[:],
 	}
+	if bd.CommitteeAddress != (address.Address{}) {
+		ret.CommitteeAddress = NewAddress(&bd.CommitteeAddress)
+	}
+	return ret
 }
 
 func (bd *ChainRecord) ChainRecord() *registry.ChainRecord {
-	return &registry.ChainRecord{
+	ret := &registry.ChainRecord{
 		ChainID:        bd.ChainID.ChainID(),
 		Color:          bd.Color.Color(),
 		CommitteeNodes: bd.CommitteeNodes[:],
 		Active:         bd.Active,
+		AccessNodes:    bd.AccessNodes[:],
+	}
+	if bd.CommitteeAddress != "" {
+		ret.CommitteeAddress = bd.CommitteeAddress.Address()
 	}
+	return ret
 }
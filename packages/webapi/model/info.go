@@ -3,5 +3,6 @@ package model
 type InfoResponse struct {
 	Version       string `swagger:"desc(Wasp version)"`
 	NetworkId     string `swagger:"desc('hostname:port'; uniquely identifies the node)"`
+	PubKey        string `swagger:"desc(Public key of the node identity (base64). Other nodes must trust it to peer with the node)"`
 	PublisherPort int    `swagger:"desc(Nanomsg port that exposes publisher messages)"`
 }
@@ -0,0 +1,22 @@
+package model
+
+import (
+	"github.com/iotaledger/wasp/packages/vm/core/nft"
+)
+
+// NFT is the non-fungible token
+type NFT struct {
+	ID       Color     `swagger:"desc(ID of the NFT (base58))"`
+	Metadata HashValue `swagger:"desc(Hash of the metadata blob (base58))"`
+	Creator  string    `swagger:"desc(Agent ID of the creator of the NFT)"`
+	Origin   ChainID   `swagger:"desc(ID of the chain where the NFT was minted (base58))"`
+}
+
+func NewNFT(rec *nft.NFTRecord) *NFT {
+	return &NFT{
+		ID:       NewColor(&rec.ID),
+		Metadata: NewHashValue(rec.Metadata),
+		Creator:  rec.Creator.String(),
+		Origin:   NewChainID(&rec.Origin),
+	}
+}
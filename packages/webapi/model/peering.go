@@ -0,0 +1,7 @@
+package model
+
+// PeeringTrustedNode describes a peer trusted by the node.
+type PeeringTrustedNode struct {
+	PubKey string `json:"pubKey" swagger:"desc(Public key of the node identity of the peer (base64).)"`
+	NetID  string `json:"netID" swagger:"desc(NetID of the peer ('hostname:port').)"`
+}
@@ -7,7 +7,8 @@ type WaitRequestProcessedParams struct {
 }
 
 type RequestStatusResponse struct {
-	IsProcessed bool `swagger:"desc(True if the request has been processed)"`
+	IsProcessed bool   `swagger:"desc(True if the request has been processed)"`
+	Status      string `swagger:"desc(Processing status of the request: unknown|backlog|solidifying_args|args_timeout|completed)"`
 }
 
 const WaitRequestProcessedDefaultTimeout = 30 * time.Second
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"github.com/iotaledger/wasp/packages/hashing"
+	"github.com/iotaledger/wasp/packages/state"
+)
+
+// StateProof is a proof of inclusion of a key/value pair into the solid state of a chain
+type StateProof struct {
+	StateIndex  uint32      `swagger:"desc(Index of the solid state)"`
+	StateHash   HashValue   `swagger:"desc(Hash of the solid state, as committed to the state transaction)"`
+	ChainedHash HashValue   `swagger:"desc(Hash of the chain of state updates)"`
+	MerkleRoot  HashValue   `swagger:"desc(Root of the Merkle tree of state variables)"`
+	StateTxID   ValueTxID   `swagger:"desc(ID of the state transaction which approves the state)"`
+	Key         Bytes       `swagger:"desc(Key (base64))"`
+	Value       Bytes       `swagger:"desc(Value (base64))"`
+	Path        []HashValue `swagger:"desc(Hashes of the siblings on the path from the leaf to the root)"`
+}
+
+func NewStateProof(vs state.VirtualState, txid ValueTxID, key []byte, value []byte, proof *state.MerkleProof) *StateProof {
+	ret := &StateProof{
+		StateIndex:  vs.BlockIndex(),
+		StateHash:   NewHashValue(vs.Hash()),
+		ChainedHash: NewHashValue(vs.ChainedHash()),
+		MerkleRoot:  NewHashValue(vs.MerkleRoot()),
+		StateTxID:   txid,
+		Key:         NewBytes(key),
+		Value:       NewBytes(value),
+		Path:        make([]HashValue, len(proof.Path)),
+	}
+	for i, h := range proof.Path {
+		ret.Path[i] = NewHashValue(h)
+	}
+	return ret
+}
+
+func (p *StateProof) MerkleProof() *state.MerkleProof {
+	ret := &state.MerkleProof{
+		Path: make([]hashing.HashValue, len(p.Path)),
+	}
+	for i, h := range p.Path {
+		ret.Path[i] = h.HashValue()
+	}
+	return ret
+}
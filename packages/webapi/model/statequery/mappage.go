@@ -0,0 +1,76 @@
+package statequery
+
+import (
+	"bytes"
+	"container/heap"
+	"sort"
+)
+
+// mapPage selects the page of map entries in one pass over the map, in any order of iteration.
+// Only the smallest limit+1 entries starting from the key are kept, so the memory is bounded by the page size
+type mapPage struct {
+	fromKey []byte
+	limit   int
+	// max-heap of the smallest entries seen so far
+	entries []KeyValuePair
+}
+
+func newMapPage(fromKey []byte, limit uint32) *mapPage {
+	if limit == 0 || limit > MaxMapPageSize {
+		limit = MaxMapPageSize
+	}
+	return &mapPage{
+		fromKey: fromKey,
+		limit:   int(limit),
+		entries: make([]KeyValuePair, 0),
+	}
+}
+
+func (p *mapPage) Len() int {
+	return len(p.entries)
+}
+
+func (p *mapPage) Less(i, j int) bool {
+	return bytes.Compare(p.entries[i].Key, p.entries[j].Key) > 0
+}
+
+func (p *mapPage) Swap(i, j int) {
+	p.entries[i], p.entries[j] = p.entries[j], p.entries[i]
+}
+
+func (p *mapPage) Push(x interface{}) {
+	p.entries = append(p.entries, x.(KeyValuePair))
+}
+
+func (p *mapPage) Pop() interface{} {
+	ret := p.entries[len(p.entries)-1]
+	p.entries = p.entries[:len(p.entries)-1]
+	return ret
+}
+
+func (p *mapPage) add(key, value []byte) {
+	if bytes.Compare(key, p.fromKey) < 0 {
+		return
+	}
+	if len(p.entries) <= p.limit {
+		heap.Push(p, KeyValuePair{Key: key, Value: value})
+		return
+	}
+	if bytes.Compare(key, p.entries[0].Key) >= 0 {
+		return
+	}
+	p.entries[0] = KeyValuePair{Key: key, Value: value}
+	heap.Fix(p, 0)
+}
+
+// result returns the entries of the page sorted by key and the key of the first entry of the next page, if any
+func (p *mapPage) result() ([]KeyValuePair, []byte) {
+	ret := p.entries
+	sort.Slice(ret, func(i, j int) bool {
+		return bytes.Compare(ret[i].Key, ret[j].Key) < 0
+	})
+	if len(ret) > p.limit {
+		return ret[:p.limit], ret[p.limit].Key
+	}
+	return ret, nil
+}
@@ -1,5 +1,3 @@
-// +build ignore
-
 package statequery
 
 import (
@@ -11,7 +9,6 @@ import (
 	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/hashing"
 	"github.com/iotaledger/wasp/packages/kv"
-	"github.com/iotaledger/wasp/packages/kv/buffered"
 	"github.com/iotaledger/wasp/packages/kv/codec"
 	"github.com/iotaledger/wasp/packages/kv/collections"
 	"github.com/iotaledger/wasp/packages/webapi/model"
@@ -66,8 +63,15 @@ type TLogSliceDataQueryParams struct {
 	Descending bool
 }
 
+// MaxMapPageSize is the maximum number of map entries returned in one page
+const MaxMapPageSize = 1000
+
+// MapQueryParams requests a page of map entries, sorted by element key.
+// The page starts at FromKey (inclusive), or at the first element if FromKey is empty.
+// Limit 0 or above MaxMapPageSize means MaxMapPageSize
 type MapQueryParams struct {
-	Limit uint32
+	Limit   uint32
+	FromKey []byte
 }
 
 type MapElementQueryParams struct {
@@ -90,9 +94,11 @@ type KeyValuePair struct {
 	Value []byte
 }
 
+// MapResult is a page of map entries. NextKey is the cursor for the next page, nil if it is the last page
 type MapResult struct {
 	Len     uint32
 	Entries []KeyValuePair
+	NextKey []byte
 }
 
 type MapElementResult struct {
@@ -143,7 +149,12 @@ func (q *Request) AddArray(key kv.Key, from uint16, to uint16) {
 }
 
 func (q *Request) AddMap(key kv.Key, limit uint32) {
-	p := &MapQueryParams{Limit: limit}
+	q.AddMapPage(key, nil, limit)
+}
+
+// AddMapPage requests the page of the map starting from the element key fromKey (usually MapResult.NextKey)
+func (q *Request) AddMapPage(key kv.Key, fromKey []byte, limit uint32) {
+	p := &MapQueryParams{Limit: limit, FromKey: fromKey}
 	params, _ := json.Marshal(p)
 	q.KeyQueries = append(q.KeyQueries, &KeyQuery{
 		Key:    []byte(key),
@@ -285,7 +296,7 @@ func (r *Results) Get(key kv.Key) *QueryResult {
 	return r.byKey[key]
 }
 
-func (q *KeyQuery) Execute(vars buffered.BufferedKVStore) (*QueryResult, error) {
+func (q *KeyQuery) Execute(vars kv.KVStoreReader) (*QueryResult, error) {
 	key := kv.Key(q.Key)
 	switch q.Type {
 	case ValueTypeScalar:
@@ -302,7 +313,7 @@ func (q *KeyQuery) Execute(vars buffered.BufferedKVStore) (*QueryResult, error)
 			return nil, err
 		}
 
-		arr := collections.NewArray(vars, string(key))
+		arr := collections.NewArrayReadOnly(vars, string(key))
 
 		size, err := arr.Len()
 		if err != nil {
@@ -325,21 +336,22 @@ func (q *KeyQuery) Execute(vars buffered.BufferedKVStore) (*QueryResult, error)
 			return nil, err
 		}
 
-		m := collections.NewMap(vars, string(key))
+		m := collections.NewMapReadOnly(vars, string(key))
 
-		entries := make([]KeyValuePair, 0)
+		page := newMapPage(params.FromKey, params.Limit)
 		err = m.Iterate(func(elemKey []byte, value []byte) bool {
-			entries = append(entries, KeyValuePair{Key: elemKey, Value: value})
-			return len(entries) < int(params.Limit)
+			page.add(elemKey, value)
+			return true
 		})
 		if err != nil {
 			return nil, err
 		}
+		entries, nextKey := page.result()
 		n, err := m.Len()
 		if err != nil {
 			return nil, err
 		}
-		return q.makeResult(MapResult{Len: n, Entries: entries})
+		return q.makeResult(MapResult{Len: n, Entries: entries, NextKey: nextKey})
 
 	case ValueTypeMapElement:
 		var params MapElementQueryParams
@@ -348,14 +360,14 @@ func (q *KeyQuery) Execute(vars buffered.BufferedKVStore) (*QueryResult, error)
 			return nil, err
 		}
 
-		m := collections.NewMap(vars, string(key))
+		m := collections.NewMapReadOnly(vars, string(key))
 
 		v, err := m.GetAt(params.Key)
 		if err != nil {
 			return nil, err
 		}
 		if v == nil {
-			return nil, nil
+			return q.makeResult(nil)
 		}
 		return q.makeResult(MapElementResult{Value: v})
 
@@ -366,7 +378,7 @@ func (q *KeyQuery) Execute(vars buffered.BufferedKVStore) (*QueryResult, error)
 			return nil, err
 		}
 
-		tlog := collections.NewTimestampedLog(vars, key)
+		tlog := collections.NewTimestampedLogReadOnly(vars, key)
 
 		tsl, err := tlog.TakeTimeSlice(params.FromTs, params.ToTs)
 		if err != nil {
@@ -390,7 +402,7 @@ func (q *KeyQuery) Execute(vars buffered.BufferedKVStore) (*QueryResult, error)
 			return nil, err
 		}
 
-		tlog := collections.NewTimestampedLog(vars, key)
+		tlog := collections.NewTimestampedLogReadOnly(vars, key)
 
 		ret := TLogSliceDataResult{}
 		ret.Values, err = tlog.LoadRecordsRaw(params.FromIndex, params.ToIndex, params.Descending)
@@ -0,0 +1,118 @@
+package statequery
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/iotaledger/wasp/packages/kv/collections"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func execute(t *testing.T, vars dict.Dict, q *Request) *Results {
+	ret := &Results{}
+	for _, kq := range q.KeyQueries {
+		res, err := kq.Execute(vars)
+		require.NoError(t, err)
+		ret.KeyQueryResults = append(ret.KeyQueryResults, res)
+	}
+	return ret
+}
+
+func TestQueryScalarAndArray(t *testing.T) {
+	vars := dict.New()
+	vars.Set("s", []byte("scalar"))
+	arr := collections.NewArray(vars, "a")
+	for i := 0; i < 5; i++ {
+		arr.MustPush([]byte(fmt.Sprintf("v%d", i)))
+	}
+
+	q := NewRequest()
+	q.AddScalar("s")
+	q.AddArray("a", 1, 3)
+	res := execute(t, vars, q)
+
+	assert.EqualValues(t, "scalar", res.Get("s").MustBytes())
+	ar := res.Get("a").MustArrayResult()
+	assert.EqualValues(t, 5, ar.Len)
+	assert.EqualValues(t, [][]byte{[]byte("v1"), []byte("v2")}, ar.Values)
+}
+
+func TestQueryMapPages(t *testing.T) {
+	vars := dict.New()
+	m := collections.NewMap(vars, "m")
+	for i := 0; i < 5; i++ {
+		m.MustSetAt([]byte(fmt.Sprintf("k%d", i)), []byte(fmt.Sprintf("v%d", i)))
+	}
+
+	var cursor []byte
+	keys := make([]string, 0)
+	for pages := 0; ; pages++ {
+		require.Less(t, pages, 3)
+		q := NewRequest()
+		q.AddMapPage("m", cursor, 2)
+		mr := execute(t, vars, q).Get("m").MustMapResult()
+		assert.EqualValues(t, 5, mr.Len)
+		for _, e := range mr.Entries {
+			keys = append(keys, string(e.Key))
+		}
+		if mr.NextKey == nil {
+			break
+		}
+		cursor = mr.NextKey
+	}
+	assert.EqualValues(t, []string{"k0", "k1", "k2", "k3", "k4"}, keys)
+
+	q := NewRequest()
+	q.AddMapElement("m", []byte("k3"))
+	q.AddMapElement("m", []byte("missing"))
+	res := execute(t, vars, q)
+	assert.EqualValues(t, "v3", res.KeyQueryResults[0].MustMapElementResult())
+	assert.Nil(t, res.KeyQueryResults[1].MustMapElementResult())
+}
+
+func TestQueryMapPageLimit(t *testing.T) {
+	vars := dict.New()
+	m := collections.NewMap(vars, "m")
+	for i := 0; i < MaxMapPageSize+10; i++ {
+		m.MustSetAt([]byte(fmt.Sprintf("k%05d", i)), []byte{byte(i)})
+	}
+
+	q := NewRequest()
+	q.AddMapPage("m", nil, 0)
+	mr := execute(t, vars, q).Get("m").MustMapResult()
+	assert.EqualValues(t, MaxMapPageSize+10, mr.Len)
+	require.Len(t, mr.Entries, MaxMapPageSize)
+	assert.EqualValues(t, "k00000", string(mr.Entries[0].Key))
+	assert.EqualValues(t, fmt.Sprintf("k%05d", MaxMapPageSize-1), string(mr.Entries[MaxMapPageSize-1].Key))
+	assert.EqualValues(t, fmt.Sprintf("k%05d", MaxMapPageSize), string(mr.NextKey))
+
+	q = NewRequest()
+	q.AddMapPage("m", mr.NextKey, 0)
+	mr = execute(t, vars, q).Get("m").MustMapResult()
+	require.Len(t, mr.Entries, 10)
+	assert.Nil(t, mr.NextKey)
+}
+
+func TestQueryTLogSlice(t *testing.T) {
+	vars := dict.New()
+	tlog := collections.NewTimestampedLog(vars, "t")
+	for i := 1; i <= 5; i++ {
+		tlog.MustAppend(int64(i*10), []byte(fmt.Sprintf("r%d", i)))
+	}
+
+	q := NewRequest()
+	q.AddTLogSlice("t", 20, 40)
+	sl := execute(t, vars, q).Get("t").MustTLogSliceResult()
+	assert.True(t, sl.IsNotEmpty)
+	assert.EqualValues(t, 1, sl.FirstIndex)
+	assert.EqualValues(t, 3, sl.LastIndex)
+	assert.EqualValues(t, 20, sl.Earliest)
+	assert.EqualValues(t, 40, sl.Latest)
+
+	q = NewRequest()
+	q.AddTLogSliceData("t", sl.FirstIndex, sl.LastIndex, false)
+	data := execute(t, vars, q).Get("t").MustTLogSliceDataResult()
+	assert.EqualValues(t, 3, len(data.Values))
+}
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"github.com/iotaledger/wasp/packages/vm/core/tokens"
+)
+
+// NativeToken is the metadata of the chain-native token
+type NativeToken struct {
+	Color    Color  `swagger:"desc(Color of the token (base58))"`
+	Symbol   string `swagger:"desc(Symbol of the token)"`
+	Name     string `swagger:"desc(Name of the token)"`
+	Decimals uint8  `swagger:"desc(Number of decimals of the token)"`
+	Supply   int64  `swagger:"desc(Number of tokens in circulation)"`
+	Owner    string `swagger:"desc(Agent ID of the owner of the token)"`
+}
+
+func NewNativeToken(rec *tokens.TokenRecord) *NativeToken {
+	return &NativeToken{
+		Color:    NewColor(&rec.Color),
+		Symbol:   rec.Symbol,
+		Name:     rec.Name,
+		Decimals: rec.Decimals,
+		Supply:   rec.Supply,
+		Owner:    rec.Owner.String(),
+	}
+}
+
+// TokenHolding is the balance of the chain-native token in the on-chain account
+type TokenHolding struct {
+	Token   *NativeToken `swagger:"desc(The token)"`
+	Balance int64        `swagger:"desc(Balance of the token in the account)"`
+}
+
+func NewTokenHolding(h *tokens.Holding) *TokenHolding {
+	return &TokenHolding{
+		Token:   NewNativeToken(h.Token),
+		Balance: h.Balance,
+	}
+}
@@ -0,0 +1,33 @@
+package model
+
+import (
+	"encoding/hex"
+
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/vm/core/eventlog"
+)
+
+// TypedEvent is a structured event emitted by the smart contract
+type TypedEvent struct {
+	Contract  string        `swagger:"desc(Hname of the contract)"`
+	Timestamp int64         `swagger:"desc(Timestamp of the event)"`
+	RequestID string        `swagger:"desc(ID of the request which emitted the event (base58))"`
+	Name      string        `swagger:"desc(Name of the event)"`
+	Topics    []string      `swagger:"desc(Indexed topics of the event (hex))"`
+	Payload   dict.JSONDict `swagger:"desc(Payload of the event)"`
+}
+
+func NewTypedEvent(rec *eventlog.EventRecord) *TypedEvent {
+	topics := make([]string, len(rec.Topics))
+	for i, topic := range rec.Topics {
+		topics[i] = hex.EncodeToString(topic)
+	}
+	return &TypedEvent{
+		Contract:  rec.Contract.String(),
+		Timestamp: rec.Timestamp,
+		RequestID: rec.RequestID.Base58(),
+		Name:      rec.Name,
+		Topics:    topics,
+		Payload:   rec.Payload.JSONDict(),
+	}
+}
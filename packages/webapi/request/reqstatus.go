@@ -35,15 +35,10 @@ func handleRequestStatus(c echo.Context) error {
 	if err != nil {
 		return err
 	}
-	var isProcessed bool
-	switch ch.GetRequestProcessingStatus(reqID) {
-	case chain.RequestProcessingStatusCompleted:
-		isProcessed = true
-	case chain.RequestProcessingStatusBacklog:
-		isProcessed = false
-	}
+	status := ch.GetRequestProcessingStatus(reqID)
 	return c.JSON(http.StatusOK, model.RequestStatusResponse{
-		IsProcessed: isProcessed,
+		IsProcessed: status == chain.RequestProcessingStatusCompleted,
+		Status:      status.String(),
 	})
 }
 
@@ -8,6 +8,10 @@ func CallView(contractID string, hname string) string {
 	return "/contract/" + contractID + "/callview/" + hname
 }
 
+func CallViewAtBlock(contractID string, hname string, blockIndex string) string {
+	return "/contract/" + contractID + "/callview/" + hname + "/block/" + blockIndex
+}
+
 func RequestStatus(chainID string, reqID string) string {
 	return "/chain/" + chainID + "/request/" + reqID + "/status"
 }
@@ -20,6 +24,38 @@ func StateQuery(chainID string) string {
 	return "/chain/" + chainID + "/state/query"
 }
 
+func StateProof(chainID string, key string) string {
+	return "/chain/" + chainID + "/state/proof/" + key
+}
+
+func ChainEventsWebSocket(chainID string) string {
+	return "/chain/" + chainID + "/events/ws"
+}
+
+func ChainEventsSSE(chainID string) string {
+	return "/chain/" + chainID + "/events/sse"
+}
+
+func EventsByTopic(chainID string, topic string) string {
+	return "/chain/" + chainID + "/events/topic/" + topic
+}
+
+func NativeTokens(chainID string) string {
+	return "/chain/" + chainID + "/tokens"
+}
+
+func TokenHoldings(chainID string, agentID string) string {
+	return "/chain/" + chainID + "/tokens/holdings/" + agentID
+}
+
+func NFT(chainID string, id string) string {
+	return "/chain/" + chainID + "/nft/" + id
+}
+
+func NFTsByOwner(chainID string, agentID string) string {
+	return "/chain/" + chainID + "/nfts/" + agentID
+}
+
 func PutBlob() string {
 	return "/blob/put"
 }
@@ -40,6 +76,10 @@ func DeactivateChain(chainID string) string {
 	return "/adm/chain/" + chainID + "/deactivate"
 }
 
+func ChainSnapshot(chainID string) string {
+	return "/adm/chain/" + chainID + "/snapshot"
+}
+
 func ListChainRecords() string {
 	return "/adm/chainrecords"
 }
@@ -67,3 +107,11 @@ func DumpState(contractID string) string {
 func Shutdown() string {
 	return "/adm/shutdown"
 }
+
+func PeeringTrustedList() string {
+	return "/adm/peering/trusted"
+}
+
+func PeeringTrustedDelete(pubKey string) string {
+	return "/adm/peering/trusted/" + pubKey
+}
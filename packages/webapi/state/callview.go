@@ -2,8 +2,10 @@ package state
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/kv"
@@ -16,7 +18,13 @@ import (
 	"github.com/pangpanglabs/echoswagger/v2"
 )
 
-func AddEndpoints(server echoswagger.ApiRouter) {
+// pastViewCalls limits the number of concurrent view calls on past states, because each call may re-play
+// many blocks of the chain
+var pastViewCalls chan struct{}
+
+// AddEndpoints adds the state endpoints. View calls on past states are served only if maxPastViewCalls > 0,
+// at most maxPastViewCalls at a time
+func AddEndpoints(server echoswagger.ApiRouter, maxPastViewCalls int) {
 	dictExample := dict.Dict{
 		kv.Key("key1"): []byte("value1"),
 	}.JSONDict()
@@ -27,6 +35,26 @@ func AddEndpoints(server echoswagger.ApiRouter) {
 		AddParamPath("getInfo", "fname", "Function name").
 		AddParamBody(dictExample, "params", "Parameters", false).
 		AddResponse(http.StatusOK, "Result", dictExample, nil)
+
+	if maxPastViewCalls > 0 {
+		pastViewCalls = make(chan struct{}, maxPastViewCalls)
+		server.GET(routes.CallViewAtBlock(":contractID", ":fname", ":blockIndex"), handleCallView).
+			SetSummary("Call a view function on a contract as of the past state of the chain").
+			AddParamPath("", "contractID", "ContractID (base58-encoded)").
+			AddParamPath("getInfo", "fname", "Function name").
+			AddParamPath("", "blockIndex", "Index of the block, after which the view is called").
+			AddParamBody(dictExample, "params", "Parameters", false).
+			AddResponse(http.StatusOK, "Result", dictExample, nil).
+			AddResponse(http.StatusNotFound, "State of the block not available", httperrors.NotFound(""), nil).
+			AddResponse(http.StatusTooManyRequests, "Too many concurrent calls", httperrors.TooManyRequests(""), nil)
+	}
+
+	addStateQueryEndpoint(server)
+	addStateProofEndpoint(server)
+}
+
+type viewCaller interface {
+	CallView(contractHname coretypes.Hname, epCode coretypes.Hname, params dict.Dict) (dict.Dict, error)
 }
 
 func handleCallView(c echo.Context) error {
@@ -50,7 +78,25 @@ func handleCallView(c echo.Context) error {
 		return httperrors.NotFound(fmt.Sprintf("Chain not found: %s", contractID.ChainID()))
 	}
 
-	vctx, err := viewcontext.NewFromDB(*chain.ID(), chain.Processors())
+	var vctx viewCaller
+	if c.Param("blockIndex") == "" {
+		vctx, err = viewcontext.NewFromDB(*chain.ID(), chain.Processors())
+	} else {
+		blockIndex, perr := strconv.ParseUint(c.Param("blockIndex"), 10, 32)
+		if perr != nil {
+			return httperrors.BadRequest(fmt.Sprintf("Invalid block index: %+v", c.Param("blockIndex")))
+		}
+		select {
+		case pastViewCalls <- struct{}{}:
+			defer func() { <-pastViewCalls }()
+		default:
+			return httperrors.TooManyRequests("Too many concurrent view calls on past states")
+		}
+		vctx, err = viewcontext.NewFromDBAtBlock(*chain.ID(), uint32(blockIndex), chain.Processors())
+		if errors.Is(err, viewcontext.ErrStateNotFound) {
+			return httperrors.NotFound(fmt.Sprintf("State #%d not available: %v", blockIndex, err))
+		}
+	}
 	if err != nil {
 		return fmt.Errorf(fmt.Sprintf("Failed to create context: %v", err))
 	}
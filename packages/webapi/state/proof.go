@@ -0,0 +1,57 @@
+package state
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/http"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/kv"
+	"github.com/iotaledger/wasp/packages/state"
+	"github.com/iotaledger/wasp/packages/webapi/httperrors"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/packages/webapi/routes"
+	"github.com/labstack/echo/v4"
+	"github.com/pangpanglabs/echoswagger/v2"
+)
+
+func addStateProofEndpoint(server echoswagger.ApiRouter) {
+	server.GET(routes.StateProof(":chainID", ":key"), handleStateProof).
+		SetSummary("Get the Merkle proof of inclusion of a key/value pair into the solid state of the chain").
+		AddParamPath("", "chainID", "ChainID (base58)").
+		AddParamPath("", "key", "Key of the state variable (hex)").
+		AddResponse(http.StatusOK, "Proof of inclusion", model.StateProof{}, nil)
+}
+
+func handleStateProof(c echo.Context) error {
+	chainID, err := coretypes.NewChainIDFromBase58(c.Param("chainID"))
+	if err != nil {
+		return httperrors.BadRequest(fmt.Sprintf("Invalid chain ID: %+v", c.Param("chainID")))
+	}
+	key, err := hex.DecodeString(c.Param("key"))
+	if err != nil {
+		return httperrors.BadRequest(fmt.Sprintf("Invalid key: %+v", c.Param("key")))
+	}
+
+	// TODO serialize access to solid state
+	vs, block, exist, err := state.LoadSolidState(&chainID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return httperrors.NotFound(fmt.Sprintf("State not found for chain %s", chainID.String()))
+	}
+	value, err := vs.Variables().Get(kv.Key(key))
+	if err != nil {
+		return err
+	}
+	if value == nil {
+		return httperrors.NotFound(fmt.Sprintf("Key not found in the state: %s", c.Param("key")))
+	}
+	proof, err := vs.GetProof(kv.Key(key))
+	if err != nil {
+		return err
+	}
+	txid := block.StateTransactionID()
+	return c.JSON(http.StatusOK, model.NewStateProof(vs, model.NewValueTxID(&txid), key, value, proof))
+}
@@ -1,5 +1,3 @@
-// +build ignore
-
 // access to the solid state of the smart contract
 package state
 
@@ -20,7 +18,6 @@ import (
 
 func addStateQueryEndpoint(server echoswagger.ApiRouter) {
 	server.GET(routes.StateQuery(":chainID"), handleStateQuery).
-		SetDeprecated().
 		SetSummary("Query the chain state").
 		AddParamPath("", "chainID", "ChainID (base58)").
 		AddParamBody(statequery.Request{}, "query", "Query parameters", true).
@@ -39,29 +36,31 @@ func handleStateQuery(c echo.Context) error {
 	}
 
 	// TODO serialize access to solid state
-	state, batch, exist, err := state.LoadSolidState(&chainID)
+	vs, block, exist, err := state.LoadSolidState(&chainID)
 	if err != nil {
 		return err
 	}
 	if !exist {
-		return httperrors.NotFound(fmt.Sprintf("State not found with address %s", chainID.String()))
+		return httperrors.NotFound(fmt.Sprintf("State not found for chain %s", chainID.String()))
 	}
-	txid := batch.StateTransactionID()
 	ret := &statequery.Results{
 		KeyQueryResults: make([]*statequery.QueryResult, len(req.KeyQueries)),
-
-		StateIndex: state.BlockIndex(),
-		Timestamp:  time.Unix(0, state.Timestamp()),
-		StateHash:  state.Hash(),
-		StateTxId:  model.NewValueTxID(&txid),
-		Requests:   make([]*coretypes.RequestID, len(batch.RequestIDs())),
 	}
-	copy(ret.Requests, batch.RequestIDs())
-	vars := state.Variables()
+	if req.QueryGeneralData {
+		txid := block.StateTransactionID()
+		stateHash := vs.Hash()
+		ret.StateIndex = vs.BlockIndex()
+		ret.Timestamp = time.Unix(0, vs.Timestamp())
+		ret.StateHash = &stateHash
+		ret.StateTxId = model.NewValueTxID(&txid)
+		ret.Requests = make([]*coretypes.RequestID, len(block.RequestIDs()))
+		copy(ret.Requests, block.RequestIDs())
+	}
+	vars := vs.Variables()
 	for i, q := range req.KeyQueries {
 		result, err := q.Execute(vars)
 		if err != nil {
-			return err
+			return httperrors.BadRequest(fmt.Sprintf("Failed executing query for key %x: %v", q.Key, err))
 		}
 		ret.KeyQueryResults[i] = result
 	}
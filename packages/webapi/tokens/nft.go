@@ -0,0 +1,78 @@
+package tokens
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/util"
+	"github.com/iotaledger/wasp/packages/vm/core/nft"
+	"github.com/iotaledger/wasp/packages/webapi/httperrors"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/packages/webapi/routes"
+	"github.com/labstack/echo/v4"
+	"github.com/pangpanglabs/echoswagger/v2"
+)
+
+func addNFTEndpoints(server echoswagger.ApiRouter) {
+	server.GET(routes.NFT(":chainID", ":id"), handleNFT).
+		SetSummary("Get the NFT").
+		AddParamPath("", "chainID", "ChainID (base58)").
+		AddParamPath("", "id", "ID of the NFT (base58)").
+		AddResponse(http.StatusOK, "The NFT", model.NFT{}, nil)
+
+	server.GET(routes.NFTsByOwner(":chainID", ":agentID"), handleNFTsByOwner).
+		SetSummary("Get NFTs owned by the agent").
+		AddParamPath("", "chainID", "ChainID (base58)").
+		AddParamPath("", "agentID", "Agent ID of the owner (base58)").
+		AddResponse(http.StatusOK, "NFTs, sorted by ID", []*model.NFT{}, nil)
+}
+
+func handleNFT(c echo.Context) error {
+	ch, err := getChain(c)
+	if err != nil {
+		return err
+	}
+	id, err := util.ColorFromString(c.Param("id"))
+	if err != nil {
+		return httperrors.BadRequest(fmt.Sprintf("Invalid NFT ID %+v: %s", c.Param("id"), err.Error()))
+	}
+	params := dict.New()
+	params.Set(nft.ParamID, codec.EncodeColor(id))
+	ret, err := callView(ch, nft.Interface.Hname(), nft.FuncGetNFT, params)
+	if err != nil {
+		return httperrors.NotFound(fmt.Sprintf("NFT not found: %s", id))
+	}
+	rec, err := nft.DecodeNFTRecord(ret.MustGet(nft.ParamNFT))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, model.NewNFT(rec))
+}
+
+func handleNFTsByOwner(c echo.Context) error {
+	ch, err := getChain(c)
+	if err != nil {
+		return err
+	}
+	agentID, err := agentIDFromBase58(c.Param("agentID"))
+	if err != nil {
+		return httperrors.BadRequest(fmt.Sprintf("Invalid agent ID %+v: %s", c.Param("agentID"), err.Error()))
+	}
+	params := dict.New()
+	params.Set(nft.ParamAgentID, codec.EncodeAgentID(agentID))
+	ret, err := callView(ch, nft.Interface.Hname(), nft.FuncGetNFTs, params)
+	if err != nil {
+		return err
+	}
+	recs, err := nft.DecodeNFTRecords(ret)
+	if err != nil {
+		return err
+	}
+	res := make([]*model.NFT, len(recs))
+	for i, rec := range recs {
+		res[i] = model.NewNFT(rec)
+	}
+	return c.JSON(http.StatusOK, res)
+}
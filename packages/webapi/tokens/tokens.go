@@ -0,0 +1,113 @@
+package tokens
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/iotaledger/wasp/packages/chain"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/kv/dict"
+	"github.com/iotaledger/wasp/packages/vm/core/tokens"
+	"github.com/iotaledger/wasp/packages/vm/viewcontext"
+	"github.com/iotaledger/wasp/packages/webapi/httperrors"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/packages/webapi/routes"
+	"github.com/iotaledger/wasp/plugins/chains"
+	"github.com/labstack/echo/v4"
+	"github.com/mr-tron/base58"
+	"github.com/pangpanglabs/echoswagger/v2"
+)
+
+func AddEndpoints(server echoswagger.ApiRouter) {
+	server.GET(routes.NativeTokens(":chainID"), handleNativeTokens).
+		SetSummary("Get all chain-native tokens of the chain").
+		AddParamPath("", "chainID", "ChainID (base58)").
+		AddResponse(http.StatusOK, "Chain-native tokens, sorted by symbol", []*model.NativeToken{}, nil)
+
+	server.GET(routes.TokenHoldings(":chainID", ":agentID"), handleTokenHoldings).
+		SetSummary("Get balances of chain-native tokens in the on-chain account").
+		AddParamPath("", "chainID", "ChainID (base58)").
+		AddParamPath("", "agentID", "Agent ID of the account (base58)").
+		AddResponse(http.StatusOK, "Non-zero balances of chain-native tokens", []*model.TokenHolding{}, nil)
+
+	addNFTEndpoints(server)
+}
+
+func handleNativeTokens(c echo.Context) error {
+	ch, err := getChain(c)
+	if err != nil {
+		return err
+	}
+	ret, err := callView(ch, tokens.Interface.Hname(), tokens.FuncGetTokens, nil)
+	if err != nil {
+		return err
+	}
+	recs, err := tokens.DecodeTokenRecords(ret)
+	if err != nil {
+		return err
+	}
+	res := make([]*model.NativeToken, len(recs))
+	for i, rec := range recs {
+		res[i] = model.NewNativeToken(rec)
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func handleTokenHoldings(c echo.Context) error {
+	ch, err := getChain(c)
+	if err != nil {
+		return err
+	}
+	agentID, err := agentIDFromBase58(c.Param("agentID"))
+	if err != nil {
+		return httperrors.BadRequest(fmt.Sprintf("Invalid agent ID %+v: %s", c.Param("agentID"), err.Error()))
+	}
+	params := dict.New()
+	params.Set(tokens.ParamAgentID, codec.EncodeAgentID(agentID))
+	ret, err := callView(ch, tokens.Interface.Hname(), tokens.FuncGetHoldings, params)
+	if err != nil {
+		return err
+	}
+	holdings, err := tokens.DecodeHoldings(ret)
+	if err != nil {
+		return err
+	}
+	res := make([]*model.TokenHolding, len(holdings))
+	for i, h := range holdings {
+		res[i] = model.NewTokenHolding(h)
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func agentIDFromBase58(s string) (coretypes.AgentID, error) {
+	data, err := base58.Decode(s)
+	if err != nil {
+		return coretypes.AgentID{}, err
+	}
+	return coretypes.NewAgentIDFromBytes(data)
+}
+
+func getChain(c echo.Context) (chain.Chain, error) {
+	chainID, err := coretypes.NewChainIDFromBase58(c.Param("chainID"))
+	if err != nil {
+		return nil, httperrors.BadRequest(fmt.Sprintf("Invalid chain ID %+v: %s", c.Param("chainID"), err.Error()))
+	}
+	ch := chains.GetChain(chainID)
+	if ch == nil {
+		return nil, httperrors.NotFound(fmt.Sprintf("Chain not found: %+v", chainID.String()))
+	}
+	return ch, nil
+}
+
+func callView(ch chain.Chain, contract coretypes.Hname, fname string, params dict.Dict) (dict.Dict, error) {
+	vctx, err := viewcontext.NewFromDB(*ch.ID(), ch.Processors())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create context: %v", err)
+	}
+	ret, err := vctx.CallView(contract, coretypes.Hn(fname), params)
+	if err != nil {
+		return nil, httperrors.BadRequest(fmt.Sprintf("View call failed: %v", err))
+	}
+	return ret, nil
+}
@@ -79,6 +79,7 @@ func run(_ *node.Plugin) {
 // - creates chain object
 // - insert it into the runtime registry
 // - subscribes for related transactions in he IOTA node
+// After the committee has been rotated, the chain ID address is followed too, because clients may still send requests to it
 func ActivateChain(chr *registry_pkg.ChainRecord) error {
 	chainsMutex.Lock()
 	defer chainsMutex.Unlock()
@@ -87,15 +88,25 @@ func ActivateChain(chr *registry_pkg.ChainRecord) error {
 		return fmt.Errorf("cannot activate chain for deactivated chain record")
 	}
 
-	_, ok := chains[chr.ChainID]
-	if ok {
-		log.Debugf("chain is already active: %s", chr.ChainID.String())
-		return nil
+	if c, ok := chains[chr.ChainID]; ok {
+		if !c.IsDismissed() {
+			log.Debugf("chain is already active: %s", chr.ChainID.String())
+			return nil
+		}
+		// the chain has been dismissed, e.g. handed over to the new committee
+		delete(chains, chr.ChainID)
+		unsubscribe(c)
 	}
 	// create new chain object
 	defaultRegistry := registry.DefaultRegistry()
+	chainID := chr.ChainID
 	c := chain.New(chr, log, peering.DefaultNetworkProvider(), defaultRegistry, defaultRegistry, func() {
-		nodeconn.Subscribe((address.Address)(chr.ChainID), chr.Color)
+		nodeconn.Subscribe(chr.Address(), chr.Color)
+		if chr.Address() != address.Address(chr.ChainID) {
+			nodeconn.Subscribe(address.Address(chr.ChainID), chr.Color)
+		}
+	}, func(newAddress address.Address) {
+		handOver(chainID, newAddress)
 	})
 	if c != nil {
 		chains[chr.ChainID] = c
@@ -106,6 +117,46 @@ func ActivateChain(chr *registry_pkg.ChainRecord) error {
 	return nil
 }
 
+// handOver is called after the committee of the chain has handed over the chain token to the new committee address.
+// The chain is activated again with the chain record of the new committee if it was put to the node.
+// Access nodes keep following the chain at the new address. Otherwise the node is not known to be
+// in the new committee and the chain record is deactivated, so the old committee is not activated on restart
+func handOver(chainID coretypes.ChainID, newAddress address.Address) {
+	chr, err := registry_pkg.UpdateChainRecord(&chainID, func(chr *registry_pkg.ChainRecord) bool {
+		if chr.Address() == newAddress {
+			return false
+		}
+		chr.CommitteeAddress = newAddress
+		if !isAccessNode(chr) {
+			chr.Active = false
+		}
+		return true
+	})
+	if err != nil {
+		log.Errorf("handover of chain %s to %s: %v", chainID.String(), newAddress.String(), err)
+		return
+	}
+	if !chr.Active {
+		log.Infof("chain %s has been handed over to %s. The node is not in the new committee: "+
+			"put the chain record of the new committee and activate it to join",
+			chainID.String(), newAddress.String())
+		return
+	}
+	if err := ActivateChain(chr); err != nil {
+		log.Errorf("handover of chain %s to %s: %v", chainID.String(), newAddress.String(), err)
+	}
+}
+
+func isAccessNode(chr *registry_pkg.ChainRecord) bool {
+	self := peering.DefaultNetworkProvider().Self().NetID()
+	for _, netID := range chr.AccessNodes {
+		if netID == self {
+			return true
+		}
+	}
+	return false
+}
+
 // DeactivateChain deactivates chain in the node
 func DeactivateChain(chr *registry_pkg.ChainRecord) error {
 	chainsMutex.Lock()
@@ -129,8 +180,34 @@ func GetChain(chainID coretypes.ChainID) chain.Chain {
 	ret, ok := chains[chainID]
 	if ok && ret.IsDismissed() {
 		delete(chains, chainID)
-		nodeconn.Unsubscribe((address.Address)(chainID))
+		unsubscribe(ret)
 		return nil
 	}
 	return ret
 }
+
+// unsubscribe stops following the addresses of the chain in the IOTA node
+func unsubscribe(c chain.Chain) {
+	nodeconn.Unsubscribe(c.Address())
+	nodeconn.Unsubscribe(address.Address(*c.ID()))
+}
+
+// GetChainByAddress returns active chain object controlled by the committee address or nil if it doesn't exist.
+// The address of the chain differs from the chain ID after the committee has been rotated.
+// The chain is also returned for its chain ID address, which is still served by the rotated committee
+func GetChainByAddress(addr address.Address) chain.Chain {
+	chainsMutex.RLock()
+	var chainID *coretypes.ChainID
+	for _, c := range chains {
+		if c.Address() == addr || address.Address(*c.ID()) == addr {
+			chainID = c.ID()
+			break
+		}
+	}
+	chainsMutex.RUnlock()
+
+	if chainID == nil {
+		return nil
+	}
+	return GetChain(*chainID)
+}
@@ -0,0 +1,31 @@
+package database
+
+import (
+	"errors"
+
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/wasp/packages/dbprovider"
+)
+
+// MigrateStateToV1 migrates the state of the chains from version 0 to version 1. It is set by the state package,
+// which knows the formats of the state and depends on this plugin
+var MigrateStateToV1 func(store kvstore.KVStore, batch kvstore.BatchedMutations) error
+
+// migrations transform the data of the previous version of the database schema to the next one,
+// for example objects of types dbprovider.ObjectTypeStateVariable or dbprovider.ObjectTypeStateUpdateBatch
+// with dbprovider.TransformObjects.
+// Version 1 stores the Merkle tree of the state variables and its root in the solid state and the results
+// of requests in the blocks. Chain records of version 0 are read without the migration: the committee address
+// and the access nodes are optional
+var migrations = []dbprovider.Migration{
+	{
+		Version:     1,
+		Description: "Merkle tree of the state variables and results of requests in blocks",
+		Migrate: func(store kvstore.KVStore, batch kvstore.BatchedMutations) error {
+			if MigrateStateToV1 == nil {
+				return errors.New("the migration of the state is not available")
+			}
+			return MigrateStateToV1(store, batch)
+		},
+	},
+}
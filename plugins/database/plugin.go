@@ -1,4 +1,5 @@
-// Package database is a plugin that manages the badger database (e.g. garbage collection).
+// Package database is a plugin that manages the database (e.g. garbage collection and migrations).
+// The engine of the persistent database is badger, bolt or pebble.
 package database
 
 import (
@@ -33,7 +34,7 @@ func configure(_ *node.Plugin) {
 
 	err := checkDatabaseVersion()
 	if errors.Is(err, ErrDBVersionIncompatible) {
-		log.Panicf("The database scheme was updated. Please delete the database folder.\n%s", err)
+		log.Panicf("The database scheme was updated and the database can't be migrated. Please delete the database folder.\n%s", err)
 	}
 	if err != nil {
 		log.Panicf("Failed to check database version: %s", err)
@@ -69,7 +70,9 @@ func createInstance() {
 		dbProvider = dbprovider.NewInMemoryDBProvider(log)
 	} else {
 		dbDir := parameters.GetString(parameters.DatabaseDir)
-		dbProvider = dbprovider.NewPersistentDBProvider(dbDir, log)
+		engine := parameters.GetString(parameters.DatabaseEngine)
+		log.Infof("%s database in %s", engine, dbDir)
+		dbProvider = dbprovider.NewPersistentDBProvider(engine, dbDir, log)
 	}
 }
 
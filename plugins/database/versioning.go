@@ -4,18 +4,19 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
-	"github.com/iotaledger/wasp/packages/dbprovider"
 
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/hive.go/logger"
 	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/dbprovider"
 	"github.com/iotaledger/wasp/packages/hashing"
-
-	"github.com/iotaledger/hive.go/kvstore"
 )
 
 const (
 	// DBVersion defines the version of the database schema this version of Wasp supports.
-	// Every time there's a breaking change regarding the stored data, this version flag should be adjusted.
-	DBVersion = 0
+	// Every time there's a breaking change regarding the stored data, this version flag should be adjusted
+	// and the migration from the previous version should be added to 'migrations'.
+	DBVersion = 1
 )
 
 var (
@@ -24,21 +25,33 @@ var (
 )
 
 // checks whether the database is compatible with the current schema version.
+// The database of the older version is migrated to the current version.
 // also automatically sets the version if the database if new.
+func checkDatabaseVersion() error {
+	return migrateDatabase(GetInstance().GetStore(), DBVersion, migrations, log)
+}
+
 // version is stored in niladdr partition.
 // it consists of one byte of version and the hash (checksum) of that one byte
-func checkDatabaseVersion() error {
-	db := GetPartition(&coretypes.NilChainID)
-	ver, err := db.Get(dbprovider.MakeKey(dbprovider.ObjectTypeDBSchemaVersion))
+func dbkeyVersion() []byte {
+	return append(coretypes.NilChainID[:], dbprovider.MakeKey(dbprovider.ObjectTypeDBSchemaVersion)...)
+}
 
+func versionData(version byte) []byte {
 	var versiondata [1 + hashing.HashSize]byte
-	versiondata[0] = DBVersion
-	vh := hashing.HashStrings(fmt.Sprintf("dbversion = %d", DBVersion))
+	versiondata[0] = version
+	vh := hashing.HashStrings(fmt.Sprintf("dbversion = %d", version))
 	copy(versiondata[1:], vh[:])
+	return versiondata[:]
+}
 
+// migrateDatabase applies migrations one by one, from the version of the database to the target version.
+// Each migration is committed atomically together with the version it migrates to
+func migrateDatabase(store kvstore.KVStore, target byte, migrations []dbprovider.Migration, log *logger.Logger) error {
+	ver, err := store.Get(dbkeyVersion())
 	if err == kvstore.ErrKeyNotFound {
 		// set the version in an empty DB
-		return db.Set(dbprovider.MakeKey(dbprovider.ObjectTypeDBSchemaVersion), versiondata[:])
+		return store.Set(dbkeyVersion(), versionData(target))
 	}
 	if err != nil {
 		return err
@@ -46,8 +59,39 @@ func checkDatabaseVersion() error {
 	if len(ver) == 0 {
 		return fmt.Errorf("%w: no database version was persisted", ErrDBVersionIncompatible)
 	}
-	if !bytes.Equal(ver, versiondata[:]) {
-		return fmt.Errorf("%w: supported version: %d, version of database: %d", ErrDBVersionIncompatible, DBVersion, ver[0])
+	if !bytes.Equal(ver, versionData(ver[0])) {
+		return fmt.Errorf("%w: corrupted database version", ErrDBVersionIncompatible)
+	}
+	if ver[0] > target {
+		return fmt.Errorf("%w: supported version: %d, version of database: %d", ErrDBVersionIncompatible, target, ver[0])
+	}
+	for current := ver[0]; current < target; current++ {
+		m := findMigration(migrations, current+1)
+		if m == nil {
+			return fmt.Errorf("%w: no migration from version %d to %d", ErrDBVersionIncompatible, current, current+1)
+		}
+		log.Infof("migrating database from version %d to %d: %s", current, m.Version, m.Description)
+		batch := store.Batched()
+		if err := m.Migrate(store, batch); err != nil {
+			batch.Cancel()
+			return fmt.Errorf("migration to version %d failed: %w", m.Version, err)
+		}
+		if err := batch.Set(dbkeyVersion(), versionData(m.Version)); err != nil {
+			batch.Cancel()
+			return err
+		}
+		if err := batch.Commit(); err != nil {
+			return fmt.Errorf("migration to version %d failed: %w", m.Version, err)
+		}
+	}
+	return nil
+}
+
+func findMigration(migrations []dbprovider.Migration, version byte) *dbprovider.Migration {
+	for i := range migrations {
+		if migrations[i].Version == version {
+			return &migrations[i]
+		}
 	}
 	return nil
 }
@@ -0,0 +1,117 @@
+package database
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/iotaledger/hive.go/kvstore"
+	"github.com/iotaledger/hive.go/kvstore/mapdb"
+	"github.com/iotaledger/hive.go/logger"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/dbprovider"
+	"github.com/stretchr/testify/require"
+)
+
+func testLogger(t *testing.T) *logger.Logger {
+	log, err := logger.NewRootLogger(logger.Config{Level: "debug", Encoding: "console", OutputPaths: []string{"stdout"}})
+	require.NoError(t, err)
+	return log.Named(t.Name())
+}
+
+func dbkey(chainID coretypes.ChainID, objType byte, key string) []byte {
+	return append(chainID[:], dbprovider.MakeKey(objType, []byte(key))...)
+}
+
+func TestMigrateDatabase(t *testing.T) {
+	log := testLogger(t)
+	store := mapdb.NewMapDB()
+	chainID := coretypes.ChainID{1, 3, 3, 7}
+
+	// new database
+	require.NoError(t, migrateDatabase(store, 1, nil, log))
+	require.NoError(t, migrateDatabase(store, 1, nil, log))
+
+	require.NoError(t, store.Set(dbkey(chainID, dbprovider.ObjectTypeStateVariable, "a"), []byte{1}))
+	require.NoError(t, store.Set(dbkey(chainID, dbprovider.ObjectTypeStateVariable, "b"), []byte{2}))
+	require.NoError(t, store.Set(dbkey(chainID, dbprovider.ObjectTypeStateUpdateBatch, "0"), []byte{3}))
+
+	migrations := []dbprovider.Migration{
+		{
+			Version:     2,
+			Description: "rename state variables",
+			Migrate: dbprovider.TransformObjects(dbprovider.ObjectTypeStateVariable, func(_ coretypes.ChainID, key, value []byte) ([]byte, []byte, error) {
+				if bytes.Equal(key, []byte("b")) {
+					return key, nil, nil
+				}
+				return append([]byte("new."), key...), value, nil
+			}),
+		},
+		{
+			Version:     3,
+			Description: "transform blocks",
+			Migrate: dbprovider.TransformObjects(dbprovider.ObjectTypeStateUpdateBatch, func(_ coretypes.ChainID, key, value []byte) ([]byte, []byte, error) {
+				return key, append(value, 4), nil
+			}),
+		},
+	}
+	// no migration to version 4
+	err := migrateDatabase(store, 4, migrations, log)
+	require.True(t, errors.Is(err, ErrDBVersionIncompatible))
+
+	// the migrations to versions 2 and 3 were committed
+	has, err := store.Has(dbkey(chainID, dbprovider.ObjectTypeStateVariable, "a"))
+	require.NoError(t, err)
+	require.False(t, has)
+	has, err = store.Has(dbkey(chainID, dbprovider.ObjectTypeStateVariable, "b"))
+	require.NoError(t, err)
+	require.False(t, has)
+	v, err := store.Get(dbkey(chainID, dbprovider.ObjectTypeStateVariable, "new.a"))
+	require.NoError(t, err)
+	require.EqualValues(t, []byte{1}, v)
+	v, err = store.Get(dbkey(chainID, dbprovider.ObjectTypeStateUpdateBatch, "0"))
+	require.NoError(t, err)
+	require.EqualValues(t, []byte{3, 4}, v)
+	v, err = store.Get(dbkeyVersion())
+	require.NoError(t, err)
+	require.EqualValues(t, 3, v[0])
+
+	// the migrations are not repeated
+	require.NoError(t, migrateDatabase(store, 3, migrations, log))
+	v, err = store.Get(dbkey(chainID, dbprovider.ObjectTypeStateUpdateBatch, "0"))
+	require.NoError(t, err)
+	require.EqualValues(t, []byte{3, 4}, v)
+
+	// downgrade is not possible
+	err = migrateDatabase(store, 2, migrations, log)
+	require.True(t, errors.Is(err, ErrDBVersionIncompatible))
+}
+
+func TestMigrateDatabaseFailed(t *testing.T) {
+	log := testLogger(t)
+	store := mapdb.NewMapDB()
+	chainID := coretypes.ChainID{1, 3, 3, 7}
+
+	require.NoError(t, migrateDatabase(store, 1, nil, log))
+	require.NoError(t, store.Set(dbkey(chainID, dbprovider.ObjectTypeStateVariable, "a"), []byte{1}))
+
+	migrations := []dbprovider.Migration{{
+		Version:     2,
+		Description: "failing migration",
+		Migrate: func(_ kvstore.KVStore, batch kvstore.BatchedMutations) error {
+			if err := batch.Delete(dbkey(chainID, dbprovider.ObjectTypeStateVariable, "a")); err != nil {
+				return err
+			}
+			return errors.New("failed")
+		},
+	}}
+	require.Error(t, migrateDatabase(store, 2, migrations, log))
+
+	// nothing was changed
+	v, err := store.Get(dbkey(chainID, dbprovider.ObjectTypeStateVariable, "a"))
+	require.NoError(t, err)
+	require.EqualValues(t, []byte{1}, v)
+	v, err = store.Get(dbkeyVersion())
+	require.NoError(t, err)
+	require.EqualValues(t, 1, v[0])
+}
@@ -8,7 +8,6 @@ import (
 	"github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/balance"
 	valuetransaction "github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
 	"github.com/iotaledger/wasp/packages/chain"
-	"github.com/iotaledger/wasp/packages/coretypes"
 	"github.com/iotaledger/wasp/packages/sctransaction"
 	"github.com/iotaledger/wasp/plugins/chains"
 )
@@ -23,6 +22,12 @@ func dispatchState(tx *sctransaction.Transaction) {
 	if cmt == nil {
 		return
 	}
+	if *txProp.MustStateColor() != *cmt.Color() {
+		// the chain ID claimed by the state section is not backed by the chain token
+		log.Warnf("dispatchState: wrong state color %s for chain %s in tx %s",
+			txProp.MustStateColor().String(), cmt.ID().String(), tx.ID().String())
+		return
+	}
 	log.Debugw("dispatchState",
 		"txid", tx.ID().String(),
 		"chainid", cmt.ID().String(),
@@ -35,15 +40,15 @@ func dispatchState(tx *sctransaction.Transaction) {
 
 func dispatchBalances(addr address.Address, bals map[valuetransaction.ID][]*balance.Balance) {
 	// pass to the committee by address
-	if cmt := chains.GetChain((coretypes.ChainID)(addr)); cmt != nil {
-		cmt.ReceiveMessage(chain.BalancesMsg{Balances: bals})
+	if cmt := chains.GetChainByAddress(addr); cmt != nil {
+		cmt.ReceiveMessage(chain.BalancesMsg{Address: addr, Balances: bals})
 	}
 }
 
 func dispatchAddressUpdate(addr address.Address, balances map[valuetransaction.ID][]*balance.Balance, tx *sctransaction.Transaction) {
 	log.Debugw("dispatchAddressUpdate", "addr", addr.String())
 
-	cmt := chains.GetChain((coretypes.ChainID)(addr))
+	cmt := chains.GetChainByAddress(addr)
 	if cmt == nil {
 		log.Debugw("committee not found", "addr", addr.String())
 		// wrong addressee
@@ -53,11 +58,12 @@ func dispatchAddressUpdate(addr address.Address, balances map[valuetransaction.I
 
 	// update balances before state and requests
 	cmt.ReceiveMessage(chain.BalancesMsg{
+		Address:  addr,
 		Balances: balances,
 	})
 
 	txProp := tx.MustProperties() // was parsed before
-	if txProp.IsState() && *txProp.MustChainID() == (coretypes.ChainID)(addr) {
+	if txProp.IsState() && *txProp.MustChainID() == *cmt.ID() && *txProp.MustStateColor() == *cmt.Color() {
 		// it is a state update to addr. Send it
 		cmt.ReceiveMessage(&chain.StateTransactionMsg{
 			Transaction: tx,
@@ -65,7 +71,8 @@ func dispatchAddressUpdate(addr address.Address, balances map[valuetransaction.I
 		log.Debugf("state tx msg posted: %s", tx.ID().String())
 	}
 
-	// send all requests to addr
+	// send all requests to addr. After the committee has been rotated, addr may also be the chain ID address:
+	// the committee keeps serving requests sent to it and sweeps them to the committee address.
 	// if there are any free tokens, they will be attached to the first message.
 	// otherwise they all will be nil
 	freeTokens := txProp.FreeTokensForAddress(addr)
@@ -73,7 +80,7 @@ func dispatchAddressUpdate(addr address.Address, balances map[valuetransaction.I
 		freeTokens = nil
 	}
 	for i, reqBlk := range tx.Requests() {
-		if reqBlk.Target().ChainID() == (coretypes.ChainID)(addr) {
+		if reqBlk.Target().ChainID() == *cmt.ID() {
 			cmt.ReceiveMessage(&chain.RequestMsg{
 				Transaction: tx,
 				Index:       (uint16)(i),
@@ -86,7 +93,7 @@ func dispatchAddressUpdate(addr address.Address, balances map[valuetransaction.I
 
 func dispatchTxInclusionLevel(level byte, txid *valuetransaction.ID, addrs []address.Address) {
 	for _, addr := range addrs {
-		cmt := chains.GetChain((coretypes.ChainID)(addr))
+		cmt := chains.GetChainByAddress(addr)
 		if cmt == nil {
 			continue
 		}
@@ -0,0 +1,83 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"github.com/iotaledger/wasp/plugins/peering"
+	"github.com/iotaledger/wasp/plugins/registry"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// peeringCollector reports liveness of the network peers at the time of scraping
+type peeringCollector struct {
+	peerAlive *prometheus.Desc
+}
+
+func newPeeringCollector() prometheus.Collector {
+	return &peeringCollector{
+		peerAlive: prometheus.NewDesc(
+			"wasp_peering_peer_alive",
+			"Whether the network peer is alive (1) or not (0).",
+			[]string{"peer", "inbound"}, nil,
+		),
+	}
+}
+
+func (c *peeringCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.peerAlive
+}
+
+func (c *peeringCollector) Collect(ch chan<- prometheus.Metric) {
+	netProvider := peering.DefaultNetworkProvider()
+	if netProvider == nil {
+		return
+	}
+	for _, peer := range netProvider.PeerStatus() {
+		alive := 0.0
+		if peer.IsAlive() {
+			alive = 1
+		}
+		inbound := "false"
+		if peer.IsInbound() {
+			inbound = "true"
+		}
+		ch <- prometheus.MustNewConstMetric(c.peerAlive, prometheus.GaugeValue, alive, peer.NetID(), inbound)
+	}
+}
+
+// blobCacheCollector reports the size of the blob cache in the registry at the time of scraping
+type blobCacheCollector struct {
+	numBlobs  *prometheus.Desc
+	sizeBytes *prometheus.Desc
+}
+
+func newBlobCacheCollector() prometheus.Collector {
+	return &blobCacheCollector{
+		numBlobs: prometheus.NewDesc(
+			"wasp_registry_blob_cache_blobs",
+			"Number of blobs in the blob cache.",
+			nil, nil,
+		),
+		sizeBytes: prometheus.NewDesc(
+			"wasp_registry_blob_cache_size_bytes",
+			"Total size of blobs in the blob cache.",
+			nil, nil,
+		),
+	}
+}
+
+func (c *blobCacheCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.numBlobs
+	ch <- c.sizeBytes
+}
+
+func (c *blobCacheCollector) Collect(ch chan<- prometheus.Metric) {
+	num, size, err := registry.DefaultRegistry().BlobCacheSize()
+	if err != nil {
+		log.Warnf("failed to collect blob cache size: %v", err)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.numBlobs, prometheus.GaugeValue, float64(num))
+	ch <- prometheus.MustNewConstMetric(c.sizeBytes, prometheus.GaugeValue, float64(size))
+}
@@ -0,0 +1,83 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/iotaledger/hive.go/daemon"
+	"github.com/iotaledger/hive.go/logger"
+	"github.com/iotaledger/hive.go/node"
+	"github.com/iotaledger/wasp/packages/metrics"
+	"github.com/iotaledger/wasp/packages/parameters"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// PluginName is the name of the metrics plugin.
+const PluginName = "Metrics"
+
+var (
+	log          *logger.Logger
+	promRegistry = prometheus.NewRegistry()
+)
+
+// Init creates the plugin, which exports metrics of the node in the Prometheus format.
+// It is disabled by default
+func Init() *node.Plugin {
+	return node.NewPlugin(PluginName, node.Disabled, configure, run)
+}
+
+func configure(_ *node.Plugin) {
+	log = logger.NewLogger(PluginName)
+
+	promRegistry.MustRegister(prometheus.NewGoCollector())
+	promRegistry.MustRegister(prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}))
+	promRegistry.MustRegister(metrics.Collectors()...)
+	promRegistry.MustRegister(newPeeringCollector())
+	promRegistry.MustRegister(newBlobCacheCollector())
+}
+
+func run(_ *node.Plugin) {
+	log.Infof("Starting %s ...", PluginName)
+	if err := daemon.BackgroundWorker(PluginName, worker); err != nil {
+		log.Errorf("Error starting as daemon: %s", err)
+	}
+}
+
+func worker(shutdownSignal <-chan struct{}) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(promRegistry, promhttp.HandlerOpts{}))
+	server := &http.Server{
+		Addr:    parameters.GetString(parameters.MetricsBindAddress),
+		Handler: mux,
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		log.Infof("%s started, bind address=%s", PluginName, server.Addr)
+		if err := server.ListenAndServe(); err != nil {
+			if !errors.Is(err, http.ErrServerClosed) {
+				log.Errorf("Error serving: %s", err)
+			}
+		}
+	}()
+
+	select {
+	case <-shutdownSignal:
+	case <-stopped:
+	}
+
+	log.Infof("Stopping %s ...", PluginName)
+	defer log.Infof("Stopping %s ... done", PluginName)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		log.Errorf("Error stopping: %s", err)
+	}
+}
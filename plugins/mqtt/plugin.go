@@ -0,0 +1,79 @@
+// Copyright 2020 IOTA Stiftung
+// SPDX-License-Identifier: Apache-2.0
+
+package mqtt
+
+import (
+	"net"
+
+	"github.com/iotaledger/hive.go/daemon"
+	"github.com/iotaledger/hive.go/events"
+	"github.com/iotaledger/hive.go/logger"
+	"github.com/iotaledger/hive.go/node"
+	"github.com/iotaledger/wasp/packages/mqttbroker"
+	"github.com/iotaledger/wasp/packages/parameters"
+	"github.com/iotaledger/wasp/packages/publisher"
+)
+
+// PluginName is the name of the MQTT plugin.
+const PluginName = "MQTT"
+
+var (
+	log    *logger.Logger
+	broker = mqttbroker.New()
+)
+
+// Init creates the plugin, which runs an embedded MQTT broker and publishes
+// the messages of the Wasp publisher to it. It is disabled by default
+func Init() *node.Plugin {
+	return node.NewPlugin(PluginName, node.Disabled, configure, run)
+}
+
+func configure(_ *node.Plugin) {
+	log = logger.NewLogger(PluginName)
+}
+
+func run(_ *node.Plugin) {
+	log.Infof("Starting %s ...", PluginName)
+	if err := daemon.BackgroundWorker(PluginName, worker); err != nil {
+		log.Errorf("Error starting as daemon: %s", err)
+	}
+}
+
+func worker(shutdownSignal <-chan struct{}) {
+	bindAddress := parameters.GetString(parameters.MQTTBindAddress)
+	listener, err := net.Listen("tcp", bindAddress)
+	if err != nil {
+		log.Errorf("Error listening: %s", err)
+		return
+	}
+
+	onPublish := events.NewClosure(func(msgType string, parts []string) {
+		for _, msg := range publisher.MQTTMessages(msgType, parts) {
+			broker.Publish(msg.Topic, msg.Payload, msg.Retain)
+		}
+	})
+	publisher.Event.Attach(onPublish)
+	defer publisher.Event.Detach(onPublish)
+
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		log.Infof("%s started, bind address=%s", PluginName, bindAddress)
+		if err := broker.Serve(listener); err != nil {
+			log.Errorf("Error serving: %s", err)
+		}
+	}()
+
+	select {
+	case <-shutdownSignal:
+	case <-stopped:
+	}
+
+	log.Infof("Stopping %s ...", PluginName)
+	defer log.Infof("Stopping %s ... done", PluginName)
+	broker.Close()
+	if err := listener.Close(); err != nil {
+		log.Errorf("Error stopping: %s", err)
+	}
+}
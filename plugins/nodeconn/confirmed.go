@@ -0,0 +1,42 @@
+package nodeconn
+
+import (
+	"fmt"
+	"time"
+
+	valuetransaction "github.com/iotaledger/goshimmer/dapps/valuetransfers/packages/transaction"
+	"github.com/iotaledger/goshimmer/dapps/waspconn/packages/waspconn"
+	"github.com/iotaledger/hive.go/events"
+)
+
+// GetConfirmedTransaction requests the confirmed transaction from the node and waits until it arrives.
+// Returns an error if the node is not connected or if the transaction doesn't arrive before the timeout,
+// for example because it is not confirmed
+func GetConfirmedTransaction(txid *valuetransaction.ID, timeout time.Duration) (*valuetransaction.Transaction, error) {
+	if !IsConnected() {
+		return nil, fmt.Errorf("not connected to the node")
+	}
+	received := make(chan *valuetransaction.Transaction, 1)
+	closure := events.NewClosure(func(msg interface{}) {
+		msgt, ok := msg.(*waspconn.WaspFromNodeConfirmedTransactionMsg)
+		if !ok || msgt.Tx.ID() != *txid {
+			return
+		}
+		select {
+		case received <- msgt.Tx:
+		default:
+		}
+	})
+	EventMessageReceived.Attach(closure)
+	defer EventMessageReceived.Detach(closure)
+
+	if err := RequestConfirmedTransactionFromNode(txid); err != nil {
+		return nil, err
+	}
+	select {
+	case tx := <-received:
+		return tx, nil
+	case <-time.After(timeout):
+		return nil, fmt.Errorf("confirmed transaction %s was not received in %v", txid.String(), timeout)
+	}
+}
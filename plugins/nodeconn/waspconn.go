@@ -14,6 +14,7 @@ import (
 	"github.com/iotaledger/hive.go/backoff"
 	"github.com/iotaledger/hive.go/events"
 	"github.com/iotaledger/hive.go/netutil/buffconn"
+	"github.com/iotaledger/wasp/packages/metrics"
 	"github.com/iotaledger/wasp/packages/parameters"
 	"github.com/iotaledger/wasp/plugins/peering"
 )
@@ -61,6 +62,7 @@ func nodeConnect() {
 	bconn.Events.ReceiveMessage.Attach(dataReceivedClosure)
 	bconn.Events.Close.Attach(events.NewClosure(func() {
 		log.Errorf("lost connection with %s", addr)
+		metrics.IncNodeconnReconnects()
 		go func() {
 			bconnMutex.Lock()
 			bconnSave := bconn
@@ -11,6 +11,7 @@ import (
 	"github.com/iotaledger/hive.go/daemon"
 	"github.com/iotaledger/hive.go/logger"
 	"github.com/iotaledger/hive.go/node"
+	"github.com/iotaledger/wasp/packages/metrics"
 	"github.com/iotaledger/wasp/packages/parameters"
 	"github.com/iotaledger/wasp/packages/util/auth"
 	"github.com/iotaledger/wasp/packages/webapi"
@@ -57,9 +58,10 @@ func configure(*node.Plugin) {
 		Format: `${time_rfc3339_nano} ${remote_ip} ${method} ${uri} ${status} error="${error}"` + "\n",
 	}))
 
+	Server.Echo().Use(metricsMiddleware)
 	auth.AddAuthentication(Server.Echo(), parameters.GetStringToString(parameters.WebAPIAuth))
 
-	webapi.Init(Server, adminWhitelist())
+	webapi.Init(Server, adminWhitelist(), parameters.GetInt(parameters.WebAPIMaxPastViewCalls))
 }
 
 func customHTTPErrorHandler(err error, c echo.Context) {
@@ -76,6 +78,25 @@ func customHTTPErrorHandler(err error, c echo.Context) {
 	c.Echo().DefaultHTTPErrorHandler(err, c)
 }
 
+// metricsMiddleware records latency of the request, labeled with the route (not the actual URI)
+func metricsMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		start := time.Now()
+		err := next(c)
+		status := c.Response().Status
+		if err != nil {
+			status = http.StatusInternalServerError
+			if he, ok := err.(*httperrors.HTTPError); ok {
+				status = he.Code
+			} else if he, ok := err.(*echo.HTTPError); ok {
+				status = he.Code
+			}
+		}
+		metrics.ObserveWebAPIRequest(c.Request().Method, c.Path(), status, time.Since(start))
+		return err
+	}
+}
+
 func adminWhitelist() []net.IP {
 	r := make([]net.IP, 0)
 	for _, ip := range parameters.GetStringSlice(parameters.WebAPIAdminWhitelist) {
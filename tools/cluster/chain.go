@@ -31,6 +31,7 @@ type Chain struct {
 	OriginatorSeed *seed.Seed
 
 	CommitteeNodes []int
+	AccessNodes    []int
 	Quorum         uint16
 	Address        address.Address
 
@@ -57,6 +58,14 @@ func (ch *Chain) PeeringHosts() []string {
 	return ch.Cluster.Config.PeeringHosts(ch.CommitteeNodes)
 }
 
+func (ch *Chain) AccessApiHosts() []string {
+	return ch.Cluster.Config.ApiHosts(ch.AccessNodes)
+}
+
+func (ch *Chain) AccessPeeringHosts() []string {
+	return ch.Cluster.Config.PeeringHosts(ch.AccessNodes)
+}
+
 func (ch *Chain) OriginatorAddress() *address.Address {
 	addr := ch.OriginatorSeed.Address(0).Address
 	return &addr
@@ -71,12 +71,19 @@ func (clu *Cluster) DeployDefaultChain() (*Chain, error) {
 }
 
 func (clu *Cluster) DeployChain(description string, committeeNodes []int, quorum uint16) (*Chain, error) {
+	return clu.DeployChainWithAccessNodes(description, committeeNodes, nil, quorum)
+}
+
+// DeployChainWithAccessNodes deploys the chain on the committee nodes. The access nodes
+// follow the chain without participating in the consensus
+func (clu *Cluster) DeployChainWithAccessNodes(description string, committeeNodes []int, accessNodes []int, quorum uint16) (*Chain, error) {
 	ownerSeed := seed.NewSeed()
 
 	chain := &Chain{
 		Description:    description,
 		OriginatorSeed: ownerSeed,
 		CommitteeNodes: committeeNodes,
+		AccessNodes:    accessNodes,
 		Quorum:         quorum,
 		Cluster:        clu,
 	}
@@ -90,6 +97,8 @@ func (clu *Cluster) DeployChain(description string, committeeNodes []int, quorum
 		Node:                  clu.Level1Client(),
 		CommitteeApiHosts:     chain.ApiHosts(),
 		CommitteePeeringHosts: chain.PeeringHosts(),
+		AccessApiHosts:        chain.AccessApiHosts(),
+		AccessPeeringHosts:    chain.AccessPeeringHosts(),
 		N:                     uint16(len(committeeNodes)),
 		T:                     quorum,
 		OriginatorSigScheme:   chain.OriginatorSigScheme(),
@@ -325,6 +334,29 @@ func (cluster *Cluster) start(dataPath string) error {
 		}
 	}
 	fmt.Printf("[cluster] started %d Wasp nodes\n", cluster.Config.Wasp.NumNodes)
+	return cluster.trustAll()
+}
+
+// trustAll adds each Wasp node of the cluster to the trusted peers of all other nodes
+func (cluster *Cluster) trustAll() error {
+	infos := make([]*model.InfoResponse, cluster.Config.Wasp.NumNodes)
+	for i := range infos {
+		info, err := cluster.WaspClient(i).Info()
+		if err != nil {
+			return err
+		}
+		infos[i] = info
+	}
+	for i := range infos {
+		for j, info := range infos {
+			if i == j {
+				continue
+			}
+			if _, err := cluster.WaspClient(i).PeeringTrust(info.PubKey, info.NetworkId); err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 }
 
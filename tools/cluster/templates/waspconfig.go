@@ -11,7 +11,8 @@ const WaspConfig = `
 {
   "database": {
     "inMemory": true,
-    "directory": "waspdb"
+    "directory": "waspdb",
+    "engine": "badger"
   },
   "logger": {
     "level": "info",
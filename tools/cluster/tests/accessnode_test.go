@@ -0,0 +1,83 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/iotaledger/wasp/contracts/examples_core/inccounter"
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/kv/codec"
+	"github.com/iotaledger/wasp/packages/vm/core/root"
+	"github.com/iotaledger/wasp/tools/cluster"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccessNode(t *testing.T) {
+	setup(t, "test_cluster")
+
+	committee := []int{0, 1, 2}
+	accessNode := 3
+
+	// the access node never runs the consensus, so it never receives requests
+	// and the committee nodes never see consensus messages from it
+	accessCounter, err := cluster.NewMessageCounter(clu, []int{accessNode}, map[string]int{
+		"request_in":            0,
+		"ignored_consensus_msg": 0,
+	})
+	check(err, t)
+	defer accessCounter.Close()
+
+	committeeCounter, err := cluster.NewMessageCounter(clu, committee, map[string]int{
+		"ignored_consensus_msg": 0,
+	})
+	check(err, t)
+	defer committeeCounter.Close()
+
+	chain, err = clu.DeployChainWithAccessNodes("access node test", committee, []int{accessNode}, 2)
+	check(err, t)
+
+	name := "inc"
+	hname := coretypes.Hn(name)
+	_, err = chain.DeployContract(name, inccounter.Interface.ProgramHash.String(), "inccounter", map[string]interface{}{
+		inccounter.VarCounter: 42,
+		root.ParamName:        name,
+	})
+	check(err, t)
+
+	testOwner := wallet.WithIndex(1)
+	err = requestFunds(clu, testOwner.Address(), "testOwner")
+	check(err, t)
+	myClient := chain.SCClient(hname, testOwner.SigScheme())
+
+	const numRequests = 3
+	for i := 0; i < numRequests; i++ {
+		tx, err := myClient.PostRequest(inccounter.FuncIncCounter)
+		check(err, t)
+		err = chain.CommitteeMultiClient().WaitUntilAllRequestsProcessed(tx, 30*time.Second)
+		check(err, t)
+		// the access node syncs the block and serves the status of the request
+		err = clu.WaspClient(accessNode).WaitUntilAllRequestsProcessed(tx, 30*time.Second)
+		check(err, t)
+	}
+
+	// the access node has the same state as the committee
+	contractID := chain.ContractID(hname)
+	expected, err := clu.WaspClient(committee[0]).DumpSCState(&contractID)
+	check(err, t)
+	actual, err := clu.WaspClient(accessNode).DumpSCState(&contractID)
+	check(err, t)
+	require.EqualValues(t, expected.Index, actual.Index)
+	require.EqualValues(t, expected.Variables, actual.Variables)
+
+	// the access node serves views
+	ret, err := clu.WaspClient(accessNode).CallView(contractID, inccounter.FuncGetCounter, nil)
+	check(err, t)
+	counterValue, _, err := codec.DecodeInt64(ret.MustGet(inccounter.VarCounter))
+	check(err, t)
+	require.EqualValues(t, 42+numRequests, counterValue)
+
+	accessCounter.CollectMessages(5 * time.Second)
+	committeeCounter.CollectMessages(5 * time.Second)
+	require.True(t, accessCounter.Report())
+	require.True(t, committeeCounter.Report())
+}
@@ -4,15 +4,30 @@ import (
 	"os"
 
 	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/kv/dict"
 	"github.com/iotaledger/wasp/tools/wasp-cli/log"
 	"github.com/iotaledger/wasp/tools/wasp-cli/util"
+	"github.com/spf13/pflag"
 )
 
+var callViewBlock int64
+
+func initCallViewFlags(flags *pflag.FlagSet) {
+	flags.Int64VarP(&callViewBlock, "block", "", -1, "call the view as of the state after the block with this index (default: current state)")
+}
+
 func callViewCmd(args []string) {
 	if len(args) < 2 {
-		log.Fatal("Usage: %s chain call-view <name> <funcname> [params]", os.Args[0])
+		log.Fatal("Usage: %s chain call-view [--block=<index>] <name> <funcname> [params]", os.Args[0])
+	}
+	client := SCClient(coretypes.Hn(args[0]))
+	var r dict.Dict
+	var err error
+	if callViewBlock >= 0 {
+		r, err = client.CallViewAtBlock(args[1], util.EncodeParams(args[2:]), uint32(callViewBlock))
+	} else {
+		r, err = client.CallView(args[1], util.EncodeParams(args[2:]))
 	}
-	r, err := SCClient(coretypes.Hn(args[0])).CallView(args[1], util.EncodeParams(args[2:]))
 	log.Check(err)
 	util.PrintDictAsJson(r)
 }
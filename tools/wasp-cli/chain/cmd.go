@@ -15,6 +15,7 @@ func InitCommands(commands map[string]func([]string), flags *pflag.FlagSet) {
 	initDeployFlags(fs)
 	initUploadFlags(fs)
 	initAliasFlags(fs)
+	initCallViewFlags(fs)
 	flags.AddFlagSet(fs)
 }
 
@@ -29,11 +30,14 @@ var subcmds = map[string]func([]string){
 	"list-blobs":      listBlobsCmd,
 	"store-blob":      storeBlobCmd,
 	"show-blob":       showBlobCmd,
+	"list-tokens":     listTokensCmd,
+	"token-holdings":  tokenHoldingsCmd,
 	"log":             logCmd,
 	"post-request":    postRequestCmd,
 	"call-view":       callViewCmd,
 	"activate":        activateCmd,
 	"deactivate":      deactivateCmd,
+	"snapshot":        snapshotCmd,
 }
 
 func chainCmd(args []string) {
@@ -15,6 +15,9 @@ func infoCmd(args []string) {
 
 	log.Printf("Chain ID: %s\n", chain.ChainID)
 	log.Printf("Committee nodes: %+v\n", chain.CommitteeNodes)
+	if len(chain.AccessNodes) > 0 {
+		log.Printf("Access nodes: %+v\n", chain.AccessNodes)
+	}
 	log.Printf("Active: %v\n", chain.Active)
 
 	if chain.Active {
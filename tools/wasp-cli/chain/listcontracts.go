@@ -28,6 +28,7 @@ func listContractsCmd(args []string) {
 		"creator",
 		"owner fee",
 		"validator fee",
+		"status",
 	}
 	rows := make([][]string, len(contracts))
 	i := 0
@@ -54,8 +55,16 @@ func listContractsCmd(args []string) {
 			creator,
 			fmt.Sprintf("%d %s", ownerFee, feeColor),
 			fmt.Sprintf("%d %s", validatorFee, feeColor),
+			contractStatus(c),
 		}
 		i++
 	}
 	log.PrintTable(header, rows)
 }
+
+func contractStatus(c *root.ContractRecord) string {
+	if c.Paused {
+		return "paused"
+	}
+	return "active"
+}
@@ -0,0 +1,39 @@
+package chain
+
+import (
+	"bufio"
+	"io"
+	"os"
+
+	"github.com/iotaledger/wasp/packages/state"
+	"github.com/iotaledger/wasp/tools/wasp-cli/config"
+	"github.com/iotaledger/wasp/tools/wasp-cli/log"
+)
+
+func snapshotCmd(args []string) {
+	if len(args) != 2 || (args[0] != "export" && args[0] != "import") {
+		log.Usage("%s chain snapshot [export|import] <file>\n", os.Args[0])
+	}
+	chainID := GetCurrentChainID()
+	switch args[0] {
+	case "export":
+		f, err := os.Create(args[1])
+		log.Check(err)
+		defer f.Close()
+		log.Check(config.WaspClient().ExportSnapshot(&chainID, f))
+		// the snapshot is incomplete if the state of the chain changed during the export
+		_, err = f.Seek(0, io.SeekStart)
+		log.Check(err)
+		snapshot := new(state.Snapshot)
+		log.Check(snapshot.Read(bufio.NewReader(f)))
+		log.Check(snapshot.Verify())
+		log.Printf("exported %s\n", snapshot.String())
+
+	case "import":
+		f, err := os.Open(args[1])
+		log.Check(err)
+		defer f.Close()
+		log.Check(config.WaspClient().ImportSnapshot(&chainID, f))
+		log.Printf("imported snapshot of chain %s from %s\n", chainID.String(), args[1])
+	}
+}
@@ -0,0 +1,56 @@
+package chain
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/iotaledger/wasp/packages/coretypes"
+	"github.com/iotaledger/wasp/packages/vm/core/tokens"
+	"github.com/iotaledger/wasp/packages/webapi/model"
+	"github.com/iotaledger/wasp/tools/wasp-cli/log"
+)
+
+func listTokensCmd(args []string) {
+	recs, err := Client().GetNativeTokens()
+	log.Check(err)
+
+	log.Printf("Total %d native token(s) in chain %s\n", len(recs), GetCurrentChainID())
+
+	header := []string{"symbol", "name", "color", "decimals", "supply", "owner"}
+	rows := make([][]string, len(recs))
+	for i, rec := range recs {
+		rows[i] = []string{
+			rec.Symbol,
+			rec.Name,
+			string(rec.Color),
+			fmt.Sprintf("%d", rec.Decimals),
+			formatAmount(rec, rec.Supply),
+			rec.Owner,
+		}
+	}
+	log.PrintTable(header, rows)
+}
+
+func tokenHoldingsCmd(args []string) {
+	if len(args) != 1 {
+		log.Usage("%s chain token-holdings <agentid>\n", os.Args[0])
+	}
+
+	agentID, err := coretypes.NewAgentIDFromString(args[0])
+	log.Check(err)
+
+	holdings, err := Client().GetTokenHoldings(agentID)
+	log.Check(err)
+
+	header := []string{"symbol", "color", "balance"}
+	rows := make([][]string, len(holdings))
+	for i, h := range holdings {
+		rows[i] = []string{h.Token.Symbol, string(h.Token.Color), formatAmount(h.Token, h.Balance)}
+	}
+	log.PrintTable(header, rows)
+}
+
+func formatAmount(token *model.NativeToken, amount int64) string {
+	rec := &tokens.TokenRecord{Symbol: token.Symbol, Decimals: token.Decimals}
+	return rec.FormatAmount(amount)
+}
@@ -0,0 +1,77 @@
+package peering
+
+import (
+	"os"
+	"strings"
+
+	"github.com/iotaledger/wasp/tools/wasp-cli/config"
+	"github.com/iotaledger/wasp/tools/wasp-cli/log"
+	"github.com/spf13/pflag"
+)
+
+func InitCommands(commands map[string]func([]string), flags *pflag.FlagSet) {
+	commands["peering"] = peeringCmd
+}
+
+var subcmds = map[string]func([]string){
+	"info":         infoCmd,
+	"list-trusted": listTrustedCmd,
+	"trust":        trustCmd,
+	"distrust":     distrustCmd,
+}
+
+func peeringCmd(args []string) {
+	if len(args) < 1 {
+		usage()
+	}
+	subcmd, ok := subcmds[args[0]]
+	if !ok {
+		usage()
+	}
+	subcmd(args[1:])
+}
+
+func usage() {
+	cmdNames := make([]string, 0)
+	for k := range subcmds {
+		cmdNames = append(cmdNames, k)
+	}
+
+	log.Usage("%s peering [%s]\n", os.Args[0], strings.Join(cmdNames, "|"))
+}
+
+func infoCmd(args []string) {
+	info, err := config.WaspClient().Info()
+	log.Check(err)
+	log.Printf("PubKey: %s\n", info.PubKey)
+	log.Printf("NetID:  %s\n", info.NetworkId)
+}
+
+func listTrustedCmd(args []string) {
+	trusted, err := config.WaspClient().PeeringTrustedList()
+	log.Check(err)
+	header := []string{"pubKey", "netID"}
+	rows := make([][]string, len(trusted))
+	for i, tp := range trusted {
+		rows[i] = []string{tp.PubKey, tp.NetID}
+	}
+	log.PrintTable(header, rows)
+}
+
+func trustCmd(args []string) {
+	if len(args) != 2 {
+		log.Usage("%s peering trust <pubKey> <netID>\n", os.Args[0])
+	}
+	tp, err := config.WaspClient().PeeringTrust(args[0], args[1])
+	log.Check(err)
+	log.Printf("Peer %s with public key %s is trusted\n", tp.NetID, tp.PubKey)
+}
+
+func distrustCmd(args []string) {
+	if len(args) != 1 {
+		log.Usage("%s peering distrust <pubKey>\n", os.Args[0])
+	}
+	tp, err := config.WaspClient().PeeringDistrust(args[0])
+	log.Check(err)
+	log.Printf("Peer %s with public key %s is not trusted anymore\n", tp.NetID, tp.PubKey)
+}